@@ -2,31 +2,110 @@ package app
 
 import (
 	"os"
+	"time"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/middleware"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/router"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
 )
 
 // AppConfig defines configuration options for initializing the application.
 // Includes toggles for middleware and required secrets.
 type AppConfig struct {
-	JWTSecret      string
+	// JWTKeys signs access tokens and verifies them in the authentication middleware.
+	// Required; see bootstrap.LoadJWTKeySet.
+	JWTKeys *utils.JWTKeySet
+
 	EnableCORS     bool
 	EnableLogger   bool
 	EnableRecovery bool
+
+	// RequireIfMatch rejects updates, deletions and revision restores of songs and documents without an If-Match header.
+	// When false, If-Match is still honoured if sent.
+	RequireIfMatch bool
+
+	// CachePolicies overrides the Cache-Control policies of the public catalogue reads, keyed by
+	// route (see router.DefaultCachePolicies). An empty policy sends no Cache-Control header.
+	CachePolicies map[string]string
+
+	// TrustedProxies lists the proxies whose X-Forwarded-For gives the client IP. None when empty.
+	// ClientIPHeader, when set, is a header holding the client IP that only the platform can set
+	// (see router.SourceIPHeader).
+	TrustedProxies []string
+	ClientIPHeader string
+
+	// AccessTokenTTL and RefreshTokenTTL control the lifetime of the tokens issued at login.
+	// They default to defaultAccessTokenTTL and defaultRefreshTokenTTL when zero.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// LoginThrottle controls the backoff and lockout applied after failed logins.
+	// Zero fields take the values of services.DefaultLoginThrottlePolicy.
+	LoginThrottle services.LoginThrottlePolicy
+
+	// OIDC enables the login through the organisation's OpenID provider. Nil disables it.
+	OIDC *services.OIDCConfig
+
+	// TwoFactor enables TOTP second factors for accounts of the users store. Nil disables them.
+	TwoFactor *services.TwoFactorConfig
+
+	// TrashRetention is how long deleted songs and documents stay in the trash before they are purged.
+	// Defaults to defaultTrashRetention when zero.
+	TrashRetention time.Duration
+
+	// TrashPurgeInterval runs the trash retention job in the background at this interval.
+	// Zero leaves purging to POST /admin/trash/purge.
+	TrashPurgeInterval time.Duration
+
+	// IdempotencyKeyTTL is how long the response to a create request sent with an Idempotency-Key is
+	// replayed to its retries. Defaults to defaultIdempotencyKeyTTL when zero.
+	IdempotencyKeyTTL time.Duration
+
+	// ConsistencyCheckInterval runs the job repairing document titles that drifted from their song
+	// in the background at this interval. Zero leaves it to POST /admin/consistency/titles/repair.
+	ConsistencyCheckInterval time.Duration
+
+	// CacheMaxEntries and CacheTTL enable the read-through cache of songs and documents, keeping up
+	// to CacheMaxEntries entries in memory for CacheTTL. Zero in either disables the cache.
+	CacheMaxEntries int
+	CacheTTL        time.Duration
+
+	// SharedCache backs the cache with a store shared by every instance. Nil caches in memory only.
+	SharedCache repository.SharedCache
+
+	// AutocompleteRefreshInterval controls how often the in-memory autocomplete index is rebuilt
+	// from DynamoDB. Defaults to defaultAutocompleteRefreshInterval when zero.
+	AutocompleteRefreshInterval time.Duration
 }
 
+// Defaults used when the corresponding AppConfig durations are not set.
+const (
+	defaultAutocompleteRefreshInterval = 15 * time.Minute
+	defaultAccessTokenTTL              = 15 * time.Minute
+	defaultRefreshTokenTTL             = 30 * 24 * time.Hour
+	defaultTrashRetention              = 30 * 24 * time.Hour
+	defaultIdempotencyKeyTTL           = 24 * time.Hour
+)
+
 // InitApp initializes all application components and returns a fully configured Gin router.
 //
 // Components initialized:
-//   - Repositories: DynamoDB implementations for songs, documents, search, and authentication
+//   - Repositories: DynamoDB implementations for songs, documents, genres, users, sessions, login attempts, API keys, pending OIDC logins,
+//     the audit trail, revisions, the trash, idempotency keys, search, and authentication, plus the static instrument catalogue and the OpenID provider client;
+//     songs and documents are read through a cache when cfg.CacheMaxEntries and cfg.CacheTTL are set
 //   - Services: business logic layers wired with required dependencies
 //   - Handlers: HTTP controllers connected to services
+//   - Middleware: JWT and API key authentication backed by the session and API key stores, and
+//     Idempotency-Key handling of create requests backed by the idempotency key store
+//   - Jobs: the trash retention job, which also reconciles interrupted song writes, when cfg.TrashPurgeInterval is set,
+//     and the document title consistency job when cfg.ConsistencyCheckInterval is set; both can also be run
+//     once through the Maintenance runner returned by InitAppWithMaintenance
 //   - Router: sets up routes and middleware with the configured handlers
 //
 // Parameters:
@@ -36,33 +115,151 @@ type AppConfig struct {
 // Returns:
 //   - a *gin.Engine instance ready to serve HTTP requests
 func InitApp(db *dynamo.DB, cfg AppConfig) *gin.Engine {
+	engine, _ := InitAppWithMaintenance(db, cfg)
+	return engine
+}
+
+// InitAppWithMaintenance initializes the application like InitApp and also returns the Maintenance
+// runner, for deployments where the background jobs cannot run and are triggered from outside instead.
+func InitAppWithMaintenance(db *dynamo.DB, cfg AppConfig) (*gin.Engine, *Maintenance) {
+	if cfg.JWTKeys == nil {
+		logrus.Fatal("AppConfig.JWTKeys is required")
+	}
 
 	// Initialize repositories
-	documentRepo := repository.NewDynamoDocumentRepository(db)
-	songRepo := repository.NewDynamoSongRepository(db, documentRepo)
-	searchRepo := repository.NewDynamoSearchRepository(db, documentRepo)
+	dynamoDocumentRepo := repository.NewDynamoDocumentRepository(db)
+	var documentRepo repository.DocumentRepository = dynamoDocumentRepo
+	dynamoSongRepo := repository.NewDynamoSongRepository(db, dynamoDocumentRepo)
+	var songRepo repository.SongRepository = dynamoSongRepo
+	searchRepo := repository.NewDynamoSearchRepository(db)
 	authRepo := repository.NewAWSAuthRepository(os.Getenv("ENV"))
+	instrumentRepo := repository.NewStaticInstrumentRepository()
+	var genreRepo repository.GenreRepository = repository.NewDynamoGenreRepository(db)
+	userRepo := repository.NewDynamoUserRepository(db)
+	sessionRepo := repository.NewDynamoSessionRepository(db)
+	loginAttemptRepo := repository.NewDynamoLoginAttemptRepository(db)
+	apiKeyRepo := repository.NewDynamoAPIKeyRepository(db)
+	oidcLoginRepo := repository.NewDynamoOIDCLoginRepository(db)
+	auditRepo := repository.NewDynamoAuditRepository(db)
+	revisionRepo := repository.NewDynamoRevisionRepository(db)
+	idempotencyRepo := repository.NewDynamoIdempotencyRepository(db)
+	var trashRepo repository.TrashRepository = repository.NewDynamoTrashRepository(db)
+	oidcProviderRepo := repository.NewHTTPOIDCProviderRepository(nil)
+
+	// Read-through cache of songs and documents. The trash and genre repositories rewrite songs
+	// and documents too, so they are wrapped to invalidate it.
+	var cache *repository.RepositoryCache
+	if cfg.CacheMaxEntries > 0 && cfg.CacheTTL > 0 {
+		cache = repository.NewRepositoryCache(cfg.CacheMaxEntries, cfg.CacheTTL, cfg.SharedCache)
+		documentRepo = repository.NewCachedDocumentRepository(documentRepo, cache)
+		songRepo = repository.NewCachedSongRepository(songRepo, documentRepo, cache)
+		trashRepo = repository.NewCachedTrashRepository(trashRepo, cache)
+		genreRepo = repository.NewCachedGenreRepository(genreRepo, cache)
+	}
 
 	// Initialize services
 	idGen := &utils.UUIDGenerator{}
 	timeProvider := &utils.UTCTimeProvider{}
-	tokenGen := &utils.JWTTokenGenerator{Secret: []byte(cfg.JWTSecret)}
+	tokenGen := &utils.JWTTokenGenerator{Keys: cfg.JWTKeys}
+
+	refreshInterval := cfg.AutocompleteRefreshInterval
+	if refreshInterval == 0 {
+		refreshInterval = defaultAutocompleteRefreshInterval
+	}
+	accessTTL := cfg.AccessTokenTTL
+	if accessTTL == 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	refreshTTL := cfg.RefreshTokenTTL
+	if refreshTTL == 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+	trashRetention := cfg.TrashRetention
+	if trashRetention == 0 {
+		trashRetention = defaultTrashRetention
+	}
+	idempotencyKeyTTL := cfg.IdempotencyKeyTTL
+	if idempotencyKeyTTL == 0 {
+		idempotencyKeyTTL = defaultIdempotencyKeyTTL
+	}
 
-	songService := services.NewSongService(songRepo, documentRepo, idGen, timeProvider)
-	documentService := services.NewDocumentService(documentRepo, songRepo, idGen, timeProvider)
-	searchService := services.NewSearchService(searchRepo)
-	authService := services.NewAuthService(authRepo, timeProvider, tokenGen)
+	throttle := services.DefaultLoginThrottlePolicy()
+	if cfg.LoginThrottle.MaxUserFailures > 0 {
+		throttle.MaxUserFailures = cfg.LoginThrottle.MaxUserFailures
+	}
+	if cfg.LoginThrottle.MaxIPFailures > 0 {
+		throttle.MaxIPFailures = cfg.LoginThrottle.MaxIPFailures
+	}
+	if cfg.LoginThrottle.FreeFailures > 0 {
+		throttle.FreeFailures = cfg.LoginThrottle.FreeFailures
+	}
+	if cfg.LoginThrottle.BaseBackoff > 0 {
+		throttle.BaseBackoff = cfg.LoginThrottle.BaseBackoff
+	}
+	if cfg.LoginThrottle.Lockout > 0 {
+		throttle.Lockout = cfg.LoginThrottle.Lockout
+	}
+
+	autocompleteService := services.NewAutocompleteService(searchRepo, timeProvider, refreshInterval)
+
+	instrumentService := services.NewInstrumentService(instrumentRepo)
+	genreService := services.NewGenreService(genreRepo, idGen, timeProvider, autocompleteService)
+
+	songService := services.NewSongService(songRepo, documentRepo, revisionRepo, idGen, timeProvider, autocompleteService, instrumentService, genreService)
+	documentService := services.NewDocumentService(documentRepo, songRepo, revisionRepo, idGen, timeProvider, autocompleteService, instrumentService)
+	searchService := services.NewSearchService(searchRepo, songRepo, instrumentService)
+	authService := services.NewAuthService(authRepo, userRepo, sessionRepo, loginAttemptRepo, idGen, timeProvider, tokenGen, accessTTL, refreshTTL, throttle, cfg.TwoFactor)
+	userService := services.NewUserService(userRepo, sessionRepo, timeProvider)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, idGen, timeProvider)
+	oidcService := services.NewOIDCService(cfg.OIDC, oidcProviderRepo, oidcLoginRepo, authService, timeProvider)
+	auditService := services.NewAuditService(auditRepo)
+	revisionService := services.NewRevisionService(revisionRepo, songRepo, documentRepo, idGen, timeProvider, autocompleteService, genreService)
+	trashService := services.NewTrashService(trashRepo, songRepo, idGen, timeProvider, autocompleteService, trashRetention)
+	cacheService := services.NewCacheService(cache, cfg.SharedCache != nil)
+	consistencyService := services.NewConsistencyService(dynamoSongRepo, trashRepo, documentRepo, idGen, timeProvider)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, timeProvider, idempotencyKeyTTL)
 
 	// Initialize handlers
 	songHandler := handlers.NewSongHandler(songService)
 	documentHandler := handlers.NewDocumentHandler(documentService)
 	searchHandler := handlers.NewSearchHandler(searchService)
 	authHandler := handlers.NewAuthHandler(authService)
+	autocompleteHandler := handlers.NewAutocompleteHandler(autocompleteService)
+	instrumentHandler := handlers.NewInstrumentHandler(instrumentService)
+	genreHandler := handlers.NewGenreHandler(genreService)
+	userHandler := handlers.NewUserHandler(userService)
+	jwksHandler := handlers.NewJWKSHandler(cfg.JWTKeys)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	cacheHandler := handlers.NewCacheHandler(cacheService)
+	consistencyHandler := handlers.NewConsistencyHandler(consistencyService)
+
+	// Background jobs
+	if cfg.TrashPurgeInterval > 0 {
+		trashService.StartRetentionJob(cfg.TrashPurgeInterval)
+	}
+	if cfg.ConsistencyCheckInterval > 0 {
+		consistencyService.StartConsistencyJob(cfg.ConsistencyCheckInterval)
+	}
+
+	// Middleware
+	authMiddleware := middleware.JWTAuthMiddleware(cfg.JWTKeys, authService, apiKeyService)
+	idempotencyMiddleware := middleware.Idempotency(idempotencyService)
+
+	maintenance := &Maintenance{trash: trashService, consistency: consistencyService}
 
 	// Router
-	return router.SetupRouter(songHandler, documentHandler, searchHandler, authHandler, router.RouterOptions{
+	engine := router.SetupRouter(songHandler, documentHandler, searchHandler, authHandler, autocompleteHandler, instrumentHandler, genreHandler, userHandler, jwksHandler, apiKeyHandler, oidcHandler, auditHandler, revisionHandler, trashHandler, cacheHandler, consistencyHandler, authMiddleware, idempotencyMiddleware, router.RouterOptions{
 		EnableCORS:     cfg.EnableCORS,
 		EnableLogger:   cfg.EnableLogger,
 		EnableRecovery: cfg.EnableRecovery,
+		RequireIfMatch: cfg.RequireIfMatch,
+		CachePolicies:  cfg.CachePolicies,
+		TrustedProxies: cfg.TrustedProxies,
+		ClientIPHeader: cfg.ClientIPHeader,
 	})
+	return engine, maintenance
 }
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/sirupsen/logrus"
+)
+
+// Maintenance runs once, on demand, the jobs that StartRetentionJob and StartConsistencyJob run in the
+// background. Lambda invokes it from scheduled events, since frozen containers cannot run tickers.
+type Maintenance struct {
+	trash       services.TrashServiceInterface
+	consistency services.ConsistencyServiceInterface
+}
+
+// Run reconciles interrupted song writes, purges the expired trash and repairs document titles.
+// Every job runs even if an earlier one fails; their errors are returned together.
+func (m *Maintenance) Run() error {
+	var errs []error
+
+	reconciled, err := m.trash.ReconcilePendingSongs(services.TrashRetentionActor)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("reconciling pending songs: %w", err))
+	}
+	purged, err := m.trash.PurgeExpired(services.TrashRetentionActor)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("purging expired trash: %w", err))
+	}
+	titles, err := m.consistency.CheckDocumentTitles(true)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("repairing document titles: %w", err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"songs_completed":   reconciled.Completed,
+		"songs_rolled_back": reconciled.RolledBack,
+		"songs_purged":      purged.Songs,
+		"documents_purged":  purged.Documents,
+		"titles_repaired":   titles.Repaired,
+	}).Info("Maintenance run finished")
+	return errors.Join(errs...)
+}
@@ -1,17 +1,87 @@
 package bootstrap
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	SongTableName     string
-	DocumentTableName string
-	AWSRegion         string
-	AppPort           string
+	SongTableName         string
+	DocumentTableName     string
+	GenreTableName        string
+	UserTableName         string
+	SessionTableName      string
+	LoginAttemptTableName string
+	APIKeyTableName       string
+	OIDCLoginTableName    string
+	AuditTableName        string
+	RevisionTableName     string
+	IdempotencyTableName  string
+	AWSRegion             string
+	AppPort               string
+	JWTIssuer             string
+	JWTAudience           string
+
+	AutocompleteRefreshInterval time.Duration
+	AccessTokenTTL              time.Duration
+	RefreshTokenTTL             time.Duration
+
+	// Trashed songs and documents are purged after TrashRetention. Outside Lambda the retention
+	// job runs every TrashPurgeInterval; zero disables it.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Responses to create requests sent with an Idempotency-Key are replayed for IdempotencyKeyTTL.
+	IdempotencyKeyTTL time.Duration
+
+	// Outside Lambda, document titles that drifted from their song are repaired every
+	// ConsistencyCheckInterval; zero disables it.
+	ConsistencyCheckInterval time.Duration
+
+	// RequireIfMatch rejects updates, deletions and revision restores of songs and documents sent without If-Match.
+	RequireIfMatch bool
+
+	// Songs and documents are cached in memory, up to CacheMaxEntries for CacheTTL; zero disables it.
+	CacheMaxEntries int
+	CacheTTL        time.Duration
+
+	// CachePolicies overrides the Cache-Control policy of public read routes, keyed by route.
+	CachePolicies map[string]string
+
+	LoginMaxUserFailures int
+	LoginMaxIPFailures   int
+	LoginFreeFailures    int
+	LoginBaseBackoff     time.Duration
+	LoginLockout         time.Duration
+
+	// OIDC login is enabled when OIDCIssuer is set.
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       []string
+	OIDCGroupsClaim  string
+	OIDCGroupRoles   map[string]string
+	OIDCSubjectRoles map[string]string
+	OIDCDefaultRole  string
+
+	// TOTP second factors are enabled when TOTP_ENCRYPTION_KEY is set (see LoadTOTPCipher).
+	TOTPIssuer        string
+	TOTPRequiredRoles []string
+	MFAChallengeTTL   time.Duration
+
+	// TrustedProxies lists the addresses or CIDRs of the reverse proxies whose X-Forwarded-For
+	// is believed. Empty trusts none, so the client IP is the address of the connection.
+	TrustedProxies []string
 )
 
 func LoadConfig() {
@@ -22,14 +92,66 @@ func LoadConfig() {
 
 	SongTableName = getEnv("SONGS_TABLE", "default_songs_table")
 	DocumentTableName = getEnv("DOCUMENTS_TABLE", "default_documents_table")
+	GenreTableName = getEnv("GENRES_TABLE", "default_genres_table")
+	UserTableName = getEnv("USERS_TABLE", "default_users_table")
+	SessionTableName = getEnv("SESSIONS_TABLE", "default_sessions_table")
+	LoginAttemptTableName = getEnv("LOGIN_ATTEMPTS_TABLE", "default_login_attempts_table")
+	APIKeyTableName = getEnv("API_KEYS_TABLE", "default_api_keys_table")
+	OIDCLoginTableName = getEnv("OIDC_LOGINS_TABLE", "default_oidc_logins_table")
+	AuditTableName = getEnv("AUDIT_TABLE", "default_audit_table")
+	RevisionTableName = getEnv("REVISIONS_TABLE", "default_revisions_table")
+	IdempotencyTableName = getEnv("IDEMPOTENCY_TABLE", "default_idempotency_table")
 	AWSRegion = getEnv("AWS_REGION", "eu-north-1")
 	AppPort = getEnv("APP_PORT", "8080")
+	JWTIssuer = getEnv("JWT_ISSUER", "rendalla-backend")
+	JWTAudience = getEnv("JWT_AUDIENCE", "rendalla-api")
+	AutocompleteRefreshInterval = time.Duration(getEnvInt("AUTOCOMPLETE_REFRESH_MINUTES", 15)) * time.Minute
+	AccessTokenTTL = time.Duration(getEnvInt("ACCESS_TOKEN_MINUTES", 15)) * time.Minute
+	RefreshTokenTTL = time.Duration(getEnvInt("REFRESH_TOKEN_DAYS", 30)) * 24 * time.Hour
+	TrashRetention = time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+	TrashPurgeInterval = time.Duration(getEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 60)) * time.Minute
+	IdempotencyKeyTTL = time.Duration(getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24)) * time.Hour
+	ConsistencyCheckInterval = time.Duration(getEnvInt("CONSISTENCY_CHECK_INTERVAL_MINUTES", 360)) * time.Minute
+	RequireIfMatch = getEnvBool("REQUIRE_IF_MATCH", false)
+	CachePolicies = getEnvStringMap("CACHE_CONTROL_POLICIES")
+	CacheMaxEntries = getEnvInt("CACHE_MAX_ENTRIES", 10000)
+	CacheTTL = time.Duration(getEnvInt("CACHE_TTL_SECONDS", 30)) * time.Second
+	LoginMaxUserFailures = getEnvInt("LOGIN_MAX_FAILURES", 5)
+	LoginMaxIPFailures = getEnvInt("LOGIN_MAX_IP_FAILURES", 20)
+	LoginFreeFailures = getEnvInt("LOGIN_FREE_FAILURES", 2)
+	LoginBaseBackoff = time.Duration(getEnvInt("LOGIN_BACKOFF_SECONDS", 1)) * time.Second
+	LoginLockout = time.Duration(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+	OIDCIssuer = getEnv("OIDC_ISSUER", "")
+	OIDCClientID = getEnv("OIDC_CLIENT_ID", "")
+	OIDCClientSecret = getEnv("OIDC_CLIENT_SECRET", "")
+	OIDCRedirectURL = getEnv("OIDC_REDIRECT_URL", "")
+	OIDCScopes = strings.Fields(getEnv("OIDC_SCOPES", "openid profile email groups"))
+	OIDCGroupsClaim = getEnv("OIDC_GROUPS_CLAIM", "groups")
+	OIDCGroupRoles = getEnvStringMap("OIDC_GROUP_ROLES")
+	OIDCSubjectRoles = getEnvStringMap("OIDC_SUBJECT_ROLES")
+	OIDCDefaultRole = getEnv("OIDC_DEFAULT_ROLE", "")
+	TOTPIssuer = getEnv("TOTP_ISSUER", "Rendalla")
+	TOTPRequiredRoles = strings.Fields(getEnv("TOTP_REQUIRED_ROLES", "admin"))
+	MFAChallengeTTL = time.Duration(getEnvInt("MFA_CHALLENGE_MINUTES", 5)) * time.Minute
+	TrustedProxies = strings.Fields(getEnv("TRUSTED_PROXIES", ""))
 
 	logrus.WithFields(logrus.Fields{
-		"SongTableName":     SongTableName,
-		"DocumentTableName": DocumentTableName,
-		"AWSRegion":         AWSRegion,
-		"AppPort":           AppPort,
+		"SongTableName":         SongTableName,
+		"DocumentTableName":     DocumentTableName,
+		"GenreTableName":        GenreTableName,
+		"UserTableName":         UserTableName,
+		"SessionTableName":      SessionTableName,
+		"LoginAttemptTableName": LoginAttemptTableName,
+		"APIKeyTableName":       APIKeyTableName,
+		"OIDCLoginTableName":    OIDCLoginTableName,
+		"AuditTableName":        AuditTableName,
+		"RevisionTableName":     RevisionTableName,
+		"IdempotencyTableName":  IdempotencyTableName,
+		"OIDCIssuer":            OIDCIssuer,
+		"AWSRegion":             AWSRegion,
+		"AppPort":               AppPort,
+		"JWTIssuer":             JWTIssuer,
+		"JWTAudience":           JWTAudience,
 	}).Info("Configuration loaded successfully")
 }
 
@@ -39,3 +161,170 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.WithField("key", key).Warn("Invalid integer environment variable, using default value")
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		logrus.WithField("key", key).Warn("Invalid boolean environment variable, using default value")
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvStringMap parses a JSON object of strings, such as {"rendalla-editors":"editor"}.
+// A missing or invalid value yields an empty map.
+func getEnvStringMap(key string) map[string]string {
+	result := map[string]string{}
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return result
+	}
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		logrus.WithField("key", key).Warn("Invalid JSON object environment variable, ignoring it")
+		return map[string]string{}
+	}
+	return result
+}
+
+// LoadTOTPCipher returns the cipher protecting TOTP seeds at rest, built from
+// TOTP_ENCRYPTION_KEY (32 bytes, base64). It returns nil without error when the variable is
+// not set, which disables second factors.
+func LoadTOTPCipher() (*utils.SecretCipher, error) {
+	raw := os.Getenv("TOTP_ENCRYPTION_KEY")
+	if raw == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decoding TOTP_ENCRYPTION_KEY: %w", err)
+	}
+	return utils.NewSecretCipher(key)
+}
+
+// jwtKeyConfig is the JSON representation of a key in JWT_KEYS.
+// HS256 keys set "secret"; RS256 and EdDSA keys set a PEM private key, inline or as a file path,
+// or only a PEM public key to keep verifying tokens signed elsewhere.
+type jwtKeyConfig struct {
+	Kid            string    `json:"kid"`
+	Secret         string    `json:"secret"`
+	PrivateKey     string    `json:"private_key"`
+	PrivateKeyFile string    `json:"private_key_file"`
+	PublicKey      string    `json:"public_key"`
+	PublicKeyFile  string    `json:"public_key_file"`
+	ActivateAt     time.Time `json:"activate_at"`
+	ExpireAt       time.Time `json:"expire_at"`
+}
+
+// defaultJWTKeyID is the kid given to the key built from JWT_PRIVATE_KEY or JWT_SECRET
+// when JWT_KEY_ID is not set.
+const defaultJWTKeyID = "default"
+
+// LoadJWTKeySet builds the key set shared by the token generator and the authentication middleware.
+//
+// Keys are read from JWT_KEYS, a JSON array of {"kid", "secret" | "private_key" | "private_key_file" |
+// "public_key" | "public_key_file", "activate_at", "expire_at"} objects (RFC 3339 times, both optional),
+// which allows scheduling the next key ahead of a rotation. The algorithm follows the key material:
+// HS256 for secrets, RS256 for RSA and EdDSA for Ed25519 PEM keys.
+// If JWT_KEYS is not set, a single key with kid JWT_KEY_ID (default "default") is read from
+// JWT_PRIVATE_KEY (PEM) or JWT_PRIVATE_KEY_FILE, and otherwise from JWT_SECRET.
+//
+// Outside test mode (ENV=test) a missing key is an error so the application refuses to start;
+// in test mode an ephemeral random key is generated instead.
+func LoadJWTKeySet(clock utils.TimeProvider) (*utils.JWTKeySet, error) {
+	var keys []utils.JWTKey
+	kid := getEnv("JWT_KEY_ID", defaultJWTKeyID)
+
+	if raw := os.Getenv("JWT_KEYS"); raw != "" {
+		var configs []jwtKeyConfig
+		if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+			return nil, fmt.Errorf("parsing JWT_KEYS: %w", err)
+		}
+		for _, cfg := range configs {
+			key, err := cfg.toJWTKey()
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %q: %w", cfg.Kid, err)
+			}
+			keys = append(keys, key)
+		}
+	} else if os.Getenv("JWT_PRIVATE_KEY") != "" || os.Getenv("JWT_PRIVATE_KEY_FILE") != "" {
+		cfg := jwtKeyConfig{
+			Kid:            kid,
+			PrivateKey:     os.Getenv("JWT_PRIVATE_KEY"),
+			PrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		}
+		key, err := cfg.toJWTKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = []utils.JWTKey{key}
+	} else if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		keys = []utils.JWTKey{{ID: kid, Secret: []byte(secret)}}
+	} else if os.Getenv("ENV") == "test" {
+		secret, err := utils.GenerateSecureToken(32)
+		if err != nil {
+			return nil, fmt.Errorf("generating ephemeral JWT key: %w", err)
+		}
+		logrus.Warn("No JWT key configured, using an ephemeral key for test mode")
+		keys = []utils.JWTKey{{ID: kid, Secret: []byte(secret)}}
+	} else {
+		return nil, fmt.Errorf("no JWT key configured: set JWT_KEYS, JWT_PRIVATE_KEY, JWT_PRIVATE_KEY_FILE or JWT_SECRET")
+	}
+
+	return utils.NewJWTKeySet(JWTIssuer, JWTAudience, keys, clock)
+}
+
+// toJWTKey loads the key material of a configured key.
+// A private key takes precedence over a public key, and inline PEM over a file.
+func (cfg jwtKeyConfig) toJWTKey() (utils.JWTKey, error) {
+	key := utils.JWTKey{ID: cfg.Kid, ActivateAt: cfg.ActivateAt, ExpireAt: cfg.ExpireAt}
+
+	privatePEM, err := readPEM(cfg.PrivateKey, cfg.PrivateKeyFile)
+	if err != nil {
+		return key, fmt.Errorf("reading private key: %w", err)
+	}
+	if privatePEM != nil {
+		key.PrivateKey, key.Algorithm, err = utils.ParsePrivateKeyPEM(privatePEM)
+		return key, err
+	}
+
+	publicPEM, err := readPEM(cfg.PublicKey, cfg.PublicKeyFile)
+	if err != nil {
+		return key, fmt.Errorf("reading public key: %w", err)
+	}
+	if publicPEM != nil {
+		key.PublicKey, key.Algorithm, err = utils.ParsePublicKeyPEM(publicPEM)
+		return key, err
+	}
+
+	key.Algorithm = utils.JWTAlgorithmHS256
+	key.Secret = []byte(cfg.Secret)
+	return key, nil
+}
+
+// readPEM returns the inline PEM if given, otherwise the content of the file, or nil if neither is set.
+func readPEM(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
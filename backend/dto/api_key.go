@@ -0,0 +1,30 @@
+package dto
+
+// CreateAPIKeyRequest describes a new API key.
+// ExpiresInDays is optional; zero creates a key that never expires.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"`
+}
+
+type APIKeyResponseItem struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Status     string   `json:"status"`
+	CreatedBy  string   `json:"created_by"`
+	ExpiresAt  int64    `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is returned once when an API key is created.
+// The key is not stored in clear and cannot be retrieved again.
+type CreateAPIKeyResponse struct {
+	Message   string   `json:"message"`
+	ID        string   `json:"id"`
+	Key       string   `json:"key"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt int64    `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// MaxAPIKeyLifetimeDays bounds the optional expiry of an API key.
+const MaxAPIKeyLifetimeDays = 3650
+
+func ToAPIKeyResponseItem(m models.APIKey) APIKeyResponseItem {
+	scopes := slices.Clone(m.Scopes)
+	slices.Sort(scopes)
+	return APIKeyResponseItem{
+		ID:         m.ID,
+		Name:       m.Name,
+		Scopes:     scopes,
+		Status:     m.Status,
+		CreatedBy:  m.CreatedBy,
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func ToAPIKeyResponseList(keys []models.APIKey) []APIKeyResponseItem {
+	out := make([]APIKeyResponseItem, len(keys))
+	for i, k := range keys {
+		out[i] = ToAPIKeyResponseItem(k)
+	}
+	return out
+}
+
+// ValidateCreateAPIKeyRequest validates CreateAPIKeyRequest DTO.
+func ValidateCreateAPIKeyRequest(req CreateAPIKeyRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 3 || len(name) > 64 {
+		return errors.ErrValidationFailed
+	}
+	if len(req.Scopes) == 0 {
+		return errors.ErrValidationFailed
+	}
+	for _, scope := range req.Scopes {
+		if !models.IsValidScope(scope) {
+			return errors.ErrValidationFailed
+		}
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > MaxAPIKeyLifetimeDays {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
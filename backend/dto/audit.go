@@ -0,0 +1,36 @@
+package dto
+
+// AuditQuery holds the query parameters of GET /admin/audit. Every field is optional.
+// Since accepts an RFC3339 timestamp (e.g., "2025-01-31T00:00:00Z"). Limit defaults to DefaultListLimit
+// and Cursor is the next_token of the previous page.
+type AuditQuery struct {
+	Entity string `form:"entity"`
+	Actor  string `form:"actor"`
+	Since  string `form:"since"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+type AuditListResponse struct {
+	Data      []AuditEntryResponseItem `json:"data"`
+	Count     int                      `json:"count"`
+	NextToken string                   `json:"next_token,omitempty"`
+}
+
+type FieldChangeResponseItem struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+type AuditEntryResponseItem struct {
+	ID        string                    `json:"id"`
+	Entity    string                    `json:"entity"`
+	EntityID  string                    `json:"entity_id"`
+	SongID    string                    `json:"song_id"`
+	Action    string                    `json:"action"`
+	Actor     string                    `json:"actor"`
+	RequestID string                    `json:"request_id,omitempty"`
+	Timestamp string                    `json:"timestamp"`
+	Changes   []FieldChangeResponseItem `json:"changes"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+func ToAuditEntryResponseItem(m models.AuditEntry) AuditEntryResponseItem {
+	return AuditEntryResponseItem{
+		ID:        m.ID,
+		Entity:    m.Entity,
+		EntityID:  m.EntityID,
+		SongID:    m.SongID,
+		Action:    m.Action,
+		Actor:     m.Actor,
+		RequestID: m.RequestID,
+		Timestamp: m.Timestamp,
+		Changes:   ToFieldChangeResponseList(m.Changes),
+	}
+}
+
+func ToAuditEntryResponseList(entries []models.AuditEntry) []AuditEntryResponseItem {
+	out := make([]AuditEntryResponseItem, len(entries))
+	for i, e := range entries {
+		out[i] = ToAuditEntryResponseItem(e)
+	}
+	return out
+}
+
+// NormalizeAuditQuery validates AuditQuery DTO and returns it trimmed, with the default page size
+// and Since converted to the UTC RFC3339 form used by stored timestamps so that they compare as strings.
+func NormalizeAuditQuery(q AuditQuery) (AuditQuery, error) {
+	q.Entity = strings.TrimSpace(q.Entity)
+	q.Actor = strings.TrimSpace(q.Actor)
+	q.Since = strings.TrimSpace(q.Since)
+
+	switch q.Entity {
+	case "", models.AuditEntitySong, models.AuditEntityDocument:
+	default:
+		return q, errors.ErrValidationFailed
+	}
+
+	if q.Limit < 0 || q.Limit > MaxListLimit {
+		return q, errors.ErrValidationFailed
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultListLimit
+	}
+
+	if q.Since != "" {
+		since, err := time.Parse(time.RFC3339, q.Since)
+		if err != nil {
+			return q, errors.ErrValidationFailed
+		}
+		q.Since = since.UTC().Format(time.RFC3339)
+	}
+	return q, nil
+}
@@ -1,15 +1,106 @@
 package dto
 
+import "github.com/CristinaRendaLopez/rendalla-backend/utils"
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
+// AuthResponse is returned by login and refresh.
+// Token is the short-lived access token; RefreshToken is single-use and must be
+// exchanged at POST /auth/refresh for a new pair.
+//
+// When the account needs a second factor, login returns no tokens but MFARequired and an
+// MFAToken valid for ExpiresIn seconds, to be sent with a one-time code to POST /auth/login/2fa.
+// MFAEnrollmentRequired means the role requires a second factor the account has not set up
+// yet; it must first be enrolled with POST /auth/login/2fa/enroll.
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token                 string `json:"token,omitempty"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	TokenType             string `json:"token_type,omitempty"`
+	ExpiresIn             int64  `json:"expires_in"`
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// SecondFactorLoginRequest completes a login that requires a second factor.
+// Code is the current TOTP code or one of the recovery codes.
+type SecondFactorLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFATokenRequest identifies a pending login by its MFA token.
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// TOTPCodeRequest carries a TOTP code, or a recovery code where accepted.
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TOTPEnrollmentResponse is returned once when a TOTP enrolment starts.
+// The seed and the recovery codes are not stored in clear and cannot be retrieved again.
+// The enrolment takes effect once confirmed with a first code.
+type TOTPEnrollmentResponse struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ChangePasswordRequest changes the password of the authenticated user.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ResetPasswordRequest sets a new password with a token issued by an admin.
+type ResetPasswordRequest struct {
+	Username    string `json:"username" binding:"required"`
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// PasswordResetResponse is returned once when an admin issues a reset token.
+// The token is not stored in clear and cannot be retrieved again.
+type PasswordResetResponse struct {
+	Message    string `json:"message"`
+	Username   string `json:"username"`
+	ResetToken string `json:"reset_token"`
+	ExpiresAt  int64  `json:"expires_at"`
 }
 
 type MeResponse struct {
 	Username string `json:"username"`
 	Role     string `json:"role"`
 }
+
+// JWKSResponse is the JSON Web Key Set served at /.well-known/jwks.json.
+type JWKSResponse struct {
+	Keys []utils.JWK `json:"keys"`
+}
+
+// OIDCLoginResponse starts a login with the organisation's OpenID provider.
+// The client sends the browser to AuthorizationURL; the provider redirects back with a code
+// and the state, which are then passed to GET /auth/oidc/callback within ExpiresIn seconds.
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+// OIDCCallbackRequest holds the query parameters the provider appends to the redirect URL.
+// Error is set instead of Code when the user or the provider refused the login.
+type OIDCCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// Suggestion types supported by the autocomplete endpoint.
+const (
+	SuggestionTypeSong       = "song"
+	SuggestionTypeAuthor     = "author"
+	SuggestionTypeGenre      = "genre"
+	SuggestionTypeInstrument = "instrument"
+)
+
+type Suggestion struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type CacheStatsResponse struct {
+	Enabled    bool    `json:"enabled"`
+	Shared     bool    `json:"shared"`
+	Hits       uint64  `json:"hits"`
+	SharedHits uint64  `json:"shared_hits"`
+	Misses     uint64  `json:"misses"`
+	HitRatio   float64 `json:"hit_ratio"`
+	Evictions  uint64  `json:"evictions"`
+	Entries    int     `json:"entries"`
+}
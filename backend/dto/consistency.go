@@ -0,0 +1,14 @@
+package dto
+
+type TitleDrift struct {
+	SongID     string `json:"song_id"`
+	DocumentID string `json:"document_id"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+}
+
+type TitleConsistencyReport struct {
+	Checked  int          `json:"checked"`
+	Drifted  []TitleDrift `json:"drifted"`
+	Repaired int          `json:"repaired"`
+}
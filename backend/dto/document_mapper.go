@@ -20,12 +20,14 @@ func ToDocumentResponseItem(m models.Document) DocumentResponseItem {
 	return DocumentResponseItem{
 		ID:         m.ID,
 		SongID:     m.SongID,
+		Title:      m.Title,
 		Type:       m.Type,
 		Instrument: m.Instrument,
 		PDFURL:     m.PDFURL,
 		AudioURL:   m.AudioURL,
 		CreatedAt:  m.CreatedAt,
 		UpdatedAt:  m.UpdatedAt,
+		Version:    m.Version,
 	}
 }
 
@@ -76,3 +78,10 @@ func ValidateUpdateDocumentRequest(doc UpdateDocumentRequest) error {
 	}
 	return nil
 }
+
+func ToDocumentSearchHit(m models.Document, song SongSummary) DocumentSearchHit {
+	return DocumentSearchHit{
+		DocumentResponseItem: ToDocumentResponseItem(m),
+		Song:                 song,
+	}
+}
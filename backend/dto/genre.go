@@ -0,0 +1,28 @@
+package dto
+
+type CreateGenreRequest struct {
+	Name     string `json:"name" binding:"required,min=3"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// UpdateGenreRequest renames and/or moves a genre.
+// A ParentID pointing to an empty string moves the genre to the top level.
+type UpdateGenreRequest struct {
+	Name     *string `json:"name,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+type MergeGenreRequest struct {
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+type GenreResponseItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+type GenreJobResponse struct {
+	Message      string `json:"message"`
+	SongsUpdated int    `json:"songs_updated"`
+}
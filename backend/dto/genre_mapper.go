@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+func ToGenreModel(req CreateGenreRequest) models.Genre {
+	name := strings.TrimSpace(req.Name)
+	return models.Genre{
+		Name:           name,
+		NameNormalized: utils.Normalize(name),
+		ParentID:       strings.TrimSpace(req.ParentID),
+	}
+}
+
+func ToGenreResponseItem(m models.Genre) GenreResponseItem {
+	return GenreResponseItem{
+		ID:       m.ID,
+		Name:     m.Name,
+		ParentID: m.ParentID,
+	}
+}
+
+func ToGenreResponseList(genres []models.Genre) []GenreResponseItem {
+	out := make([]GenreResponseItem, len(genres))
+	for i, g := range genres {
+		out[i] = ToGenreResponseItem(g)
+	}
+	return out
+}
+
+// ValidateCreateGenreRequest validates CreateGenreRequest DTO.
+func ValidateCreateGenreRequest(req CreateGenreRequest) error {
+	if len(strings.TrimSpace(req.Name)) < 3 {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
+
+// ValidateUpdateGenreRequest validates UpdateGenreRequest DTO.
+func ValidateUpdateGenreRequest(req UpdateGenreRequest) error {
+	if req.Name == nil && req.ParentID == nil {
+		return errors.ErrValidationFailed
+	}
+	if req.Name != nil && len(strings.TrimSpace(*req.Name)) < 3 {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
+
+// ValidateMergeGenreRequest validates MergeGenreRequest DTO.
+func ValidateMergeGenreRequest(req MergeGenreRequest) error {
+	if utils.IsEmptyString(req.TargetID) {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
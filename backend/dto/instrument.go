@@ -0,0 +1,19 @@
+package dto
+
+type InstrumentResponseItem struct {
+	ID      string            `json:"id"`
+	Names   map[string]string `json:"names"`
+	Aliases []string          `json:"aliases"`
+	Family  string            `json:"family"`
+}
+
+type InstrumentFamilyResponseItem struct {
+	ID          string            `json:"id"`
+	Names       map[string]string `json:"names"`
+	Instruments []string          `json:"instruments"`
+}
+
+type InstrumentCatalogResponse struct {
+	Instruments []InstrumentResponseItem       `json:"instruments"`
+	Families    []InstrumentFamilyResponseItem `json:"families"`
+}
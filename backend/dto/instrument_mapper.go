@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+func ToInstrumentResponseItem(m models.Instrument) InstrumentResponseItem {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return InstrumentResponseItem{
+		ID:      m.ID,
+		Names:   m.Names,
+		Aliases: aliases,
+		Family:  m.Family,
+	}
+}
+
+// ToInstrumentCatalogResponse groups the catalogue by family, listing the member IDs of each family.
+func ToInstrumentCatalogResponse(instruments []models.Instrument, families []models.InstrumentFamily) InstrumentCatalogResponse {
+	members := make(map[string][]string, len(families))
+	items := make([]InstrumentResponseItem, len(instruments))
+	for i, inst := range instruments {
+		items[i] = ToInstrumentResponseItem(inst)
+		members[inst.Family] = append(members[inst.Family], inst.ID)
+	}
+
+	familyItems := make([]InstrumentFamilyResponseItem, len(families))
+	for i, f := range families {
+		ids := members[f.ID]
+		if ids == nil {
+			ids = []string{}
+		}
+		familyItems[i] = InstrumentFamilyResponseItem{
+			ID:          f.ID,
+			Names:       f.Names,
+			Instruments: ids,
+		}
+	}
+
+	return InstrumentCatalogResponse{
+		Instruments: items,
+		Families:    familyItems,
+	}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"slices"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+)
+
+// Page sizes of GET /songs and GET /songs/:song_id/documents.
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Fields that songs and documents can be listed by.
+var (
+	SongSortFields     = []string{"title", "created_at", "updated_at"}
+	DocumentSortFields = []string{"type", "created_at", "updated_at"}
+)
+
+// ListQuery holds the paging query parameters of GET /songs and GET /songs/:song_id/documents.
+// Cursor is the next_token of the previous page. Without Sort, items come in storage order;
+// Order is "asc" (default) or "desc" and requires Sort.
+type ListQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	Order  string `form:"order"`
+}
+
+type SongListResponse struct {
+	Data      []SongResponseItem `json:"data"`
+	Count     int                `json:"count"`
+	NextToken string             `json:"next_token,omitempty"`
+}
+
+type DocumentListResponse struct {
+	Data      []DocumentResponseItem `json:"data"`
+	Count     int                    `json:"count"`
+	NextToken string                 `json:"next_token,omitempty"`
+}
+
+// ValidateListQuery checks the limit, the sort field against sortFields and the order.
+func ValidateListQuery(q ListQuery, sortFields []string) error {
+	if q.Limit < 0 || q.Limit > MaxListLimit {
+		return errors.ErrValidationFailed
+	}
+	if q.Sort != "" && !slices.Contains(sortFields, q.Sort) {
+		return errors.ErrValidationFailed
+	}
+	if q.Order != "" && (q.Sort == "" || (q.Order != "asc" && q.Order != "desc")) {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionETag returns the entity tag of a song or document at the given version.
+func VersionETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Precondition holds the entity tags of an If-Match header sent with a write.
+// The zero value, as well as "If-Match: *", matches any version.
+type Precondition struct {
+	ETags []string
+}
+
+// NewPrecondition parses the value of an If-Match header.
+func NewPrecondition(ifMatch string) Precondition {
+	var p Precondition
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return Precondition{}
+		}
+		if tag != "" {
+			p.ETags = append(p.ETags, tag)
+		}
+	}
+	return p
+}
+
+// Matches reports whether the precondition allows writing over the given version.
+// If-Match uses the strong comparison, so weak tags (W/"...") never match.
+func (p Precondition) Matches(version int) bool {
+	if len(p.ETags) == 0 {
+		return true
+	}
+	current := VersionETag(version)
+	for _, tag := range p.ETags {
+		if tag == current {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package dto
+
+// RevisionDiffQuery holds the query parameters of GET /songs/:song_id/revisions/diff.
+// To is optional; when absent the revision From is compared with the current version.
+type RevisionDiffQuery struct {
+	From int `form:"from"`
+	To   int `form:"to"`
+}
+
+type RevisionResponseItem struct {
+	SongID    string                `json:"song_id"`
+	Revision  int                   `json:"revision"`
+	Entity    string                `json:"entity"`
+	EntityID  string                `json:"entity_id"`
+	Song      *SongResponseItem     `json:"song,omitempty"`
+	Document  *DocumentResponseItem `json:"document,omitempty"`
+	Actor     string                `json:"actor"`
+	RequestID string                `json:"request_id,omitempty"`
+	CreatedAt string                `json:"created_at"`
+}
+
+// RevisionDiffResponse lists the fields that differ between two versions of a song or document.
+// To is omitted when the revision was compared with the current version.
+type RevisionDiffResponse struct {
+	SongID   string                    `json:"song_id"`
+	Entity   string                    `json:"entity"`
+	EntityID string                    `json:"entity_id"`
+	From     int                       `json:"from"`
+	To       int                       `json:"to,omitempty"`
+	Changes  []FieldChangeResponseItem `json:"changes"`
+}
+
+type RestoreRevisionResponse struct {
+	Message  string `json:"message"`
+	Revision int    `json:"revision"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+func ToRevisionResponseItem(m models.Revision) RevisionResponseItem {
+	item := RevisionResponseItem{
+		SongID:    m.SongID,
+		Revision:  m.Revision,
+		Entity:    m.Entity,
+		EntityID:  m.EntityID,
+		Actor:     m.Actor,
+		RequestID: m.RequestID,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.Song != nil {
+		song := ToSongResponseItem(*m.Song)
+		item.Song = &song
+	}
+	if m.Document != nil {
+		doc := ToDocumentResponseItem(*m.Document)
+		item.Document = &doc
+	}
+	return item
+}
+
+func ToRevisionResponseList(revisions []models.Revision) []RevisionResponseItem {
+	out := make([]RevisionResponseItem, len(revisions))
+	for i, r := range revisions {
+		out[i] = ToRevisionResponseItem(r)
+	}
+	return out
+}
+
+func ToFieldChangeResponseList(changes []models.FieldChange) []FieldChangeResponseItem {
+	out := make([]FieldChangeResponseItem, len(changes))
+	for i, c := range changes {
+		out[i] = FieldChangeResponseItem{Field: c.Field, Before: c.Before, After: c.After}
+	}
+	return out
+}
+
+// ValidateRevisionDiffQuery checks that From names a revision and To, if present, a different one.
+func ValidateRevisionDiffQuery(q RevisionDiffQuery) error {
+	if q.From < 1 || q.To < 0 || q.To == q.From {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
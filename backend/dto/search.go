@@ -0,0 +1,58 @@
+package dto
+
+// Result types returned by the unified search endpoint.
+const (
+	SearchResultTypeSong     = "song"
+	SearchResultTypeDocument = "document"
+)
+
+// Fields that can carry highlight spans in search responses. They name a field of the song or document
+// the highlights belong to.
+const (
+	HighlightFieldTitle  = "title"
+	HighlightFieldAuthor = "author"
+)
+
+// Highlight marks a matched substring of a response field.
+// Start and End are rune (Unicode code point) offsets into the original, non-normalized
+// field value; Start is inclusive and End is exclusive.
+type Highlight struct {
+	Field string `json:"field"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type SongSummary struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+type DocumentSearchHit struct {
+	DocumentResponseItem
+	Song SongSummary `json:"song"`
+}
+
+type SearchResultItem struct {
+	Type       string             `json:"type"`
+	Score      int                `json:"score"`
+	Song       *SongResponseItem  `json:"song,omitempty"`
+	Document   *DocumentSearchHit `json:"document,omitempty"`
+	Highlights []Highlight        `json:"highlights"`
+}
+
+// SearchCounts holds the number of hits of each type in the segment of the results a page was ranked in.
+type SearchCounts struct {
+	Songs     int `json:"songs"`
+	Documents int `json:"documents"`
+}
+
+// SearchResponse is a page of the unified search. Hits are ranked and counted within a segment of the
+// catalogue; Complete tells whether that segment is the whole catalogue, in which case SegmentCounts are
+// the totals of the search and the ranking is global.
+type SearchResponse struct {
+	Data          []SearchResultItem `json:"data"`
+	SegmentCounts SearchCounts       `json:"segment_counts"`
+	Complete      bool               `json:"complete"`
+	NextToken     string             `json:"next_token,omitempty"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+// ToHighlights returns the spans of text matched by query (ignoring case and accents) for the given field.
+func ToHighlights(field, text, query string) []Highlight {
+	out := []Highlight{}
+	for _, span := range utils.FindMatchSpans(text, query) {
+		out = append(out, Highlight{Field: field, Start: span.Start, End: span.End})
+	}
+	return out
+}
+
+// ToSongHighlights returns the highlight spans of a song's title and author for the given query.
+func ToSongHighlights(m models.Song, query string) []Highlight {
+	return append(
+		ToHighlights(HighlightFieldTitle, m.Title, query),
+		ToHighlights(HighlightFieldAuthor, m.Author, query)...,
+	)
+}
+
+// ToDocumentHighlights returns the highlight spans of a document's title for the given query.
+// The spans refer to the title the document holds, which may differ from its song's while a rename is
+// being copied to the documents.
+func ToDocumentHighlights(m models.Document, query string) []Highlight {
+	return ToHighlights(HighlightFieldTitle, m.Title, query)
+}
+
+// ToDocumentHighlightsByID returns the highlight spans of each document keyed by document ID.
+func ToDocumentHighlightsByID(documents []models.Document, query string) map[string][]Highlight {
+	out := make(map[string][]Highlight, len(documents))
+	for _, d := range documents {
+		out[d.ID] = ToDocumentHighlights(d, query)
+	}
+	return out
+}
+
+// ToSongHighlightsByID returns the highlight spans of each song keyed by song ID.
+func ToSongHighlightsByID(songs []models.Song, query string) map[string][]Highlight {
+	out := make(map[string][]Highlight, len(songs))
+	for _, s := range songs {
+		out[s.ID] = ToSongHighlights(s, query)
+	}
+	return out
+}
@@ -19,8 +19,10 @@ type CreateSongResponse struct {
 }
 
 type SongResponseItem struct {
-	ID     string   `json:"id"`
-	Title  string   `json:"title"`
-	Author string   `json:"author"`
-	Genres []string `json:"genres"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Author    string   `json:"author"`
+	Genres    []string `json:"genres"`
+	UpdatedAt string   `json:"updated_at"`
+	Version   int      `json:"version"`
 }
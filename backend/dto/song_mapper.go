@@ -30,22 +30,19 @@ func ToSongAndDocuments(req CreateSongRequest) (models.Song, []models.Document)
 
 func ToSongResponseItem(m models.Song) SongResponseItem {
 	return SongResponseItem{
-		ID:     m.ID,
-		Title:  m.Title,
-		Author: m.Author,
-		Genres: m.Genres,
+		ID:        m.ID,
+		Title:     m.Title,
+		Author:    m.Author,
+		Genres:    m.Genres,
+		UpdatedAt: m.UpdatedAt,
+		Version:   m.Version,
 	}
 }
 
 func ToSongResponseList(songs []models.Song) []SongResponseItem {
 	out := make([]SongResponseItem, len(songs))
 	for i, s := range songs {
-		out[i] = SongResponseItem{
-			ID:     s.ID,
-			Title:  s.Title,
-			Author: s.Author,
-			Genres: s.Genres,
-		}
+		out[i] = ToSongResponseItem(s)
 	}
 	return out
 }
@@ -94,3 +91,11 @@ func ValidateUpdateSongRequest(update UpdateSongRequest) error {
 	}
 	return nil
 }
+
+func ToSongSummary(m models.Song) SongSummary {
+	return SongSummary{
+		ID:     m.ID,
+		Title:  m.Title,
+		Author: m.Author,
+	}
+}
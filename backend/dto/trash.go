@@ -0,0 +1,43 @@
+package dto
+
+// TrashedSongItem is a song in the trash. Documents counts the documents trashed with it,
+// which are restored or purged together with the song.
+type TrashedSongItem struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Author    string   `json:"author"`
+	Genres    []string `json:"genres"`
+	Documents int      `json:"documents"`
+	DeletedAt string   `json:"deleted_at"`
+	DeletedBy string   `json:"deleted_by"`
+	PurgeAt   string   `json:"purge_at"`
+}
+
+// TrashedDocumentItem is a document moved to the trash on its own.
+type TrashedDocumentItem struct {
+	ID         string   `json:"id"`
+	SongID     string   `json:"song_id"`
+	Type       string   `json:"type"`
+	Instrument []string `json:"instrument"`
+	PDFURL     string   `json:"pdf_url"`
+	DeletedAt  string   `json:"deleted_at"`
+	DeletedBy  string   `json:"deleted_by"`
+	PurgeAt    string   `json:"purge_at"`
+}
+
+type TrashResponse struct {
+	Songs     []TrashedSongItem     `json:"songs"`
+	Documents []TrashedDocumentItem `json:"documents"`
+}
+
+type PurgeTrashResponse struct {
+	Message   string `json:"message"`
+	Songs     int    `json:"songs"`
+	Documents int    `json:"documents"`
+}
+
+type ReconcileSongsResponse struct {
+	Message    string `json:"message"`
+	Completed  int    `json:"completed"`
+	RolledBack int    `json:"rolled_back"`
+}
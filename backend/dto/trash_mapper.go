@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+func ToTrashedSongItem(m models.Song, documents int, purgeAt string) TrashedSongItem {
+	return TrashedSongItem{
+		ID:        m.ID,
+		Title:     m.Title,
+		Author:    m.Author,
+		Genres:    m.Genres,
+		Documents: documents,
+		DeletedAt: m.DeletedAt,
+		DeletedBy: m.DeletedBy,
+		PurgeAt:   purgeAt,
+	}
+}
+
+func ToTrashedDocumentItem(m models.Document, purgeAt string) TrashedDocumentItem {
+	return TrashedDocumentItem{
+		ID:         m.ID,
+		SongID:     m.SongID,
+		Type:       m.Type,
+		Instrument: m.Instrument,
+		PDFURL:     m.PDFURL,
+		DeletedAt:  m.DeletedAt,
+		DeletedBy:  m.DeletedBy,
+		PurgeAt:    purgeAt,
+	}
+}
@@ -0,0 +1,31 @@
+package dto
+
+type InviteUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+type AcceptInviteRequest struct {
+	Username string `json:"username" binding:"required"`
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UserResponseItem struct {
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	TOTPEnabled bool   `json:"totp_enabled"`
+	InvitedBy   string `json:"invited_by,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// InviteUserResponse is returned once when an invitation is created.
+// The token is not stored in clear and cannot be retrieved again.
+type InviteUserResponse struct {
+	Message     string `json:"message"`
+	Username    string `json:"username"`
+	InviteToken string `json:"invite_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// usernamePattern restricts usernames to lowercase letters, digits, dots, dashes and underscores.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,32}$`)
+
+// MinPasswordLength is the minimum number of characters accepted for a user password.
+const MinPasswordLength = 8
+
+// Strength policy applied when a password is changed or reset.
+const (
+	// MinPassphraseLength is the length from which a password is accepted without mixing character classes.
+	MinPassphraseLength = 16
+	// MaxPasswordBytes is the longest password bcrypt takes into account.
+	MaxPasswordBytes = 72
+)
+
+// commonPasswords are refused regardless of their length and character classes.
+var commonPasswords = map[string]bool{
+	"password1!":   true,
+	"password123":  true,
+	"p@ssw0rd":     true,
+	"p@ssword1":    true,
+	"qwerty123!":   true,
+	"welcome1!":    true,
+	"admin123!":    true,
+	"rendalla1!":   true,
+	"changeme123":  true,
+	"letmein123!":  true,
+	"iloveyou1!":   true,
+	"passw0rd!":    true,
+	"123456789abc": true,
+}
+
+// NormalizeUsername trims and lowercases a username so lookups are case-insensitive.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func ToUserResponseItem(m models.User) UserResponseItem {
+	return UserResponseItem{
+		Username:    m.Username,
+		Role:        m.Role,
+		Status:      m.Status,
+		TOTPEnabled: m.TOTPEnabled,
+		InvitedBy:   m.InvitedBy,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func ToUserResponseList(users []models.User) []UserResponseItem {
+	out := make([]UserResponseItem, len(users))
+	for i, u := range users {
+		out[i] = ToUserResponseItem(u)
+	}
+	return out
+}
+
+// ValidateInviteUserRequest validates InviteUserRequest DTO.
+func ValidateInviteUserRequest(req InviteUserRequest) error {
+	if !usernamePattern.MatchString(NormalizeUsername(req.Username)) {
+		return errors.ErrValidationFailed
+	}
+	if !models.IsValidRole(req.Role) {
+		return errors.ErrValidationFailed
+	}
+	return nil
+}
+
+// ValidatePasswordStrength checks a new password against the strength policy: at least
+// MinPasswordLength characters mixing three of lowercase, uppercase, digits and symbols, or a
+// passphrase of MinPassphraseLength characters; at most MaxPasswordBytes bytes; not containing
+// the username and not a common password.
+func ValidatePasswordStrength(username, password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < MinPasswordLength {
+		return fmt.Errorf("password must have at least %d characters: %w", MinPasswordLength, errors.ErrValidationFailed)
+	}
+	if len(password) > MaxPasswordBytes {
+		return fmt.Errorf("password must have at most %d bytes: %w", MaxPasswordBytes, errors.ErrValidationFailed)
+	}
+
+	lowered := strings.ToLower(password)
+	if name := NormalizeUsername(username); name != "" && strings.Contains(lowered, name) {
+		return fmt.Errorf("password must not contain the username: %w", errors.ErrValidationFailed)
+	}
+	if commonPasswords[lowered] {
+		return fmt.Errorf("password is too common: %w", errors.ErrValidationFailed)
+	}
+
+	if length >= MinPassphraseLength {
+		return nil
+	}
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	if lower+upper+digit+symbol < 3 {
+		return fmt.Errorf("password must mix three of lowercase, uppercase, digits and symbols, or have at least %d characters: %w", MinPassphraseLength, errors.ErrValidationFailed)
+	}
+	return nil
+}
+
+// ValidateAcceptInviteRequest validates AcceptInviteRequest DTO.
+// The chosen password must satisfy ValidatePasswordStrength.
+func ValidateAcceptInviteRequest(req AcceptInviteRequest) error {
+	if strings.TrimSpace(req.Username) == "" || strings.TrimSpace(req.Token) == "" {
+		return errors.ErrValidationFailed
+	}
+	return ValidatePasswordStrength(req.Username, req.Password)
+}
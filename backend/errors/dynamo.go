@@ -33,6 +33,11 @@ func HandleDynamoError(err error) error {
 		return ErrTooManyResults
 	}
 
+	var canceled *dynamodb.TransactionCanceledException
+	if stdErrors.As(err, &canceled) {
+		return handleTransactionCanceled(canceled)
+	}
+
 	if awsErr, ok := err.(awserr.Error); ok {
 		switch awsErr.Code() {
 		case dynamodb.ErrCodeConditionalCheckFailedException:
@@ -52,7 +57,7 @@ func HandleDynamoError(err error) error {
 		case AWSItemCollectionSizeLimitExceeded:
 			return ErrValidationFailed
 		case AWSTransactionConflictException:
-			return ErrOperationNotAllowed
+			return ErrConflict
 		default:
 			return ErrInternalServer
 		}
@@ -60,3 +65,36 @@ func HandleDynamoError(err error) error {
 
 	return ErrInternalServer
 }
+
+// ConditionFailedAt reports whether err is a canceled transaction whose item at index failed its
+// condition expression. Repositories use it to tell a stale version apart from other conflicts.
+func ConditionFailedAt(err error, index int) bool {
+	var canceled *dynamodb.TransactionCanceledException
+	if !stdErrors.As(err, &canceled) || index >= len(canceled.CancellationReasons) {
+		return false
+	}
+	reason := canceled.CancellationReasons[index]
+	return reason != nil && reason.Code != nil && *reason.Code == "ConditionalCheckFailed"
+}
+
+// handleTransactionCanceled maps a canceled transaction to the error of its first failed item.
+// A failed condition means the items are not in a state that allows the write; a conflicting
+// transaction means another request was writing the same items, and the write may be retried.
+func handleTransactionCanceled(err *dynamodb.TransactionCanceledException) error {
+	for _, reason := range err.CancellationReasons {
+		if reason == nil || reason.Code == nil {
+			continue
+		}
+		switch *reason.Code {
+		case "ConditionalCheckFailed":
+			return ErrOperationNotAllowed
+		case "TransactionConflict":
+			return ErrConflict
+		case "ProvisionedThroughputExceeded", "ThrottlingError":
+			return ErrThroughputExceeded
+		case "ValidationError":
+			return ErrBadRequest
+		}
+	}
+	return ErrInternalServer
+}
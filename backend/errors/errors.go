@@ -17,6 +17,11 @@ var (
 	ErrResourceNotFound    = errors.New("resource not found")
 	ErrOperationNotAllowed = errors.New("operation not allowed")
 
+	// Concurrency
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+	ErrConflict             = errors.New("conflict")
+
 	// System
 	ErrThroughputExceeded = errors.New("throughput limit exceeded")
 	ErrInternalServer     = errors.New("internal server error")
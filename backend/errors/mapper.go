@@ -14,6 +14,12 @@ func MapErrorToStatus(err error) int {
 		return http.StatusNotFound
 	case errors.Is(err, ErrOperationNotAllowed):
 		return http.StatusForbidden
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
 	case errors.Is(err, ErrThroughputExceeded):
 		return http.StatusTooManyRequests
 	case errors.Is(err, ErrInvalidCredentials), errors.Is(err, ErrUnauthorized):
@@ -35,6 +41,12 @@ func MapErrorToMessage(err error) string {
 		return "The requested resource was not found"
 	case errors.Is(err, ErrOperationNotAllowed):
 		return "You are not allowed to perform this operation"
+	case errors.Is(err, ErrPreconditionFailed):
+		return "The resource was modified since you last retrieved it"
+	case errors.Is(err, ErrPreconditionRequired):
+		return "This request must include an If-Match header"
+	case errors.Is(err, ErrConflict):
+		return "The request conflicts with a previous request"
 	case errors.Is(err, ErrThroughputExceeded):
 		return "Too many requests, please try again later"
 	case errors.Is(err, ErrInvalidCredentials):
@@ -62,6 +74,12 @@ func MapErrorToCode(err error) string {
 		return "resource_not_found"
 	case errors.Is(err, ErrOperationNotAllowed):
 		return "operation_not_allowed"
+	case errors.Is(err, ErrPreconditionFailed):
+		return "precondition_failed"
+	case errors.Is(err, ErrPreconditionRequired):
+		return "precondition_required"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
 	case errors.Is(err, ErrThroughputExceeded):
 		return "throughput_exceeded"
 	case errors.Is(err, ErrInvalidCredentials):
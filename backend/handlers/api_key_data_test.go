@@ -0,0 +1,21 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var APIKeyList = []dto.APIKeyResponseItem{
+	{ID: "key-2", Name: "reader", Scopes: []string{"songs:read"}, Status: "active", CreatedBy: "admin"},
+	{ID: "key-1", Name: "nightly import", Scopes: []string{"songs:write"}, Status: "revoked", CreatedBy: "admin", LastUsedAt: "2025-01-01T00:00:00Z"},
+}
+
+var CreateAPIKeyJSON = `{"name": "nightly import", "scopes": ["songs:write"], "expires_in_days": 30}`
+var CreateAPIKeyMissingScopesJSON = `{"name": "nightly import"}`
+
+var CreateAPIKeyRequest = dto.CreateAPIKeyRequest{Name: "nightly import", Scopes: []string{"songs:write"}, ExpiresInDays: 30}
+
+var CreatedAPIKey = dto.CreateAPIKeyResponse{
+	Message:   "API key created successfully",
+	ID:        "key-1",
+	Key:       "rk_key-1.secret",
+	Scopes:    []string{"songs:write"},
+	ExpiresAt: 2593000,
+}
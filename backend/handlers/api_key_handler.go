@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// APIKeyHandler handles HTTP requests related to API keys for automation clients.
+// It delegates the business logic to the APIKeyServiceInterface.
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyServiceInterface
+}
+
+// NewAPIKeyHandler returns a new instance of APIKeyHandler.
+func NewAPIKeyHandler(apiKeyService services.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// ListAPIKeysHandler handles GET /admin/api-keys.
+// Returns every key with its scopes, status and last use, never the key itself.
+func (h *APIKeyHandler) ListAPIKeysHandler(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys()
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve API keys")
+		return
+	}
+
+	logrus.WithField("api_keys", len(keys)).Debug("Fetched all API keys successfully")
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// CreateAPIKeyHandler handles POST /admin/api-keys.
+// Creates a key and returns it in clear; it cannot be retrieved again.
+func (h *APIKeyHandler) CreateAPIKeyHandler(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.GetString("username"), req)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to create API key")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"api_key_id": key.ID, "scopes": key.Scopes}).Info("API key created successfully")
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKeyHandler handles DELETE /admin/api-keys/:key_id.
+func (h *APIKeyHandler) RevokeAPIKeyHandler(c *gin.Context) {
+	keyID, ok := utils.RequireParam(c, "key_id")
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.GetString("username"), keyID); err != nil {
+		errors.HandleAPIError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	logrus.WithField("api_key_id", keyID).Info("API key revoked successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
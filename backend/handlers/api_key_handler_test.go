@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAPIKeyHandlerTest() (*handlers.APIKeyHandler, *mocks.MockAPIKeyService) {
+	mockService := new(mocks.MockAPIKeyService)
+	handler := handlers.NewAPIKeyHandler(mockService)
+	return handler, mockService
+}
+
+func TestListAPIKeysHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockKeys     []dto.APIKeyResponseItem
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "returns keys", mockKeys: APIKeyList, expectedCode: http.StatusOK},
+		{name: "service error", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAPIKeyHandlerTest()
+			mockService.On("ListAPIKeys").Return(tt.mockKeys, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/admin/api-keys", nil)
+			handler.ListAPIKeysHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data []dto.APIKeyResponseItem `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockKeys, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateAPIKeyHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", input: CreateAPIKeyJSON, setupMock: true, expectedCode: http.StatusCreated},
+		{name: "missing scopes", input: CreateAPIKeyMissingScopesJSON, expectedCode: http.StatusBadRequest},
+		{name: "invalid scope", input: CreateAPIKeyJSON, setupMock: true, mockErr: errors.ErrValidationFailed, expectedCode: http.StatusBadRequest},
+		{name: "service error", input: CreateAPIKeyJSON, setupMock: true, mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAPIKeyHandlerTest()
+			if tt.setupMock {
+				if tt.mockErr != nil {
+					mockService.On("CreateAPIKey", "admin", CreateAPIKeyRequest).Return(nil, tt.mockErr)
+				} else {
+					mockService.On("CreateAPIKey", "admin", CreateAPIKeyRequest).Return(&CreatedAPIKey, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/admin/api-keys", strings.NewReader(tt.input))
+			c.Set("username", "admin")
+			handler.CreateAPIKeyHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusCreated {
+				response, err := DecodeJSONResponse[dto.CreateAPIKeyResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, CreatedAPIKey, response)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeAPIKeyHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		keyID        string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", keyID: "key-1", setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing key ID", keyID: "", expectedCode: http.StatusBadRequest},
+		{name: "not found", keyID: "key-9", setupMock: true, mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAPIKeyHandlerTest()
+			if tt.setupMock {
+				mockService.On("RevokeAPIKey", "admin", tt.keyID).Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodDelete, "/admin/api-keys/"+tt.keyID, nil)
+			c.Params = gin.Params{{Key: "key_id", Value: tt.keyID}}
+			c.Set("username", "admin")
+			handler.RevokeAPIKeyHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers_test
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+var AuditEntryList = []dto.AuditEntryResponseItem{
+	{
+		ID:        "audit-2",
+		Entity:    models.AuditEntitySong,
+		EntityID:  "song-1",
+		SongID:    "song-1",
+		Action:    models.AuditActionUpdate,
+		Actor:     "maria",
+		RequestID: "req-2",
+		Timestamp: "2025-01-02T10:00:00Z",
+		Changes:   []dto.FieldChangeResponseItem{{Field: "author", Before: "Queen", After: "Freddie Mercury"}},
+	},
+	{
+		ID:        "audit-1",
+		Entity:    models.AuditEntitySong,
+		EntityID:  "song-1",
+		SongID:    "song-1",
+		Action:    models.AuditActionCreate,
+		Actor:     "maria",
+		RequestID: "req-1",
+		Timestamp: "2025-01-01T10:00:00Z",
+		Changes:   []dto.FieldChangeResponseItem{{Field: "author", After: "Queen"}},
+	},
+}
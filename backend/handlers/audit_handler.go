@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AuditHandler handles HTTP requests related to the audit trail of catalogue changes.
+// It delegates the business logic to the AuditServiceInterface.
+type AuditHandler struct {
+	auditService services.AuditServiceInterface
+}
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler(auditService services.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditEntriesHandler handles GET /admin/audit.
+// Returns one page of the audit entries, newest first, optionally filtered by the "entity", "actor" and
+// "since" (RFC3339) query parameters. The page is selected with "limit" and "cursor", and the response
+// carries the next_token of the following page.
+func (h *AuditHandler) ListAuditEntriesHandler(c *gin.Context) {
+	var query dto.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.auditService.ListAuditEntries(query)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve audit entries")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"audit_entries":  page.Count,
+		"has_next_token": page.NextToken != "",
+	}).Debug("Fetched audit entries successfully")
+	c.JSON(http.StatusOK, page)
+}
+
+// actorFromContext returns who is making the request, as set by JWTAuthMiddleware and RequestIDMiddleware.
+func actorFromContext(c *gin.Context) models.Actor {
+	return models.Actor{
+		Username:  c.GetString("username"),
+		RequestID: c.GetString("request_id"),
+	}
+}
@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAuditHandlerTest() (*handlers.AuditHandler, *mocks.MockAuditService) {
+	mockService := new(mocks.MockAuditService)
+	handler := handlers.NewAuditHandler(mockService)
+	return handler, mockService
+}
+
+func TestListAuditEntriesHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		expectQuery  dto.AuditQuery
+		mockEntries  []dto.AuditEntryResponseItem
+		mockNext     string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "returns entries", url: "/admin/audit", mockEntries: AuditEntryList, expectedCode: http.StatusOK},
+		{
+			name:         "passes filters",
+			url:          "/admin/audit?entity=song&actor=maria&since=2025-01-01T00:00:00Z",
+			expectQuery:  dto.AuditQuery{Entity: "song", Actor: "maria", Since: "2025-01-01T00:00:00Z"},
+			mockEntries:  AuditEntryList[:1],
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "passes paging",
+			url:          "/admin/audit?limit=1&cursor=abc",
+			expectQuery:  dto.AuditQuery{Limit: 1, Cursor: "abc"},
+			mockEntries:  AuditEntryList[:1],
+			mockNext:     "def",
+			expectedCode: http.StatusOK,
+		},
+		{name: "invalid filter", url: "/admin/audit?entity=genre", expectQuery: dto.AuditQuery{Entity: "genre"}, mockErr: errors.ErrValidationFailed, expectedCode: http.StatusBadRequest},
+		{name: "service error", url: "/admin/audit", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuditHandlerTest()
+			page := dto.AuditListResponse{Data: tt.mockEntries, Count: len(tt.mockEntries), NextToken: tt.mockNext}
+			mockService.On("ListAuditEntries", tt.expectQuery).Return(page, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, tt.url, nil)
+			handler.ListAuditEntriesHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.AuditListResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.mockEntries), len(response.Data))
+				assert.Equal(t, tt.mockEntries[0].ID, response.Data[0].ID)
+				assert.Equal(t, tt.mockNext, response.NextToken)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCatalogHandlers_PassActorFromContext(t *testing.T) {
+	actor := models.Actor{Username: "maria", RequestID: "req-1"}
+
+	songService := new(mocks.MockSongService)
+	songService.On("DeleteSongWithDocuments", actor, "song-1", dto.Precondition{}).Return(nil)
+	documentService := new(mocks.MockDocumentService)
+	documentService.On("DeleteDocument", actor, "song-1", "doc-1", dto.Precondition{}).Return(nil)
+
+	c, w := utils.CreateTestContext(http.MethodDelete, "/songs/song-1", nil)
+	c.Params = gin.Params{{Key: "song_id", Value: "song-1"}}
+	c.Set("username", "maria")
+	c.Set("request_id", "req-1")
+	handlers.NewSongHandler(songService).DeleteSongWithDocumentsHandler(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	c, w = utils.CreateTestContext(http.MethodDelete, "/songs/song-1/documents/doc-1", nil)
+	c.Params = gin.Params{{Key: "song_id", Value: "song-1"}, {Key: "doc_id", Value: "doc-1"}}
+	c.Set("username", "maria")
+	c.Set("request_id", "req-1")
+	handlers.NewDocumentHandler(documentService).DeleteDocumentHandler(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	songService.AssertExpectations(t)
+	documentService.AssertExpectations(t)
+}
@@ -1,5 +1,7 @@
 package handlers_test
 
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
 const ValidLoginJSON = `
 {
 	"username": "admin",
@@ -15,3 +17,32 @@ const MissingPasswordJSON = `
 {
 	"username": "admin"
 }`
+
+const RefreshTokenValue = "sess-1.secret"
+
+const ValidRefreshJSON = `{"refresh_token": "sess-1.secret"}`
+
+// TestClientIP is the client IP gin reports for requests built with httptest.NewRequest.
+const TestClientIP = "192.0.2.1"
+
+const MFATokenValue = "sess-2.challenge"
+
+const ValidSecondFactorJSON = `{"mfa_token": "sess-2.challenge", "code": "287082"}`
+
+const MissingCodeJSON = `{"mfa_token": "sess-2.challenge"}`
+
+const ValidMFATokenJSON = `{"mfa_token": "sess-2.challenge"}`
+
+const ValidTOTPCodeJSON = `{"code": "287082"}`
+
+var TOTPEnrollment = dto.TOTPEnrollmentResponse{
+	Secret:        "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+	OTPAuthURI:    "otpauth://totp/Rendalla:ana?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+	RecoveryCodes: []string{"abcd-efgh", "ijkl-mnop"},
+}
+
+const ChangePasswordJSON = `{"current_password": "secret", "new_password": "Tr0mbone-Solo"}`
+
+const ResetPasswordJSON = `{"username": "maria", "token": "reset-token", "new_password": "Tr0mbone-Solo"}`
+
+var ResetPasswordRequest = dto.ResetPasswordRequest{Username: "maria", Token: "reset-token", NewPassword: "Tr0mbone-Solo"}
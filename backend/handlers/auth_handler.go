@@ -12,7 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// AuthHandler handles HTTP requests related to admin authentication.
+// AuthHandler handles HTTP requests related to user authentication.
 // It delegates logic to the AuthServiceInterface.
 type AuthHandler struct {
 	authService services.AuthServiceInterface
@@ -24,7 +24,9 @@ func NewAuthHandler(authService services.AuthServiceInterface) *AuthHandler {
 }
 
 // LoginHandler handles POST /auth/login.
-// Validates credentials and returns a signed JWT token upon successful authentication.
+// Validates credentials and returns an access token and a refresh token upon successful authentication.
+// Accounts that need a second factor receive an MFA token for POST /auth/login/2fa instead.
+// Repeated failures for the same username or from the same client IP are answered with 429.
 func (h *AuthHandler) LoginHandler(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -32,12 +34,14 @@ func (h *AuthHandler) LoginHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.AuthenticateUser(req.Username, req.Password)
+	tokens, err := h.authService.AuthenticateUser(req.Username, req.Password, c.ClientIP())
 	if err != nil {
 		message := "Authentication failed"
 		switch {
 		case stdErrors.Is(err, errors.ErrInvalidCredentials):
 			message = "Invalid credentials"
+		case stdErrors.Is(err, errors.ErrThroughputExceeded):
+			message = "Too many failed login attempts, try again later"
 		case stdErrors.Is(err, errors.ErrTokenGenerationFailed):
 			message = "Failed to generate token"
 		case stdErrors.Is(err, errors.ErrInternalServer):
@@ -47,13 +51,335 @@ func (h *AuthHandler) LoginHandler(c *gin.Context) {
 		return
 	}
 
-	logrus.WithField("username", req.Username).Info("User authenticated successfully")
+	if tokens.MFARequired {
+		logrus.WithField("username", req.Username).Info("Password accepted, second factor required")
+	} else {
+		logrus.WithField("username", req.Username).Info("User authenticated successfully")
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LoginSecondFactorHandler handles POST /auth/login/2fa.
+// Completes a login with the MFA token and a TOTP code or a recovery code.
+func (h *AuthHandler) LoginSecondFactorHandler(c *gin.Context) {
+	var req dto.SecondFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	tokens, err := h.authService.CompleteSecondFactor(req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		message := "Authentication failed"
+		switch {
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Invalid or expired code"
+		case stdErrors.Is(err, errors.ErrBadRequest):
+			message = "A second factor must be enrolled first"
+		case stdErrors.Is(err, errors.ErrThroughputExceeded):
+			message = "Too many failed login attempts, try again later"
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "Second factors are not enabled"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LoginEnrollTOTPHandler handles POST /auth/login/2fa/enroll.
+// Starts the TOTP enrolment that the role of the account requires, during a login.
+// The first code of the new factor is then sent to POST /auth/login/2fa.
+func (h *AuthHandler) LoginEnrollTOTPHandler(c *gin.Context) {
+	var req dto.MFATokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	enrollment, err := h.authService.BeginTOTPEnrollmentForLogin(req.MFAToken)
+	if err != nil {
+		h.handleTOTPEnrollmentError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// EnrollTOTPHandler handles POST /auth/2fa/enroll.
+// Starts a TOTP enrolment for the authenticated user; it takes effect once confirmed.
+func (h *AuthHandler) EnrollTOTPHandler(c *gin.Context) {
+	enrollment, err := h.authService.BeginTOTPEnrollment(c.GetString("username"))
+	if err != nil {
+		h.handleTOTPEnrollmentError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *AuthHandler) handleTOTPEnrollmentError(c *gin.Context, err error) {
+	message := "Failed to start second factor enrolment"
+	switch {
+	case stdErrors.Is(err, errors.ErrInvalidCredentials):
+		message = "Invalid or expired MFA token"
+	case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+		message = "Second factor enrolment not allowed for this account"
+	case stdErrors.Is(err, errors.ErrResourceNotFound):
+		message = "Second factors are not enabled"
+	}
+	errors.HandleAPIError(c, err, message)
+}
+
+// ConfirmTOTPHandler handles POST /auth/2fa/confirm.
+// Activates the pending TOTP enrolment of the authenticated user with a first code.
+func (h *AuthHandler) ConfirmTOTPHandler(c *gin.Context) {
+	var req dto.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	if err := h.authService.ConfirmTOTPEnrollment(c.GetString("username"), req.Code); err != nil {
+		message := "Failed to confirm second factor"
+		switch {
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Invalid code"
+		case stdErrors.Is(err, errors.ErrBadRequest):
+			message = "No second factor enrolment pending"
+		case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+			message = "Second factor enrolment not allowed for this account"
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "Second factors are not enabled"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Second factor enabled"})
+}
+
+// DisableTOTPHandler handles POST /auth/2fa/disable.
+// Removes the second factor of the authenticated user after checking a current code.
+func (h *AuthHandler) DisableTOTPHandler(c *gin.Context) {
+	var req dto.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	if err := h.authService.DisableTOTP(c.GetString("username"), req.Code); err != nil {
+		message := "Failed to disable second factor"
+		switch {
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Invalid code"
+		case stdErrors.Is(err, errors.ErrBadRequest):
+			message = "No second factor enabled"
+		case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+			message = "Your role requires a second factor"
+		case stdErrors.Is(err, errors.ErrThroughputExceeded):
+			message = "Too many failed attempts, try again later"
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "Second factors are not enabled"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Second factor disabled"})
+}
+
+// ResetTOTPHandler handles DELETE /admin/users/:username/2fa.
+// Removes the second factor of a user who lost access to it.
+func (h *AuthHandler) ResetTOTPHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	if err := h.authService.ResetTOTP(c.GetString("username"), username); err != nil {
+		message := "Failed to reset second factor"
+		switch {
+		case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+			message = "User not found"
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "Second factors are not enabled"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Second factor reset successfully"})
+}
+
+// RefreshHandler handles POST /auth/refresh.
+// Exchanges a refresh token for a new access token and a new refresh token.
+func (h *AuthHandler) RefreshHandler(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	tokens, err := h.authService.RefreshSession(req.RefreshToken)
+	if err != nil {
+		message := "Failed to refresh session"
+		if stdErrors.Is(err, errors.ErrInvalidCredentials) {
+			message = "Invalid or expired refresh token"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LogoutHandler handles POST /auth/logout.
+// Revokes the session of the access token used for the request.
+func (h *AuthHandler) LogoutHandler(c *gin.Context) {
+	if err := h.authService.Logout(c.GetString("session_id")); err != nil {
+		errors.HandleAPIError(c, err, "Failed to log out")
+		return
+	}
+
+	logrus.WithField("username", c.GetString("username")).Info("User logged out successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutAllHandler handles POST /auth/logout-all.
+// Revokes every session of the authenticated user.
+func (h *AuthHandler) LogoutAllHandler(c *gin.Context) {
+	username := c.GetString("username")
+	if utils.IsEmptyString(username) {
+		errors.HandleAPIError(c, errors.ErrUnauthorized, "Unauthorized")
+		return
+	}
+
+	revoked, err := h.authService.LogoutAll(username)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to log out all sessions")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"username": username, "sessions": revoked}).Info("All sessions logged out successfully")
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Logged out of all sessions",
+		"sessions_revoked": revoked,
+	})
+}
+
+// ChangePasswordHandler handles POST /auth/password.
+// Changes the password of the authenticated user and revokes all of their sessions,
+// so the client must log in again.
+func (h *AuthHandler) ChangePasswordHandler(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	username := c.GetString("username")
+	if utils.IsEmptyString(username) {
+		errors.HandleAPIError(c, errors.ErrUnauthorized, "Unauthorized")
+		return
+	}
+
+	revoked, err := h.authService.ChangePassword(username, req.CurrentPassword, req.NewPassword, c.ClientIP())
+	if err != nil {
+		message := "Failed to change password"
+		switch {
+		case stdErrors.Is(err, errors.ErrValidationFailed):
+			message = "New password does not meet the password policy"
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Current password is incorrect"
+		case stdErrors.Is(err, errors.ErrThroughputExceeded):
+			message = "Too many failed attempts, try again later"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Password changed, please log in again",
+		"sessions_revoked": revoked,
+	})
+}
+
+// ResetPasswordHandler handles POST /auth/password/reset.
+// Sets a new password with a reset token issued by an admin.
+func (h *AuthHandler) ResetPasswordHandler(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid request data")
+		return
+	}
+
+	revoked, err := h.authService.ResetPassword(req)
+	if err != nil {
+		message := "Failed to reset password"
+		switch {
+		case stdErrors.Is(err, errors.ErrValidationFailed):
+			message = "New password does not meet the password policy"
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Invalid or expired reset token"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Password reset successfully",
+		"sessions_revoked": revoked,
+	})
+}
+
+// IssuePasswordResetHandler handles POST /admin/users/:username/password-reset.
+// Returns a single-use reset token to hand over to the user.
+func (h *AuthHandler) IssuePasswordResetHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
+
+	resp, err := h.authService.IssuePasswordReset(c.GetString("username"), username)
+	if err != nil {
+		message := "Failed to issue password reset"
+		switch {
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "User not found"
+		case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+			message = "Only active users can reset their password"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UnlockAccountHandler handles POST /admin/users/:username/unlock.
+// Lifts the lockout caused by failed logins of the given username.
+func (h *AuthHandler) UnlockAccountHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
+
+	if err := h.authService.UnlockAccount(c.GetString("username"), username); err != nil {
+		errors.HandleAPIError(c, err, "Failed to unlock account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked successfully"})
 }
 
 // MeHandler handles GET /auth/me.
-// Returns the username of the authenticated user and a hardcoded role.
+// Returns the username and role of the authenticated user.
 func (h *AuthHandler) MeHandler(c *gin.Context) {
 	username, exists := c.Get("username")
 	if !exists {
@@ -67,7 +393,9 @@ func (h *AuthHandler) MeHandler(c *gin.Context) {
 		return
 	}
 
-	logrus.WithField("username", strUsername).Info("User details retrieved successfully")
+	role := c.GetString("role")
+
+	logrus.WithFields(logrus.Fields{"username": strUsername, "role": role}).Info("User details retrieved successfully")
 
-	c.JSON(http.StatusOK, gin.H{"username": strUsername, "role": "admin"})
+	c.JSON(http.StatusOK, dto.MeResponse{Username: strUsername, Role: role})
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,6 +73,13 @@ func TestLoginHandler(t *testing.T) {
 			mockError:    errors.ErrInternalServer,
 			expectedCode: http.StatusInternalServerError,
 		},
+		{
+			name:         "too many failed attempts",
+			body:         ValidLoginJSON,
+			setupMock:    true,
+			mockError:    errors.ErrThroughputExceeded,
+			expectedCode: http.StatusTooManyRequests,
+		},
 	}
 
 	for _, tt := range tests {
@@ -79,8 +87,12 @@ func TestLoginHandler(t *testing.T) {
 			handler, mockService := setupAuthHandlerTest()
 
 			if tt.setupMock {
-				mockService.On("AuthenticateUser", "admin", "secret123").
-					Return(tt.mockToken, tt.mockError)
+				if tt.mockError != nil {
+					mockService.On("AuthenticateUser", "admin", "secret123", TestClientIP).Return(nil, tt.mockError)
+				} else {
+					mockService.On("AuthenticateUser", "admin", "secret123", TestClientIP).
+						Return(&dto.AuthResponse{Token: tt.mockToken, RefreshToken: RefreshTokenValue}, nil)
+				}
 			}
 
 			c, w := utils.CreateTestContext(http.MethodPost, "/auth/login", strings.NewReader(tt.body))
@@ -93,6 +105,7 @@ func TestLoginHandler(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &res)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.mockToken, res.Token)
+				assert.Equal(t, RefreshTokenValue, res.RefreshToken)
 			}
 
 			mockService.AssertExpectations(t)
@@ -104,15 +117,24 @@ func TestMeHandler(t *testing.T) {
 	tests := []struct {
 		name         string
 		username     interface{}
+		role         string
 		expectedCode int
 		expectedRole string
 	}{
 		{
 			name:         "valid username in context",
 			username:     "admin",
+			role:         "admin",
 			expectedCode: http.StatusOK,
 			expectedRole: "admin",
 		},
+		{
+			name:         "editor role in context",
+			username:     "admin",
+			role:         "editor",
+			expectedCode: http.StatusOK,
+			expectedRole: "editor",
+		},
 		{
 			name:         "missing username in context",
 			username:     nil,
@@ -138,6 +160,9 @@ func TestMeHandler(t *testing.T) {
 			if tt.username != nil {
 				c.Set("username", tt.username)
 			}
+			if tt.role != "" {
+				c.Set("role", tt.role)
+			}
 
 			handler.MeHandler(c)
 
@@ -153,3 +178,448 @@ func TestMeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockError    error
+		expectedCode int
+	}{
+		{name: "success", body: ValidRefreshJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing token", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "reused token", body: ValidRefreshJSON, setupMock: true, mockError: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				if tt.mockError != nil {
+					mockService.On("RefreshSession", RefreshTokenValue).Return(nil, tt.mockError)
+				} else {
+					mockService.On("RefreshSession", RefreshTokenValue).Return(&dto.AuthResponse{Token: "new.jwt", RefreshToken: "sess-1.next"}, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/refresh", strings.NewReader(tt.body))
+			handler.RefreshHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				res, err := DecodeJSONResponse[dto.AuthResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, "sess-1.next", res.RefreshToken)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogoutHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockError    error
+		expectedCode int
+	}{
+		{name: "success", expectedCode: http.StatusOK},
+		{name: "token without session", mockError: errors.ErrBadRequest, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			mockService.On("Logout", "sess-1").Return(tt.mockError)
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/logout", nil)
+			c.Set("username", "admin")
+			c.Set("session_id", "sess-1")
+			handler.LogoutHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogoutAllHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		mockError    error
+		expectedCode int
+	}{
+		{name: "success", username: "admin", expectedCode: http.StatusOK},
+		{name: "service error", username: "admin", mockError: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+		{name: "missing username", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.username != "" {
+				mockService.On("LogoutAll", tt.username).Return(2, tt.mockError)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/logout-all", nil)
+			if tt.username != "" {
+				c.Set("username", tt.username)
+			}
+			handler.LogoutAllHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				res, err := DecodeJSONResponse[map[string]interface{}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, float64(2), res["sessions_revoked"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnlockAccountHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", username: "maria", setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing username", username: "", expectedCode: http.StatusBadRequest},
+		{name: "service error", username: "maria", setupMock: true, mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("UnlockAccount", "admin", tt.username).Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/admin/users/"+tt.username+"/unlock", nil)
+			c.Params = gin.Params{{Key: "username", Value: tt.username}}
+			c.Set("username", "admin")
+			handler.UnlockAccountHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginSecondFactorHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ValidSecondFactorJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing code", body: MissingCodeJSON, expectedCode: http.StatusBadRequest},
+		{name: "wrong code", body: ValidSecondFactorJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "enrolment required", body: ValidSecondFactorJSON, setupMock: true, mockErr: errors.ErrBadRequest, expectedCode: http.StatusBadRequest},
+		{name: "locked", body: ValidSecondFactorJSON, setupMock: true, mockErr: errors.ErrThroughputExceeded, expectedCode: http.StatusTooManyRequests},
+		{name: "not configured", body: ValidSecondFactorJSON, setupMock: true, mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				if tt.mockErr != nil {
+					mockService.On("CompleteSecondFactor", MFATokenValue, "287082", TestClientIP).Return(nil, tt.mockErr)
+				} else {
+					mockService.On("CompleteSecondFactor", MFATokenValue, "287082", TestClientIP).Return(&dto.AuthResponse{Token: "access", RefreshToken: "refresh"}, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/login/2fa", strings.NewReader(tt.body))
+			handler.LoginSecondFactorHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				resp, err := DecodeJSONResponse[dto.AuthResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, "access", resp.Token)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginEnrollTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ValidMFATokenJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing token", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid token", body: ValidMFATokenJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "already enrolled", body: ValidMFATokenJSON, setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				if tt.mockErr != nil {
+					mockService.On("BeginTOTPEnrollmentForLogin", MFATokenValue).Return(nil, tt.mockErr)
+				} else {
+					mockService.On("BeginTOTPEnrollmentForLogin", MFATokenValue).Return(&TOTPEnrollment, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/login/2fa/enroll", strings.NewReader(tt.body))
+			handler.LoginEnrollTOTPHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				resp, err := DecodeJSONResponse[dto.TOTPEnrollmentResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, TOTPEnrollment, resp)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnrollTOTPHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		handler, mockService := setupAuthHandlerTest()
+		mockService.On("BeginTOTPEnrollment", "ana").Return(&TOTPEnrollment, nil)
+
+		c, w := utils.CreateTestContext(http.MethodPost, "/auth/2fa/enroll", nil)
+		c.Set("username", "ana")
+		handler.EnrollTOTPHandler(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resp, err := DecodeJSONResponse[dto.TOTPEnrollmentResponse](w)
+		assert.NoError(t, err)
+		assert.Equal(t, TOTPEnrollment, resp)
+	})
+
+	t.Run("identity outside the users store", func(t *testing.T) {
+		handler, mockService := setupAuthHandlerTest()
+		mockService.On("BeginTOTPEnrollment", "oidc:ext-1").Return(nil, errors.ErrOperationNotAllowed)
+
+		c, w := utils.CreateTestContext(http.MethodPost, "/auth/2fa/enroll", nil)
+		c.Set("username", "oidc:ext-1")
+		handler.EnrollTOTPHandler(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestConfirmTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ValidTOTPCodeJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing code", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "wrong code", body: ValidTOTPCodeJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "nothing pending", body: ValidTOTPCodeJSON, setupMock: true, mockErr: errors.ErrBadRequest, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("ConfirmTOTPEnrollment", "ana", "287082").Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/2fa/confirm", strings.NewReader(tt.body))
+			c.Set("username", "ana")
+			handler.ConfirmTOTPHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisableTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ValidTOTPCodeJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing code", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "wrong code", body: ValidTOTPCodeJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "required by role", body: ValidTOTPCodeJSON, setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("DisableTOTP", "ana", "287082").Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/2fa/disable", strings.NewReader(tt.body))
+			c.Set("username", "ana")
+			handler.DisableTOTPHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetTOTPHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", username: "ana", setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing username", username: "", expectedCode: http.StatusBadRequest},
+		{name: "unknown user", username: "ghost", setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("ResetTOTP", "admin", tt.username).Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodDelete, "/admin/users/"+tt.username+"/2fa", nil)
+			c.Params = gin.Params{{Key: "username", Value: tt.username}}
+			c.Set("username", "admin")
+			handler.ResetTOTPHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePasswordHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		username     string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ChangePasswordJSON, username: "maria", setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing field", body: `{"new_password": "Tr0mbone-Solo"}`, username: "maria", expectedCode: http.StatusBadRequest},
+		{name: "unauthenticated", body: ChangePasswordJSON, expectedCode: http.StatusUnauthorized},
+		{name: "weak password", body: ChangePasswordJSON, username: "maria", setupMock: true, mockErr: errors.ErrValidationFailed, expectedCode: http.StatusBadRequest},
+		{name: "wrong current password", body: ChangePasswordJSON, username: "maria", setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "locked", body: ChangePasswordJSON, username: "maria", setupMock: true, mockErr: errors.ErrThroughputExceeded, expectedCode: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("ChangePassword", "maria", "secret", "Tr0mbone-Solo", TestClientIP).Return(2, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/password", strings.NewReader(tt.body))
+			if tt.username != "" {
+				c.Set("username", tt.username)
+			}
+			handler.ChangePasswordHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				var resp map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, float64(2), resp["sessions_revoked"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", body: ResetPasswordJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing token", body: `{"username": "maria", "new_password": "Tr0mbone-Solo"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid token", body: ResetPasswordJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "weak password", body: ResetPasswordJSON, setupMock: true, mockErr: errors.ErrValidationFailed, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				mockService.On("ResetPassword", ResetPasswordRequest).Return(1, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/password/reset", strings.NewReader(tt.body))
+			handler.ResetPasswordHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuePasswordResetHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", username: "maria", setupMock: true, expectedCode: http.StatusCreated},
+		{name: "missing username", username: "", expectedCode: http.StatusBadRequest},
+		{name: "unknown user", username: "maria", setupMock: true, mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "inactive user", username: "maria", setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAuthHandlerTest()
+			if tt.setupMock {
+				if tt.mockErr != nil {
+					mockService.On("IssuePasswordReset", "admin", tt.username).Return(nil, tt.mockErr)
+				} else {
+					mockService.On("IssuePasswordReset", "admin", tt.username).Return(&dto.PasswordResetResponse{Username: "maria", ResetToken: "reset-token", ExpiresAt: 4600}, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/admin/users/"+tt.username+"/password-reset", nil)
+			c.Params = gin.Params{{Key: "username", Value: tt.username}}
+			c.Set("username", "admin")
+			handler.IssuePasswordResetHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusCreated {
+				resp, err := DecodeJSONResponse[dto.PasswordResetResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, "reset-token", resp.ResetToken)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var SuggestionsBo = []dto.Suggestion{
+	{Value: "Bohemian Rhapsody", Type: dto.SuggestionTypeSong},
+	{Value: "bolero", Type: dto.SuggestionTypeGenre},
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxAutocompleteLimit caps the number of suggestions returned per keystroke.
+const maxAutocompleteLimit = 25
+
+// AutocompleteHandler handles search-as-you-type requests.
+// It delegates the business logic to the AutocompleteServiceInterface.
+type AutocompleteHandler struct {
+	autocompleteService services.AutocompleteServiceInterface
+}
+
+// NewAutocompleteHandler returns a new instance of AutocompleteHandler.
+func NewAutocompleteHandler(autocompleteService services.AutocompleteServiceInterface) *AutocompleteHandler {
+	return &AutocompleteHandler{autocompleteService: autocompleteService}
+}
+
+// SuggestHandler handles GET /autocomplete.
+// Returns the top suggestions for the "q" prefix, optionally restricted by "type"
+// (song, author, genre or instrument).
+func (h *AutocompleteHandler) SuggestHandler(c *gin.Context) {
+	prefix, ok := utils.RequireQuery(c, "q")
+	if !ok {
+		return
+	}
+	suggestionType := c.Query("type")
+	limit, _ := utils.ExtractPaginationParams(c)
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	suggestions, err := h.autocompleteService.Suggest(prefix, suggestionType, limit)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve suggestions")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prefix":      prefix,
+		"type":        suggestionType,
+		"suggestions": len(suggestions),
+	}).Debug("Autocomplete suggestions retrieved")
+
+	c.JSON(http.StatusOK, gin.H{"data": suggestions})
+}
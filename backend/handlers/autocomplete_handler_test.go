@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAutocompleteHandlerTest() (*handlers.AutocompleteHandler, *mocks.MockAutocompleteService) {
+	mockService := new(mocks.MockAutocompleteService)
+	handler := handlers.NewAutocompleteHandler(mockService)
+	return handler, mockService
+}
+
+func TestSuggestHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    bool
+		mockPrefix   string
+		mockType     string
+		mockLimit    int
+		mockReturn   []dto.Suggestion
+		mockErr      error
+		expectedCode int
+		expectedBody []string
+	}{
+		{
+			name:         "returns suggestions",
+			query:        "q=bo",
+			setupMock:    true,
+			mockPrefix:   "bo",
+			mockLimit:    10,
+			mockReturn:   SuggestionsBo,
+			expectedCode: http.StatusOK,
+			expectedBody: []string{"Bohemian Rhapsody", `"type":"genre"`},
+		},
+		{
+			name:         "filters by type",
+			query:        "q=bo&type=song",
+			setupMock:    true,
+			mockPrefix:   "bo",
+			mockType:     "song",
+			mockLimit:    10,
+			mockReturn:   SuggestionsBo[:1],
+			expectedCode: http.StatusOK,
+			expectedBody: []string{"Bohemian Rhapsody"},
+		},
+		{
+			name:         "caps limit",
+			query:        "q=bo&limit=500",
+			setupMock:    true,
+			mockPrefix:   "bo",
+			mockLimit:    25,
+			mockReturn:   []dto.Suggestion{},
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`"data":[]`},
+		},
+		{
+			name:         "missing prefix",
+			query:        "type=song",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown type",
+			query:        "q=bo&type=album",
+			setupMock:    true,
+			mockPrefix:   "bo",
+			mockType:     "album",
+			mockLimit:    10,
+			mockErr:      errors.ErrValidationFailed,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "service error",
+			query:        "q=bo",
+			setupMock:    true,
+			mockPrefix:   "bo",
+			mockLimit:    10,
+			mockErr:      errors.ErrInternalServer,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAutocompleteHandlerTest()
+
+			if tt.setupMock {
+				mockService.On("Suggest", tt.mockPrefix, tt.mockType, tt.mockLimit).Return(tt.mockReturn, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/autocomplete?"+tt.query, nil)
+
+			handler.SuggestHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			for _, s := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), s)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CacheHandler exposes the metrics of the song and document cache.
+// It delegates the business logic to the CacheServiceInterface.
+type CacheHandler struct {
+	cacheService services.CacheServiceInterface
+}
+
+// NewCacheHandler returns a new instance of CacheHandler.
+func NewCacheHandler(cacheService services.CacheServiceInterface) *CacheHandler {
+	return &CacheHandler{cacheService: cacheService}
+}
+
+// GetCacheStatsHandler handles GET /admin/cache.
+// Returns the hits, misses and evictions of the cache since startup.
+func (h *CacheHandler) GetCacheStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.cacheService.Stats()})
+}
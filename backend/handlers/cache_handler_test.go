@@ -0,0 +1,30 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCacheStatsHandler(t *testing.T) {
+	mockService := new(mocks.MockCacheService)
+	handler := handlers.NewCacheHandler(mockService)
+	stats := dto.CacheStatsResponse{Enabled: true, Hits: 3, Misses: 1, HitRatio: 0.75, Entries: 1}
+	mockService.On("Stats").Return(stats)
+
+	c, w := utils.CreateTestContext(http.MethodGet, "/admin/cache", nil)
+	handler.GetCacheStatsHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body, err := DecodeJSONResponse[struct {
+		Data dto.CacheStatsResponse `json:"data"`
+	}](w)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, body.Data)
+	mockService.AssertExpectations(t)
+}
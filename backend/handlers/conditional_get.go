@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// respondCacheable writes body as a 200 JSON response with an ETag, a Last-Modified header when
+// lastModified is set, and the Cache-Control policy of the route (see middleware.CacheControl).
+// An empty etag is derived from the encoded body. If the client's copy is still current according
+// to If-None-Match or, without it, If-Modified-Since, it answers 304 Not Modified with no body.
+func respondCacheable(c *gin.Context, etag string, lastModified time.Time, body interface{}) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		errors.HandleAPIError(c, errors.ErrInternalServer, "Failed to encode response")
+		return
+	}
+	if etag == "" {
+		etag = contentETag(payload)
+	}
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if policy := c.GetString("cache_control"); policy != "" {
+		c.Header("Cache-Control", policy)
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+// contentETag returns a strong entity tag for an encoded response body.
+func contentETag(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates If-None-Match and If-Modified-Since as RFC 9110 does for GET: If-None-Match
+// uses the weak comparison and, when present, If-Modified-Since is ignored. lastModified cannot see
+// items that left a list, so clients should prefer If-None-Match for collections.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		current := strings.TrimPrefix(etag, "W/")
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// latestTimestamp returns the most recent of the given RFC3339 timestamps, or the zero time if
+// none is valid.
+func latestTimestamp(timestamps ...string) time.Time {
+	var latest time.Time
+	for _, ts := range timestamps {
+		t, err := time.Parse(time.RFC3339, ts)
+		if err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
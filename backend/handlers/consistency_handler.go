@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ConsistencyHandler handles HTTP requests that check and repair data denormalized across tables.
+// It delegates the business logic to the ConsistencyServiceInterface.
+type ConsistencyHandler struct {
+	consistencyService services.ConsistencyServiceInterface
+}
+
+// NewConsistencyHandler returns a new instance of ConsistencyHandler.
+func NewConsistencyHandler(consistencyService services.ConsistencyServiceInterface) *ConsistencyHandler {
+	return &ConsistencyHandler{consistencyService: consistencyService}
+}
+
+// CheckDocumentTitlesHandler handles GET /admin/consistency/titles.
+// Reports the documents whose copy of the song title differs from their song, without changing them.
+func (h *ConsistencyHandler) CheckDocumentTitlesHandler(c *gin.Context) {
+	report, err := h.consistencyService.CheckDocumentTitles(false)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to check document titles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// RepairDocumentTitlesHandler handles POST /admin/consistency/titles/repair.
+// Copies the current song title to the documents that drifted; the consistency job also does it on every run.
+func (h *ConsistencyHandler) RepairDocumentTitlesHandler(c *gin.Context) {
+	report, err := h.consistencyService.CheckDocumentTitles(true)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to repair document titles")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"drifted":  len(report.Drifted),
+		"repaired": report.Repaired,
+	}).Info("Document titles checked and repaired")
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var titleReport = dto.TitleConsistencyReport{
+	Checked:  2,
+	Drifted:  []dto.TitleDrift{{SongID: "song-123", DocumentID: "doc-2", Expected: "bohemian rhapsody (live)", Actual: "bohemian rhapsody"}},
+	Repaired: 1,
+}
+
+func TestConsistencyHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		repair       bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "check", method: http.MethodGet, url: "/admin/consistency/titles", expectedCode: http.StatusOK},
+		{name: "check fails", method: http.MethodGet, url: "/admin/consistency/titles", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+		{name: "repair", method: http.MethodPost, url: "/admin/consistency/titles/repair", repair: true, expectedCode: http.StatusOK},
+		{name: "repair fails", method: http.MethodPost, url: "/admin/consistency/titles/repair", repair: true, mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockConsistencyService)
+			handler := handlers.NewConsistencyHandler(mockService)
+			mockService.On("CheckDocumentTitles", tt.repair).Return(titleReport, tt.mockErr)
+
+			c, w := utils.CreateTestContext(tt.method, tt.url, nil)
+			handle := gin.HandlerFunc(handler.CheckDocumentTitlesHandler)
+			if tt.repair {
+				handle = handler.RepairDocumentTitlesHandler
+			}
+			handle(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data dto.TitleConsistencyReport `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, titleReport, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -31,6 +31,7 @@ var DocumentResponseScore = dto.DocumentResponseItem{
 	Type:       "score",
 	Instrument: []string{"piano"},
 	PDFURL:     "https://example.com/bohemian-piano.pdf",
+	Version:    2,
 }
 
 var DocumentResponseTablature = dto.DocumentResponseItem{
@@ -46,7 +46,7 @@ func (h *DocumentHandler) CreateDocumentHandler(c *gin.Context) {
 	// 	return
 	// }
 
-	documentID, err := h.documentService.CreateDocument(req)
+	documentID, err := h.documentService.CreateDocument(actorFromContext(c), req)
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to create document")
 		return
@@ -64,26 +64,42 @@ func (h *DocumentHandler) CreateDocumentHandler(c *gin.Context) {
 }
 
 // GetAllDocumentsBySongIDHandler handles GET /songs/:song_id/documents.
-// Retrieves all documents associated with a specific song.
+// Returns one page of the documents of a song, with the same query parameters, envelope and
+// caching as GET /songs.
 func (h *DocumentHandler) GetAllDocumentsBySongIDHandler(c *gin.Context) {
 	songID, ok := utils.RequireParam(c, "song_id")
 	if !ok {
 		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
 		return
 	}
+	var query dto.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid query parameters")
+		return
+	}
 
-	documents, err := h.documentService.GetDocumentsBySongID(songID)
+	page, err := h.documentService.ListDocuments(songID, query)
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to retrieve documents")
 		return
 	}
 
-	logrus.WithField("song_id", songID).Info("Documents retrieved successfully")
-	c.JSON(http.StatusOK, gin.H{"data": documents})
+	updatedAt := make([]string, len(page.Data))
+	for i, doc := range page.Data {
+		updatedAt[i] = doc.UpdatedAt
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":        songID,
+		"count":          page.Count,
+		"has_next_token": page.NextToken != "",
+	}).Info("Documents retrieved successfully")
+	respondCacheable(c, "", latestTimestamp(updatedAt...), page)
 }
 
 // GetDocumentByIDHandler handles GET /songs/:song_id/documents/:doc_id.
 // Retrieves a single document by song ID and document ID.
+// The ETag is the version, for If-Match on writes, and conditional requests are answered with 304.
 func (h *DocumentHandler) GetDocumentByIDHandler(c *gin.Context) {
 	songID, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -111,11 +127,12 @@ func (h *DocumentHandler) GetDocumentByIDHandler(c *gin.Context) {
 		"song_id":     songID,
 		"document_id": docID,
 	}).Info("Document retrieved successfully")
-	c.JSON(http.StatusOK, gin.H{"data": document})
+	respondCacheable(c, dto.VersionETag(document.Version), latestTimestamp(document.UpdatedAt), gin.H{"data": document})
 }
 
 // UpdateDocumentHandler handles PUT /songs/:song_id/documents/:doc_id.
-// Applies updates to a specific document.
+// Applies updates to a specific document. An If-Match header is honoured
+// and the ETag of the updated document is returned.
 func (h *DocumentHandler) UpdateDocumentHandler(c *gin.Context) {
 	songID, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -139,7 +156,7 @@ func (h *DocumentHandler) UpdateDocumentHandler(c *gin.Context) {
 		return
 	}
 
-	err := h.documentService.UpdateDocument(songID, docID, docUpdate)
+	version, err := h.documentService.UpdateDocument(actorFromContext(c), songID, docID, docUpdate, dto.NewPrecondition(c.GetHeader("If-Match")))
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to update document")
 		return
@@ -151,11 +168,12 @@ func (h *DocumentHandler) UpdateDocumentHandler(c *gin.Context) {
 		"updates":     docUpdate,
 	}).Info("Document updated successfully")
 
+	c.Header("ETag", dto.VersionETag(version))
 	c.JSON(http.StatusOK, gin.H{"message": "Document updated successfully"})
 }
 
 // DeleteDocumentHandler handles DELETE /songs/:song_id/documents/:doc_id.
-// Deletes a specific document linked to a song.
+// Deletes a specific document linked to a song. An If-Match header is honoured.
 func (h *DocumentHandler) DeleteDocumentHandler(c *gin.Context) {
 	songID, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -169,7 +187,7 @@ func (h *DocumentHandler) DeleteDocumentHandler(c *gin.Context) {
 		return
 	}
 
-	err := h.documentService.DeleteDocument(songID, docID)
+	err := h.documentService.DeleteDocument(actorFromContext(c), songID, docID, dto.NewPrecondition(c.GetHeader("If-Match")))
 	if err != nil {
 		message := "Failed to delete document"
 		if stdErrors.Is(err, errors.ErrResourceNotFound) {
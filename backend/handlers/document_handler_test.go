@@ -13,6 +13,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func setupDocumentHandlerTest() (*handlers.DocumentHandler, *mocks.MockDocumentService) {
@@ -82,7 +83,7 @@ func TestCreateDocumentHandler(t *testing.T) {
 				req.SongID = tt.songID
 
 				mockService.
-					On("CreateDocument", req).
+					On("CreateDocument", mock.Anything, req).
 					Return(tt.mockReturnID, tt.mockReturnErr)
 			}
 
@@ -122,7 +123,10 @@ func TestGetAllDocumentsBySongIDHandler(t *testing.T) {
 		name           string
 		songID         string
 		setupParam     bool
+		query          string
+		expectedQuery  dto.ListQuery
 		mockDocuments  []dto.DocumentResponseItem
+		mockNext       string
 		mockError      error
 		expectedCode   int
 		expectedResult []dto.DocumentResponseItem
@@ -144,6 +148,26 @@ func TestGetAllDocumentsBySongIDHandler(t *testing.T) {
 			expectedCode:   http.StatusOK,
 			expectedResult: []dto.DocumentResponseItem{},
 		},
+		{
+			name:           "passes paging parameters",
+			songID:         "1",
+			setupParam:     true,
+			query:          "?limit=1&sort=type&order=asc&cursor=abc",
+			expectedQuery:  dto.ListQuery{Limit: 1, Sort: "type", Order: "asc", Cursor: "abc"},
+			mockDocuments:  []dto.DocumentResponseItem{DocumentResponseScore},
+			mockNext:       "next",
+			expectedCode:   http.StatusOK,
+			expectedResult: []dto.DocumentResponseItem{DocumentResponseScore},
+		},
+		{
+			name:          "invalid paging parameters",
+			songID:        "1",
+			setupParam:    true,
+			query:         "?order=sideways",
+			expectedQuery: dto.ListQuery{Order: "sideways"},
+			expectedCode:  http.StatusBadRequest,
+			mockError:     errors.ErrBadRequest,
+		},
 		{
 			name:         "missing song_id param",
 			setupParam:   false,
@@ -164,13 +188,13 @@ func TestGetAllDocumentsBySongIDHandler(t *testing.T) {
 
 			if tt.setupParam && tt.mockError != errors.ErrValidationFailed {
 				mockService.
-					On("GetDocumentsBySongID", tt.songID).
-					Return(tt.mockDocuments, tt.mockError)
+					On("ListDocuments", tt.songID, tt.expectedQuery).
+					Return(dto.DocumentListResponse{Data: tt.mockDocuments, Count: len(tt.mockDocuments), NextToken: tt.mockNext}, tt.mockError)
 			}
 
 			path := "/songs"
 			if tt.setupParam {
-				path += "/" + tt.songID + "/documents"
+				path += "/" + tt.songID + "/documents" + tt.query
 			}
 
 			c, w := utils.CreateTestContext(http.MethodGet, path, nil)
@@ -183,12 +207,12 @@ func TestGetAllDocumentsBySongIDHandler(t *testing.T) {
 			assert.Equal(t, tt.expectedCode, w.Code)
 
 			if tt.expectedCode == http.StatusOK {
-				var response struct {
-					Data []dto.DocumentResponseItem `json:"data"`
-				}
+				var response dto.DocumentListResponse
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResult, response.Data)
+				assert.Equal(t, len(tt.expectedResult), response.Count)
+				assert.Equal(t, tt.mockNext, response.NextToken)
 			}
 
 			if tt.setupParam && tt.mockError != errors.ErrValidationFailed {
@@ -285,6 +309,7 @@ func TestGetDocumentByIDHandler(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResult, response.Data)
+				assert.Equal(t, dto.VersionETag(tt.expectedResult.Version), w.Header().Get("ETag"))
 			}
 
 			if tt.setupParams && tt.expectedCode != http.StatusBadRequest {
@@ -373,8 +398,8 @@ func TestUpdateDocumentHandler(t *testing.T) {
 				_ = json.Unmarshal([]byte(tt.body), &update)
 
 				mockService.
-					On("UpdateDocument", tt.songID, tt.docID, update).
-					Return(tt.mockError)
+					On("UpdateDocument", mock.Anything, tt.songID, tt.docID, update, dto.Precondition{}).
+					Return(2, tt.mockError)
 			}
 
 			path := "/songs"
@@ -452,7 +477,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 
 			if tt.setupParams && tt.expectedCode != http.StatusBadRequest {
 				mockService.
-					On("DeleteDocument", tt.songID, tt.docID).
+					On("DeleteDocument", mock.Anything, tt.songID, tt.docID, dto.Precondition{}).
 					Return(tt.mockError)
 			}
 
@@ -481,3 +506,92 @@ func TestDeleteDocumentHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentWriteHandlers_HonourIfMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockError    error
+		expectedCode int
+		expectedETag string
+	}{
+		{name: "matching version", expectedCode: http.StatusOK, expectedETag: `"3"`},
+		{name: "stale version", mockError: errors.ErrPreconditionFailed, expectedCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupDocumentHandlerTest()
+			precondition := dto.Precondition{ETags: []string{`W/"2"`, `"2"`}}
+			params := []gin.Param{{Key: "song_id", Value: "1"}, {Key: "doc_id", Value: "doc-1"}}
+			mockService.On("UpdateDocument", mock.Anything, "1", "doc-1", dto.UpdateDocumentRequest{Type: "score"}, precondition).Return(3, tt.mockError)
+			mockService.On("DeleteDocument", mock.Anything, "1", "doc-1", precondition).Return(tt.mockError)
+
+			c, w := utils.CreateTestContext(http.MethodPut, "/songs/1/documents/doc-1", strings.NewReader(`{ "type": "score" }`))
+			c.Request.Header.Set("If-Match", `W/"2", "2"`)
+			c.Params = params
+			handler.UpdateDocumentHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+
+			c, w = utils.CreateTestContext(http.MethodDelete, "/songs/1/documents/doc-1", nil)
+			c.Request.Header.Set("If-Match", `W/"2", "2"`)
+			c.Params = params
+			handler.DeleteDocumentHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDocumentReadHandlers_ConditionalRequests(t *testing.T) {
+	document := DocumentResponseScore
+	document.UpdatedAt = "2025-02-01T10:00:00Z"
+
+	t.Run("list answers 304 to its own ETag", func(t *testing.T) {
+		handler, mockService := setupDocumentHandlerTest()
+		mockService.On("ListDocuments", "1", dto.ListQuery{}).Return(dto.DocumentListResponse{Data: []dto.DocumentResponseItem{document}, Count: 1}, nil)
+
+		c, w := utils.CreateTestContext(http.MethodGet, "/songs/1/documents", nil)
+		c.Params = []gin.Param{{Key: "song_id", Value: "1"}}
+		handler.GetAllDocumentsBySongIDHandler(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Sat, 01 Feb 2025 10:00:00 GMT", w.Header().Get("Last-Modified"))
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+
+		c, revalidated := utils.CreateTestContext(http.MethodGet, "/songs/1/documents", nil)
+		c.Params = []gin.Param{{Key: "song_id", Value: "1"}}
+		c.Request.Header.Set("If-None-Match", w.Header().Get("ETag"))
+		handler.GetAllDocumentsBySongIDHandler(c)
+
+		assert.Equal(t, http.StatusNotModified, revalidated.Code)
+		assert.Empty(t, revalidated.Body.String())
+	})
+
+	t.Run("document answers 304 to its version ETag", func(t *testing.T) {
+		handler, mockService := setupDocumentHandlerTest()
+		mockService.On("GetDocumentByID", "1", "doc-1").Return(document, nil)
+
+		c, w := utils.CreateTestContext(http.MethodGet, "/songs/1/documents/doc-1", nil)
+		c.Params = []gin.Param{{Key: "song_id", Value: "1"}, {Key: "doc_id", Value: "doc-1"}}
+		c.Request.Header.Set("If-None-Match", dto.VersionETag(document.Version))
+		handler.GetDocumentByIDHandler(c)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, dto.VersionETag(document.Version), w.Header().Get("ETag"))
+	})
+
+	t.Run("document is sent again after an update", func(t *testing.T) {
+		handler, mockService := setupDocumentHandlerTest()
+		mockService.On("GetDocumentByID", "1", "doc-1").Return(document, nil)
+
+		c, w := utils.CreateTestContext(http.MethodGet, "/songs/1/documents/doc-1", nil)
+		c.Params = []gin.Param{{Key: "song_id", Value: "1"}, {Key: "doc_id", Value: "doc-1"}}
+		c.Request.Header.Set("If-None-Match", dto.VersionETag(document.Version-1))
+		handler.GetDocumentByIDHandler(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
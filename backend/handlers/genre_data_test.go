@@ -0,0 +1,15 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var GenreList = []dto.GenreResponseItem{
+	{ID: "g-flamenco", Name: "Flamenco", ParentID: "g-folk"},
+	{ID: "g-folk", Name: "Folk"},
+}
+
+var GenreValidJSON = `{"name": "Rumba", "parent_id": "g-flamenco"}`
+var GenreInvalidJSON = `{"name": `
+var GenreRenameJSON = `{"name": "Rock & Roll"}`
+var GenreEmptyUpdateJSON = `{}`
+var GenreMergeJSON = `{"target_id": "g-flamenco"}`
+var GenreMergeMissingTargetJSON = `{}`
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GenreHandler handles HTTP requests related to the genre catalogue.
+// It delegates the business logic to the GenreServiceInterface.
+type GenreHandler struct {
+	genreService services.GenreServiceInterface
+}
+
+// NewGenreHandler returns a new instance of GenreHandler.
+func NewGenreHandler(genreService services.GenreServiceInterface) *GenreHandler {
+	return &GenreHandler{genreService: genreService}
+}
+
+// ListGenresHandler handles GET /genres.
+// Returns every genre with its parent, ordered by name.
+func (h *GenreHandler) ListGenresHandler(c *gin.Context) {
+	genres, err := h.genreService.ListGenres()
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve genres")
+		return
+	}
+
+	logrus.WithField("genres", len(genres)).Debug("Fetched all genres successfully")
+	c.JSON(http.StatusOK, gin.H{"data": genres})
+}
+
+// CreateGenreHandler handles POST /genres.
+// Delegates validation and creation to the service layer.
+func (h *GenreHandler) CreateGenreHandler(c *gin.Context) {
+	var req dto.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	genreID, err := h.genreService.CreateGenre(req)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to create genre")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"genre_id": genreID, "name": req.Name}).Info("Genre created successfully")
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Genre created successfully",
+		"genre_id": genreID,
+	})
+}
+
+// UpdateGenreHandler handles PUT /genres/:genre_id.
+// Renames and/or moves a genre; a rename rewrites every song that uses it.
+func (h *GenreHandler) UpdateGenreHandler(c *gin.Context) {
+	id, ok := utils.RequireParam(c, "genre_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	if err := dto.ValidateUpdateGenreRequest(req); err != nil {
+		errors.HandleAPIError(c, err, "Invalid update payload")
+		return
+	}
+
+	songsUpdated, err := h.genreService.UpdateGenre(actorFromContext(c), id, req)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to update genre")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genre_id":      id,
+		"songs_updated": songsUpdated,
+	}).Info("Genre updated successfully")
+
+	c.JSON(http.StatusOK, dto.GenreJobResponse{
+		Message:      "Genre updated successfully",
+		SongsUpdated: songsUpdated,
+	})
+}
+
+// MergeGenresHandler handles POST /genres/:genre_id/merge.
+// Folds the genre into the target given in the body and rewrites every affected song.
+func (h *GenreHandler) MergeGenresHandler(c *gin.Context) {
+	sourceID, ok := utils.RequireParam(c, "genre_id")
+	if !ok {
+		return
+	}
+
+	var req dto.MergeGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	songsUpdated, err := h.genreService.MergeGenres(actorFromContext(c), sourceID, req.TargetID)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to merge genres")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"source_id":     sourceID,
+		"target_id":     req.TargetID,
+		"songs_updated": songsUpdated,
+	}).Info("Genres merged successfully")
+
+	c.JSON(http.StatusOK, dto.GenreJobResponse{
+		Message:      "Genres merged successfully",
+		SongsUpdated: songsUpdated,
+	})
+}
@@ -0,0 +1,224 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGenreHandlerTest() (*handlers.GenreHandler, *mocks.MockGenreService) {
+	mockService := new(mocks.MockGenreService)
+	handler := handlers.NewGenreHandler(mockService)
+	return handler, mockService
+}
+
+func TestListGenresHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockGenres   []dto.GenreResponseItem
+		mockErr      error
+		expectedCode int
+	}{
+		{
+			name:         "returns genres",
+			mockGenres:   GenreList,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "service error",
+			mockErr:      errors.ErrInternalServer,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupGenreHandlerTest()
+			mockService.On("ListGenres").Return(tt.mockGenres, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/genres", nil)
+			handler.ListGenresHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data []dto.GenreResponseItem `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockGenres, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateGenreHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		setupMock    bool
+		mockID       string
+		mockErr      error
+		expectedCode int
+	}{
+		{
+			name:         "success",
+			input:        GenreValidJSON,
+			setupMock:    true,
+			mockID:       "g-new",
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid JSON",
+			input:        GenreInvalidJSON,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "parent not found",
+			input:        GenreValidJSON,
+			setupMock:    true,
+			mockErr:      errors.ErrResourceNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupGenreHandlerTest()
+			if tt.setupMock {
+				mockService.On("CreateGenre", dto.CreateGenreRequest{Name: "Rumba", ParentID: "g-flamenco"}).Return(tt.mockID, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/genres", strings.NewReader(tt.input))
+			handler.CreateGenreHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusCreated {
+				assert.Contains(t, w.Body.String(), `"genre_id":"g-new"`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateGenreHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		genreID      string
+		input        string
+		setupMock    bool
+		mockUpdated  int
+		mockErr      error
+		expectedCode int
+	}{
+		{
+			name:         "rename rewrites songs",
+			genreID:      "g-rock",
+			input:        GenreRenameJSON,
+			setupMock:    true,
+			mockUpdated:  3,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "empty update",
+			genreID:      "g-rock",
+			input:        GenreEmptyUpdateJSON,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "missing genre_id",
+			input:        GenreRenameJSON,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "genre not found",
+			genreID:      "g-missing",
+			input:        GenreRenameJSON,
+			setupMock:    true,
+			mockErr:      errors.ErrResourceNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupGenreHandlerTest()
+			if tt.setupMock {
+				mockService.On("UpdateGenre", mock.Anything, tt.genreID, mock.Anything).Return(tt.mockUpdated, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPut, "/genres/"+tt.genreID, strings.NewReader(tt.input))
+			c.Params = []gin.Param{{Key: "genre_id", Value: tt.genreID}}
+			handler.UpdateGenreHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.GenreJobResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockUpdated, response.SongsUpdated)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeGenresHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		setupMock    bool
+		mockUpdated  int
+		mockErr      error
+		expectedCode int
+	}{
+		{
+			name:         "success",
+			input:        GenreMergeJSON,
+			setupMock:    true,
+			mockUpdated:  2,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "missing target",
+			input:        GenreMergeMissingTargetJSON,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "merge into itself",
+			input:        GenreMergeJSON,
+			setupMock:    true,
+			mockErr:      errors.ErrValidationFailed,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupGenreHandlerTest()
+			if tt.setupMock {
+				mockService.On("MergeGenres", mock.Anything, "g-folk", "g-flamenco").Return(tt.mockUpdated, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/genres/g-folk/merge", strings.NewReader(tt.input))
+			c.Params = []gin.Param{{Key: "genre_id", Value: "g-folk"}}
+			handler.MergeGenresHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.GenreJobResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockUpdated, response.SongsUpdated)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
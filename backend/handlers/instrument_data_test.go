@@ -0,0 +1,12 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var InstrumentCatalog = dto.InstrumentCatalogResponse{
+	Instruments: []dto.InstrumentResponseItem{
+		{ID: "guitar", Names: map[string]string{"es": "Guitarra", "en": "Guitar"}, Aliases: []string{"gtr"}, Family: "strings"},
+	},
+	Families: []dto.InstrumentFamilyResponseItem{
+		{ID: "strings", Names: map[string]string{"es": "Cuerdas", "en": "Strings"}, Instruments: []string{"guitar"}},
+	},
+}
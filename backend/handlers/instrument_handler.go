@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// InstrumentHandler exposes the canonical instrument catalogue.
+// It delegates the business logic to the InstrumentServiceInterface.
+type InstrumentHandler struct {
+	instrumentService services.InstrumentServiceInterface
+}
+
+// NewInstrumentHandler returns a new instance of InstrumentHandler.
+func NewInstrumentHandler(instrumentService services.InstrumentServiceInterface) *InstrumentHandler {
+	return &InstrumentHandler{instrumentService: instrumentService}
+}
+
+// ListInstrumentsHandler handles GET /instruments.
+// Returns every canonical instrument with its localized names and aliases,
+// together with the instrument families used for grouped search.
+func (h *InstrumentHandler) ListInstrumentsHandler(c *gin.Context) {
+	catalog, err := h.instrumentService.ListInstruments()
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve instruments")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"instruments": len(catalog.Instruments),
+		"families":    len(catalog.Families),
+	}).Debug("Instrument catalogue retrieved")
+
+	c.JSON(http.StatusOK, gin.H{"data": catalog})
+}
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupInstrumentHandlerTest() (*handlers.InstrumentHandler, *mocks.MockInstrumentService) {
+	mockService := new(mocks.MockInstrumentService)
+	handler := handlers.NewInstrumentHandler(mockService)
+	return handler, mockService
+}
+
+func TestListInstrumentsHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockReturn   dto.InstrumentCatalogResponse
+		mockErr      error
+		expectedCode int
+		expectedBody []string
+	}{
+		{
+			name:         "returns catalogue",
+			mockReturn:   InstrumentCatalog,
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`"id":"guitar"`, `"Guitarra"`, `"instruments":["guitar"]`},
+		},
+		{
+			name:         "service error",
+			mockErr:      errors.ErrInternalServer,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupInstrumentHandlerTest()
+
+			mockService.On("ListInstruments").Return(tt.mockReturn, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/instruments", nil)
+
+			handler.ListInstrumentsHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			for _, s := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), s)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys that verify access tokens,
+// so other services can validate them without sharing a secret.
+type JWKSHandler struct {
+	keys *utils.JWTKeySet
+}
+
+// NewJWKSHandler returns a new instance of JWKSHandler.
+func NewJWKSHandler(keys *utils.JWTKeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKSHandler handles GET /.well-known/jwks.json.
+// Returns the RSA and Ed25519 public keys of the key set; HMAC keys are never published,
+// so the list is empty when only shared secrets are configured.
+func (h *JWKSHandler) GetJWKSHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, dto.JWKSResponse{Keys: h.keys.PublicJWKs()})
+}
@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJWKSHandler(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, expiredPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := utils.NewJWTKeySet("rendalla-backend", "rendalla-api", []utils.JWTKey{
+		{ID: "hmac", Secret: []byte("shared-secret")},
+		{ID: "rsa", Algorithm: utils.JWTAlgorithmRS256, PrivateKey: rsaKey},
+		{ID: "ed", Algorithm: utils.JWTAlgorithmEdDSA, PrivateKey: edPrivate},
+		{ID: "expired", Algorithm: utils.JWTAlgorithmEdDSA, PrivateKey: expiredPrivate, ExpireAt: time.Now().Add(-time.Hour)},
+	}, nil)
+	require.NoError(t, err)
+
+	handler := handlers.NewJWKSHandler(keys)
+	c, w := utils.CreateTestContext(http.MethodGet, "/.well-known/jwks.json", nil)
+	handler.GetJWKSHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response, err := DecodeJSONResponse[dto.JWKSResponse](w)
+	require.NoError(t, err)
+	require.Len(t, response.Keys, 2)
+
+	rsaJWK := response.Keys[0]
+	assert.Equal(t, "RSA", rsaJWK.Kty)
+	assert.Equal(t, "rsa", rsaJWK.Kid)
+	assert.Equal(t, "RS256", rsaJWK.Alg)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), rsaJWK.N)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+
+	edJWK := response.Keys[1]
+	assert.Equal(t, "OKP", edJWK.Kty)
+	assert.Equal(t, "Ed25519", edJWK.Crv)
+	assert.Equal(t, "EdDSA", edJWK.Alg)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(edPublic), edJWK.X)
+}
+
+func TestGetJWKSHandler_OnlySharedSecrets(t *testing.T) {
+	keys, err := utils.NewJWTKeySet("rendalla-backend", "rendalla-api", []utils.JWTKey{
+		{ID: "hmac", Secret: []byte("shared-secret")},
+	}, nil)
+	require.NoError(t, err)
+
+	handler := handlers.NewJWKSHandler(keys)
+	c, w := utils.CreateTestContext(http.MethodGet, "/.well-known/jwks.json", nil)
+	handler.GetJWKSHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"keys": []}`, w.Body.String())
+}
@@ -0,0 +1,16 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var OIDCLoginStarted = dto.OIDCLoginResponse{
+	AuthorizationURL: "https://idp.example.org/authorize?client_id=rendalla&state=state-1",
+	State:            "state-1",
+	ExpiresIn:        600,
+}
+
+var OIDCSessionTokens = dto.AuthResponse{
+	Token:        "oidc.jwt",
+	RefreshToken: "sess-1.secret",
+	TokenType:    "Bearer",
+	ExpiresIn:    900,
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	stdErrors "errors"
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// OIDCStateCookie holds the digest of the state of the login started by the browser. The callback is only
+// accepted with the state of that login, so that a login started elsewhere cannot be completed in it.
+const OIDCStateCookie = "rendalla_oidc_state"
+
+// oidcCookiePath limits the state cookie to the login endpoints.
+const oidcCookiePath = "/auth/oidc"
+
+// OIDCHandler handles HTTP requests of the login with the organisation's OpenID provider.
+// It delegates logic to the OIDCServiceInterface.
+type OIDCHandler struct {
+	oidcService services.OIDCServiceInterface
+}
+
+// NewOIDCHandler returns a new instance of OIDCHandler.
+func NewOIDCHandler(oidcService services.OIDCServiceInterface) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// LoginHandler handles GET /auth/oidc/login.
+// Starts a login and returns the provider authorization URL the browser must be sent to.
+// The digest of the login state is set in OIDCStateCookie for as long as the login can be completed.
+func (h *OIDCHandler) LoginHandler(c *gin.Context) {
+	login, err := h.oidcService.BeginLogin()
+	if err != nil {
+		message := "Failed to start OIDC login"
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			message = "OIDC login is not enabled"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, utils.HashToken(login.State), int(login.ExpiresIn), oidcCookiePath, "", true, true)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, login)
+}
+
+// CallbackHandler handles GET /auth/oidc/callback.
+// Completes the login with the code and state returned by the provider and
+// returns an access token and a refresh token, like POST /auth/login.
+// The state must match OIDCStateCookie, which is cleared whatever the outcome.
+func (h *OIDCHandler) CallbackHandler(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid callback parameters")
+		return
+	}
+
+	stateDigest, _ := c.Cookie(OIDCStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, "", -1, oidcCookiePath, "", true, true)
+
+	if req.Error != "" {
+		logrus.WithFields(logrus.Fields{
+			"oauth_error":       req.Error,
+			"error_description": req.ErrorDescription,
+		}).Warn("OIDC provider refused the login")
+		errors.HandleAPIError(c, errors.ErrInvalidCredentials, "Login was refused by the identity provider")
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stateDigest), []byte(utils.HashToken(req.State))) != 1 {
+		logrus.Warn("OIDC callback state does not match the login started by this browser")
+		errors.HandleAPIError(c, errors.ErrInvalidCredentials, "OIDC login was not started in this browser")
+		return
+	}
+
+	tokens, err := h.oidcService.CompleteLogin(req.Code, req.State)
+	if err != nil {
+		message := "OIDC login failed"
+		switch {
+		case stdErrors.Is(err, errors.ErrResourceNotFound):
+			message = "OIDC login is not enabled"
+		case stdErrors.Is(err, errors.ErrInvalidCredentials):
+			message = "Invalid or expired OIDC login"
+		case stdErrors.Is(err, errors.ErrOperationNotAllowed):
+			message = "No Rendalla role is granted to this account"
+		}
+		errors.HandleAPIError(c, err, message)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupOIDCHandlerTest() (*handlers.OIDCHandler, *mocks.MockOIDCService) {
+	mockService := new(mocks.MockOIDCService)
+	handler := handlers.NewOIDCHandler(mockService)
+	return handler, mockService
+}
+
+func TestOIDCLoginHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockResp     *dto.OIDCLoginResponse
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "returns authorization URL", mockResp: &OIDCLoginStarted, expectedCode: http.StatusOK},
+		{name: "not configured", mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "provider unavailable", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupOIDCHandlerTest()
+			mockService.On("BeginLogin").Return(tt.mockResp, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/auth/oidc/login", nil)
+			handler.LoginHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.OIDCLoginResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, OIDCLoginStarted, response)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+				cookies := w.Result().Cookies()
+				if assert.Len(t, cookies, 1) {
+					assert.Equal(t, handlers.OIDCStateCookie, cookies[0].Name)
+					assert.Equal(t, utils.HashToken(OIDCLoginStarted.State), cookies[0].Value)
+					assert.Equal(t, int(OIDCLoginStarted.ExpiresIn), cookies[0].MaxAge)
+					assert.True(t, cookies[0].HttpOnly)
+					assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+				}
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		stateCookie  string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", query: "?code=code-1&state=state-1", stateCookie: utils.HashToken("state-1"), setupMock: true, expectedCode: http.StatusOK},
+		{name: "missing state", query: "?code=code-1", expectedCode: http.StatusBadRequest},
+		{name: "refused by provider", query: "?error=access_denied&state=state-1", stateCookie: utils.HashToken("state-1"), expectedCode: http.StatusUnauthorized},
+		{name: "missing state cookie", query: "?code=code-1&state=state-1", expectedCode: http.StatusUnauthorized},
+		{name: "state of another login", query: "?code=code-1&state=state-1", stateCookie: utils.HashToken("state-2"), expectedCode: http.StatusUnauthorized},
+		{name: "invalid login", query: "?code=code-1&state=state-1", stateCookie: utils.HashToken("state-1"), setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+		{name: "no role mapped", query: "?code=code-1&state=state-1", stateCookie: utils.HashToken("state-1"), setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupOIDCHandlerTest()
+			if tt.setupMock {
+				if tt.mockErr != nil {
+					mockService.On("CompleteLogin", "code-1", "state-1").Return(nil, tt.mockErr)
+				} else {
+					mockService.On("CompleteLogin", "code-1", "state-1").Return(&OIDCSessionTokens, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/auth/oidc/callback"+tt.query, nil)
+			if tt.stateCookie != "" {
+				c.Request.AddCookie(&http.Cookie{Name: handlers.OIDCStateCookie, Value: tt.stateCookie})
+			}
+			handler.CallbackHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.AuthResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, OIDCSessionTokens, response)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handlers_test
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+var RevisionList = []dto.RevisionResponseItem{
+	{
+		SongID:    "song-1",
+		Revision:  2,
+		Entity:    models.AuditEntityDocument,
+		EntityID:  "doc-1",
+		Document:  &dto.DocumentResponseItem{ID: "doc-1", SongID: "song-1", Type: "score", PDFURL: "https://example.com/v1.pdf"},
+		Actor:     "maria",
+		CreatedAt: "2025-01-02T10:00:00Z",
+	},
+	{
+		SongID:    "song-1",
+		Revision:  1,
+		Entity:    models.AuditEntitySong,
+		EntityID:  "song-1",
+		Song:      &dto.SongResponseItem{ID: "song-1", Title: "Bohemian Rapsody", Author: "Queen"},
+		Actor:     "maria",
+		CreatedAt: "2025-01-01T10:00:00Z",
+	},
+}
+
+var RevisionDiff = dto.RevisionDiffResponse{
+	SongID:   "song-1",
+	Entity:   models.AuditEntitySong,
+	EntityID: "song-1",
+	From:     1,
+	Changes:  []dto.FieldChangeResponseItem{{Field: "title", Before: "Bohemian Rapsody", After: "Bohemian Rhapsody"}},
+}
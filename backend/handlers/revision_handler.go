@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	stdErrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RevisionHandler handles HTTP requests related to the previous versions of songs and documents.
+// It delegates the business logic to the RevisionServiceInterface.
+type RevisionHandler struct {
+	revisionService services.RevisionServiceInterface
+}
+
+// NewRevisionHandler returns a new instance of RevisionHandler.
+func NewRevisionHandler(revisionService services.RevisionServiceInterface) *RevisionHandler {
+	return &RevisionHandler{revisionService: revisionService}
+}
+
+// ListRevisionsHandler handles GET /songs/:song_id/revisions.
+// Returns the revisions of the song and its documents, newest first.
+func (h *RevisionHandler) ListRevisionsHandler(c *gin.Context) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return
+	}
+
+	revisions, err := h.revisionService.ListRevisions(songID)
+	if err != nil {
+		msg := "Failed to retrieve revisions"
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			msg = "Song not found"
+		}
+		errors.HandleAPIError(c, err, msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"song_id": songID, "revisions": len(revisions)}).Debug("Fetched revisions successfully")
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+// DiffRevisionsHandler handles GET /songs/:song_id/revisions/diff.
+// Compares revision "from" with revision "to", or with the current version when "to" is absent.
+func (h *RevisionHandler) DiffRevisionsHandler(c *gin.Context) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return
+	}
+
+	var query dto.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid query parameters")
+		return
+	}
+
+	diff, err := h.revisionService.DiffRevisions(songID, query)
+	if err != nil {
+		msg := "Failed to compare revisions"
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			msg = "Revision not found"
+		}
+		errors.HandleAPIError(c, err, msg)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+// RestoreRevisionHandler handles POST /songs/:song_id/revisions/:rev/restore.
+// Brings the song or document copied in the revision back to that version. An If-Match header is
+// honoured against the current version of the restored song or document.
+func (h *RevisionHandler) RestoreRevisionHandler(c *gin.Context) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || revision < 1 {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid parameter: rev")
+		return
+	}
+
+	newRevision, err := h.revisionService.RestoreRevision(actorFromContext(c), songID, revision, dto.NewPrecondition(c.GetHeader("If-Match")))
+	if err != nil {
+		msg := "Failed to restore revision"
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			msg = "Revision not found"
+		}
+		errors.HandleAPIError(c, err, msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  songID,
+		"restored": revision,
+		"revision": newRevision,
+	}).Info("Revision restored successfully")
+
+	c.JSON(http.StatusOK, dto.RestoreRevisionResponse{
+		Message:  "Revision restored successfully",
+		Revision: newRevision,
+	})
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupRevisionHandlerTest() (*handlers.RevisionHandler, *mocks.MockRevisionService) {
+	mockService := new(mocks.MockRevisionService)
+	handler := handlers.NewRevisionHandler(mockService)
+	return handler, mockService
+}
+
+func TestListRevisionsHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockRevisions []dto.RevisionResponseItem
+		mockErr       error
+		expectedCode  int
+	}{
+		{name: "returns revisions", mockRevisions: RevisionList, expectedCode: http.StatusOK},
+		{name: "unknown song", mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "service error", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupRevisionHandlerTest()
+			mockService.On("ListRevisions", "song-1").Return(tt.mockRevisions, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/songs/song-1/revisions", nil)
+			c.Params = gin.Params{{Key: "song_id", Value: "song-1"}}
+			handler.ListRevisionsHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data []dto.RevisionResponseItem `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockRevisions, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiffRevisionsHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		expectQuery  *dto.RevisionDiffQuery
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "against current version", url: "/songs/song-1/revisions/diff?from=1", expectQuery: &dto.RevisionDiffQuery{From: 1}, expectedCode: http.StatusOK},
+		{name: "between revisions", url: "/songs/song-1/revisions/diff?from=1&to=3", expectQuery: &dto.RevisionDiffQuery{From: 1, To: 3}, expectedCode: http.StatusOK},
+		{name: "non-numeric revision", url: "/songs/song-1/revisions/diff?from=first", expectedCode: http.StatusBadRequest},
+		{name: "invalid query", url: "/songs/song-1/revisions/diff?to=2", expectQuery: &dto.RevisionDiffQuery{To: 2}, mockErr: errors.ErrValidationFailed, expectedCode: http.StatusBadRequest},
+		{name: "unknown revision", url: "/songs/song-1/revisions/diff?from=9", expectQuery: &dto.RevisionDiffQuery{From: 9}, mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupRevisionHandlerTest()
+			if tt.expectQuery != nil {
+				mockService.On("DiffRevisions", "song-1", *tt.expectQuery).Return(RevisionDiff, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodGet, tt.url, nil)
+			c.Params = gin.Params{{Key: "song_id", Value: "song-1"}}
+			handler.DiffRevisionsHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data dto.RevisionDiffResponse `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, RevisionDiff.Changes[0].Field, response.Data.Changes[0].Field)
+			}
+			if tt.expectQuery == nil {
+				mockService.AssertNotCalled(t, "DiffRevisions", mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreRevisionHandler(t *testing.T) {
+	actor := models.Actor{Username: "maria", RequestID: "req-1"}
+
+	tests := []struct {
+		name         string
+		rev          string
+		ifMatch      string
+		expectCall   bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "restores revision", rev: "2", expectCall: true, expectedCode: http.StatusOK},
+		{name: "unknown revision", rev: "2", expectCall: true, mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "concurrent update", rev: "2", expectCall: true, mockErr: errors.ErrConflict, expectedCode: http.StatusConflict},
+		{name: "stale version", rev: "2", ifMatch: `"3"`, expectCall: true, mockErr: errors.ErrPreconditionFailed, expectedCode: http.StatusPreconditionFailed},
+		{name: "non-numeric revision", rev: "latest", expectedCode: http.StatusBadRequest},
+		{name: "revision zero", rev: "0", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupRevisionHandlerTest()
+			if tt.expectCall {
+				mockService.On("RestoreRevision", actor, "song-1", 2, dto.NewPrecondition(tt.ifMatch)).Return(5, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/songs/song-1/revisions/"+tt.rev+"/restore", nil)
+			c.Params = gin.Params{{Key: "song_id", Value: "song-1"}, {Key: "rev", Value: tt.rev}}
+			c.Set("username", "maria")
+			c.Set("request_id", "req-1")
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			handler.RestoreRevisionHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[dto.RestoreRevisionResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, 5, response.Revision)
+			}
+			if !tt.expectCall {
+				mockService.AssertNotCalled(t, "RestoreRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package handlers_test
 
-import "github.com/CristinaRendaLopez/rendalla-backend/models"
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
 
 // --- SONGS ---
 
@@ -83,6 +86,7 @@ var DocTablatureGuitar = models.Document{
 var DocViolinLoveOfMyLife = models.Document{
 	ID:              "3",
 	SongID:          "s3",
+	Title:           "Love of My Life",
 	TitleNormalized: "love of my life",
 	Type:            "sheet_music",
 	Instrument:      []string{"Violin"},
@@ -91,6 +95,7 @@ var DocViolinLoveOfMyLife = models.Document{
 var DocViolinSomebodyToLove = models.Document{
 	ID:              "4",
 	SongID:          "s4",
+	Title:           "Somebody to Love",
 	TitleNormalized: "somebody to love",
 	Type:            "sheet_music",
 	Instrument:      []string{"Violin"},
@@ -123,3 +128,25 @@ var DocInnuendo = models.Document{
 	TitleNormalized: "innuendo",
 	CreatedAt:       "1991-01-01T00:00:00Z",
 }
+
+// --- UNIFIED SEARCH ---
+
+var SearchResponseLove = dto.SearchResponse{
+	Data: []dto.SearchResultItem{
+		{
+			Type:  dto.SearchResultTypeSong,
+			Score: 75,
+			Song:  &dto.SongResponseItem{ID: "1", Title: "Love of My Life", Author: "Queen"},
+		},
+		{
+			Type:  dto.SearchResultTypeDocument,
+			Score: 75,
+			Document: &dto.DocumentSearchHit{
+				DocumentResponseItem: dto.DocumentResponseItem{ID: "3", SongID: "1", Type: "sheet_music"},
+				Song:                 dto.SongSummary{ID: "1", Title: "Love of My Life", Author: "Queen"},
+			},
+		},
+	},
+	SegmentCounts: dto.SearchCounts{Songs: 1, Documents: 1},
+	NextToken:     "b2Zmc2V0OjI",
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
@@ -23,6 +24,7 @@ func NewSearchHandler(searchService services.SearchServiceInterface) *SearchHand
 
 // ListSongsHandler handles GET /songs/search.
 // Supports filtering by title and sorting/pagination options.
+// When a title filter is given, the response includes highlight spans keyed by song ID.
 func (h *SearchHandler) ListSongsHandler(c *gin.Context) {
 	title := c.Query("title")
 	sortField := c.Query("sort")
@@ -43,14 +45,20 @@ func (h *SearchHandler) ListSongsHandler(c *gin.Context) {
 		"next_token": nextToken,
 	}).Info("Songs listed successfully with filters")
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"data":       songs,
 		"next_token": nextKey,
-	})
+	}
+	if title != "" {
+		response["highlights"] = dto.ToSongHighlightsByID(songs, title)
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // ListDocumentsHandler handles GET /documents/search.
 // Supports filtering by title, instrument, and type, as well as sorting and pagination.
+// When a title filter is given, the response includes highlight spans keyed by document ID.
 func (h *SearchHandler) ListDocumentsHandler(c *gin.Context) {
 	title := c.Query("title")
 	instrument := c.Query("instrument")
@@ -75,8 +83,43 @@ func (h *SearchHandler) ListDocumentsHandler(c *gin.Context) {
 		"next_token": nextToken,
 	}).Info("Documents listed successfully with filters")
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":       documents,
+	response := gin.H{
+		"data":       dto.ToDocumentResponseList(documents),
 		"next_token": nextKey,
-	})
+	}
+	if title != "" {
+		response["highlights"] = dto.ToDocumentHighlightsByID(documents, title)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchCatalogHandler handles GET /search.
+// Searches songs and documents together and returns a ranked, typed result list with cursor pagination.
+// Large result sets are ranked and counted in segments: segment_counts are the hits of each type in the
+// segment of the page, and complete tells whether that segment covers the whole catalogue, making them
+// the totals of the search. next_token moves through a segment and then resumes the scan with the next one.
+func (h *SearchHandler) SearchCatalogHandler(c *gin.Context) {
+	query, ok := utils.RequireQuery(c, "q")
+	if !ok {
+		return
+	}
+	limit, _ := utils.ExtractPaginationParams(c)
+	cursor := c.Query("next_token")
+
+	response, err := h.searchService.SearchCatalog(query, limit, cursor)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to search catalog")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"query":          query,
+		"limit":          limit,
+		"song_hits":      response.SegmentCounts.Songs,
+		"document_hits":  response.SegmentCounts.Documents,
+		"has_next_token": response.NextToken != "",
+	}).Info("Catalog searched successfully")
+
+	c.JSON(http.StatusOK, response)
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
@@ -41,6 +42,14 @@ func TestListSongsHandler(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: []string{"Love of My Life"},
 		},
+		{
+			name:         "highlights matched title",
+			query:        "title=LOVE",
+			mockTitle:    "LOVE",
+			mockReturn:   []models.Song{SongLoveOfMyLife},
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`"highlights":{"1":[{"field":"title","start":0,"end":4}]}`},
+		},
 		{
 			name:         "sort by title desc",
 			query:        "title=love&sort=title&order=desc",
@@ -113,6 +122,7 @@ func TestListDocumentsHandler(t *testing.T) {
 		mockErr      error
 		expectedCode int
 		expectedIDs  []string
+		expectedBody []string
 	}{
 		{
 			name:         "filter by title",
@@ -145,6 +155,7 @@ func TestListDocumentsHandler(t *testing.T) {
 			mockReturn:   []models.Document{DocViolinLoveOfMyLife, DocViolinSomebodyToLove},
 			expectedCode: http.StatusOK,
 			expectedIDs:  []string{"3", "4"},
+			expectedBody: []string{`"highlights":{"3":[{"field":"title","start":0,"end":4}],"4":[{"field":"title","start":12,"end":16}]}`},
 		},
 		{
 			name:         "sort by created_at desc",
@@ -204,6 +215,86 @@ func TestListDocumentsHandler(t *testing.T) {
 				}
 				assert.ElementsMatch(t, tt.expectedIDs, resultIDs)
 			}
+			for _, part := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), part)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchCatalogHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    bool
+		mockQuery    string
+		mockCursor   string
+		mockReturn   dto.SearchResponse
+		mockErr      error
+		expectedCode int
+		expectedBody []string
+	}{
+		{
+			name:         "returns ranked results",
+			query:        "q=love",
+			setupMock:    true,
+			mockQuery:    "love",
+			mockReturn:   SearchResponseLove,
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`"type":"song"`, `"type":"document"`, `"segment_counts":{"songs":1,"documents":1}`, `"next_token"`},
+		},
+		{
+			name:         "forwards cursor",
+			query:        "q=love&next_token=abc",
+			setupMock:    true,
+			mockQuery:    "love",
+			mockCursor:   "abc",
+			mockReturn:   dto.SearchResponse{Data: []dto.SearchResultItem{}},
+			expectedCode: http.StatusOK,
+			expectedBody: []string{`"data":[]`},
+		},
+		{
+			name:         "missing query",
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid cursor",
+			query:        "q=love&next_token=bad",
+			setupMock:    true,
+			mockQuery:    "love",
+			mockCursor:   "bad",
+			mockErr:      errors.ErrBadRequest,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "service error",
+			query:        "q=love",
+			setupMock:    true,
+			mockQuery:    "love",
+			mockErr:      errors.ErrInternalServer,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupSearchHandlerTest()
+
+			if tt.setupMock {
+				mockService.On("SearchCatalog", tt.mockQuery, 10, tt.mockCursor).Return(tt.mockReturn, tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/search?"+tt.query, nil)
+
+			handler.SearchCatalogHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			for _, s := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), s)
+			}
 
 			mockService.AssertExpectations(t)
 		})
@@ -32,7 +32,7 @@ func (h *SongHandler) CreateSongHandler(c *gin.Context) {
 		return
 	}
 
-	songID, err := h.songService.CreateSongWithDocuments(req)
+	songID, err := h.songService.CreateSongWithDocuments(actorFromContext(c), req)
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to create song")
 		return
@@ -46,20 +46,39 @@ func (h *SongHandler) CreateSongHandler(c *gin.Context) {
 }
 
 // GetAllSongsHandler handles GET /songs.
-// Returns all songs stored in the system.
+// Returns one page of songs, selected with the limit, cursor, sort and order query
+// parameters, together with its count and the next_token of the following page. The response can be
+// cached: its ETag is derived from the body, Last-Modified is the latest updated_at of the page, and
+// If-None-Match or If-Modified-Since yield 304.
 func (h *SongHandler) GetAllSongsHandler(c *gin.Context) {
-	songs, err := h.songService.GetAllSongs()
+	var query dto.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.songService.ListSongs(query)
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to retrieve songs")
 		return
 	}
 
-	logrus.Info("Fetched all songs successfully")
-	c.JSON(http.StatusOK, gin.H{"data": songs})
+	updatedAt := make([]string, len(page.Data))
+	for i, song := range page.Data {
+		updatedAt[i] = song.UpdatedAt
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"count":          page.Count,
+		"sort":           query.Sort,
+		"has_next_token": page.NextToken != "",
+	}).Info("Fetched songs successfully")
+	respondCacheable(c, "", latestTimestamp(updatedAt...), page)
 }
 
 // GetSongByIDHandler handles GET /songs/:song_id.
 // Retrieves a song by its unique ID.
+// The ETag is the version, for If-Match on writes, and conditional requests are answered with 304.
 func (h *SongHandler) GetSongByIDHandler(c *gin.Context) {
 	id, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -78,11 +97,12 @@ func (h *SongHandler) GetSongByIDHandler(c *gin.Context) {
 	}
 
 	logrus.WithField("song_id", id).Info("Fetched song successfully")
-	c.JSON(http.StatusOK, gin.H{"data": song})
+	respondCacheable(c, dto.VersionETag(song.Version), latestTimestamp(song.UpdatedAt), gin.H{"data": song})
 }
 
 // UpdateSongHandler handles PUT /songs/:song_id.
-// Delegates validation and update logic to the service layer.
+// Delegates validation and update logic to the service layer. An If-Match header is honoured
+// and the ETag of the updated song is returned.
 func (h *SongHandler) UpdateSongHandler(c *gin.Context) {
 	id, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -101,7 +121,8 @@ func (h *SongHandler) UpdateSongHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.songService.UpdateSong(id, songUpdate); err != nil {
+	version, err := h.songService.UpdateSong(actorFromContext(c), id, songUpdate, dto.NewPrecondition(c.GetHeader("If-Match")))
+	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to update song")
 		return
 	}
@@ -111,11 +132,12 @@ func (h *SongHandler) UpdateSongHandler(c *gin.Context) {
 		"updates": songUpdate,
 	}).Info("Song updated successfully")
 
+	c.Header("ETag", dto.VersionETag(version))
 	c.JSON(http.StatusOK, gin.H{"message": "Song updated successfully"})
 }
 
 // DeleteSongWithDocumentsHandler handles DELETE /songs/:song_id.
-// Deletes the song and all documents linked to it.
+// Deletes the song and all documents linked to it. An If-Match header is honoured.
 func (h *SongHandler) DeleteSongWithDocumentsHandler(c *gin.Context) {
 	id, ok := utils.RequireParam(c, "song_id")
 	if !ok {
@@ -123,7 +145,7 @@ func (h *SongHandler) DeleteSongWithDocumentsHandler(c *gin.Context) {
 		return
 	}
 
-	err := h.songService.DeleteSongWithDocuments(id)
+	err := h.songService.DeleteSongWithDocuments(actorFromContext(c), id, dto.NewPrecondition(c.GetHeader("If-Match")))
 	if err != nil {
 		errors.HandleAPIError(c, err, "Failed to delete song")
 		return
@@ -101,31 +101,63 @@ func TestCreateSongHandler(t *testing.T) {
 
 func TestGetAllSongsHandler(t *testing.T) {
 	tests := []struct {
-		name         string
-		mockSongs    []dto.SongResponseItem
-		mockError    error
-		expectedCode int
-		expectedData []dto.SongResponseItem
+		name          string
+		url           string
+		expectedQuery *dto.ListQuery
+		mockPage      dto.SongListResponse
+		mockError     error
+		expectedCode  int
+		expectedPage  dto.SongListResponse
 	}{
 		{
-			name:         "success with songs",
-			mockSongs:    []dto.SongResponseItem{{ID: "1", Title: "Don't Stop Me Now", Author: "Queen", Genres: []string{"rock"}}},
-			mockError:    nil,
-			expectedCode: http.StatusOK,
-			expectedData: []dto.SongResponseItem{{ID: "1", Title: "Don't Stop Me Now", Author: "Queen", Genres: []string{"rock"}}},
+			name:          "success with songs",
+			url:           "/songs",
+			expectedQuery: &dto.ListQuery{},
+			mockPage:      dto.SongListResponse{Data: []dto.SongResponseItem{{ID: "1", Title: "Don't Stop Me Now", Author: "Queen", Genres: []string{"rock"}}}, Count: 1, NextToken: "next"},
+			expectedCode:  http.StatusOK,
+			expectedPage:  dto.SongListResponse{Data: []dto.SongResponseItem{{ID: "1", Title: "Don't Stop Me Now", Author: "Queen", Genres: []string{"rock"}}}, Count: 1, NextToken: "next"},
 		},
 		{
-			name:         "success with empty list",
-			mockSongs:    []dto.SongResponseItem{},
-			mockError:    nil,
-			expectedCode: http.StatusOK,
-			expectedData: []dto.SongResponseItem{},
+			name:          "success with empty list",
+			url:           "/songs",
+			expectedQuery: &dto.ListQuery{},
+			mockPage:      dto.SongListResponse{Data: []dto.SongResponseItem{}},
+			expectedCode:  http.StatusOK,
+			expectedPage:  dto.SongListResponse{Data: []dto.SongResponseItem{}},
 		},
 		{
-			name:         "internal service error",
-			mockSongs:    nil,
-			mockError:    errors.ErrInternalServer,
-			expectedCode: http.StatusInternalServerError,
+			name:          "passes paging parameters",
+			url:           "/songs?limit=2&cursor=abc&sort=title&order=desc",
+			expectedQuery: &dto.ListQuery{Limit: 2, Cursor: "abc", Sort: "title", Order: "desc"},
+			mockPage:      dto.SongListResponse{Data: []dto.SongResponseItem{}},
+			expectedCode:  http.StatusOK,
+			expectedPage:  dto.SongListResponse{Data: []dto.SongResponseItem{}},
+		},
+		{
+			name:         "non-numeric limit",
+			url:          "/songs?limit=many",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "invalid paging parameters",
+			url:           "/songs?sort=genre",
+			expectedQuery: &dto.ListQuery{Sort: "genre"},
+			mockError:     errors.ErrValidationFailed,
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "invalid cursor",
+			url:           "/songs?cursor=broken",
+			expectedQuery: &dto.ListQuery{Cursor: "broken"},
+			mockError:     errors.ErrBadRequest,
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "internal service error",
+			url:           "/songs",
+			expectedQuery: &dto.ListQuery{},
+			mockError:     errors.ErrInternalServer,
+			expectedCode:  http.StatusInternalServerError,
 		},
 	}
 
@@ -133,22 +165,22 @@ func TestGetAllSongsHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			handler, mockService := setupSongHandlerTest()
 
-			mockService.
-				On("GetAllSongs").
-				Return(tt.mockSongs, tt.mockError)
+			if tt.expectedQuery != nil {
+				mockService.
+					On("ListSongs", *tt.expectedQuery).
+					Return(tt.mockPage, tt.mockError)
+			}
 
-			c, w := utils.CreateTestContext(http.MethodGet, "/songs", nil)
+			c, w := utils.CreateTestContext(http.MethodGet, tt.url, nil)
 			handler.GetAllSongsHandler(c)
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 
 			if tt.expectedCode == http.StatusOK {
-				response := struct {
-					Data []dto.SongResponseItem `json:"data"`
-				}{}
+				var response dto.SongListResponse
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedData, response.Data)
+				assert.Equal(t, tt.expectedPage, response)
 			}
 
 			mockService.AssertExpectations(t)
@@ -170,10 +202,11 @@ func TestGetSongByIDHandler(t *testing.T) {
 			songID:     "1",
 			setupParam: true,
 			mockResult: dto.SongResponseItem{
-				ID:     "1",
-				Title:  "Radio Ga Ga",
-				Author: "Queen",
-				Genres: []string{"pop", "rock"},
+				ID:      "1",
+				Title:   "Radio Ga Ga",
+				Author:  "Queen",
+				Genres:  []string{"pop", "rock"},
+				Version: 3,
 			},
 			mockError:    nil,
 			expectedCode: http.StatusOK,
@@ -230,6 +263,7 @@ func TestGetSongByIDHandler(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.mockResult, response.Data)
+				assert.Equal(t, `"3"`, w.Header().Get("ETag"))
 			}
 
 			if tt.setupParam {
@@ -315,8 +349,8 @@ func TestUpdateSongHandler(t *testing.T) {
 				_ = json.Unmarshal([]byte(tt.body), &update)
 
 				mockService.
-					On("UpdateSong", tt.songID, update).
-					Return(tt.mockError)
+					On("UpdateSong", mock.Anything, tt.songID, update, dto.Precondition{}).
+					Return(2, tt.mockError)
 			}
 
 			path := "/songs"
@@ -382,7 +416,7 @@ func TestDeleteSongWithDocumentsHandler(t *testing.T) {
 
 			if tt.setupParam && tt.expectedCode != http.StatusBadRequest {
 				mockService.
-					On("DeleteSongWithDocuments", tt.songID).
+					On("DeleteSongWithDocuments", mock.Anything, tt.songID, dto.Precondition{}).
 					Return(tt.mockError)
 			}
 
@@ -406,3 +440,100 @@ func TestDeleteSongWithDocumentsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestSongWriteHandlers_HonourIfMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockError    error
+		expectedCode int
+		expectedETag string
+	}{
+		{name: "matching version", expectedCode: http.StatusOK, expectedETag: `"4"`},
+		{name: "stale version", mockError: errors.ErrPreconditionFailed, expectedCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupSongHandlerTest()
+			author := "Queen"
+			precondition := dto.Precondition{ETags: []string{`"3"`}}
+			mockService.On("UpdateSong", mock.Anything, "1", dto.UpdateSongRequest{Author: &author}, precondition).Return(4, tt.mockError)
+			mockService.On("DeleteSongWithDocuments", mock.Anything, "1", precondition).Return(tt.mockError)
+
+			c, w := utils.CreateTestContext(http.MethodPut, "/songs/1", strings.NewReader(`{ "author": "Queen" }`))
+			c.Request.Header.Set("If-Match", `"3"`)
+			c.Params = []gin.Param{{Key: "song_id", Value: "1"}}
+			handler.UpdateSongHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+
+			c, w = utils.CreateTestContext(http.MethodDelete, "/songs/1", nil)
+			c.Request.Header.Set("If-Match", `"3"`)
+			c.Params = []gin.Param{{Key: "song_id", Value: "1"}}
+			handler.DeleteSongWithDocumentsHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllSongsHandler_ConditionalRequests(t *testing.T) {
+	songs := []dto.SongResponseItem{
+		{ID: "1", Title: "Don't Stop Me Now", Author: "Queen", UpdatedAt: "2025-02-01T10:00:00Z", Version: 2},
+		{ID: "2", Title: "Bohemian Rhapsody", Author: "Queen", UpdatedAt: "2025-02-03T08:30:00Z", Version: 1},
+	}
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		handler, mockService := setupSongHandlerTest()
+		mockService.On("ListSongs", dto.ListQuery{}).Return(dto.SongListResponse{Data: songs, Count: len(songs)}, nil)
+
+		c, w := utils.CreateTestContext(http.MethodGet, "/songs", nil)
+		c.Set("cache_control", "public, max-age=60")
+		for name, value := range headers {
+			c.Request.Header.Set(name, value)
+		}
+		handler.GetAllSongsHandler(c)
+		return w
+	}
+
+	first := get(nil)
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "Mon, 03 Feb 2025 08:30:00 GMT", first.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"))
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		expectedCode int
+	}{
+		{name: "matching If-None-Match", headers: map[string]string{"If-None-Match": etag}, expectedCode: http.StatusNotModified},
+		{name: "weak If-None-Match in a list", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, expectedCode: http.StatusNotModified},
+		{name: "stale If-None-Match", headers: map[string]string{"If-None-Match": `"other"`}, expectedCode: http.StatusOK},
+		{name: "If-Modified-Since at Last-Modified", headers: map[string]string{"If-Modified-Since": "Mon, 03 Feb 2025 08:30:00 GMT"}, expectedCode: http.StatusNotModified},
+		{name: "If-Modified-Since before Last-Modified", headers: map[string]string{"If-Modified-Since": "Sun, 02 Feb 2025 00:00:00 GMT"}, expectedCode: http.StatusOK},
+		{
+			name:         "stale If-None-Match takes precedence over If-Modified-Since",
+			headers:      map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Mon, 03 Feb 2025 08:30:00 GMT"},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.headers)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+			if tt.expectedCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.Equal(t, first.Body.String(), w.Body.String())
+			}
+		})
+	}
+}
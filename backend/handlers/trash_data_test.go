@@ -0,0 +1,12 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var TrashContents = dto.TrashResponse{
+	Songs: []dto.TrashedSongItem{
+		{ID: "song-1", Title: "Radio Ga Ga", Author: "Queen", Documents: 2, DeletedAt: "2025-01-01T00:00:00Z", DeletedBy: "admin", PurgeAt: "2025-01-31T00:00:00Z"},
+	},
+	Documents: []dto.TrashedDocumentItem{
+		{ID: "doc-1", SongID: "song-2", Type: "score", DeletedAt: "2025-01-02T00:00:00Z", DeletedBy: "maria", PurgeAt: "2025-02-01T00:00:00Z"},
+	},
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	stdErrors "errors"
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TrashHandler handles HTTP requests related to deleted songs and documents.
+// It delegates the business logic to the TrashServiceInterface.
+type TrashHandler struct {
+	trashService services.TrashServiceInterface
+}
+
+// NewTrashHandler returns a new instance of TrashHandler.
+func NewTrashHandler(trashService services.TrashServiceInterface) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// ListTrashHandler handles GET /admin/trash.
+// Returns the songs in the trash and the documents deleted on their own.
+func (h *TrashHandler) ListTrashHandler(c *gin.Context) {
+	trash, err := h.trashService.ListTrash()
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve trash")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"songs": len(trash.Songs), "documents": len(trash.Documents)}).Debug("Fetched trash successfully")
+	c.JSON(http.StatusOK, gin.H{"data": trash})
+}
+
+// RestoreSongHandler handles POST /admin/trash/songs/:song_id/restore.
+// Restores the song together with the documents deleted with it.
+func (h *TrashHandler) RestoreSongHandler(c *gin.Context) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return
+	}
+
+	if err := h.trashService.RestoreSong(actorFromContext(c), songID); err != nil {
+		errors.HandleAPIError(c, err, trashErrorMessage(err, "Failed to restore song", "Song not found in trash"))
+		return
+	}
+
+	logrus.WithField("song_id", songID).Info("Song restored from trash successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Song restored successfully"})
+}
+
+// RestoreDocumentHandler handles POST /admin/trash/songs/:song_id/documents/:doc_id/restore.
+// Restores a document deleted on its own.
+func (h *TrashHandler) RestoreDocumentHandler(c *gin.Context) {
+	songID, docID, ok := trashDocumentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreDocument(actorFromContext(c), songID, docID); err != nil {
+		errors.HandleAPIError(c, err, trashErrorMessage(err, "Failed to restore document", "Document not found in trash"))
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"song_id": songID, "document_id": docID}).Info("Document restored from trash successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Document restored successfully"})
+}
+
+// PurgeSongHandler handles DELETE /admin/trash/songs/:song_id.
+// Permanently deletes the song and every document of it.
+func (h *TrashHandler) PurgeSongHandler(c *gin.Context) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return
+	}
+
+	if err := h.trashService.PurgeSong(actorFromContext(c), songID); err != nil {
+		errors.HandleAPIError(c, err, trashErrorMessage(err, "Failed to purge song", "Song not found in trash"))
+		return
+	}
+
+	logrus.WithField("song_id", songID).Info("Song purged from trash successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Song purged successfully"})
+}
+
+// PurgeDocumentHandler handles DELETE /admin/trash/songs/:song_id/documents/:doc_id.
+// Permanently deletes a document in the trash.
+func (h *TrashHandler) PurgeDocumentHandler(c *gin.Context) {
+	songID, docID, ok := trashDocumentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.PurgeDocument(actorFromContext(c), songID, docID); err != nil {
+		errors.HandleAPIError(c, err, trashErrorMessage(err, "Failed to purge document", "Document not found in trash"))
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"song_id": songID, "document_id": docID}).Info("Document purged from trash successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Document purged successfully"})
+}
+
+// PurgeExpiredHandler handles POST /admin/trash/purge.
+// Runs the retention job now; meant for schedulers where the background job does not run (e.g. Lambda).
+func (h *TrashHandler) PurgeExpiredHandler(c *gin.Context) {
+	result, err := h.trashService.PurgeExpired(actorFromContext(c))
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to purge expired trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReconcilePendingSongsHandler handles POST /admin/trash/reconcile.
+// Finishes or rolls back interrupted writes on songs with many documents now; the retention job
+// also does it on every run.
+func (h *TrashHandler) ReconcilePendingSongsHandler(c *gin.Context) {
+	result, err := h.trashService.ReconcilePendingSongs(actorFromContext(c))
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to reconcile pending songs")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// trashDocumentParams reads the song and document IDs of a trash document route,
+// answering the request with a validation error if either is missing.
+func trashDocumentParams(c *gin.Context) (string, string, bool) {
+	songID, ok := utils.RequireParam(c, "song_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: song_id")
+		return "", "", false
+	}
+	docID, ok := utils.RequireParam(c, "doc_id")
+	if !ok {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Missing parameter: doc_id")
+		return "", "", false
+	}
+	return songID, docID, true
+}
+
+// trashErrorMessage returns notFound for errors.ErrResourceNotFound and fallback otherwise.
+func trashErrorMessage(err error, fallback, notFound string) string {
+	if stdErrors.Is(err, errors.ErrResourceNotFound) {
+		return notFound
+	}
+	return fallback
+}
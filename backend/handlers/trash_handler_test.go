@@ -0,0 +1,178 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTrashHandlerTest() (*handlers.TrashHandler, *mocks.MockTrashService) {
+	mockService := new(mocks.MockTrashService)
+	handler := handlers.NewTrashHandler(mockService)
+	return handler, mockService
+}
+
+var trashActor = models.Actor{Username: "admin", RequestID: "req-1"}
+
+func newTrashTestContext(method, url string, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	c, w := utils.CreateTestContext(method, url, nil)
+	c.Params = params
+	c.Set("username", trashActor.Username)
+	c.Set("request_id", trashActor.RequestID)
+	return c, w
+}
+
+func TestListTrashHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "returns trash", expectedCode: http.StatusOK},
+		{name: "service error", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTrashHandlerTest()
+			mockService.On("ListTrash").Return(TrashContents, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/admin/trash", nil)
+			handler.ListTrashHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data dto.TrashResponse `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, TrashContents, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreSongHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "restores song", expectedCode: http.StatusOK},
+		{name: "not in trash", mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "service error", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTrashHandlerTest()
+			mockService.On("RestoreSong", trashActor, "song-1").Return(tt.mockErr)
+
+			c, w := newTrashTestContext(http.MethodPost, "/admin/trash/songs/song-1/restore", gin.Params{{Key: "song_id", Value: "song-1"}})
+			handler.RestoreSongHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreDocumentHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "restores document", expectedCode: http.StatusOK},
+		{name: "trashed with its song", mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+		{name: "not in trash", mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupTrashHandlerTest()
+			mockService.On("RestoreDocument", trashActor, "song-1", "doc-1").Return(tt.mockErr)
+
+			c, w := newTrashTestContext(http.MethodPost, "/admin/trash/songs/song-1/documents/doc-1/restore",
+				gin.Params{{Key: "song_id", Value: "song-1"}, {Key: "doc_id", Value: "doc-1"}})
+			handler.RestoreDocumentHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPurgeSongHandler(t *testing.T) {
+	handler, mockService := setupTrashHandlerTest()
+	mockService.On("PurgeSong", trashActor, "song-1").Return(nil)
+
+	c, w := newTrashTestContext(http.MethodDelete, "/admin/trash/songs/song-1", gin.Params{{Key: "song_id", Value: "song-1"}})
+	handler.PurgeSongHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurgeDocumentHandler(t *testing.T) {
+	handler, mockService := setupTrashHandlerTest()
+	mockService.On("PurgeDocument", trashActor, "song-1", "doc-1").Return(errors.ErrResourceNotFound)
+
+	c, w := newTrashTestContext(http.MethodDelete, "/admin/trash/songs/song-1/documents/doc-1",
+		gin.Params{{Key: "song_id", Value: "song-1"}, {Key: "doc_id", Value: "doc-1"}})
+	handler.PurgeDocumentHandler(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurgeExpiredHandler(t *testing.T) {
+	handler, mockService := setupTrashHandlerTest()
+	mockService.On("PurgeExpired", trashActor).Return(dto.PurgeTrashResponse{Message: "Expired trash purged successfully", Songs: 1, Documents: 3}, nil)
+
+	c, w := newTrashTestContext(http.MethodPost, "/admin/trash/purge", nil)
+	handler.PurgeExpiredHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response, err := DecodeJSONResponse[dto.PurgeTrashResponse](w)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Songs)
+	assert.Equal(t, 3, response.Documents)
+	mockService.AssertExpectations(t)
+}
+
+func TestReconcilePendingSongsHandler(t *testing.T) {
+	handler, mockService := setupTrashHandlerTest()
+	mockService.On("ReconcilePendingSongs", trashActor).Return(dto.ReconcileSongsResponse{Message: "Pending songs reconciled successfully", Completed: 2, RolledBack: 1}, nil)
+
+	c, w := newTrashTestContext(http.MethodPost, "/admin/trash/reconcile", nil)
+	handler.ReconcilePendingSongsHandler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response, err := DecodeJSONResponse[dto.ReconcileSongsResponse](w)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Completed)
+	assert.Equal(t, 1, response.RolledBack)
+	mockService.AssertExpectations(t)
+}
+
+func TestReconcilePendingSongsHandler_Failure(t *testing.T) {
+	handler, mockService := setupTrashHandlerTest()
+	mockService.On("ReconcilePendingSongs", trashActor).Return(dto.ReconcileSongsResponse{}, errors.ErrInternalServer)
+
+	c, w := newTrashTestContext(http.MethodPost, "/admin/trash/reconcile", nil)
+	handler.ReconcilePendingSongsHandler(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
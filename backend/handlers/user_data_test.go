@@ -0,0 +1,20 @@
+package handlers_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+var UserList = []dto.UserResponseItem{
+	{Username: "admin", Role: "admin", Status: "active"},
+	{Username: "lucia", Role: "editor", Status: "invited", InvitedBy: "admin"},
+}
+
+var InviteUserJSON = `{"username": "lucia", "role": "editor"}`
+var InviteUserMissingRoleJSON = `{"username": "lucia"}`
+var AcceptInviteJSON = `{"username": "lucia", "token": "tok", "password": "Tr0mbone-Solo"}`
+var AcceptInviteInvalidJSON = `{"username": `
+
+var InviteResponse = dto.InviteUserResponse{
+	Message:     "User invited successfully",
+	Username:    "lucia",
+	InviteToken: "tok",
+	ExpiresAt:   605800,
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// UserHandler handles HTTP requests related to back-office user management.
+// It delegates the business logic to the UserServiceInterface.
+type UserHandler struct {
+	userService services.UserServiceInterface
+}
+
+// NewUserHandler returns a new instance of UserHandler.
+func NewUserHandler(userService services.UserServiceInterface) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// ListUsersHandler handles GET /admin/users.
+// Returns every user with its role and status.
+func (h *UserHandler) ListUsersHandler(c *gin.Context) {
+	users, err := h.userService.ListUsers()
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to retrieve users")
+		return
+	}
+
+	logrus.WithField("users", len(users)).Debug("Fetched all users successfully")
+	c.JSON(http.StatusOK, gin.H{"data": users})
+}
+
+// InviteUserHandler handles POST /admin/users.
+// Creates an invited account and returns its one-time invitation token.
+func (h *UserHandler) InviteUserHandler(c *gin.Context) {
+	var req dto.InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	invite, err := h.userService.InviteUser(c.GetString("username"), req)
+	if err != nil {
+		errors.HandleAPIError(c, err, "Failed to invite user")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{"username": invite.Username, "role": req.Role}).Info("User invited successfully")
+	c.JSON(http.StatusCreated, invite)
+}
+
+// AcceptInviteHandler handles POST /auth/invite/accept.
+// Sets the password of an invited user and activates the account.
+func (h *UserHandler) AcceptInviteHandler(c *gin.Context) {
+	var req dto.AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid JSON payload")
+		return
+	}
+
+	if err := h.userService.AcceptInvite(req); err != nil {
+		errors.HandleAPIError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	logrus.WithField("username", req.Username).Info("Invitation accepted successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+}
+
+// DisableUserHandler handles POST /admin/users/:username/disable.
+func (h *UserHandler) DisableUserHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DisableUser(c.GetString("username"), username); err != nil {
+		errors.HandleAPIError(c, err, "Failed to disable user")
+		return
+	}
+
+	logrus.WithField("username", username).Info("User disabled successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "User disabled successfully"})
+}
+
+// EnableUserHandler handles POST /admin/users/:username/enable.
+func (h *UserHandler) EnableUserHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.EnableUser(c.GetString("username"), username); err != nil {
+		errors.HandleAPIError(c, err, "Failed to enable user")
+		return
+	}
+
+	logrus.WithField("username", username).Info("User enabled successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "User enabled successfully"})
+}
+
+// DeleteUserHandler handles DELETE /admin/users/:username.
+func (h *UserHandler) DeleteUserHandler(c *gin.Context) {
+	username, ok := utils.RequireParam(c, "username")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DeleteUser(c.GetString("username"), username); err != nil {
+		errors.HandleAPIError(c, err, "Failed to delete user")
+		return
+	}
+
+	logrus.WithField("username", username).Info("User deleted successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
@@ -0,0 +1,157 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupUserHandlerTest() (*handlers.UserHandler, *mocks.MockUserService) {
+	mockService := new(mocks.MockUserService)
+	handler := handlers.NewUserHandler(mockService)
+	return handler, mockService
+}
+
+func TestListUsersHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockUsers    []dto.UserResponseItem
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "returns users", mockUsers: UserList, expectedCode: http.StatusOK},
+		{name: "service error", mockErr: errors.ErrInternalServer, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupUserHandlerTest()
+			mockService.On("ListUsers").Return(tt.mockUsers, tt.mockErr)
+
+			c, w := utils.CreateTestContext(http.MethodGet, "/admin/users", nil)
+			handler.ListUsersHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				response, err := DecodeJSONResponse[struct {
+					Data []dto.UserResponseItem `json:"data"`
+				}](w)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockUsers, response.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInviteUserHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", input: InviteUserJSON, setupMock: true, expectedCode: http.StatusCreated},
+		{name: "missing role", input: InviteUserMissingRoleJSON, expectedCode: http.StatusBadRequest},
+		{name: "username taken", input: InviteUserJSON, setupMock: true, mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupUserHandlerTest()
+			if tt.setupMock {
+				req := dto.InviteUserRequest{Username: "lucia", Role: "editor"}
+				if tt.mockErr != nil {
+					mockService.On("InviteUser", "admin", req).Return(nil, tt.mockErr)
+				} else {
+					mockService.On("InviteUser", "admin", req).Return(&InviteResponse, nil)
+				}
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/admin/users", strings.NewReader(tt.input))
+			c.Set("username", "admin")
+			handler.InviteUserHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusCreated {
+				response, err := DecodeJSONResponse[dto.InviteUserResponse](w)
+				assert.NoError(t, err)
+				assert.Equal(t, InviteResponse, response)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAcceptInviteHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		setupMock    bool
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "success", input: AcceptInviteJSON, setupMock: true, expectedCode: http.StatusOK},
+		{name: "invalid JSON", input: AcceptInviteInvalidJSON, expectedCode: http.StatusBadRequest},
+		{name: "invalid token", input: AcceptInviteJSON, setupMock: true, mockErr: errors.ErrInvalidCredentials, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupUserHandlerTest()
+			if tt.setupMock {
+				mockService.On("AcceptInvite", dto.AcceptInviteRequest{Username: "lucia", Token: "tok", Password: "Tr0mbone-Solo"}).Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/auth/invite/accept", strings.NewReader(tt.input))
+			handler.AcceptInviteHandler(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserStatusHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		call         func(h *handlers.UserHandler, c *gin.Context)
+		param        string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "disable success", method: "DisableUser", call: (*handlers.UserHandler).DisableUserHandler, param: "pablo", expectedCode: http.StatusOK},
+		{name: "disable itself", method: "DisableUser", call: (*handlers.UserHandler).DisableUserHandler, param: "admin", mockErr: errors.ErrOperationNotAllowed, expectedCode: http.StatusForbidden},
+		{name: "enable success", method: "EnableUser", call: (*handlers.UserHandler).EnableUserHandler, param: "pablo", expectedCode: http.StatusOK},
+		{name: "delete success", method: "DeleteUser", call: (*handlers.UserHandler).DeleteUserHandler, param: "pablo", expectedCode: http.StatusOK},
+		{name: "delete not found", method: "DeleteUser", call: (*handlers.UserHandler).DeleteUserHandler, param: "nobody", mockErr: errors.ErrResourceNotFound, expectedCode: http.StatusNotFound},
+		{name: "missing username", method: "DeleteUser", call: (*handlers.UserHandler).DeleteUserHandler, param: "", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupUserHandlerTest()
+			if tt.param != "" {
+				mockService.On(tt.method, "admin", tt.param).Return(tt.mockErr)
+			}
+
+			c, w := utils.CreateTestContext(http.MethodPost, "/admin/users/"+tt.param, nil)
+			c.Params = []gin.Param{{Key: "username", Value: tt.param}}
+			c.Set("username", "admin")
+			tt.call(handler, c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	IntegrationTestSuite
+	adminToken string
+}
+
+func (s *APIKeyTestSuite) SetupTest() {
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+}
+
+func (s *APIKeyTestSuite) createKey(req dto.CreateAPIKeyRequest) dto.CreateAPIKeyResponse {
+	body, err := json.Marshal(req)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/admin/api-keys", bytes.NewReader(body), s.adminToken)
+	s.Require().Equal(http.StatusCreated, res.Code)
+
+	var created dto.CreateAPIKeyResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&created))
+	s.Require().True(strings.HasPrefix(created.Key, "rk_"))
+	return created
+}
+
+func (s *APIKeyTestSuite) TestAPIKey_ShouldBeLimitedToItsScopes() {
+	key := s.createKey(dto.CreateAPIKeyRequest{Name: "nightly import", Scopes: []string{models.ScopeSongsWrite}})
+
+	body, err := json.Marshal(WeAreTheChampionsPayload)
+	s.Require().NoError(err)
+	res := MakeAPIKeyRequest(s.Router, "POST", "/songs", bytes.NewReader(body), key.Key)
+	s.Require().Equal(http.StatusCreated, res.Code)
+
+	var song dto.CreateSongResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&song))
+
+	docBody, err := json.Marshal(BohemianDocs[0])
+	s.Require().NoError(err)
+	res = MakeAPIKeyRequest(s.Router, "POST", "/songs/"+song.SongID+"/documents", bytes.NewReader(docBody), key.Key)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeAPIKeyRequest(s.Router, "GET", "/admin/users", nil, key.Key)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func (s *APIKeyTestSuite) TestAPIKey_ShouldRecordLastUseAndStopWorkingOnceRevoked() {
+	key := s.createKey(dto.CreateAPIKeyRequest{Name: "short-lived", Scopes: []string{models.ScopeSongsWrite}, ExpiresInDays: 1})
+
+	res := MakeAPIKeyRequest(s.Router, "DELETE", "/songs/does-not-exist", nil, key.Key)
+	s.NotEqual(http.StatusUnauthorized, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/admin/api-keys", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var list struct {
+		Data []dto.APIKeyResponseItem `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&list))
+	var listed *dto.APIKeyResponseItem
+	for i := range list.Data {
+		if list.Data[i].ID == key.ID {
+			listed = &list.Data[i]
+		}
+	}
+	s.Require().NotNil(listed)
+	s.NotEmpty(listed.LastUsedAt)
+	s.NotZero(listed.ExpiresAt)
+	s.NotContains(res.Body.String(), key.Key)
+
+	res = MakeRequest(s.Router, "DELETE", "/admin/api-keys/"+key.ID, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeAPIKeyRequest(s.Router, "DELETE", "/songs/does-not-exist", nil, key.Key)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *APIKeyTestSuite) TestAPIKey_ShouldRejectUnknownKey() {
+	res := MakeAPIKeyRequest(s.Router, "POST", "/songs", strings.NewReader("{}"), "rk_unknown.secret")
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *APIKeyTestSuite) TestAPIKey_ShouldNotManageKeys() {
+	key := s.createKey(dto.CreateAPIKeyRequest{Name: "genres", Scopes: []string{models.ScopeGenresWrite}})
+
+	body, err := json.Marshal(dto.CreateAPIKeyRequest{Name: "escalation", Scopes: []string{models.ScopeSongsWrite}})
+	s.Require().NoError(err)
+	res := MakeAPIKeyRequest(s.Router, "POST", "/admin/api-keys", bytes.NewReader(body), key.Key)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func TestAPIKeySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
@@ -0,0 +1,114 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	IntegrationTestSuite
+	adminToken  string
+	editorToken string
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+
+	token, err = GenerateTestJWTWithRole("maria", models.RoleEditor)
+	s.Require().NoError(err)
+	s.editorToken = token
+}
+
+func (s *AuditTestSuite) listAudit(query string) []dto.AuditEntryResponseItem {
+	return s.listAuditPage(query).Data
+}
+
+func (s *AuditTestSuite) listAuditPage(query string) dto.AuditListResponse {
+	res := MakeRequest(s.Router, "GET", "/admin/audit"+query, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var page dto.AuditListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&page))
+	return page
+}
+
+func (s *AuditTestSuite) TestAudit_ShouldRecordEveryChangeOfASong() {
+	body, err := json.Marshal(BohemianRhapsodyPayload)
+	s.Require().NoError(err)
+	req := httptest.NewRequest("POST", "/songs", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+s.editorToken)
+	req.Header.Set("X-Request-ID", "import-42")
+	res := httptest.NewRecorder()
+	s.Router.ServeHTTP(res, req)
+	s.Require().Equal(http.StatusCreated, res.Code)
+	s.Equal("import-42", res.Header().Get("X-Request-ID"))
+
+	var created dto.CreateSongResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&created))
+
+	author := "Freddie Mercury"
+	update, err := json.Marshal(dto.UpdateSongRequest{Author: &author})
+	s.Require().NoError(err)
+	res = MakeRequest(s.Router, "PUT", "/songs/"+created.SongID, bytes.NewReader(update), s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.NotEmpty(res.Header().Get("X-Request-ID"))
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/"+created.SongID, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	entries := s.listAudit("?entity=song")
+	s.Require().Len(entries, 3)
+	actions := map[string]dto.AuditEntryResponseItem{}
+	for _, entry := range entries {
+		s.Equal(created.SongID, entry.EntityID)
+		actions[entry.Action] = entry
+	}
+
+	s.Equal("maria", actions[models.AuditActionCreate].Actor)
+	s.Equal("import-42", actions[models.AuditActionCreate].RequestID)
+	s.Equal([]dto.FieldChangeResponseItem{{Field: "author", Before: "Queen", After: author}}, actions[models.AuditActionUpdate].Changes)
+	s.Equal(ValidLogin.Username, actions[models.AuditActionDelete].Actor)
+
+	s.Len(s.listAudit("?actor=maria"), 2)
+	s.Empty(s.listAudit("?entity=document"))
+	s.Empty(s.listAudit("?since=2999-01-01T00:00:00Z"))
+
+	var paged []string
+	page := s.listAuditPage("?limit=2")
+	for {
+		for _, entry := range page.Data {
+			paged = append(paged, entry.ID)
+		}
+		if page.NextToken == "" {
+			break
+		}
+		page = s.listAuditPage("?limit=2&cursor=" + page.NextToken)
+	}
+	var all []string
+	for _, entry := range s.listAudit("") {
+		all = append(all, entry.ID)
+	}
+	s.Equal(all, paged)
+	s.Len(paged, 3)
+}
+
+func (s *AuditTestSuite) TestAudit_ShouldBeReservedToAdmins() {
+	res := MakeRequest(s.Router, "GET", "/admin/audit", nil, s.editorToken)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/admin/audit?since=yesterday", nil, s.adminToken)
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func TestAuditSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
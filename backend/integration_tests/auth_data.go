@@ -18,3 +18,6 @@ var InvalidPasswordLogin = dto.LoginRequest{
 }
 
 var InvalidJSONLogin = `{"username":`
+
+const SeededEditor = "editor-ana"
+const SeededEditorPassword = "editorpass"
@@ -7,7 +7,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -79,6 +82,35 @@ func (s *AuthTestSuite) TestMe_ShouldReturn401_WithInvalidToken() {
 	s.Equal(http.StatusUnauthorized, res.Code)
 }
 
+func (s *AuthTestSuite) TestMe_ShouldReturn401_WithWrongSignature() {
+	token, err := GenerateTestJWTWithSecret(ValidLogin.Username, "not-the-configured-secret")
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *AuthTestSuite) TestMe_ShouldReturn401_WithUnexpectedAudience() {
+	keys, err := bootstrap.LoadJWTKeySet(s.TimeProvider)
+	s.Require().NoError(err)
+	keys.Audience = "another-api"
+
+	token, err := (&utils.JWTTokenGenerator{Keys: keys}).GenerateToken(testClaims(ValidLogin.Username, "admin"))
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *AuthTestSuite) TestMe_ShouldReturn401_WithUnsignedToken() {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims(ValidLogin.Username, "admin")).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
 func TestAuthSuite(t *testing.T) {
 	suite.Run(t, new(AuthTestSuite))
 }
@@ -0,0 +1,86 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthorizationTestSuite struct {
+	IntegrationTestSuite
+}
+
+func (s *AuthorizationTestSuite) tokenFor(role string) string {
+	token, err := GenerateTestJWTWithRole(role+"-user", role)
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *AuthorizationTestSuite) TestEditor_CanCreateAndUpdateSongButNotDelete() {
+	token := s.tokenFor(models.RoleEditor)
+
+	body, err := json.Marshal(WeAreTheChampionsPayload)
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/songs", bytes.NewReader(body), token)
+	s.Require().Equal(http.StatusCreated, res.Code)
+
+	var created dto.CreateSongResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&created))
+
+	update, err := json.Marshal(dto.UpdateSongRequest{Author: &WeAreTheChampionsPayload.Author})
+	s.Require().NoError(err)
+	res = MakeRequest(s.Router, "PUT", "/songs/"+created.SongID, bytes.NewReader(update), token)
+	s.Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/"+created.SongID, nil, token)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/"+created.SongID, nil, s.tokenFor(models.RoleAdmin))
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *AuthorizationTestSuite) TestEditor_CannotManageUsers() {
+	res := MakeRequest(s.Router, "GET", "/admin/users", nil, s.tokenFor(models.RoleEditor))
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func (s *AuthorizationTestSuite) TestEditor_CannotManageGenres() {
+	body, err := json.Marshal(dto.CreateGenreRequest{Name: "Flamenco"})
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/genres", bytes.NewReader(body), s.tokenFor(models.RoleEditor))
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeRequest(s.Router, "POST", "/genres", bytes.NewReader(body), s.tokenFor(models.RoleAdmin))
+	s.Equal(http.StatusCreated, res.Code)
+}
+
+func (s *AuthorizationTestSuite) TestViewer_CannotModifyContent() {
+	token := s.tokenFor(models.RoleViewer)
+
+	body, err := json.Marshal(WeAreTheChampionsPayload)
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/songs", bytes.NewReader(body), token)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	doc, err := json.Marshal(ViolinScore)
+	s.Require().NoError(err)
+	res = MakeRequest(s.Router, "POST", "/songs/queen-001/documents", bytes.NewReader(doc), token)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *AuthorizationTestSuite) TestUnknownRole_IsDenied() {
+	res := MakeRequest(s.Router, "DELETE", "/songs/queen-001", nil, s.tokenFor("owner"))
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func TestAuthorizationSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
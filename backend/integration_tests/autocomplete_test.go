@@ -0,0 +1,59 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/stretchr/testify/suite"
+)
+
+type AutocompleteTestSuite struct {
+	IntegrationTestSuite
+}
+
+type suggestionListResponse struct {
+	Data []dto.Suggestion `json:"data"`
+}
+
+func (s *AutocompleteTestSuite) TestAutocomplete_SongTitles() {
+	res := MakeRequest(s.Router, "GET", "/autocomplete?q=boh&type=song", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var body suggestionListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	s.Require().Len(body.Data, 1)
+	s.Equal("Bohemian Rhapsody", body.Data[0].Value)
+}
+
+func (s *AutocompleteTestSuite) TestAutocomplete_ReflectsNewSong() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "GET", "/autocomplete?q=we+are&type=song", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	body, err := json.Marshal(WeAreTheChampionsPayload)
+	s.Require().NoError(err)
+	createRes := MakeRequest(s.Router, "POST", "/songs", bytes.NewReader(body), token)
+	s.Require().Equal(http.StatusCreated, createRes.Code)
+
+	res = MakeRequest(s.Router, "GET", "/autocomplete?q=we+are&type=song", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var suggestions suggestionListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&suggestions))
+	s.Require().NotEmpty(suggestions.Data)
+	s.Equal(WeAreTheChampionsPayload.Title, suggestions.Data[0].Value)
+}
+
+func (s *AutocompleteTestSuite) TestAutocomplete_InvalidType() {
+	res := MakeRequest(s.Router, "GET", "/autocomplete?q=boh&type=album", nil, "")
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func TestAutocompleteSuite(t *testing.T) {
+	suite.Run(t, new(AutocompleteTestSuite))
+}
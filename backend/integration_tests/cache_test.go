@@ -0,0 +1,84 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/app"
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// CacheTestSuite runs the application with the song and document cache enabled.
+type CacheTestSuite struct {
+	IntegrationTestSuite
+	CachedRouter *gin.Engine
+	token        string
+}
+
+func (s *CacheTestSuite) SetupSuite() {
+	s.IntegrationTestSuite.SetupSuite()
+
+	keys, err := bootstrap.LoadJWTKeySet(s.TimeProvider)
+	s.Require().NoError(err)
+	s.CachedRouter = app.InitApp(s.DB, app.AppConfig{
+		JWTKeys:         keys,
+		EnableRecovery:  true,
+		CacheMaxEntries: 100,
+		CacheTTL:        time.Minute,
+	})
+}
+
+func (s *CacheTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.token = token
+}
+
+func (s *CacheTestSuite) stats() dto.CacheStatsResponse {
+	res := MakeRequest(s.CachedRouter, "GET", "/admin/cache", nil, s.token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data dto.CacheStatsResponse `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(res.Body.Bytes(), &body))
+	return body.Data
+}
+
+func (s *CacheTestSuite) TestSongReads_ShouldBeServedFromCacheUntilAWrite() {
+	before := s.stats()
+	s.True(before.Enabled)
+
+	for i := 0; i < 3; i++ {
+		res := MakeRequest(s.CachedRouter, "GET", "/songs/queen-001", nil, "")
+		s.Require().Equal(http.StatusOK, res.Code)
+	}
+	after := s.stats()
+	s.Equal(before.Misses+1, after.Misses)
+	s.Equal(before.Hits+2, after.Hits)
+
+	res := MakeRequest(s.CachedRouter, "PUT", "/songs/queen-001", strings.NewReader(`{"author":"Queen (remastered)"}`), s.token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.CachedRouter, "GET", "/songs/queen-001", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Contains(res.Body.String(), "Queen (remastered)", "writes must invalidate the cached song")
+}
+
+func (s *CacheTestSuite) TestCacheStats_ShouldRequireAdmin() {
+	res := MakeRequest(s.CachedRouter, "GET", "/admin/cache", nil, "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
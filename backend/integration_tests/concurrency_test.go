@@ -0,0 +1,73 @@
+package integration_tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConcurrencyTestSuite struct {
+	IntegrationTestSuite
+	adminToken  string
+	editorToken string
+}
+
+func (s *ConcurrencyTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+
+	token, err = GenerateTestJWTWithRole("maria", models.RoleEditor)
+	s.Require().NoError(err)
+	s.editorToken = token
+}
+
+func (s *ConcurrencyTestSuite) TestSongUpdate_ShouldRejectStaleETag() {
+	res := MakeRequest(s.Router, "GET", "/songs/queen-001", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	etag := res.Header().Get("ETag")
+	s.Equal(`"0"`, etag, "songs stored before versioning start at version 0")
+
+	res = MakeConditionalRequest(s.Router, "PUT", "/songs/queen-001", strings.NewReader(`{"author":"Queen (remastered)"}`), s.editorToken, etag)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(`"1"`, res.Header().Get("ETag"))
+
+	res = MakeConditionalRequest(s.Router, "PUT", "/songs/queen-001", strings.NewReader(`{"author":"Freddie Mercury"}`), s.editorToken, etag)
+	s.Equal(http.StatusPreconditionFailed, res.Code, "a second editor working from the old version must not overwrite the change")
+
+	res = MakeConditionalRequest(s.Router, "DELETE", "/songs/queen-001", nil, s.adminToken, etag)
+	s.Equal(http.StatusPreconditionFailed, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(`"1"`, res.Header().Get("ETag"))
+	s.Contains(res.Body.String(), "Queen (remastered)")
+}
+
+func (s *ConcurrencyTestSuite) TestDocumentUpdate_ShouldRejectStaleETag() {
+	res := MakeConditionalRequest(s.Router, "PUT", "/songs/queen-001/documents/doc-br-piano", strings.NewReader(`{"type":"tablature"}`), s.editorToken, `"0"`)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(`"1"`, res.Header().Get("ETag"))
+
+	res = MakeConditionalRequest(s.Router, "DELETE", "/songs/queen-001/documents/doc-br-piano", nil, s.editorToken, `"0"`)
+	s.Equal(http.StatusPreconditionFailed, res.Code)
+
+	res = MakeConditionalRequest(s.Router, "DELETE", "/songs/queen-001/documents/doc-br-piano", nil, s.editorToken, `"1"`)
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *ConcurrencyTestSuite) TestWrites_WithoutIfMatchStillSucceed() {
+	res := MakeRequest(s.Router, "PUT", "/songs/queen-001", strings.NewReader(`{"author":"Queen"}`), s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(`"1"`, res.Header().Get("ETag"))
+}
+
+func TestConcurrencySuite(t *testing.T) {
+	suite.Run(t, new(ConcurrencyTestSuite))
+}
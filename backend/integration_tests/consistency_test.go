@@ -0,0 +1,90 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsistencyTestSuite struct {
+	IntegrationTestSuite
+	adminToken string
+}
+
+func (s *ConsistencyTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+}
+
+func (s *ConsistencyTestSuite) titleReport(method, path string) dto.TitleConsistencyReport {
+	res := MakeRequest(s.Router, method, path, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data dto.TitleConsistencyReport `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	return body.Data
+}
+
+func (s *ConsistencyTestSuite) TestConsistency_RenamePropagatesToDocuments() {
+	title := "Bohemian Rhapsody (Live Aid)"
+	body, err := json.Marshal(dto.UpdateSongRequest{Title: &title})
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "PUT", "/songs/queen-001", bytes.NewReader(body), s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/documents/search?title=live%20aid", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var found struct {
+		Data       []dto.DocumentResponseItem `json:"data"`
+		Highlights map[string][]dto.Highlight `json:"highlights"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&found))
+	s.Len(found.Data, 2, "both documents are found by the new title")
+	for _, doc := range found.Data {
+		s.Equal(title, doc.Title)
+		s.Equal([]dto.Highlight{{Field: dto.HighlightFieldTitle, Start: 19, End: 27}}, found.Highlights[doc.ID])
+	}
+
+	report := s.titleReport("GET", "/admin/consistency/titles")
+	s.Equal(3, report.Checked)
+	s.Empty(report.Drifted)
+}
+
+func (s *ConsistencyTestSuite) TestConsistency_ReportsAndRepairsDrift() {
+	s.Require().NoError(s.DB.Table(bootstrap.DocumentTableName).
+		Update("song_id", "queen-001").
+		Range("id", "doc-br-voice").
+		Set("title_normalized", "bohemian rhapsody (old)").
+		Run())
+
+	report := s.titleReport("GET", "/admin/consistency/titles")
+	s.Require().Len(report.Drifted, 1)
+	s.Equal(dto.TitleDrift{
+		SongID:     "queen-001",
+		DocumentID: "doc-br-voice",
+		Expected:   "bohemian rhapsody",
+		Actual:     "bohemian rhapsody (old)",
+	}, report.Drifted[0])
+	s.Zero(report.Repaired)
+
+	report = s.titleReport("POST", "/admin/consistency/titles/repair")
+	s.Equal(1, report.Repaired)
+
+	report = s.titleReport("GET", "/admin/consistency/titles")
+	s.Empty(report.Drifted)
+}
+
+func TestConsistencySuite(t *testing.T) {
+	suite.Run(t, new(ConsistencyTestSuite))
+}
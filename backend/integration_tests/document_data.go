@@ -27,11 +27,25 @@ var FluteScore = dto.CreateDocumentRequest{
 // Valid document update
 var TablatureUpdate = dto.UpdateDocumentRequest{
 	Type:       "tablature",
-	Instrument: []string{"guitar", "lead"},
+	Instrument: []string{"guitar", "electric_guitar"},
 	PDFURL:     "https://test-updated.com/guitar-lead.pdf",
 	AudioURL:   "https://test-updated.com/audio.mp3",
 }
 
+// Instruments given by localized name and alias, stored as canonical IDs
+var AliasedInstrumentScore = dto.CreateDocumentRequest{
+	Type:       "score",
+	Instrument: []string{"Violín", "Chelo"},
+	PDFURL:     "https://s3.test/strings.pdf",
+}
+
+// Instrument outside the catalogue
+var UnknownInstrumentScore = dto.CreateDocumentRequest{
+	Type:       "score",
+	Instrument: []string{"theremin"},
+	PDFURL:     "https://s3.test/theremin.pdf",
+}
+
 // Malformed JSON
 var InvalidJSONDocument = `{"type":`
 
@@ -42,6 +42,28 @@ func (s *DocumentTestSuite) TestGetDocumentsBySongID_ShouldReturnEmptyList() {
 	s.Empty(response.Data)
 }
 
+func (s *DocumentTestSuite) TestGetDocumentsBySongID_ShouldPageWithNextToken() {
+	w := MakeRequest(s.Router, "GET", "/songs/queen-001/documents?limit=1&sort=created_at", nil, "")
+	s.Equal(http.StatusOK, w.Code)
+
+	var first dto.DocumentListResponse
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&first))
+	s.Equal(1, first.Count)
+	s.Require().NotEmpty(first.NextToken)
+
+	w = MakeRequest(s.Router, "GET", "/songs/queen-001/documents?limit=1&sort=created_at&cursor="+first.NextToken, nil, "")
+	s.Equal(http.StatusOK, w.Code)
+
+	var second dto.DocumentListResponse
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&second))
+	s.Equal(1, second.Count)
+	s.Empty(second.NextToken)
+	s.NotEqual(first.Data[0].ID, second.Data[0].ID)
+
+	w = MakeRequest(s.Router, "GET", "/songs/queen-001/documents?limit=1&sort=type&cursor="+first.NextToken, nil, "")
+	s.Equal(http.StatusBadRequest, w.Code, "a cursor only continues the sort it was issued for")
+}
+
 func (s *DocumentTestSuite) TestGetDocumentByID_ShouldReturnSeededDocument() {
 	w := MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
 	s.Equal(http.StatusOK, w.Code)
@@ -77,6 +99,40 @@ func (s *DocumentTestSuite) TestCreateDocument_ShouldSucceedWithJWT() {
 	s.NotEmpty(res.DocumentID)
 }
 
+func (s *DocumentTestSuite) TestCreateDocument_ShouldStoreCanonicalInstruments() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(AliasedInstrumentScore)
+	s.Require().NoError(err)
+
+	w := MakeRequest(s.Router, "POST", "/songs/queen-001/documents", bytes.NewReader(body), token)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	var res struct {
+		DocumentID string `json:"document_id"`
+	}
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&res))
+
+	getRes := MakeRequest(s.Router, "GET", "/songs/queen-001/documents/"+res.DocumentID, nil, "")
+	s.Equal(http.StatusOK, getRes.Code)
+
+	var getBody DocumentDetailResponse
+	s.Require().NoError(json.NewDecoder(getRes.Body).Decode(&getBody))
+	s.Equal([]string{"violin", "cello"}, getBody.Data.Instrument)
+}
+
+func (s *DocumentTestSuite) TestCreateDocument_ShouldReturn400ForUnknownInstrument() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(UnknownInstrumentScore)
+	s.Require().NoError(err)
+
+	w := MakeRequest(s.Router, "POST", "/songs/queen-001/documents", bytes.NewReader(body), token)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (s *DocumentTestSuite) TestCreateDocument_ShouldReturn401WithoutToken() {
 
 	body, err := json.Marshal(FluteScore)
@@ -0,0 +1,138 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/stretchr/testify/suite"
+)
+
+type GenreTestSuite struct {
+	IntegrationTestSuite
+}
+
+type genreListResponse struct {
+	Data []dto.GenreResponseItem `json:"data"`
+}
+
+func (s *GenreTestSuite) getSong(id string) dto.SongResponseItem {
+	res := MakeRequest(s.Router, "GET", "/songs/"+id, nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data dto.SongResponseItem `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	return body.Data
+}
+
+func (s *GenreTestSuite) TestListGenres_ShouldReturnSeededHierarchy() {
+	res := MakeRequest(s.Router, "GET", "/genres", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var body genreListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	s.Contains(body.Data, dto.GenreResponseItem{ID: "genre-progressive", Name: "Progressive", ParentID: "genre-rock"})
+}
+
+func (s *GenreTestSuite) TestCreateGenre_ShouldSucceedUnderParent() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(dto.CreateGenreRequest{Name: "Glam Rock", ParentID: "genre-rock"})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/genres", bytes.NewReader(body), token)
+	s.Equal(http.StatusCreated, res.Code)
+}
+
+func (s *GenreTestSuite) TestCreateGenre_ShouldReturn400ForDuplicateName() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(dto.CreateGenreRequest{Name: "ÓPERA"})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/genres", bytes.NewReader(body), token)
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func (s *GenreTestSuite) TestCreateGenre_ShouldReturn401WithoutToken() {
+	body, err := json.Marshal(dto.CreateGenreRequest{Name: "Jazz"})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/genres", bytes.NewReader(body), "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *GenreTestSuite) TestRenameGenre_ShouldRewriteSongs() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	name := "Pop Music"
+	body, err := json.Marshal(dto.UpdateGenreRequest{Name: &name})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "PUT", "/genres/genre-pop", bytes.NewReader(body), token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var job dto.GenreJobResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&job))
+	s.Equal(1, job.SongsUpdated)
+
+	s.ElementsMatch([]string{"Rock", "Pop Music"}, s.getSong("queen-002").Genres)
+
+	res = MakeRequest(s.Router, "GET", "/admin/audit?entity=song&actor=admin", nil, token)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var audit dto.AuditListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&audit))
+	var rewrites []dto.FieldChangeResponseItem
+	for _, entry := range audit.Data {
+		if entry.EntityID == "queen-002" {
+			rewrites = append(rewrites, entry.Changes...)
+		}
+	}
+	s.Contains(rewrites, dto.FieldChangeResponseItem{
+		Field:  "genres",
+		Before: []interface{}{"Rock", "Pop"},
+		After:  []interface{}{"Rock", "Pop Music"},
+	})
+}
+
+func (s *GenreTestSuite) TestMergeGenres_ShouldRewriteSongsAndRemoveSource() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(dto.MergeGenreRequest{TargetID: "genre-rock"})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/genres/genre-progressive/merge", bytes.NewReader(body), token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	s.Equal([]string{"Rock"}, s.getSong("queen-001").Genres)
+
+	listRes := MakeRequest(s.Router, "GET", "/genres", nil, "")
+	var list genreListResponse
+	s.Require().NoError(json.NewDecoder(listRes.Body).Decode(&list))
+	for _, g := range list.Data {
+		s.NotEqual("genre-progressive", g.ID)
+	}
+}
+
+func (s *GenreTestSuite) TestCreateSong_ShouldReturn400ForUnknownGenre() {
+	token, err := GenerateTestJWT("admin")
+	s.Require().NoError(err)
+
+	body, err := json.Marshal(dto.CreateSongRequest{Title: "Unknown Genre Song", Author: "Nobody", Genres: []string{"Polka"}})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/songs", bytes.NewReader(body), token)
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
+func TestGenreSuite(t *testing.T) {
+	suite.Run(t, new(GenreTestSuite))
+}
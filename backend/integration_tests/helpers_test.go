@@ -16,3 +16,32 @@ func MakeRequest(router *gin.Engine, method, path string, body io.Reader, token
 	router.ServeHTTP(w, req)
 	return w
 }
+
+// MakeAPIKeyRequest sends a request authenticated with an API key instead of a Bearer token.
+func MakeAPIKeyRequest(router *gin.Engine, method, path string, body io.Reader, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// MakeConditionalRequest sends a Bearer-authenticated request with an If-Match header.
+func MakeConditionalRequest(router *gin.Engine, method, path string, body io.Reader, token, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("If-Match", ifMatch)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// MakeIdempotentRequest sends a Bearer-authenticated request with an Idempotency-Key header.
+func MakeIdempotentRequest(router *gin.Engine, method, path string, body io.Reader, token, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
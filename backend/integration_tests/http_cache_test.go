@@ -0,0 +1,74 @@
+package integration_tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPCacheTestSuite struct {
+	IntegrationTestSuite
+	token string
+}
+
+func (s *HTTPCacheTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.token = token
+}
+
+func (s *HTTPCacheTestSuite) revalidate(path, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set(header, value)
+	w := httptest.NewRecorder()
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *HTTPCacheTestSuite) TestSongList_ShouldRevalidateUntilASongChanges() {
+	res := MakeRequest(s.Router, "GET", "/songs", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	etag := res.Header().Get("ETag")
+	s.Require().NotEmpty(etag)
+	s.NotEmpty(res.Header().Get("Last-Modified"))
+	s.Equal("public, max-age=60, must-revalidate", res.Header().Get("Cache-Control"))
+
+	res = s.revalidate("/songs", "If-None-Match", etag)
+	s.Equal(http.StatusNotModified, res.Code)
+	s.Empty(res.Body.String())
+
+	res = MakeRequest(s.Router, "PUT", "/songs/queen-001", strings.NewReader(`{"author":"Queen (remastered)"}`), s.token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = s.revalidate("/songs", "If-None-Match", etag)
+	s.Equal(http.StatusOK, res.Code)
+	s.NotEqual(etag, res.Header().Get("ETag"))
+	s.Contains(res.Body.String(), "Queen (remastered)")
+}
+
+func (s *HTTPCacheTestSuite) TestDocumentList_ShouldRevalidateWhenADocumentIsDeleted() {
+	res := MakeRequest(s.Router, "GET", "/songs/queen-001/documents", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	etag := res.Header().Get("ETag")
+	lastModified := res.Header().Get("Last-Modified")
+
+	res = s.revalidate("/songs/queen-001/documents", "If-Modified-Since", lastModified)
+	s.Equal(http.StatusNotModified, res.Code)
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/queen-001/documents/doc-br-voice", nil, s.token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = s.revalidate("/songs/queen-001/documents", "If-None-Match", etag)
+	s.Equal(http.StatusOK, res.Code, "the ETag covers documents leaving the list, which Last-Modified cannot")
+	s.NotContains(res.Body.String(), "doc-br-voice")
+}
+
+func TestHTTPCacheSuite(t *testing.T) {
+	suite.Run(t, new(HTTPCacheTestSuite))
+}
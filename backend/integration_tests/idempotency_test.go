@@ -0,0 +1,98 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyTestSuite struct {
+	IntegrationTestSuite
+	token string
+}
+
+func (s *IdempotencyTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.token = token
+}
+
+func (s *IdempotencyTestSuite) createSong(payload dto.CreateSongRequest, key string) (int, string, http.Header) {
+	body, err := json.Marshal(payload)
+	s.Require().NoError(err)
+
+	res := MakeIdempotentRequest(s.Router, "POST", "/songs", bytes.NewReader(body), s.token, key)
+	var created dto.CreateSongResponse
+	_ = json.Unmarshal(res.Body.Bytes(), &created)
+	return res.Code, created.SongID, res.Header()
+}
+
+func (s *IdempotencyTestSuite) countSongsTitled(title string) int {
+	var songs []models.Song
+	s.Require().NoError(s.DB.Table(bootstrap.SongTableName).Scan().Filter("title = ?", title).All(&songs))
+	return len(songs)
+}
+
+func (s *IdempotencyTestSuite) TestIdempotency_RetryReplaysTheFirstResponse() {
+	code, songID, _ := s.createSong(WeAreTheChampionsPayload, "retry-key-1")
+	s.Require().Equal(http.StatusCreated, code)
+
+	code, replayedID, header := s.createSong(WeAreTheChampionsPayload, "retry-key-1")
+	s.Equal(http.StatusCreated, code)
+	s.Equal(songID, replayedID)
+	s.Equal("true", header.Get("Idempotent-Replayed"))
+	s.Equal(1, s.countSongsTitled(WeAreTheChampionsPayload.Title), "the retry does not create a second song")
+
+	code, otherID, _ := s.createSong(WeAreTheChampionsPayload, "retry-key-2")
+	s.Equal(http.StatusCreated, code, "another key creates another song")
+	s.NotEqual(songID, otherID)
+}
+
+func (s *IdempotencyTestSuite) TestIdempotency_KeyReusedForAnotherPayloadConflicts() {
+	code, _, _ := s.createSong(WeAreTheChampionsPayload, "reused-key")
+	s.Require().Equal(http.StatusCreated, code)
+
+	code, _, _ = s.createSong(DontStopMeNowPayload, "reused-key")
+	s.Equal(http.StatusConflict, code)
+}
+
+func (s *IdempotencyTestSuite) TestIdempotency_FailedAttemptDoesNotKeepTheKey() {
+	code, _, _ := s.createSong(dto.CreateSongRequest{}, "fixed-key")
+	s.Require().Equal(http.StatusBadRequest, code)
+
+	code, songID, header := s.createSong(WeAreTheChampionsPayload, "fixed-key")
+	s.Equal(http.StatusCreated, code)
+	s.NotEmpty(songID)
+	s.Empty(header.Get("Idempotent-Replayed"))
+}
+
+func (s *IdempotencyTestSuite) TestIdempotency_KeysAreScopedToTheUser() {
+	code, songID, _ := s.createSong(WeAreTheChampionsPayload, "shared-key")
+	s.Require().Equal(http.StatusCreated, code)
+
+	token, err := GenerateTestJWTWithRole("maria", models.RoleEditor)
+	s.Require().NoError(err)
+	s.token = token
+
+	code, otherID, _ := s.createSong(WeAreTheChampionsPayload, "shared-key")
+	s.Equal(http.StatusCreated, code)
+	s.NotEqual(songID, otherID)
+}
+
+func (s *IdempotencyTestSuite) TestIdempotency_InvalidKeyIsRejected() {
+	code, _, _ := s.createSong(WeAreTheChampionsPayload, "not a valid key")
+	s.Equal(http.StatusBadRequest, code)
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/guregu/dynamo"
@@ -16,7 +17,7 @@ func CreateTestTables(db *dynamo.DB) error {
 		logrus.Fatal("Failed to assert Dynamo client as *dynamodb.DynamoDB")
 	}
 
-	for _, table := range []string{bootstrap.SongTableName, bootstrap.DocumentTableName} {
+	for _, table := range []string{bootstrap.SongTableName, bootstrap.DocumentTableName, bootstrap.GenreTableName, bootstrap.UserTableName, bootstrap.SessionTableName, bootstrap.LoginAttemptTableName, bootstrap.APIKeyTableName, bootstrap.OIDCLoginTableName, bootstrap.AuditTableName, bootstrap.RevisionTableName, bootstrap.IdempotencyTableName} {
 		_, err := svc.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(table)})
 		if err != nil {
 			logrus.WithError(err).Warnf("Could not delete table %s", table)
@@ -31,6 +32,42 @@ func CreateTestTables(db *dynamo.DB) error {
 		return err
 	}
 
+	if err := createGenresTable(svc); err != nil {
+		return err
+	}
+
+	if err := createUsersTable(svc); err != nil {
+		return err
+	}
+
+	if err := createSessionsTable(svc); err != nil {
+		return err
+	}
+
+	if err := createLoginAttemptsTable(svc); err != nil {
+		return err
+	}
+
+	if err := createAPIKeysTable(svc); err != nil {
+		return err
+	}
+
+	if err := createOIDCLoginsTable(svc); err != nil {
+		return err
+	}
+
+	if err := createAuditTable(svc); err != nil {
+		return err
+	}
+
+	if err := createRevisionsTable(svc); err != nil {
+		return err
+	}
+
+	if err := createIdempotencyTable(svc); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -82,6 +119,230 @@ func createDocumentsTable(svc *dynamodb.DynamoDB) error {
 	return waitForTableToBeActive(svc, bootstrap.DocumentTableName)
 }
 
+func createGenresTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.GenreTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create GenresTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.GenreTableName)
+}
+
+func createUsersTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.UserTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("username"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("username"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create UsersTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.UserTableName)
+}
+
+func createSessionsTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.SessionTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create SessionsTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.SessionTableName)
+}
+
+func createLoginAttemptsTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.LoginAttemptTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create LoginAttemptsTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.LoginAttemptTableName)
+}
+
+func createAPIKeysTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.APIKeyTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create APIKeysTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.APIKeyTableName)
+}
+
+func createOIDCLoginsTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.OIDCLoginTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create OIDCLoginsTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.OIDCLoginTableName)
+}
+
+func createAuditTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.AuditTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("entity"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("timestamp"), AttributeType: aws.String("S")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(repository.AuditTimestampIndex),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("entity"), KeyType: aws.String("HASH")},
+					{AttributeName: aws.String("timestamp"), KeyType: aws.String("RANGE")},
+				},
+				Projection: &dynamodb.Projection{ProjectionType: aws.String(dynamodb.ProjectionTypeAll)},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+				},
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create AuditTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.AuditTableName)
+}
+
+func createRevisionsTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.RevisionTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("song_id"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("revision"), KeyType: aws.String("RANGE")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("song_id"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("revision"), AttributeType: aws.String("N")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create RevisionsTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.RevisionTableName)
+}
+
+func createIdempotencyTable(svc *dynamodb.DynamoDB) error {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(bootstrap.IdempotencyTableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5),
+		},
+	}
+
+	_, err := svc.CreateTable(input)
+	if err != nil && !strings.Contains(err.Error(), dynamodb.ErrCodeResourceInUseException) {
+		logrus.WithError(err).Error("Failed to create IdempotencyTable")
+		return err
+	}
+
+	return waitForTableToBeActive(svc, bootstrap.IdempotencyTableName)
+}
+
 func waitForTableToBeActive(svc *dynamodb.DynamoDB, tableName string) error {
 	return svc.WaitUntilTableExists(&dynamodb.DescribeTableInput{
 		TableName: aws.String(tableName),
@@ -89,7 +350,7 @@ func waitForTableToBeActive(svc *dynamodb.DynamoDB, tableName string) error {
 }
 
 func ClearTestTables(db *dynamo.DB) error {
-	tables := []string{bootstrap.SongTableName, bootstrap.DocumentTableName}
+	tables := []string{bootstrap.SongTableName, bootstrap.DocumentTableName, bootstrap.GenreTableName, bootstrap.UserTableName, bootstrap.SessionTableName, bootstrap.LoginAttemptTableName, bootstrap.APIKeyTableName, bootstrap.OIDCLoginTableName, bootstrap.AuditTableName, bootstrap.RevisionTableName, bootstrap.IdempotencyTableName}
 
 	for _, tableName := range tables {
 		table := db.Table(tableName)
@@ -102,12 +363,26 @@ func ClearTestTables(db *dynamo.DB) error {
 
 		for _, item := range items {
 			switch tableName {
-			case bootstrap.SongTableName:
+			case bootstrap.SongTableName, bootstrap.GenreTableName, bootstrap.SessionTableName, bootstrap.LoginAttemptTableName, bootstrap.APIKeyTableName, bootstrap.OIDCLoginTableName, bootstrap.AuditTableName, bootstrap.IdempotencyTableName:
 				if id, ok := item["id"].(string); ok {
 					if err := table.Delete("id", id).Run(); err != nil {
 						logrus.WithError(err).Warnf("Failed to delete item %s from %s", id, tableName)
 					}
 				}
+			case bootstrap.UserTableName:
+				if username, ok := item["username"].(string); ok {
+					if err := table.Delete("username", username).Run(); err != nil {
+						logrus.WithError(err).Warnf("Failed to delete user %s from %s", username, tableName)
+					}
+				}
+			case bootstrap.RevisionTableName:
+				songID, ok1 := item["song_id"].(string)
+				revision, ok2 := item["revision"].(float64)
+				if ok1 && ok2 {
+					if err := table.Delete("song_id", songID).Range("revision", int(revision)).Run(); err != nil {
+						logrus.WithError(err).Warnf("Failed to delete revision %d of song %s", int(revision), songID)
+					}
+				}
 			case bootstrap.DocumentTableName:
 				songID, ok1 := item["song_id"].(string)
 				id, ok2 := item["id"].(string)
@@ -0,0 +1,108 @@
+package integration_tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/app"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+// JWKSTestSuite runs the application with an Ed25519 signing key so that tokens
+// can be verified by a third party from the published key set alone.
+type JWKSTestSuite struct {
+	IntegrationTestSuite
+	EdRouter *gin.Engine
+}
+
+func (s *JWKSTestSuite) SetupSuite() {
+	s.IntegrationTestSuite.SetupSuite()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	keys, err := utils.NewJWTKeySet("rendalla-backend", "rendalla-api", []utils.JWTKey{
+		{ID: "ed-test", Algorithm: utils.JWTAlgorithmEdDSA, PrivateKey: private},
+	}, s.TimeProvider)
+	s.Require().NoError(err)
+
+	s.EdRouter = app.InitApp(s.DB, app.AppConfig{JWTKeys: keys, EnableRecovery: true})
+}
+
+func (s *JWKSTestSuite) fetchJWKS(router *gin.Engine) dto.JWKSResponse {
+	res := MakeRequest(router, "GET", "/.well-known/jwks.json", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var jwks dto.JWKSResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&jwks))
+	return jwks
+}
+
+func (s *JWKSTestSuite) login(router *gin.Engine) string {
+	body, err := json.Marshal(ValidLogin)
+	s.Require().NoError(err)
+
+	res := MakeRequest(router, "POST", "/auth/login", bytes.NewReader(body), "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var resp dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&resp))
+	return resp.Token
+}
+
+func (s *JWKSTestSuite) TestJWKS_ShouldNotPublishSharedSecrets() {
+	jwks := s.fetchJWKS(s.Router)
+	s.Empty(jwks.Keys)
+}
+
+func (s *JWKSTestSuite) TestJWKS_TokenVerifiesWithPublishedKey() {
+	jwks := s.fetchJWKS(s.EdRouter)
+	s.Require().Len(jwks.Keys, 1)
+	published := jwks.Keys[0]
+	s.Equal("OKP", published.Kty)
+	s.Equal("EdDSA", published.Alg)
+
+	x, err := base64.RawURLEncoding.DecodeString(published.X)
+	s.Require().NoError(err)
+
+	token := s.login(s.EdRouter)
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		s.Equal(published.Kid, t.Header["kid"])
+		return ed25519.PublicKey(x), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	s.Require().NoError(err)
+	s.True(parsed.Valid)
+
+	res := MakeRequest(s.EdRouter, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *JWKSTestSuite) TestJWKS_ShouldRejectPublicKeyUsedAsHMACSecret() {
+	jwks := s.fetchJWKS(s.EdRouter)
+	s.Require().Len(jwks.Keys, 1)
+	x, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+	s.Require().NoError(err)
+
+	claims := testClaims(ValidLogin.Username, "admin")
+	claims["iss"] = "rendalla-backend"
+	claims["aud"] = "rendalla-api"
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = jwks.Keys[0].Kid
+	token, err := forged.SignedString(x)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.EdRouter, "GET", "/auth/me", nil, token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func TestJWKSSuite(t *testing.T) {
+	suite.Run(t, new(JWKSTestSuite))
+}
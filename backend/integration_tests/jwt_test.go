@@ -1,21 +1,43 @@
 package integration_tests
 
 import (
-	"os"
 	"time"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 func GenerateTestJWT(username string) (string, error) {
-	return GenerateTestJWTWithSecret(username, os.Getenv("JWT_SECRET"))
+	return GenerateTestJWTWithRole(username, models.RoleAdmin)
 }
 
+func GenerateTestJWTWithRole(username, role string) (string, error) {
+	keys, err := bootstrap.LoadJWTKeySet(&utils.UTCTimeProvider{})
+	if err != nil {
+		return "", err
+	}
+	generator := &utils.JWTTokenGenerator{Keys: keys}
+	return generator.GenerateToken(testClaims(username, role))
+}
+
+// GenerateTestJWTWithSecret signs a token with the given secret under the configured kid,
+// issuer and audience, so that only the signature differs from a valid token.
 func GenerateTestJWTWithSecret(username, secret string) (string, error) {
-	claims := jwt.MapClaims{
+	keys, err := utils.NewJWTKeySet(bootstrap.JWTIssuer, bootstrap.JWTAudience,
+		[]utils.JWTKey{{ID: "default", Secret: []byte(secret)}}, &utils.UTCTimeProvider{})
+	if err != nil {
+		return "", err
+	}
+	generator := &utils.JWTTokenGenerator{Keys: keys}
+	return generator.GenerateToken(testClaims(username, models.RoleAdmin))
+}
+
+func testClaims(username, role string) jwt.MapClaims {
+	return jwt.MapClaims{
 		"username": username,
+		"role":     role,
 		"exp":      time.Now().Add(1 * time.Hour).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
 }
@@ -0,0 +1,79 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/router"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginThrottleTestSuite struct {
+	IntegrationTestSuite
+}
+
+func (s *LoginThrottleTestSuite) loginAs(username, password string) int {
+	body, err := json.Marshal(dto.LoginRequest{Username: username, Password: password})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/auth/login", bytes.NewReader(body), "")
+	return res.Code
+}
+
+func (s *LoginThrottleTestSuite) TestLogin_ShouldBackOffAndUnlock() {
+	// The first failures beyond the free ones impose a backoff that the next attempt hits immediately.
+	for i := 0; i < 3; i++ {
+		s.Equal(http.StatusUnauthorized, s.loginAs(SeededEditor, "wrong-password"))
+	}
+	s.Equal(http.StatusTooManyRequests, s.loginAs(SeededEditor, "wrong-password"))
+	s.Equal(http.StatusTooManyRequests, s.loginAs(SeededEditor, SeededEditorPassword))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/admin/users/"+SeededEditor+"/unlock", nil, token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	s.Equal(http.StatusOK, s.loginAs(SeededEditor, SeededEditorPassword))
+}
+
+func (s *LoginThrottleTestSuite) TestLogin_ShouldIgnoreSpoofedClientIPHeaders() {
+	body, err := json.Marshal(dto.LoginRequest{Username: "spoofing-client", Password: "wrong-password"})
+	s.Require().NoError(err)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(body))
+	req.RemoteAddr = "198.51.100.7:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	req.Header.Set("X-Real-IP", "203.0.113.10")
+	req.Header.Set(router.SourceIPHeader, "203.0.113.11")
+	res := httptest.NewRecorder()
+	s.Router.ServeHTTP(res, req)
+	s.Require().Equal(http.StatusUnauthorized, res.Code)
+
+	var attempts []models.LoginAttempt
+	s.Require().NoError(s.DB.Table(bootstrap.LoginAttemptTableName).Scan().All(&attempts))
+	ids := []string{}
+	for _, attempt := range attempts {
+		ids = append(ids, attempt.ID)
+	}
+	s.Contains(ids, "ip:198.51.100.7")
+	s.NotContains(ids, "ip:203.0.113.9")
+	s.NotContains(ids, "ip:203.0.113.10")
+	s.NotContains(ids, "ip:203.0.113.11")
+}
+
+func (s *LoginThrottleTestSuite) TestUnlock_ShouldRequireAdmin() {
+	token, err := GenerateTestJWTWithRole(SeededEditor, models.RoleEditor)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/admin/users/"+SeededEditor+"/unlock", nil, token)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func TestLoginThrottleSuite(t *testing.T) {
+	suite.Run(t, new(LoginThrottleTestSuite))
+}
@@ -0,0 +1,157 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/app"
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/handlers"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+// OIDCTestSuite runs the OIDC login against a local mock OpenID provider.
+type OIDCTestSuite struct {
+	IntegrationTestSuite
+	Provider   *mocks.OIDCProviderServer
+	OIDCRouter *gin.Engine
+}
+
+func (s *OIDCTestSuite) SetupSuite() {
+	s.IntegrationTestSuite.SetupSuite()
+
+	s.Provider = mocks.NewOIDCProviderServer("rendalla-test")
+	s.Provider.ClientSecret = "client-secret"
+
+	jwtKeys, err := bootstrap.LoadJWTKeySet(s.TimeProvider)
+	s.Require().NoError(err)
+
+	s.OIDCRouter = app.InitApp(s.DB, app.AppConfig{
+		JWTKeys:        jwtKeys,
+		EnableRecovery: true,
+		OIDC: &services.OIDCConfig{
+			Issuer:       s.Provider.Issuer(),
+			ClientID:     "rendalla-test",
+			ClientSecret: "client-secret",
+			RedirectURL:  "http://localhost:5173/auth/callback",
+			Scopes:       []string{"openid", "profile", "email", "groups"},
+			RoleMapping: services.OIDCRoleMapping{
+				Groups: map[string]string{"music-editors": models.RoleEditor},
+			},
+		},
+	})
+}
+
+func (s *OIDCTestSuite) TearDownSuite() {
+	s.Provider.Close()
+	s.IntegrationTestSuite.TearDownSuite()
+}
+
+func (s *OIDCTestSuite) SetupTest() {
+	s.Provider.TamperClaims = nil
+}
+
+// startLogin begins a login and lets the mock provider approve it, returning the callback query
+// and the state cookie set by the login.
+func (s *OIDCTestSuite) startLogin(identity mocks.OIDCIdentity) (string, *http.Cookie) {
+	res := MakeRequest(s.OIDCRouter, "GET", "/auth/oidc/login", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var login dto.OIDCLoginResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&login))
+	cookies := res.Result().Cookies()
+	s.Require().Len(cookies, 1)
+	s.Require().Equal(handlers.OIDCStateCookie, cookies[0].Name)
+
+	s.Provider.SetIdentity(identity)
+	code, state, err := s.Provider.Authorize(login.AuthorizationURL)
+	s.Require().NoError(err)
+	s.Require().Equal(login.State, state)
+
+	return "?" + url.Values{"code": {code}, "state": {state}}.Encode(), cookies[0]
+}
+
+// callback completes a login from the browser holding stateCookie, or from another one when it is nil.
+func (s *OIDCTestSuite) callback(query string, stateCookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/auth/oidc/callback"+query, nil)
+	if stateCookie != nil {
+		req.AddCookie(&http.Cookie{Name: stateCookie.Name, Value: stateCookie.Value})
+	}
+	w := httptest.NewRecorder()
+	s.OIDCRouter.ServeHTTP(w, req)
+	return w
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldOpenSession_ForMappedGroup() {
+	query, cookie := s.startLogin(mocks.OIDCIdentity{Subject: "ext-123", Email: "ana@example.org", Groups: []string{"staff", "music-editors"}})
+
+	res := s.callback(query, cookie)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var tokens dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&tokens))
+	s.NotEmpty(tokens.RefreshToken)
+
+	me := MakeRequest(s.OIDCRouter, "GET", "/auth/me", nil, tokens.Token)
+	s.Require().Equal(http.StatusOK, me.Code)
+	var user dto.MeResponse
+	s.Require().NoError(json.NewDecoder(me.Body).Decode(&user))
+	s.Equal("oidc:ext-123", user.Username)
+	s.Equal(models.RoleEditor, user.Role)
+
+	body, err := json.Marshal(dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	s.Require().NoError(err)
+	refreshed := MakeRequest(s.OIDCRouter, "POST", "/auth/refresh", bytes.NewReader(body), "")
+	s.Equal(http.StatusOK, refreshed.Code)
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldReject_ReusedState() {
+	query, cookie := s.startLogin(mocks.OIDCIdentity{Subject: "ext-123", Groups: []string{"music-editors"}})
+
+	first := s.callback(query, cookie)
+	s.Require().Equal(http.StatusOK, first.Code)
+
+	second := s.callback(query, cookie)
+	s.Equal(http.StatusUnauthorized, second.Code)
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldReturn403_WithoutMappedRole() {
+	query, cookie := s.startLogin(mocks.OIDCIdentity{Subject: "ext-456", Groups: []string{"staff"}})
+
+	res := s.callback(query, cookie)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldReturn401_WithWrongNonce() {
+	s.Provider.TamperClaims = func(claims jwt.MapClaims) { claims["nonce"] = "replayed" }
+	query, cookie := s.startLogin(mocks.OIDCIdentity{Subject: "ext-123", Groups: []string{"music-editors"}})
+
+	res := s.callback(query, cookie)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldReturn401_WithoutStateCookie() {
+	query, _ := s.startLogin(mocks.OIDCIdentity{Subject: "ext-123", Groups: []string{"music-editors"}})
+
+	res := s.callback(query, nil)
+	s.Equal(http.StatusUnauthorized, res.Code, "a login started in another browser cannot be completed")
+}
+
+func (s *OIDCTestSuite) TestOIDCLogin_ShouldReturn404_WhenNotConfigured() {
+	res := MakeRequest(s.Router, "GET", "/auth/oidc/login", nil, "")
+	s.Equal(http.StatusNotFound, res.Code)
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
@@ -0,0 +1,93 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordUser        = "pw-lucia"
+	passwordUserInitial = "initial-pass"
+	passwordUserNew     = "Tr0mbone-Solo"
+)
+
+type PasswordTestSuite struct {
+	IntegrationTestSuite
+}
+
+func (s *PasswordTestSuite) SetupTest() {
+	hash, err := bcrypt.GenerateFromPassword([]byte(passwordUserInitial), bcrypt.MinCost)
+	s.Require().NoError(err)
+	now := s.TimeProvider.Now()
+	user := models.User{Username: passwordUser, PasswordHash: string(hash), Role: models.RoleEditor, Status: models.UserStatusActive, CreatedAt: now, UpdatedAt: now}
+	s.Require().NoError(s.DB.Table(bootstrap.UserTableName).Put(user).Run())
+}
+
+func (s *PasswordTestSuite) post(path string, body interface{}, token string) *httptest.ResponseRecorder {
+	raw, err := json.Marshal(body)
+	s.Require().NoError(err)
+	return MakeRequest(s.Router, "POST", path, bytes.NewReader(raw), token)
+}
+
+func (s *PasswordTestSuite) login(password string) *httptest.ResponseRecorder {
+	return s.post("/auth/login", dto.LoginRequest{Username: passwordUser, Password: password}, "")
+}
+
+func (s *PasswordTestSuite) TestChangePassword_ShouldRevokeSessions() {
+	res := s.login(passwordUserInitial)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var tokens dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&tokens))
+
+	weak := s.post("/auth/password", dto.ChangePasswordRequest{CurrentPassword: passwordUserInitial, NewPassword: "short"}, tokens.Token)
+	s.Equal(http.StatusBadRequest, weak.Code)
+
+	changed := s.post("/auth/password", dto.ChangePasswordRequest{CurrentPassword: passwordUserInitial, NewPassword: passwordUserNew}, tokens.Token)
+	s.Require().Equal(http.StatusOK, changed.Code)
+
+	me := MakeRequest(s.Router, "GET", "/auth/me", nil, tokens.Token)
+	s.Equal(http.StatusUnauthorized, me.Code)
+	refreshed := s.post("/auth/refresh", dto.RefreshRequest{RefreshToken: tokens.RefreshToken}, "")
+	s.Equal(http.StatusUnauthorized, refreshed.Code)
+
+	s.Equal(http.StatusUnauthorized, s.login(passwordUserInitial).Code)
+	s.Equal(http.StatusOK, s.login(passwordUserNew).Code)
+}
+
+func (s *PasswordTestSuite) TestResetPassword_ShouldAcceptTokenOnce() {
+	admin, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/admin/users/"+passwordUser+"/password-reset", nil, admin)
+	s.Require().Equal(http.StatusCreated, res.Code)
+	var issued dto.PasswordResetResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&issued))
+
+	reset := dto.ResetPasswordRequest{Username: passwordUser, Token: issued.ResetToken, NewPassword: passwordUserNew}
+	s.Require().Equal(http.StatusOK, s.post("/auth/password/reset", reset, "").Code)
+	s.Equal(http.StatusOK, s.login(passwordUserNew).Code)
+
+	reset.NewPassword = "An0ther-Secret"
+	s.Equal(http.StatusUnauthorized, s.post("/auth/password/reset", reset, "").Code)
+}
+
+func (s *PasswordTestSuite) TestIssuePasswordReset_ShouldRequireAdmin() {
+	token, err := GenerateTestJWTWithRole(SeededEditor, models.RoleEditor)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/admin/users/"+passwordUser+"/password-reset", nil, token)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func TestPasswordSuite(t *testing.T) {
+	suite.Run(t, new(PasswordTestSuite))
+}
@@ -0,0 +1,124 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type RevisionTestSuite struct {
+	IntegrationTestSuite
+	editorToken string
+}
+
+type revisionListResponse struct {
+	Data []dto.RevisionResponseItem `json:"data"`
+}
+
+type revisionDiffResponse struct {
+	Data dto.RevisionDiffResponse `json:"data"`
+}
+
+type documentResponse struct {
+	Data dto.DocumentResponseItem `json:"data"`
+}
+
+func (s *RevisionTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWTWithRole("maria", models.RoleEditor)
+	s.Require().NoError(err)
+	s.editorToken = token
+}
+
+func (s *RevisionTestSuite) put(path string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "PUT", path, bytes.NewReader(body), s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+}
+
+func (s *RevisionTestSuite) listRevisions(songID string) []dto.RevisionResponseItem {
+	res := MakeRequest(s.Router, "GET", "/songs/"+songID+"/revisions", nil, s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var list revisionListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&list))
+	return list.Data
+}
+
+func (s *RevisionTestSuite) TestRevisions_ShouldKeepEveryReplacedVersion() {
+	original := MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	s.Require().Equal(http.StatusOK, original.Code)
+	var before documentResponse
+	s.Require().NoError(json.NewDecoder(original.Body).Decode(&before))
+
+	title := "Bohemian Rhapsody (Remastered)"
+	s.put("/songs/queen-001", dto.UpdateSongRequest{Title: &title})
+	s.put("/songs/queen-001/documents/doc-br-piano", dto.UpdateDocumentRequest{PDFURL: "https://example.com/bohemian-piano-v2.pdf"})
+
+	revisions := s.listRevisions("queen-001")
+	s.Require().Len(revisions, 2)
+	s.Equal(2, revisions[0].Revision)
+	s.Equal(before.Data.PDFURL, revisions[0].Document.PDFURL)
+	s.Equal(1, revisions[1].Revision)
+	s.Equal("Bohemian Rhapsody", revisions[1].Song.Title)
+	s.Equal("maria", revisions[1].Actor)
+
+	res := MakeRequest(s.Router, "GET", "/songs/queen-001/revisions/diff?from=1", nil, s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var diff revisionDiffResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&diff))
+	s.Equal([]dto.FieldChangeResponseItem{{Field: "title", Before: "Bohemian Rhapsody", After: title}}, diff.Data.Changes)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/revisions/diff?from=1&to=2", nil, s.editorToken)
+	s.Equal(http.StatusBadRequest, res.Code, "a song revision cannot be compared with a document revision")
+}
+
+func (s *RevisionTestSuite) TestRevisions_ShouldRestoreAnEarlierFile() {
+	original := MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	s.Require().Equal(http.StatusOK, original.Code)
+	var before documentResponse
+	s.Require().NoError(json.NewDecoder(original.Body).Decode(&before))
+
+	s.put("/songs/queen-001/documents/doc-br-piano", dto.UpdateDocumentRequest{PDFURL: "https://example.com/wrong-file.pdf"})
+	revisions := s.listRevisions("queen-001")
+	s.Require().NotEmpty(revisions)
+	latest := revisions[0]
+
+	res := MakeRequest(s.Router, "POST", "/songs/queen-001/revisions/"+strconv.Itoa(latest.Revision)+"/restore", nil, s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var restored dto.RestoreRevisionResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&restored))
+	s.Equal(latest.Revision+1, restored.Revision)
+
+	current := MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	var after documentResponse
+	s.Require().NoError(json.NewDecoder(current.Body).Decode(&after))
+	s.Equal(before.Data.PDFURL, after.Data.PDFURL)
+
+	revisions = s.listRevisions("queen-001")
+	s.Equal("https://example.com/wrong-file.pdf", revisions[0].Document.PDFURL, "the restore keeps the version it replaces")
+}
+
+func (s *RevisionTestSuite) TestRevisions_ShouldRejectUnknownRevisionsAndAnonymousUsers() {
+	res := MakeRequest(s.Router, "POST", "/songs/queen-001/revisions/999/restore", nil, s.editorToken)
+	s.Equal(http.StatusNotFound, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/non-existent-id/revisions", nil, s.editorToken)
+	s.Equal(http.StatusNotFound, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/revisions", nil, "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func TestRevisionSuite(t *testing.T) {
+	suite.Run(t, new(RevisionTestSuite))
+}
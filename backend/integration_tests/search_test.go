@@ -102,6 +102,34 @@ func (s *SearchTestSuite) TestSearchDocuments_ByInstrument() {
 	s.Contains(body.Data[0].Instrument, "guitar")
 }
 
+func (s *SearchTestSuite) TestSearchDocuments_ByInstrumentFamily() {
+	res := MakeRequest(s.Router, "GET", "/documents/search?instrument=voices", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data []dto.DocumentResponseItem `json:"data"`
+	}
+	err := json.NewDecoder(res.Body).Decode(&body)
+	s.Require().NoError(err)
+
+	s.Len(body.Data, 1)
+	s.Equal("doc-br-voice", body.Data[0].ID)
+}
+
+func (s *SearchTestSuite) TestListInstruments() {
+	res := MakeRequest(s.Router, "GET", "/instruments", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data dto.InstrumentCatalogResponse `json:"data"`
+	}
+	err := json.NewDecoder(res.Body).Decode(&body)
+	s.Require().NoError(err)
+
+	s.NotEmpty(body.Data.Instruments)
+	s.NotEmpty(body.Data.Families)
+}
+
 func (s *SearchTestSuite) TestSearchDocuments_CombinedFilters() {
 	res := MakeRequest(s.Router, "GET", "/documents/search?instrument=piano&type=score", nil, "")
 	s.Equal(http.StatusOK, res.Code)
@@ -129,6 +157,27 @@ func (s *SearchTestSuite) TestSearchDocuments_NoMatch() {
 	s.Len(body.Data, 0)
 }
 
+func (s *SearchTestSuite) TestSearchCatalog_ReturnsTypedResults() {
+	res := MakeRequest(s.Router, "GET", "/search?q=bohemian", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	var body dto.SearchResponse
+	err := json.NewDecoder(res.Body).Decode(&body)
+	s.Require().NoError(err)
+
+	s.Equal(1, body.SegmentCounts.Songs)
+	s.True(body.Complete)
+	s.Require().NotEmpty(body.Data)
+	s.Equal(dto.SearchResultTypeSong, body.Data[0].Type)
+	s.Equal("Bohemian Rhapsody", body.Data[0].Song.Title)
+	s.Equal([]dto.Highlight{{Field: dto.HighlightFieldTitle, Start: 0, End: 8}}, body.Data[0].Highlights)
+}
+
+func (s *SearchTestSuite) TestSearchCatalog_MissingQuery() {
+	res := MakeRequest(s.Router, "GET", "/search", nil, "")
+	s.Equal(http.StatusBadRequest, res.Code)
+}
+
 func TestSearchSuite(t *testing.T) {
 	suite.Run(t, new(SearchTestSuite))
 }
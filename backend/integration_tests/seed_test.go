@@ -6,6 +6,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/guregu/dynamo"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func SeedTestData(db *dynamo.DB, timeProvider utils.TimeProvider) error {
@@ -24,22 +25,26 @@ func SeedTestData(db *dynamo.DB, timeProvider utils.TimeProvider) error {
 
 	bohemianDocuments := []models.Document{
 		{
-			ID:         "doc-br-piano",
-			SongID:     bohemianRhapsody.ID,
-			Type:       "score",
-			Instrument: []string{"piano"},
-			PDFURL:     "https://s3.test/bohemian_rhapsody_piano.pdf",
-			CreatedAt:  now,
-			UpdatedAt:  now,
+			ID:              "doc-br-piano",
+			SongID:          bohemianRhapsody.ID,
+			Title:           bohemianRhapsody.Title,
+			TitleNormalized: bohemianRhapsody.TitleNormalized,
+			Type:            "score",
+			Instrument:      []string{"piano"},
+			PDFURL:          "https://s3.test/bohemian_rhapsody_piano.pdf",
+			CreatedAt:       now,
+			UpdatedAt:       now,
 		},
 		{
-			ID:         "doc-br-voice",
-			SongID:     bohemianRhapsody.ID,
-			Type:       "tablatura",
-			Instrument: []string{"voz"},
-			PDFURL:     "https://s3.test/bohemian_rhapsody_voz.pdf",
-			CreatedAt:  now,
-			UpdatedAt:  now,
+			ID:              "doc-br-voice",
+			SongID:          bohemianRhapsody.ID,
+			Title:           bohemianRhapsody.Title,
+			TitleNormalized: bohemianRhapsody.TitleNormalized,
+			Type:            "tablatura",
+			Instrument:      []string{"voz"},
+			PDFURL:          "https://s3.test/bohemian_rhapsody_voz.pdf",
+			CreatedAt:       now,
+			UpdatedAt:       now,
 		},
 	}
 
@@ -56,16 +61,50 @@ func SeedTestData(db *dynamo.DB, timeProvider utils.TimeProvider) error {
 
 	dontStopMeNowDocs := []models.Document{
 		{
-			ID:         "doc-dsmn-guitar",
-			SongID:     dontStopMeNow.ID,
-			Type:       "score",
-			Instrument: []string{"guitar"},
-			PDFURL:     "https://s3.test/dontstop_guitar.pdf",
-			CreatedAt:  now,
-			UpdatedAt:  now,
+			ID:              "doc-dsmn-guitar",
+			SongID:          dontStopMeNow.ID,
+			Title:           dontStopMeNow.Title,
+			TitleNormalized: dontStopMeNow.TitleNormalized,
+			Type:            "score",
+			Instrument:      []string{"guitar"},
+			PDFURL:          "https://s3.test/dontstop_guitar.pdf",
+			CreatedAt:       now,
+			UpdatedAt:       now,
 		},
 	}
 
+	genres := []models.Genre{
+		{ID: "genre-rock", Name: "Rock", NameNormalized: "rock"},
+		{ID: "genre-progressive", Name: "Progressive", NameNormalized: "progressive", ParentID: "genre-rock"},
+		{ID: "genre-pop", Name: "Pop", NameNormalized: "pop"},
+		{ID: "genre-opera", Name: "Opera", NameNormalized: "opera"},
+	}
+
+	editorHash, err := bcrypt.GenerateFromPassword([]byte(SeededEditorPassword), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+	users := []models.User{
+		{Username: SeededEditor, PasswordHash: string(editorHash), Role: models.RoleEditor, Status: models.UserStatusActive},
+		{Username: "viewer-disabled", PasswordHash: string(editorHash), Role: models.RoleViewer, Status: models.UserStatusDisabled},
+	}
+
+	for _, user := range users {
+		user.CreatedAt, user.UpdatedAt = now, now
+		if err := db.Table(bootstrap.UserTableName).Put(user).Run(); err != nil {
+			logrus.WithField("username", user.Username).WithError(err).Error("Failed to insert user")
+			return err
+		}
+	}
+
+	for _, genre := range genres {
+		genre.CreatedAt, genre.UpdatedAt = now, now
+		if err := db.Table(bootstrap.GenreTableName).Put(genre).Run(); err != nil {
+			logrus.WithField("genre_id", genre.ID).WithError(err).Error("Failed to insert genre")
+			return err
+		}
+	}
+
 	if err := db.Table(bootstrap.SongTableName).Put(bohemianRhapsody).Run(); err != nil {
 		logrus.WithError(err).Error("Failed to insert Bohemian Rhapsody")
 		return err
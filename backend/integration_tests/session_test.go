@@ -0,0 +1,96 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionTestSuite struct {
+	IntegrationTestSuite
+}
+
+func (s *SessionTestSuite) login() dto.AuthResponse {
+	body, err := json.Marshal(ValidLogin)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/auth/login", bytes.NewReader(body), "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var tokens dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&tokens))
+	s.Require().NotEmpty(tokens.RefreshToken)
+	return tokens
+}
+
+func (s *SessionTestSuite) refresh(refreshToken string) (int, dto.AuthResponse) {
+	body, err := json.Marshal(dto.RefreshRequest{RefreshToken: refreshToken})
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/auth/refresh", bytes.NewReader(body), "")
+	var tokens dto.AuthResponse
+	if res.Code == http.StatusOK {
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&tokens))
+	}
+	return res.Code, tokens
+}
+
+func (s *SessionTestSuite) TestRefresh_ShouldRotateToken() {
+	tokens := s.login()
+
+	code, rotated := s.refresh(tokens.RefreshToken)
+	s.Require().Equal(http.StatusOK, code)
+	s.NotEqual(tokens.RefreshToken, rotated.RefreshToken)
+
+	res := MakeRequest(s.Router, "GET", "/auth/me", nil, rotated.Token)
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *SessionTestSuite) TestRefresh_ReuseShouldRevokeFamily() {
+	tokens := s.login()
+
+	code, rotated := s.refresh(tokens.RefreshToken)
+	s.Require().Equal(http.StatusOK, code)
+
+	code, _ = s.refresh(tokens.RefreshToken)
+	s.Equal(http.StatusUnauthorized, code)
+
+	// The legitimate holder is logged out as well
+	code, _ = s.refresh(rotated.RefreshToken)
+	s.Equal(http.StatusUnauthorized, code)
+
+	res := MakeRequest(s.Router, "GET", "/auth/me", nil, rotated.Token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *SessionTestSuite) TestLogout_ShouldRevokeAccessAndRefreshTokens() {
+	tokens := s.login()
+
+	res := MakeRequest(s.Router, "POST", "/auth/logout", nil, tokens.Token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/auth/me", nil, tokens.Token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+
+	code, _ := s.refresh(tokens.RefreshToken)
+	s.Equal(http.StatusUnauthorized, code)
+}
+
+func (s *SessionTestSuite) TestLogoutAll_ShouldRevokeEverySession() {
+	first := s.login()
+	second := s.login()
+
+	res := MakeRequest(s.Router, "POST", "/auth/logout-all", nil, first.Token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/auth/me", nil, second.Token)
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
@@ -48,8 +48,13 @@ func TestMain(m *testing.M) {
 		logrus.Fatal("Failed to seed test data:", err)
 	}
 
+	jwtKeys, err := bootstrap.LoadJWTKeySet(TestTimeProvider)
+	if err != nil {
+		logrus.Fatal("Invalid JWT configuration:", err)
+	}
+
 	TestRouter = app.InitApp(db, app.AppConfig{
-		JWTSecret:      os.Getenv("JWT_SECRET"),
+		JWTKeys:        jwtKeys,
 		EnableCORS:     false,
 		EnableLogger:   false,
 		EnableRecovery: true,
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
@@ -117,6 +118,74 @@ func (s *SongTestSuite) TestGetSongs_ShouldIncludeBohemian() {
 	s.True(found)
 }
 
+// listSongPages follows next_token from path until the last page and returns every song listed.
+func (s *SongTestSuite) listSongPages(path string) []dto.SongResponseItem {
+	var songs []dto.SongResponseItem
+	next := ""
+	for page := 0; page < 50; page++ {
+		url := path
+		if next != "" {
+			url += "&cursor=" + next
+		}
+		w := MakeRequest(s.Router, "GET", url, nil, "")
+		s.Require().Equal(http.StatusOK, w.Code)
+
+		var response dto.SongListResponse
+		s.Require().NoError(json.NewDecoder(w.Body).Decode(&response))
+		s.Equal(len(response.Data), response.Count)
+		s.LessOrEqual(response.Count, 1)
+		songs = append(songs, response.Data...)
+
+		if response.NextToken == "" {
+			return songs
+		}
+		next = response.NextToken
+	}
+	s.FailNow("listing did not end")
+	return nil
+}
+
+func (s *SongTestSuite) TestGetSongs_ShouldPageInStorageOrder() {
+	songs := s.listSongPages("/songs?limit=1")
+
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	s.Contains(ids, "queen-001")
+	s.Contains(ids, "queen-002")
+	s.Len(slices.Compact(slices.Sorted(slices.Values(ids))), len(ids), "no song is listed twice")
+}
+
+func (s *SongTestSuite) TestGetSongs_ShouldPageSortedByTitle() {
+	for _, order := range []string{"asc", "desc"} {
+		songs := s.listSongPages("/songs?limit=1&sort=title&order=" + order)
+
+		titles := make([]string, len(songs))
+		for i, song := range songs {
+			titles[i] = strings.ToLower(song.Title)
+		}
+		s.GreaterOrEqual(len(titles), 2)
+		if order == "desc" {
+			slices.Reverse(titles)
+		}
+		s.True(slices.IsSorted(titles), "titles in %s order: %v", order, titles)
+	}
+}
+
+func (s *SongTestSuite) TestGetSongs_ShouldReturn400ForInvalidPaging() {
+	for _, path := range []string{
+		"/songs?limit=0x10",
+		"/songs?limit=101",
+		"/songs?sort=author",
+		"/songs?order=desc",
+		"/songs?cursor=not-a-token",
+	} {
+		w := MakeRequest(s.Router, "GET", path, nil, "")
+		s.Equal(http.StatusBadRequest, w.Code, path)
+	}
+}
+
 func (s *SongTestSuite) TestGetSongByID_ShouldReturnBohemian() {
 	w := MakeRequest(s.Router, "GET", "/songs/queen-001", nil, "")
 	s.Equal(http.StatusOK, w.Code)
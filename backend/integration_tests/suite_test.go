@@ -1,7 +1,6 @@
 package integration_tests
 
 import (
-	"os"
 	"path/filepath"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/app"
@@ -49,8 +48,13 @@ func (s *IntegrationTestSuite) SetupSuite() {
 		s.FailNow("failed to seed test data", err)
 	}
 
+	jwtKeys, err := bootstrap.LoadJWTKeySet(s.TimeProvider)
+	if err != nil {
+		s.FailNow("invalid JWT configuration", err)
+	}
+
 	s.Router = app.InitApp(s.DB, app.AppConfig{
-		JWTSecret:      os.Getenv("JWT_SECRET"),
+		JWTKeys:        jwtKeys,
 		EnableCORS:     false,
 		EnableLogger:   false,
 		EnableRecovery: true,
@@ -63,7 +67,7 @@ func (s *IntegrationTestSuite) TearDownSuite() {
 		logrus.Warn("could not assert client as *dynamodb.DynamoDB for teardown")
 		return
 	}
-	for _, table := range []string{bootstrap.SongTableName, bootstrap.DocumentTableName} {
+	for _, table := range []string{bootstrap.SongTableName, bootstrap.DocumentTableName, bootstrap.GenreTableName, bootstrap.UserTableName, bootstrap.SessionTableName, bootstrap.LoginAttemptTableName, bootstrap.APIKeyTableName, bootstrap.OIDCLoginTableName, bootstrap.AuditTableName, bootstrap.RevisionTableName, bootstrap.IdempotencyTableName} {
 		_, err := svc.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(table)})
 		if err != nil {
 			logrus.WithError(err).Warnf("could not delete table %s during teardown", table)
@@ -0,0 +1,251 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashTestSuite struct {
+	IntegrationTestSuite
+	adminToken  string
+	editorToken string
+}
+
+type trashListResponse struct {
+	Data dto.TrashResponse `json:"data"`
+}
+
+func (s *TrashTestSuite) SetupTest() {
+	s.Require().NoError(ClearTestTables(s.DB))
+	s.Require().NoError(SeedTestData(s.DB, s.TimeProvider))
+
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+
+	token, err = GenerateTestJWTWithRole("maria", models.RoleEditor)
+	s.Require().NoError(err)
+	s.editorToken = token
+}
+
+func (s *TrashTestSuite) listTrash() dto.TrashResponse {
+	res := MakeRequest(s.Router, "GET", "/admin/trash", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var list trashListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&list))
+	return list.Data
+}
+
+func (s *TrashTestSuite) searchSongs(query string) []dto.SongResponseItem {
+	res := MakeRequest(s.Router, "GET", "/songs/search"+query, nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data []dto.SongResponseItem `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	return body.Data
+}
+
+func (s *TrashTestSuite) TestTrash_DeletedSongIsHiddenAndRestoredWithItsDocuments() {
+	res := MakeRequest(s.Router, "DELETE", "/songs/queen-001", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001", nil, "")
+	s.Equal(http.StatusNotFound, res.Code)
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	s.Equal(http.StatusNotFound, res.Code)
+	s.Empty(s.searchSongs("?title=Bohemian"))
+
+	trash := s.listTrash()
+	s.Require().Len(trash.Songs, 1)
+	s.Equal("queen-001", trash.Songs[0].ID)
+	s.Equal(2, trash.Songs[0].Documents)
+	s.Empty(trash.Documents, "documents deleted with their song are listed under it")
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/songs/queen-001/restore", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-voice", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+	s.Len(s.searchSongs("?title=Bohemian"), 1)
+	s.Empty(s.listTrash().Songs)
+}
+
+func (s *TrashTestSuite) TestTrash_DocumentDeletedOnItsOwnIsRestoredAlone() {
+	res := MakeRequest(s.Router, "DELETE", "/songs/queen-001/documents/doc-br-piano", nil, s.editorToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	s.Equal(http.StatusNotFound, res.Code)
+
+	trash := s.listTrash()
+	s.Require().Len(trash.Documents, 1)
+	s.Equal("maria", trash.Documents[0].DeletedBy)
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/songs/queen-001/documents/doc-br-piano/restore", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "GET", "/songs/queen-001/documents/doc-br-piano", nil, "")
+	s.Equal(http.StatusOK, res.Code)
+}
+
+func (s *TrashTestSuite) TestTrash_PurgedSongIsGoneForGood() {
+	res := MakeRequest(s.Router, "DELETE", "/songs/queen-001", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "DELETE", "/admin/trash/songs/queen-001", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	trash := s.listTrash()
+	s.Empty(trash.Songs)
+	s.Empty(trash.Documents)
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/songs/queen-001/restore", nil, s.adminToken)
+	s.Equal(http.StatusNotFound, res.Code)
+}
+
+// countDocuments pages through the live documents of a song and returns how many there are.
+func (s *TrashTestSuite) countDocuments(songID string) int {
+	count := 0
+	next := ""
+	for {
+		res := MakeRequest(s.Router, "GET", "/songs/"+songID+"/documents?limit=100&cursor="+next, nil, "")
+		s.Require().Equal(http.StatusOK, res.Code)
+
+		var page dto.DocumentListResponse
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&page))
+		count += page.Count
+		if page.NextToken == "" {
+			return count
+		}
+		next = page.NextToken
+	}
+}
+
+func (s *TrashTestSuite) TestTrash_SongWithMoreDocumentsThanOneTransaction() {
+	payload := dto.CreateSongRequest{Title: "Innuendo", Author: "Queen", Genres: []string{"Rock"}}
+	for i := 0; i < 150; i++ {
+		payload.Documents = append(payload.Documents, dto.CreateDocumentRequest{
+			Type:       "score",
+			Instrument: []string{"piano"},
+			PDFURL:     fmt.Sprintf("https://test-bucket/innuendo-%03d.pdf", i),
+		})
+	}
+	body, err := json.Marshal(payload)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "POST", "/songs", bytes.NewReader(body), s.adminToken)
+	s.Require().Equal(http.StatusCreated, res.Code)
+	var created dto.CreateSongResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&created))
+
+	res = MakeRequest(s.Router, "GET", "/songs/"+created.SongID, nil, "")
+	s.Equal(http.StatusOK, res.Code, "the song is visible once every document is written")
+	s.Equal(150, s.countDocuments(created.SongID))
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/"+created.SongID, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(0, s.countDocuments(created.SongID))
+	trash := s.listTrash()
+	s.Require().Len(trash.Songs, 1)
+	s.Equal(150, trash.Songs[0].Documents)
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/songs/"+created.SongID+"/restore", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(150, s.countDocuments(created.SongID))
+
+	res = MakeRequest(s.Router, "DELETE", "/songs/"+created.SongID, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	res = MakeRequest(s.Router, "DELETE", "/admin/trash/songs/"+created.SongID, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var remaining []models.Document
+	s.Require().NoError(s.DB.Table(bootstrap.DocumentTableName).Get("song_id", created.SongID).All(&remaining))
+	s.Empty(remaining, "no document outlives its purged song")
+	s.Empty(s.listTrash().Songs)
+}
+
+func (s *TrashTestSuite) TestTrash_ReconcileFinishesInterruptedWrites() {
+	interrupted := "2025-01-01T00:00:00Z"
+	creating := models.Song{ID: "song-creating", Title: "Mustapha", Author: "Queen", Pending: models.SongPendingCreate, PendingSince: interrupted}
+	trashing := models.Song{
+		ID: "song-trashing", Title: "Mother Love", Author: "Queen",
+		DeletedAt: interrupted, DeletedBy: ValidLogin.Username,
+		Pending: models.SongPendingTrash, PendingSince: interrupted,
+	}
+	for _, song := range []models.Song{creating, trashing} {
+		s.Require().NoError(s.DB.Table(bootstrap.SongTableName).Put(song).Run())
+		for i := 0; i < 3; i++ {
+			doc := models.Document{ID: fmt.Sprintf("doc-%d", i), SongID: song.ID, Title: song.Title, TitleNormalized: strings.ToLower(song.Title), Type: "score", Instrument: []string{"piano"}}
+			s.Require().NoError(s.DB.Table(bootstrap.DocumentTableName).Put(doc).Run())
+		}
+	}
+
+	res := MakeRequest(s.Router, "GET", "/songs/song-creating", nil, "")
+	s.Equal(http.StatusNotFound, res.Code, "a song is hidden while its creation is pending")
+	res = MakeRequest(s.Router, "GET", "/songs/song-creating/documents/doc-0", nil, "")
+	s.Equal(http.StatusNotFound, res.Code, "so are its documents")
+	res = MakeRequest(s.Router, "GET", "/songs/song-creating/documents", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var listed dto.DocumentListResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&listed))
+	s.Empty(listed.Data)
+	res = MakeRequest(s.Router, "GET", "/documents/search?title=mustapha", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var found struct {
+		Data []dto.DocumentResponseItem `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&found))
+	s.Empty(found.Data)
+	res = MakeRequest(s.Router, "GET", "/search?q=mustapha", nil, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var searched dto.SearchResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&searched))
+	s.Empty(searched.Data)
+
+	res = MakeRequest(s.Router, "DELETE", "/admin/trash/songs/song-trashing", nil, s.adminToken)
+	s.NotEqual(http.StatusOK, res.Code, "a song is not purged while its trash is pending")
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/reconcile", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var result dto.ReconcileSongsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&result))
+	s.Equal(1, result.Completed)
+	s.Equal(1, result.RolledBack)
+
+	var leftovers []models.Document
+	s.Require().NoError(s.DB.Table(bootstrap.DocumentTableName).Get("song_id", "song-creating").All(&leftovers))
+	s.Empty(leftovers, "an interrupted creation is rolled back with its documents")
+	s.Equal(0, s.countDocuments("song-trashing"))
+
+	trash := s.listTrash()
+	s.Require().Len(trash.Songs, 1)
+	s.Equal("song-trashing", trash.Songs[0].ID)
+	s.Equal(3, trash.Songs[0].Documents)
+}
+
+func (s *TrashTestSuite) TestTrash_ShouldRejectLiveItemsAndEditors() {
+	res := MakeRequest(s.Router, "DELETE", "/admin/trash/songs/queen-001", nil, s.adminToken)
+	s.Equal(http.StatusNotFound, res.Code, "live songs cannot be purged")
+
+	res = MakeRequest(s.Router, "GET", "/admin/trash", nil, s.editorToken)
+	s.Equal(http.StatusForbidden, res.Code)
+
+	res = MakeRequest(s.Router, "POST", "/admin/trash/purge", nil, s.editorToken)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func TestTrashSuite(t *testing.T) {
+	suite.Run(t, new(TrashTestSuite))
+}
@@ -0,0 +1,146 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/app"
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	totpAdmin         = "admin-totp"
+	totpAdminPassword = "adminpass"
+)
+
+// TwoFactorTestSuite runs logins with TOTP second factors required for admins.
+type TwoFactorTestSuite struct {
+	IntegrationTestSuite
+	TwoFactorRouter *gin.Engine
+}
+
+func (s *TwoFactorTestSuite) SetupSuite() {
+	s.IntegrationTestSuite.SetupSuite()
+
+	cipher, err := utils.NewSecretCipher([]byte("integration-test-totp-key-32byte"))
+	s.Require().NoError(err)
+	jwtKeys, err := bootstrap.LoadJWTKeySet(s.TimeProvider)
+	s.Require().NoError(err)
+
+	s.TwoFactorRouter = app.InitApp(s.DB, app.AppConfig{
+		JWTKeys:        jwtKeys,
+		EnableRecovery: true,
+		TwoFactor: &services.TwoFactorConfig{
+			Issuer:        "Rendalla",
+			Cipher:        cipher,
+			RequiredRoles: []string{models.RoleAdmin},
+			ChallengeTTL:  time.Minute,
+		},
+	})
+}
+
+func (s *TwoFactorTestSuite) SetupTest() {
+	hash, err := bcrypt.GenerateFromPassword([]byte(totpAdminPassword), bcrypt.MinCost)
+	s.Require().NoError(err)
+	now := s.TimeProvider.Now()
+	admin := models.User{Username: totpAdmin, PasswordHash: string(hash), Role: models.RoleAdmin, Status: models.UserStatusActive, CreatedAt: now, UpdatedAt: now}
+	s.Require().NoError(s.DB.Table(bootstrap.UserTableName).Put(admin).Run())
+}
+
+func (s *TwoFactorTestSuite) post(path string, body interface{}, token string) *httptest.ResponseRecorder {
+	raw, err := json.Marshal(body)
+	s.Require().NoError(err)
+	return MakeRequest(s.TwoFactorRouter, "POST", path, bytes.NewReader(raw), token)
+}
+
+func (s *TwoFactorTestSuite) login(username, password string) dto.AuthResponse {
+	res := s.post("/auth/login", dto.LoginRequest{Username: username, Password: password}, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var resp dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&resp))
+	return resp
+}
+
+// currentCode computes the TOTP code an authenticator app would show for the seed.
+func (s *TwoFactorTestSuite) currentCode(secret string) string {
+	seed, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	s.Require().NoError(err)
+	return utils.TOTPCode(seed, utils.TOTPStep(time.Now().Unix()))
+}
+
+func (s *TwoFactorTestSuite) TestLogin_ShouldEnforceEnrolmentForAdmins() {
+	challenge := s.login(totpAdmin, totpAdminPassword)
+	s.Require().True(challenge.MFARequired)
+	s.Require().True(challenge.MFAEnrollmentRequired)
+	s.Empty(challenge.Token)
+
+	res := s.post("/auth/login/2fa/enroll", dto.MFATokenRequest{MFAToken: challenge.MFAToken}, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var enrollment dto.TOTPEnrollmentResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&enrollment))
+	s.Len(enrollment.RecoveryCodes, 10)
+
+	res = s.post("/auth/login/2fa", dto.SecondFactorLoginRequest{MFAToken: challenge.MFAToken, Code: s.currentCode(enrollment.Secret)}, "")
+	s.Require().Equal(http.StatusOK, res.Code)
+	var tokens dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&tokens))
+	s.NotEmpty(tokens.Token)
+
+	// The challenge is single-use.
+	res = s.post("/auth/login/2fa", dto.SecondFactorLoginRequest{MFAToken: challenge.MFAToken, Code: s.currentCode(enrollment.Secret)}, "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+
+	// The next login needs a code, and the code already used is rejected; a recovery code works once.
+	next := s.login(totpAdmin, totpAdminPassword)
+	s.Require().True(next.MFARequired)
+	s.False(next.MFAEnrollmentRequired)
+
+	res = s.post("/auth/login/2fa", dto.SecondFactorLoginRequest{MFAToken: next.MFAToken, Code: s.currentCode(enrollment.Secret)}, "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+
+	res = s.post("/auth/login/2fa", dto.SecondFactorLoginRequest{MFAToken: next.MFAToken, Code: enrollment.RecoveryCodes[0]}, "")
+	s.Equal(http.StatusOK, res.Code)
+
+	again := s.login(totpAdmin, totpAdminPassword)
+	res = s.post("/auth/login/2fa", dto.SecondFactorLoginRequest{MFAToken: again.MFAToken, Code: enrollment.RecoveryCodes[0]}, "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+}
+
+func (s *TwoFactorTestSuite) TestLogin_ShouldNotChallengeEditorsWithoutFactor() {
+	resp := s.login(SeededEditor, SeededEditorPassword)
+
+	s.False(resp.MFARequired)
+	s.NotEmpty(resp.Token)
+}
+
+func (s *TwoFactorTestSuite) TestLegacyAdmin_ShouldBeRefusedWhenAdminsNeedFactor() {
+	res := s.post("/auth/login", dto.LoginRequest{Username: ValidLogin.Username, Password: ValidLogin.Password}, "")
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func (s *TwoFactorTestSuite) TestResetTOTP_ShouldRequireEnrolmentAgain() {
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.TwoFactorRouter, "DELETE", "/admin/users/"+totpAdmin+"/2fa", nil, token)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	challenge := s.login(totpAdmin, totpAdminPassword)
+	s.True(challenge.MFAEnrollmentRequired)
+}
+
+func TestTwoFactorSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
@@ -0,0 +1,159 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserTestSuite struct {
+	IntegrationTestSuite
+	adminToken string
+}
+
+func (s *UserTestSuite) SetupTest() {
+	token, err := GenerateTestJWT(ValidLogin.Username)
+	s.Require().NoError(err)
+	s.adminToken = token
+}
+
+func (s *UserTestSuite) login(username, password string) *http.Response {
+	body, err := json.Marshal(dto.LoginRequest{Username: username, Password: password})
+	s.Require().NoError(err)
+	return MakeRequest(s.Router, "POST", "/auth/login", bytes.NewReader(body), "").Result()
+}
+
+func (s *UserTestSuite) TestLegacyAdmin_ShouldStillLogIn() {
+	res := s.login(ValidLogin.Username, ValidLogin.Password)
+	s.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (s *UserTestSuite) TestInviteAcceptAndLogin_ShouldIssueEditorToken() {
+	body, err := json.Marshal(dto.InviteUserRequest{Username: "new-editor", Role: models.RoleEditor})
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/admin/users", bytes.NewReader(body), s.adminToken)
+	s.Require().Equal(http.StatusCreated, res.Code)
+
+	var invite dto.InviteUserResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&invite))
+	s.NotEmpty(invite.InviteToken)
+
+	// Cannot log in before accepting the invitation
+	s.Equal(http.StatusUnauthorized, s.login("new-editor", "Tr0mbone-Solo").StatusCode)
+
+	accept, err := json.Marshal(dto.AcceptInviteRequest{Username: "new-editor", Token: invite.InviteToken, Password: "Tr0mbone-Solo"})
+	s.Require().NoError(err)
+	res = MakeRequest(s.Router, "POST", "/auth/invite/accept", bytes.NewReader(accept), "")
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	// The token is single-use
+	res = MakeRequest(s.Router, "POST", "/auth/invite/accept", bytes.NewReader(accept), "")
+	s.Equal(http.StatusUnauthorized, res.Code)
+
+	login := s.login("new-editor", "Tr0mbone-Solo")
+	s.Require().Equal(http.StatusOK, login.StatusCode)
+	var auth dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(login.Body).Decode(&auth))
+
+	res = MakeRequest(s.Router, "GET", "/auth/me", nil, auth.Token)
+	s.Require().Equal(http.StatusOK, res.Code)
+	var me dto.MeResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&me))
+	s.Equal(dto.MeResponse{Username: "new-editor", Role: models.RoleEditor}, me)
+}
+
+func (s *UserTestSuite) TestAdminRoutes_ShouldRejectNonAdmins() {
+	token, err := GenerateTestJWTWithRole(SeededEditor, models.RoleEditor)
+	s.Require().NoError(err)
+
+	res := MakeRequest(s.Router, "GET", "/admin/users", nil, token)
+	s.Equal(http.StatusForbidden, res.Code)
+}
+
+func (s *UserTestSuite) TestListUsers_ShouldReturnSeededUsers() {
+	res := MakeRequest(s.Router, "GET", "/admin/users", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	var body struct {
+		Data []dto.UserResponseItem `json:"data"`
+	}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+	usernames := []string{}
+	for _, u := range body.Data {
+		usernames = append(usernames, u.Username)
+	}
+	s.Contains(usernames, "viewer-disabled")
+}
+
+func (s *UserTestSuite) refresh(refreshToken string) int {
+	body, err := json.Marshal(dto.RefreshRequest{RefreshToken: refreshToken})
+	s.Require().NoError(err)
+	return MakeRequest(s.Router, "POST", "/auth/refresh", bytes.NewReader(body), "").Code
+}
+
+func (s *UserTestSuite) loginTokens(username, password string) dto.AuthResponse {
+	res := s.login(username, password)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var auth dto.AuthResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&auth))
+	return auth
+}
+
+func (s *UserTestSuite) TestDisabledUser_ShouldNotLogIn() {
+	s.Equal(http.StatusUnauthorized, s.login("viewer-disabled", SeededEditorPassword).StatusCode)
+}
+
+func (s *UserTestSuite) TestDisableAndDeleteUser_ShouldRevokeSessions() {
+	const username, password = "revoked-editor", "Tr0mbone-Solo"
+	body, err := json.Marshal(dto.InviteUserRequest{Username: username, Role: models.RoleEditor})
+	s.Require().NoError(err)
+	res := MakeRequest(s.Router, "POST", "/admin/users", bytes.NewReader(body), s.adminToken)
+	s.Require().Equal(http.StatusCreated, res.Code)
+	var invite dto.InviteUserResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&invite))
+	accept, err := json.Marshal(dto.AcceptInviteRequest{Username: username, Token: invite.InviteToken, Password: password})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, MakeRequest(s.Router, "POST", "/auth/invite/accept", bytes.NewReader(accept), "").Code)
+
+	tokens := s.loginTokens(username, password)
+	res = MakeRequest(s.Router, "POST", "/admin/users/"+username+"/disable", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(http.StatusUnauthorized, s.refresh(tokens.RefreshToken))
+
+	res = MakeRequest(s.Router, "POST", "/admin/users/"+username+"/enable", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	// Re-enabling the account does not bring back the revoked session
+	s.Equal(http.StatusUnauthorized, s.refresh(tokens.RefreshToken))
+
+	tokens = s.loginTokens(username, password)
+	res = MakeRequest(s.Router, "DELETE", "/admin/users/"+username, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(http.StatusUnauthorized, s.refresh(tokens.RefreshToken))
+}
+
+func (s *UserTestSuite) TestDisableEnableAndDeleteUser() {
+	s.Require().Equal(http.StatusOK, s.login(SeededEditor, SeededEditorPassword).StatusCode)
+
+	res := MakeRequest(s.Router, "POST", "/admin/users/"+SeededEditor+"/disable", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(http.StatusUnauthorized, s.login(SeededEditor, SeededEditorPassword).StatusCode)
+
+	res = MakeRequest(s.Router, "POST", "/admin/users/"+SeededEditor+"/enable", nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+	s.Equal(http.StatusOK, s.login(SeededEditor, SeededEditorPassword).StatusCode)
+
+	res = MakeRequest(s.Router, "DELETE", "/admin/users/"+SeededEditor, nil, s.adminToken)
+	s.Require().Equal(http.StatusOK, res.Code)
+
+	res = MakeRequest(s.Router, "DELETE", "/admin/users/"+SeededEditor, nil, s.adminToken)
+	s.Equal(http.StatusNotFound, res.Code)
+}
+
+func TestUserSuite(t *testing.T) {
+	suite.Run(t, new(UserTestSuite))
+}
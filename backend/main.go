@@ -1,11 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/app"
 	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/router"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
@@ -23,18 +28,103 @@ func main() {
 	bootstrap.LoadConfig()
 	bootstrap.InitDB()
 
-	app := app.InitApp(bootstrap.DB, app.AppConfig{
-		JWTSecret:      os.Getenv("JWT_SECRET"),
+	jwtKeys, err := bootstrap.LoadJWTKeySet(&utils.UTCTimeProvider{})
+	if err != nil {
+		logrus.WithError(err).Fatal("Refusing to start without a valid JWT configuration")
+	}
+
+	var oidc *services.OIDCConfig
+	if bootstrap.OIDCIssuer != "" {
+		oidc = &services.OIDCConfig{
+			Issuer:       bootstrap.OIDCIssuer,
+			ClientID:     bootstrap.OIDCClientID,
+			ClientSecret: bootstrap.OIDCClientSecret,
+			RedirectURL:  bootstrap.OIDCRedirectURL,
+			Scopes:       bootstrap.OIDCScopes,
+			GroupsClaim:  bootstrap.OIDCGroupsClaim,
+			RoleMapping: services.OIDCRoleMapping{
+				Subjects:    bootstrap.OIDCSubjectRoles,
+				Groups:      bootstrap.OIDCGroupRoles,
+				DefaultRole: bootstrap.OIDCDefaultRole,
+			},
+		}
+	}
+
+	totpCipher, err := bootstrap.LoadTOTPCipher()
+	if err != nil {
+		logrus.WithError(err).Fatal("Refusing to start with an invalid TOTP encryption key")
+	}
+	var twoFactor *services.TwoFactorConfig
+	if totpCipher != nil {
+		twoFactor = &services.TwoFactorConfig{
+			Issuer:        bootstrap.TOTPIssuer,
+			Cipher:        totpCipher,
+			RequiredRoles: bootstrap.TOTPRequiredRoles,
+			ChallengeTTL:  bootstrap.MFAChallengeTTL,
+		}
+	} else {
+		logrus.Warn("TOTP_ENCRYPTION_KEY is not set, second factors are disabled")
+	}
+
+	lambdaMode := os.Getenv("LAMBDA_TASK_ROOT") != ""
+
+	// Behind API Gateway the client IP comes from the request context, never from client headers.
+	clientIPHeader := ""
+	if lambdaMode {
+		clientIPHeader = router.SourceIPHeader
+	}
+
+	// Frozen Lambda containers cannot run background jobs; there the trash retention and consistency
+	// jobs run when the function is invoked by a scheduled EventBridge rule instead.
+	trashPurgeInterval := bootstrap.TrashPurgeInterval
+	consistencyCheckInterval := bootstrap.ConsistencyCheckInterval
+	if lambdaMode {
+		trashPurgeInterval = 0
+		consistencyCheckInterval = 0
+	}
+
+	engine, maintenance := app.InitAppWithMaintenance(bootstrap.DB, app.AppConfig{
+		JWTKeys:        jwtKeys,
 		EnableCORS:     true,
 		EnableLogger:   true,
 		EnableRecovery: true,
+		RequireIfMatch: bootstrap.RequireIfMatch,
+		CachePolicies:  bootstrap.CachePolicies,
+		TrustedProxies: bootstrap.TrustedProxies,
+		ClientIPHeader: clientIPHeader,
+
+		AccessTokenTTL:  bootstrap.AccessTokenTTL,
+		RefreshTokenTTL: bootstrap.RefreshTokenTTL,
+		LoginThrottle: services.LoginThrottlePolicy{
+			MaxUserFailures: bootstrap.LoginMaxUserFailures,
+			MaxIPFailures:   bootstrap.LoginMaxIPFailures,
+			FreeFailures:    bootstrap.LoginFreeFailures,
+			BaseBackoff:     bootstrap.LoginBaseBackoff,
+			Lockout:         bootstrap.LoginLockout,
+		},
+		OIDC:                        oidc,
+		TwoFactor:                   twoFactor,
+		AutocompleteRefreshInterval: bootstrap.AutocompleteRefreshInterval,
+		TrashRetention:              bootstrap.TrashRetention,
+		TrashPurgeInterval:          trashPurgeInterval,
+		ConsistencyCheckInterval:    consistencyCheckInterval,
+		IdempotencyKeyTTL:           bootstrap.IdempotencyKeyTTL,
+		CacheMaxEntries:             bootstrap.CacheMaxEntries,
+		CacheTTL:                    bootstrap.CacheTTL,
 	})
 
-	if os.Getenv("LAMBDA_TASK_ROOT") != "" {
+	if lambdaMode {
 		logrus.Info("Running in AWS Lambda mode")
-		lambdaAdapter := ginadapter.New(app)
-		lambda.Start(func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-			return lambdaAdapter.Proxy(req)
+		lambdaAdapter := ginadapter.New(engine)
+		lambda.Start(func(raw json.RawMessage) (interface{}, error) {
+			if isScheduledEvent(raw) {
+				return nil, maintenance.Run()
+			}
+			var req events.APIGatewayProxyRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				return nil, fmt.Errorf("decoding API Gateway request: %w", err)
+			}
+			return lambdaAdapter.Proxy(withSourceIP(req))
 		})
 	} else {
 		// Get and validate port
@@ -44,6 +134,42 @@ func main() {
 		}
 
 		logrus.Infof("Rendalla backend is running on port %s", port)
-		app.Run(fmt.Sprintf(":%s", port))
+		engine.Run(fmt.Sprintf(":%s", port))
+	}
+}
+
+// withSourceIP replaces any router.SourceIPHeader sent by the client with the source IP
+// API Gateway recorded for the request.
+func withSourceIP(req events.APIGatewayProxyRequest) events.APIGatewayProxyRequest {
+	headers := make(map[string]string, len(req.Headers)+1)
+	for name, value := range req.Headers {
+		if !strings.EqualFold(name, router.SourceIPHeader) {
+			headers[name] = value
+		}
+	}
+	multiValueHeaders := make(map[string][]string, len(req.MultiValueHeaders)+1)
+	for name, values := range req.MultiValueHeaders {
+		if !strings.EqualFold(name, router.SourceIPHeader) {
+			multiValueHeaders[name] = values
+		}
+	}
+
+	sourceIP := req.RequestContext.Identity.SourceIP
+	headers[router.SourceIPHeader] = sourceIP
+	if len(req.MultiValueHeaders) > 0 {
+		multiValueHeaders[router.SourceIPHeader] = []string{sourceIP}
+	}
+	req.Headers = headers
+	req.MultiValueHeaders = multiValueHeaders
+	return req
+}
+
+// isScheduledEvent reports whether the Lambda invocation comes from a scheduled EventBridge rule
+// rather than from API Gateway.
+func isScheduledEvent(raw json.RawMessage) bool {
+	var event events.CloudWatchEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return false
 	}
+	return event.Source == "aws.events" && event.DetailType == "Scheduled Event"
 }
@@ -2,44 +2,66 @@ package middleware
 
 import (
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
-	"os"
 	"strings"
-	"time"
 
+	apiErrors "github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/joho/godotenv"
 )
 
-// Initializes environment variables from a .env file.
-// Logs a warning if the file is not found.
-func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Warning: Could not load .env file, using default values")
-	}
+// SessionValidator reports whether the login session an access token belongs to has been revoked.
+type SessionValidator interface {
+	IsSessionRevoked(sessionID string) (bool, error)
 }
 
-// getJWTSecret retrieves the JWT secret from the environment.
-// Falls back to a default value if none is defined.
-func getJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Println("Warning: JWT_SECRET is not defined in .env, using default secret")
-		return []byte("default_secret")
-	}
-	return []byte(secret)
+// APIKeyAuthenticator verifies the API keys sent by automation clients.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(rawKey string) (*models.APIKey, error)
 }
 
-// JWTAuthMiddleware is a Gin middleware that enforces JWT authentication.
+// APIKeyHeader is the header automation clients send their API key in.
+const APIKeyHeader = "X-API-Key"
+
+// JWTAuthMiddleware is a Gin middleware that enforces authentication.
 // It checks the Authorization header for a valid Bearer token, parses it,
-// and extracts the username claim to make it available in the context.
+// and extracts the username, role and session claims to make them available in the context.
+//
+// Requests may instead send an API key in the X-API-Key header. The key's scopes are then set
+// in the context as "scopes" and RequirePermission checks them instead of a role; "username"
+// is set to "api-key:<id>" so handlers can attribute changes to the key.
+//
+// Tokens are verified with the key named by their "kid" header in the same key set used to sign them.
+// Only the algorithms of the key set are accepted, each token must use the algorithm of its key,
+// and the "iss", "aud" and "exp" claims must be present and valid.
+// Tokens carrying a session ID ("sid") are rejected once their session has been revoked
+// by a logout or a refresh token reuse.
 //
-// If the token is invalid, expired, or missing, it responds with 401 Unauthorized.
-func JWTAuthMiddleware() gin.HandlerFunc {
+// If the token or key is invalid, expired, revoked or missing, it responds with 401 Unauthorized.
+func JWTAuthMiddleware(keys *utils.JWTKeySet, sessions SessionValidator, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(keys.Algorithms()),
+		jwt.WithIssuer(keys.Issuer),
+		jwt.WithAudience(keys.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("token has no kid")
+		}
+		return keys.VerificationKey(kid, token.Method.Alg())
+	}
+
 	return func(c *gin.Context) {
+		if rawKey := c.GetHeader(APIKeyHeader); rawKey != "" {
+			authenticateAPIKey(c, apiKeys, rawKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -49,9 +71,7 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 		claims := jwt.MapClaims{}
-		_, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return getJWTSecret(), nil
-		})
+		_, err := parser.ParseWithClaims(tokenStr, claims, keyFunc)
 
 		if err != nil {
 			var errorMsg string
@@ -69,16 +89,45 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if exp, ok := claims["exp"].(float64); ok {
-			if time.Now().Unix() > int64(exp) {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has expired"})
+		sessionID, _ := claims["sid"].(string)
+		if sessionID != "" {
+			revoked, err := sessions.IsSessionRevoked(sessionID)
+			if err != nil {
+				apiErrors.HandleAPIError(c, err, "Failed to validate session")
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
 				c.Abort()
 				return
 			}
 		}
 
 		username, _ := claims["username"].(string)
+		role, _ := claims["role"].(string)
 		c.Set("username", username)
+		c.Set("role", role)
+		c.Set("session_id", sessionID)
 		c.Next()
 	}
 }
+
+// authenticateAPIKey verifies the key and sets the client identity and scopes in the context.
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, rawKey string) {
+	key, err := apiKeys.AuthenticateAPIKey(rawKey)
+	if err != nil {
+		if errors.Is(err, apiErrors.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		} else {
+			apiErrors.HandleAPIError(c, err, "Failed to validate API key")
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("username", "api-key:"+key.ID)
+	c.Set("api_key_id", key.ID)
+	c.Set("scopes", key.Scopes)
+	c.Next()
+}
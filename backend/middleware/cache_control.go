@@ -0,0 +1,16 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// CacheControl is a Gin middleware for cacheable read routes.
+// It sets policy in the context as "cache_control"; handlers send it as the Cache-Control header
+// of successful and 304 Not Modified responses only, so CDNs never keep errors.
+// An empty policy leaves the header out.
+func CacheControl(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy != "" {
+			c.Set("cache_control", policy)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// IdempotencyKeyHeader carries the client-chosen key that makes a create request safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on responses replayed for a retried request.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds the keys clients may send; UUIDs and similar tokens fit easily.
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers kept with a stored response, besides its body.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyStore keeps the create requests sent with an Idempotency-Key and their responses
+// (see services.IdempotencyServiceInterface).
+type IdempotencyStore interface {
+	BeginIdempotentRequest(actor, key, fingerprint string) (*models.IdempotencyKey, error)
+	CompleteIdempotentRequest(actor, key, fingerprint string, response models.StoredResponse) error
+	ReleaseIdempotentRequest(actor, key, fingerprint string) error
+}
+
+// Idempotency is a Gin middleware for create routes, placed after authentication.
+// Requests without an Idempotency-Key header go through unchanged. The first request with a key runs;
+// if it succeeds its response is stored and replayed, with Idempotent-Replayed: true, to every retry
+// with the same key, method, path and body until the key expires. A key reused for a different request,
+// or retried while the first attempt is still running, is answered with 409 Conflict. Unsuccessful
+// responses are not stored, so the client can correct the request and retry with the same key.
+// Keys are scoped to the authenticated user or API key.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			errors.HandleAPIError(c, errors.ErrValidationFailed, "Invalid Idempotency-Key header")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			errors.HandleAPIError(c, errors.ErrBadRequest, "Failed to read request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		actor := c.GetString("username")
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		existing, err := store.BeginIdempotentRequest(actor, key, fingerprint)
+		if err != nil {
+			msg := "Failed to process Idempotency-Key"
+			if errors.MapErrorToStatus(err) == http.StatusConflict {
+				msg = "Idempotency-Key was already used for a different request"
+			}
+			errors.HandleAPIError(c, err, msg)
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			if existing.Response == nil {
+				errors.HandleAPIError(c, errors.ErrConflict, "A request with this Idempotency-Key is still in progress")
+				c.Abort()
+				return
+			}
+			replay(c, *existing.Response)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := store.ReleaseIdempotentRequest(actor, key, fingerprint); err != nil {
+				logrus.WithField("actor", actor).WithError(err).Error("Failed to release idempotency key")
+			}
+			return
+		}
+
+		response := models.StoredResponse{StatusCode: status, Body: recorder.body.String()}
+		for _, name := range replayedHeaders {
+			if value := c.Writer.Header().Get(name); value != "" {
+				if response.Headers == nil {
+					response.Headers = make(map[string]string)
+				}
+				response.Headers[name] = value
+			}
+		}
+		if err := store.CompleteIdempotentRequest(actor, key, fingerprint, response); err != nil {
+			logrus.WithField("actor", actor).WithError(err).Error("Failed to store idempotent response")
+		}
+	}
+}
+
+// replay answers the request with a stored response and stops the chain.
+func replay(c *gin.Context, response models.StoredResponse) {
+	for name, value := range response.Headers {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(response.StatusCode)
+	_, _ = c.Writer.WriteString(response.Body)
+	c.Abort()
+}
+
+// validIdempotencyKey accepts non-empty keys of printable ASCII up to maxIdempotencyKeyLength characters.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint identifies a request by its method, path and body.
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body written by the handlers.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Permission identifies an action that a route may require from the authenticated user.
+type Permission string
+
+// Permissions checked by the router.
+const (
+	PermSongsCreate     Permission = "songs:create"
+	PermSongsUpdate     Permission = "songs:update"
+	PermSongsDelete     Permission = "songs:delete"
+	PermDocumentsCreate Permission = "documents:create"
+	PermDocumentsUpdate Permission = "documents:update"
+	PermDocumentsDelete Permission = "documents:delete"
+	PermGenresManage    Permission = "genres:manage"
+	PermUsersManage     Permission = "users:manage"
+)
+
+// RolePermissions is the authorization policy: the permissions granted to each role.
+// Roles not listed here are granted nothing. Viewers hold no permission: every catalogue read is public.
+var RolePermissions = map[string][]Permission{
+	models.RoleEditor: {
+		PermSongsCreate, PermSongsUpdate,
+		PermDocumentsCreate, PermDocumentsUpdate, PermDocumentsDelete,
+	},
+	models.RoleAdmin: {
+		PermSongsCreate, PermSongsUpdate, PermSongsDelete,
+		PermDocumentsCreate, PermDocumentsUpdate, PermDocumentsDelete,
+		PermGenresManage,
+		PermUsersManage,
+	},
+}
+
+// ScopePermissions maps each API key scope to the permissions it grants.
+// No scope grants PermUsersManage: API keys cannot administer accounts or other keys.
+// The read scopes grant nothing, since every catalogue read is public.
+var ScopePermissions = map[string][]Permission{
+	models.ScopeSongsWrite:     {PermSongsCreate, PermSongsUpdate, PermSongsDelete},
+	models.ScopeDocumentsWrite: {PermDocumentsCreate, PermDocumentsUpdate, PermDocumentsDelete},
+	models.ScopeGenresWrite:    {PermGenresManage},
+}
+
+// ScopesHavePermission reports whether any of the scopes grants the permission.
+func ScopesHavePermission(scopes []string, perm Permission) bool {
+	for _, scope := range scopes {
+		if slices.Contains(ScopePermissions[scope], perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleHasPermission reports whether the policy grants the permission to the role.
+func RoleHasPermission(role string, perm Permission) bool {
+	return slices.Contains(RolePermissions[role], perm)
+}
+
+// RequirePermission is a Gin middleware that only lets through requests whose role,
+// taken from the JWT claims by JWTAuthMiddleware, is granted the given permission.
+// Requests authenticated with an API key are checked against the key's scopes instead.
+// It must run after JWTAuthMiddleware.
+//
+// Requests without the permission are rejected with 403 Forbidden.
+func RequirePermission(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := RoleHasPermission(c.GetString("role"), perm)
+		if scopes, ok := c.Get("scopes"); ok {
+			allowed = ScopesHavePermission(scopes.([]string), perm)
+		}
+		if !allowed {
+			errors.HandleAPIError(c, errors.ErrOperationNotAllowed, "Missing permission "+string(perm))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
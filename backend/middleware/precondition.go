@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// IfMatchHeader carries the entity tag a write is based on (see dto.Precondition).
+const IfMatchHeader = "If-Match"
+
+// RequireIfMatch is a Gin middleware for writes to versioned resources.
+// When required is true, requests without an If-Match header are rejected with
+// 428 Precondition Required, so clients cannot overwrite changes they have not seen.
+// Otherwise every request goes through and If-Match is only honoured when sent.
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader(IfMatchHeader) == "" {
+			errors.HandleAPIError(c, errors.ErrPreconditionRequired, "Missing If-Match header")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
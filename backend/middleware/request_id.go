@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the identifier that correlates a request with its logs and audit entries.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits the request IDs accepted from clients or proxies to short, printable tokens.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware makes sure every request has a request ID.
+// A valid X-Request-ID sent by the client or a proxy is kept; otherwise a random one is generated.
+// The ID is set in the context as "request_id", written back to the request header so that
+// errors.HandleAPIError reports it, and returned in the X-Request-ID response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			// A failing random source only leaves the request without an ID.
+			requestID, _ = utils.GenerateSecureToken(16)
+		}
+
+		c.Request.Header.Set(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
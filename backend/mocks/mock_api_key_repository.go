@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(key models.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByID(id string) (*models.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAllAPIKeys() ([]models.APIKey, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(id, updatedAt string) error {
+	args := m.Called(id, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchAPIKey(id, lastUsedAt string) error {
+	args := m.Called(id, lastUsedAt)
+	return args.Error(0)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+var _ services.APIKeyServiceInterface = (*MockAPIKeyService)(nil)
+
+func (m *MockAPIKeyService) ListAPIKeys() ([]dto.APIKeyResponseItem, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.APIKeyResponseItem), args.Error(1)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(actor string, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	args := m.Called(actor, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CreateAPIKeyResponse), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(actor, id string) error {
+	args := m.Called(actor, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) AuthenticateAPIKey(rawKey string) (*models.APIKey, error) {
+	args := m.Called(rawKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
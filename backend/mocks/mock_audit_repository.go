@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) ListAuditEntries(filter repository.AuditFilter, opts repository.ListOptions) ([]models.AuditEntry, string, error) {
+	args := m.Called(filter, opts)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).([]models.AuditEntry), args.String(1), args.Error(2)
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+var _ services.AuditServiceInterface = (*MockAuditService)(nil)
+
+func (m *MockAuditService) ListAuditEntries(query dto.AuditQuery) (dto.AuditListResponse, error) {
+	args := m.Called(query)
+	return args.Get(0).(dto.AuditListResponse), args.Error(1)
+}
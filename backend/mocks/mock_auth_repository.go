@@ -16,3 +16,8 @@ func (m *MockAuthRepository) GetAuthCredentials() (*repository.AuthCredentials,
 	}
 	return args.Get(0).(*repository.AuthCredentials), args.Error(1)
 }
+
+func (m *MockAuthRepository) UpdateAuthCredentials(creds repository.AuthCredentials) error {
+	args := m.Called(creds)
+	return args.Error(0)
+}
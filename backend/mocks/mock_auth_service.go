@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/stretchr/testify/mock"
@@ -12,9 +13,105 @@ type MockAuthService struct {
 
 var _ services.AuthServiceInterface = (*MockAuthService)(nil)
 
-func (m *MockAuthService) AuthenticateUser(username, password string) (string, error) {
-	args := m.Called(username, password)
-	return args.String(0), args.Error(1)
+func (m *MockAuthService) AuthenticateUser(username, password, clientIP string) (*dto.AuthResponse, error) {
+	args := m.Called(username, password, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) OpenExternalSession(username, role string) (*dto.AuthResponse, error) {
+	args := m.Called(username, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) CompleteSecondFactor(mfaToken, code, clientIP string) (*dto.AuthResponse, error) {
+	args := m.Called(mfaToken, code, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) BeginTOTPEnrollmentForLogin(mfaToken string) (*dto.TOTPEnrollmentResponse, error) {
+	args := m.Called(mfaToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TOTPEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockAuthService) BeginTOTPEnrollment(username string) (*dto.TOTPEnrollmentResponse, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TOTPEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ConfirmTOTPEnrollment(username, code string) error {
+	args := m.Called(username, code)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) DisableTOTP(username, code string) error {
+	args := m.Called(username, code)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetTOTP(actor, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RefreshSession(refreshToken string) (*dto.AuthResponse, error) {
+	args := m.Called(refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(sessionID string) error {
+	args := m.Called(sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ChangePassword(username, currentPassword, newPassword, clientIP string) (int, error) {
+	args := m.Called(username, currentPassword, newPassword, clientIP)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthService) IssuePasswordReset(actor, username string) (*dto.PasswordResetResponse, error) {
+	args := m.Called(actor, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PasswordResetResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ResetPassword(req dto.ResetPasswordRequest) (int, error) {
+	args := m.Called(req)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthService) UnlockAccount(actor, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutAll(username string) (int, error) {
+	args := m.Called(username)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	args := m.Called(sessionID)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockAuthService) GetAuthCredentials() (*repository.AuthCredentials, error) {
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAutocompleteService struct {
+	mock.Mock
+}
+
+var _ services.AutocompleteServiceInterface = (*MockAutocompleteService)(nil)
+
+func (m *MockAutocompleteService) Suggest(prefix, suggestionType string, limit int) ([]dto.Suggestion, error) {
+	args := m.Called(prefix, suggestionType, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.Suggestion), args.Error(1)
+}
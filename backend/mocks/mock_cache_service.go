@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCacheService struct {
+	mock.Mock
+}
+
+var _ services.CacheServiceInterface = (*MockCacheService)(nil)
+
+func (m *MockCacheService) Stats() dto.CacheStatsResponse {
+	args := m.Called()
+	return args.Get(0).(dto.CacheStatsResponse)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCatalogIndexer struct {
+	mock.Mock
+}
+
+var _ services.CatalogIndexer = (*MockCatalogIndexer)(nil)
+
+func (m *MockCatalogIndexer) IndexSong(song models.Song) {
+	m.Called(song)
+}
+
+func (m *MockCatalogIndexer) RemoveSong(songID string) {
+	m.Called(songID)
+}
+
+func (m *MockCatalogIndexer) IndexDocument(doc models.Document) {
+	m.Called(doc)
+}
+
+func (m *MockCatalogIndexer) RemoveDocument(songID, docID string) {
+	m.Called(songID, docID)
+}
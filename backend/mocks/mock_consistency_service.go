@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConsistencyService struct {
+	mock.Mock
+}
+
+var _ services.ConsistencyServiceInterface = (*MockConsistencyService)(nil)
+
+func (m *MockConsistencyService) CheckDocumentTitles(repair bool) (dto.TitleConsistencyReport, error) {
+	args := m.Called(repair)
+	return args.Get(0).(dto.TitleConsistencyReport), args.Error(1)
+}
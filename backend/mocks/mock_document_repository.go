@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,8 +10,8 @@ type MockDocumentRepository struct {
 	mock.Mock
 }
 
-func (m *MockDocumentRepository) CreateDocument(doc models.Document) error {
-	args := m.Called(doc)
+func (m *MockDocumentRepository) CreateDocument(doc models.Document, audit models.AuditEntry) error {
+	args := m.Called(doc, audit)
 	return args.Error(0)
 }
 
@@ -19,6 +20,11 @@ func (m *MockDocumentRepository) GetDocumentsBySongID(songID string) ([]models.D
 	return args.Get(0).([]models.Document), args.Error(1)
 }
 
+func (m *MockDocumentRepository) ListDocumentsBySongID(songID string, opts repository.ListOptions) ([]models.Document, string, error) {
+	args := m.Called(songID, opts)
+	return args.Get(0).([]models.Document), args.String(1), args.Error(2)
+}
+
 func (m *MockDocumentRepository) GetDocumentByID(songID string, docID string) (*models.Document, error) {
 	args := m.Called(songID, docID)
 	if args.Get(0) == nil {
@@ -27,12 +33,34 @@ func (m *MockDocumentRepository) GetDocumentByID(songID string, docID string) (*
 	return args.Get(0).(*models.Document), args.Error(1)
 }
 
-func (m *MockDocumentRepository) UpdateDocument(songID string, docID string, updates map[string]interface{}) error {
-	args := m.Called(songID, docID, updates)
+func (m *MockDocumentRepository) GetDocumentByIDUncached(songID string, docID string) (*models.Document, error) {
+	args := m.Called(songID, docID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepository) UpdateDocument(songID string, docID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
+	args := m.Called(songID, docID, version, updates, audit, revision)
 	return args.Error(0)
 }
 
-func (m *MockDocumentRepository) DeleteDocument(songID string, docID string) error {
-	args := m.Called(songID, docID)
+func (m *MockDocumentRepository) TrashDocument(songID string, docID string, version int, audit models.AuditEntry) error {
+	args := m.Called(songID, docID, version, audit)
+	return args.Error(0)
+}
+
+// ScanDocuments visits the documents given as the first return value, then returns the second.
+func (m *MockDocumentRepository) ScanDocuments(visit func(models.Document)) error {
+	args := m.Called()
+	for _, doc := range args.Get(0).([]models.Document) {
+		visit(doc)
+	}
+	return args.Error(1)
+}
+
+func (m *MockDocumentRepository) SetDocumentTitles(songID string, documents []models.Document, title string, audit models.AuditEntry) error {
+	args := m.Called(songID, documents, title, audit)
 	return args.Error(0)
 }
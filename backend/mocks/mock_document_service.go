@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,14 +13,14 @@ type MockDocumentService struct {
 
 var _ services.DocumentServiceInterface = (*MockDocumentService)(nil)
 
-func (m *MockDocumentService) CreateDocument(document dto.CreateDocumentRequest) (string, error) {
-	args := m.Called(document)
+func (m *MockDocumentService) CreateDocument(actor models.Actor, document dto.CreateDocumentRequest) (string, error) {
+	args := m.Called(actor, document)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockDocumentService) GetDocumentsBySongID(songID string) ([]dto.DocumentResponseItem, error) {
-	args := m.Called(songID)
-	return args.Get(0).([]dto.DocumentResponseItem), args.Error(1)
+func (m *MockDocumentService) ListDocuments(songID string, query dto.ListQuery) (dto.DocumentListResponse, error) {
+	args := m.Called(songID, query)
+	return args.Get(0).(dto.DocumentListResponse), args.Error(1)
 }
 
 func (m *MockDocumentService) GetDocumentByID(songID string, docID string) (dto.DocumentResponseItem, error) {
@@ -27,12 +28,12 @@ func (m *MockDocumentService) GetDocumentByID(songID string, docID string) (dto.
 	return args.Get(0).(dto.DocumentResponseItem), args.Error(1)
 }
 
-func (m *MockDocumentService) UpdateDocument(songID string, docID string, updates dto.UpdateDocumentRequest) error {
-	args := m.Called(songID, docID, updates)
-	return args.Error(0)
+func (m *MockDocumentService) UpdateDocument(actor models.Actor, songID string, docID string, updates dto.UpdateDocumentRequest, ifMatch dto.Precondition) (int, error) {
+	args := m.Called(actor, songID, docID, updates, ifMatch)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockDocumentService) DeleteDocument(songID string, docID string) error {
-	args := m.Called(songID, docID)
+func (m *MockDocumentService) DeleteDocument(actor models.Actor, songID string, docID string, ifMatch dto.Precondition) error {
+	args := m.Called(actor, songID, docID, ifMatch)
 	return args.Error(0)
 }
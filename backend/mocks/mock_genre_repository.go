@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGenreRepository struct {
+	mock.Mock
+}
+
+func (m *MockGenreRepository) GetAllGenres() ([]models.Genre, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Genre), args.Error(1)
+}
+
+func (m *MockGenreRepository) CreateGenre(genre models.Genre) error {
+	args := m.Called(genre)
+	return args.Error(0)
+}
+
+func (m *MockGenreRepository) ScanAllSongs() ([]models.Song, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Song), args.Error(1)
+}
+
+func (m *MockGenreRepository) ApplyGenreBatch(batch repository.GenreBatch) (int, error) {
+	args := m.Called(batch)
+	return args.Int(0), args.Error(1)
+}
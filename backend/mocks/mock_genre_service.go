@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGenreService struct {
+	mock.Mock
+}
+
+var _ services.GenreServiceInterface = (*MockGenreService)(nil)
+
+func (m *MockGenreService) ListGenres() ([]dto.GenreResponseItem, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.GenreResponseItem), args.Error(1)
+}
+
+func (m *MockGenreService) CreateGenre(req dto.CreateGenreRequest) (string, error) {
+	args := m.Called(req)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGenreService) UpdateGenre(actor models.Actor, id string, req dto.UpdateGenreRequest) (int, error) {
+	args := m.Called(actor, id, req)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGenreService) MergeGenres(actor models.Actor, sourceID, targetID string) (int, error) {
+	args := m.Called(actor, sourceID, targetID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGenreService) ResolveGenres(names []string) ([]string, error) {
+	args := m.Called(names)
+	if fn, ok := args.Get(0).(func([]string) []string); ok {
+		return fn(names), args.Error(1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+var _ repository.IdempotencyRepository = (*MockIdempotencyRepository)(nil)
+
+func (m *MockIdempotencyRepository) ReserveIdempotencyKey(key models.IdempotencyKey, now int64) error {
+	args := m.Called(key, now)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) GetIdempotencyKey(id string) (*models.IdempotencyKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) CompleteIdempotencyKey(id string, fingerprint string, response models.StoredResponse) error {
+	args := m.Called(id, fingerprint, response)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) ReleaseIdempotencyKey(id string, fingerprint string) error {
+	args := m.Called(id, fingerprint)
+	return args.Error(0)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInstrumentService struct {
+	mock.Mock
+}
+
+var _ services.InstrumentServiceInterface = (*MockInstrumentService)(nil)
+
+func (m *MockInstrumentService) ListInstruments() (dto.InstrumentCatalogResponse, error) {
+	args := m.Called()
+	return args.Get(0).(dto.InstrumentCatalogResponse), args.Error(1)
+}
+
+func (m *MockInstrumentService) NormalizeInstruments(names []string) ([]string, error) {
+	args := m.Called(names)
+	if fn, ok := args.Get(0).(func([]string) []string); ok {
+		return fn(names), args.Error(1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockInstrumentService) ExpandInstrumentQuery(query string) ([]string, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) GetLoginAttempt(id string) (*models.LoginAttempt, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordLoginFailure(id string, failedAt, expiresAt int64) (*models.LoginAttempt, error) {
+	args := m.Called(id, failedAt, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) LockLoginAttempt(id string, lockedUntil, expiresAt int64) error {
+	args := m.Called(id, lockedUntil, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) DeleteLoginAttempt(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOIDCLoginRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCLoginRepository) CreateOIDCLogin(login models.OIDCLogin) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockOIDCLoginRepository) ConsumeOIDCLogin(id string) (*models.OIDCLogin, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OIDCLogin), args.Error(1)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOIDCProviderRepository struct {
+	mock.Mock
+}
+
+var _ repository.OIDCProviderRepository = (*MockOIDCProviderRepository)(nil)
+
+func (m *MockOIDCProviderRepository) GetDiscovery(issuer string) (*repository.OIDCDiscovery, error) {
+	args := m.Called(issuer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.OIDCDiscovery), args.Error(1)
+}
+
+func (m *MockOIDCProviderRepository) GetJWKS(jwksURI string) ([]utils.JWK, error) {
+	args := m.Called(jwksURI)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]utils.JWK), args.Error(1)
+}
+
+func (m *MockOIDCProviderRepository) ExchangeCode(tokenEndpoint string, exchange repository.OIDCCodeExchange) (*repository.OIDCTokenResponse, error) {
+	args := m.Called(tokenEndpoint, exchange)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.OIDCTokenResponse), args.Error(1)
+}
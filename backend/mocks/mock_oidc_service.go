@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOIDCService struct {
+	mock.Mock
+}
+
+var _ services.OIDCServiceInterface = (*MockOIDCService)(nil)
+
+func (m *MockOIDCService) BeginLogin() (*dto.OIDCLoginResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OIDCLoginResponse), args.Error(1)
+}
+
+func (m *MockOIDCService) CompleteLogin(code, state string) (*dto.AuthResponse, error) {
+	args := m.Called(code, state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthResponse), args.Error(1)
+}
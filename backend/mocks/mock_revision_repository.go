@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevisionRepository) GetRevisionsBySongID(songID string) ([]models.Revision, error) {
+	args := m.Called(songID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepository) GetRevision(songID string, revision int) (*models.Revision, error) {
+	args := m.Called(songID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepository) GetLatestRevisionNumber(songID string) (int, error) {
+	args := m.Called(songID)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRevisionService struct {
+	mock.Mock
+}
+
+var _ services.RevisionServiceInterface = (*MockRevisionService)(nil)
+
+func (m *MockRevisionService) ListRevisions(songID string) ([]dto.RevisionResponseItem, error) {
+	args := m.Called(songID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.RevisionResponseItem), args.Error(1)
+}
+
+func (m *MockRevisionService) DiffRevisions(songID string, query dto.RevisionDiffQuery) (dto.RevisionDiffResponse, error) {
+	args := m.Called(songID, query)
+	return args.Get(0).(dto.RevisionDiffResponse), args.Error(1)
+}
+
+func (m *MockRevisionService) RestoreRevision(actor models.Actor, songID string, revision int, ifMatch dto.Precondition) (int, error) {
+	args := m.Called(actor, songID, revision, ifMatch)
+	return args.Int(0), args.Error(1)
+}
@@ -15,7 +15,12 @@ func (m *MockSearchRepository) ListSongs(title, sortField, sortOrder string, lim
 	return args.Get(0).([]models.Song), args.Get(1).(repository.PagingKey), args.Error(2)
 }
 
-func (m *MockSearchRepository) ListDocuments(title, instrument, docType, sortField, sortOrder string, limit int, nextToken repository.PagingKey) ([]models.Document, repository.PagingKey, error) {
-	args := m.Called(title, instrument, docType, sortField, sortOrder, limit, nextToken)
+func (m *MockSearchRepository) ListDocuments(title string, instruments []string, docType, sortField, sortOrder string, limit int, nextToken repository.PagingKey) ([]models.Document, repository.PagingKey, error) {
+	args := m.Called(title, instruments, docType, sortField, sortOrder, limit, nextToken)
 	return args.Get(0).([]models.Document), args.Get(1).(repository.PagingKey), args.Error(2)
 }
+
+func (m *MockSearchRepository) SearchCatalog(query string, maxHits int, cursor string) ([]models.Song, []models.Document, string, error) {
+	args := m.Called(query, maxHits, cursor)
+	return args.Get(0).([]models.Song), args.Get(1).([]models.Document), args.String(2), args.Error(3)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
@@ -23,3 +24,8 @@ func (m *MockSearchService) ListDocuments(title, instrument, docType, sortField,
 	args := m.Called(title, instrument, docType, sortField, sortOrder, limit, nextToken)
 	return args.Get(0).([]models.Document), args.Get(1), args.Error(2)
 }
+
+func (m *MockSearchService) SearchCatalog(query string, limit int, cursor string) (dto.SearchResponse, error) {
+	args := m.Called(query, limit, cursor)
+	return args.Get(0).(dto.SearchResponse), args.Error(1)
+}
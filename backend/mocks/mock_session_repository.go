@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(session models.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetSessionsByUsername(username string) ([]models.Session, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) RotateSessionToken(id, oldHash, newHash string, expiresAt int64, updatedAt string) error {
+	args := m.Called(id, oldHash, newHash, expiresAt, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeSession(id, reason, updatedAt string) error {
+	args := m.Called(id, reason, updatedAt)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,8 +10,8 @@ type MockSongRepository struct {
 	mock.Mock
 }
 
-func (m *MockSongRepository) CreateSongWithDocuments(song models.Song, documents []models.Document) error {
-	args := m.Called(song, documents)
+func (m *MockSongRepository) CreateSongWithDocuments(song models.Song, documents []models.Document, audit models.AuditEntry) error {
+	args := m.Called(song, documents, audit)
 	return args.Error(0)
 }
 
@@ -19,6 +20,11 @@ func (m *MockSongRepository) GetAllSongs() ([]models.Song, error) {
 	return args.Get(0).([]models.Song), args.Error(1)
 }
 
+func (m *MockSongRepository) ListSongs(opts repository.ListOptions) ([]models.Song, string, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.Song), args.String(1), args.Error(2)
+}
+
 func (m *MockSongRepository) GetSongByID(id string) (*models.Song, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -27,12 +33,20 @@ func (m *MockSongRepository) GetSongByID(id string) (*models.Song, error) {
 	return args.Get(0).(*models.Song), args.Error(1)
 }
 
-func (m *MockSongRepository) UpdateSong(id string, updates map[string]interface{}) error {
-	args := m.Called(id, updates)
+func (m *MockSongRepository) GetSongByIDUncached(id string) (*models.Song, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Song), args.Error(1)
+}
+
+func (m *MockSongRepository) UpdateSong(id string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
+	args := m.Called(id, version, updates, audit, revision)
 	return args.Error(0)
 }
 
-func (m *MockSongRepository) DeleteSongWithDocuments(id string) error {
-	args := m.Called(id)
+func (m *MockSongRepository) TrashSongWithDocuments(id string, version int, audit models.AuditEntry) error {
+	args := m.Called(id, version, audit)
 	return args.Error(0)
 }
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,9 +13,9 @@ type MockSongService struct {
 
 var _ services.SongServiceInterface = (*MockSongService)(nil)
 
-func (m *MockSongService) GetAllSongs() ([]dto.SongResponseItem, error) {
-	args := m.Called()
-	return args.Get(0).([]dto.SongResponseItem), args.Error(1)
+func (m *MockSongService) ListSongs(query dto.ListQuery) (dto.SongListResponse, error) {
+	args := m.Called(query)
+	return args.Get(0).(dto.SongListResponse), args.Error(1)
 }
 
 func (m *MockSongService) GetSongByID(id string) (dto.SongResponseItem, error) {
@@ -22,17 +23,17 @@ func (m *MockSongService) GetSongByID(id string) (dto.SongResponseItem, error) {
 	return args.Get(0).(dto.SongResponseItem), args.Error(1)
 }
 
-func (m *MockSongService) CreateSongWithDocuments(req dto.CreateSongRequest) (string, error) {
-	args := m.Called(req)
+func (m *MockSongService) CreateSongWithDocuments(actor models.Actor, req dto.CreateSongRequest) (string, error) {
+	args := m.Called(actor, req)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockSongService) UpdateSong(id string, updates dto.UpdateSongRequest) error {
-	args := m.Called(id, updates)
-	return args.Error(0)
+func (m *MockSongService) UpdateSong(actor models.Actor, id string, updates dto.UpdateSongRequest, ifMatch dto.Precondition) (int, error) {
+	args := m.Called(actor, id, updates, ifMatch)
+	return args.Int(0), args.Error(1)
 }
 
-func (m *MockSongService) DeleteSongWithDocuments(id string) error {
-	args := m.Called(id)
+func (m *MockSongService) DeleteSongWithDocuments(actor models.Actor, id string, ifMatch dto.Precondition) error {
+	args := m.Called(actor, id, ifMatch)
 	return args.Error(0)
 }
@@ -0,0 +1,88 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) GetTrashedSongs() ([]models.Song, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Song), args.Error(1)
+}
+
+func (m *MockTrashRepository) GetTrashedDocuments() ([]models.Document, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Document), args.Error(1)
+}
+
+func (m *MockTrashRepository) GetTrashedSongByID(songID string) (*models.Song, error) {
+	args := m.Called(songID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Song), args.Error(1)
+}
+
+func (m *MockTrashRepository) GetTrashedDocumentsBySongID(songID string) ([]models.Document, error) {
+	args := m.Called(songID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Document), args.Error(1)
+}
+
+func (m *MockTrashRepository) GetTrashedDocumentByID(songID string, docID string) (*models.Document, error) {
+	args := m.Called(songID, docID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Document), args.Error(1)
+}
+
+func (m *MockTrashRepository) RestoreSong(songID string, documentIDs []string, audit models.AuditEntry) error {
+	args := m.Called(songID, documentIDs, audit)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) RestoreDocument(songID string, docID string, audit models.AuditEntry) error {
+	args := m.Called(songID, docID, audit)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) PurgeSong(songID string, audit models.AuditEntry) error {
+	args := m.Called(songID, audit)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) PurgeDocument(songID string, docID string, audit models.AuditEntry) error {
+	args := m.Called(songID, docID, audit)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) GetPendingSongs() ([]models.Song, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Song), args.Error(1)
+}
+
+func (m *MockTrashRepository) CompletePendingSong(song models.Song) error {
+	args := m.Called(song)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepository) RollBackSongCreation(songID string, audit models.AuditEntry) error {
+	args := m.Called(songID, audit)
+	return args.Error(0)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTrashService struct {
+	mock.Mock
+}
+
+var _ services.TrashServiceInterface = (*MockTrashService)(nil)
+
+func (m *MockTrashService) ListTrash() (dto.TrashResponse, error) {
+	args := m.Called()
+	return args.Get(0).(dto.TrashResponse), args.Error(1)
+}
+
+func (m *MockTrashService) RestoreSong(actor models.Actor, songID string) error {
+	args := m.Called(actor, songID)
+	return args.Error(0)
+}
+
+func (m *MockTrashService) RestoreDocument(actor models.Actor, songID string, docID string) error {
+	args := m.Called(actor, songID, docID)
+	return args.Error(0)
+}
+
+func (m *MockTrashService) PurgeSong(actor models.Actor, songID string) error {
+	args := m.Called(actor, songID)
+	return args.Error(0)
+}
+
+func (m *MockTrashService) PurgeDocument(actor models.Actor, songID string, docID string) error {
+	args := m.Called(actor, songID, docID)
+	return args.Error(0)
+}
+
+func (m *MockTrashService) PurgeExpired(actor models.Actor) (dto.PurgeTrashResponse, error) {
+	args := m.Called(actor)
+	return args.Get(0).(dto.PurgeTrashResponse), args.Error(1)
+}
+
+func (m *MockTrashService) ReconcilePendingSongs(actor models.Actor) (dto.ReconcileSongsResponse, error) {
+	args := m.Called(actor)
+	return args.Get(0).(dto.ReconcileSongsResponse), args.Error(1)
+}
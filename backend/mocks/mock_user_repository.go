@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetUserByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetAllUsers() ([]models.User, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ResetPassword(username, tokenHash, passwordHash, updatedAt string) error {
+	args := m.Called(username, tokenHash, passwordHash, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateUser(user models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUser(username string, updates map[string]interface{}) error {
+	args := m.Called(username, updates)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteUser(username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SaveTOTPEnrollment(username, encryptedSecret string, recoveryCodeHashes []string, updatedAt string) error {
+	args := m.Called(username, encryptedSecret, recoveryCodeHashes, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableTOTP(username, encryptedSecret string, recoveryCodeHashes []string, step int64, updatedAt string) error {
+	args := m.Called(username, encryptedSecret, recoveryCodeHashes, step, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DisableTOTP(username, updatedAt string) error {
+	args := m.Called(username, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) RecordTOTPStep(username string, step int64) error {
+	args := m.Called(username, step)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ConsumeRecoveryCode(username, codeHash string) error {
+	args := m.Called(username, codeHash)
+	return args.Error(0)
+}
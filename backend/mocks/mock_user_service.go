@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserService struct {
+	mock.Mock
+}
+
+var _ services.UserServiceInterface = (*MockUserService)(nil)
+
+func (m *MockUserService) ListUsers() ([]dto.UserResponseItem, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.UserResponseItem), args.Error(1)
+}
+
+func (m *MockUserService) InviteUser(actor string, req dto.InviteUserRequest) (*dto.InviteUserResponse, error) {
+	args := m.Called(actor, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.InviteUserResponse), args.Error(1)
+}
+
+func (m *MockUserService) AcceptInvite(req dto.AcceptInviteRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) DisableUser(actor, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserService) EnableUser(actor, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserService) DeleteUser(actor, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
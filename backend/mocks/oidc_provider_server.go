@@ -0,0 +1,228 @@
+package mocks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCIdentity is the user the mock provider signs in at its authorization endpoint.
+type OIDCIdentity struct {
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+// OIDCProviderServer is a local OpenID provider for tests and development.
+// It serves discovery, JWKS, authorization and token endpoints, enforces PKCE (S256) and
+// signs ID tokens with an Ed25519 key. The authorization endpoint approves every request for
+// the configured identity and redirects to redirect_uri with a single-use code.
+type OIDCProviderServer struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string // When set, the token endpoint requires it with HTTP basic auth
+
+	// TamperClaims, if set, may modify the ID token claims before signing.
+	TamperClaims func(claims jwt.MapClaims)
+
+	mu       sync.Mutex
+	identity OIDCIdentity
+	keys     *utils.JWTKeySet
+	codes    map[string]pendingOIDCCode
+}
+
+type pendingOIDCCode struct {
+	identity      OIDCIdentity
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// NewOIDCProviderServer starts a mock provider for the given client.
+// The issuer is the server URL. Call Close when done.
+func NewOIDCProviderServer(clientID string) *OIDCProviderServer {
+	p := &OIDCProviderServer{ClientID: clientID, codes: map[string]pendingOIDCCode{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("/jwks", p.handleJWKS)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	p.Server = httptest.NewServer(mux)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	p.keys, err = utils.NewJWTKeySet(p.URL, clientID, []utils.JWTKey{
+		{ID: "mock-oidc-key", Algorithm: utils.JWTAlgorithmEdDSA, PrivateKey: private},
+	}, &utils.UTCTimeProvider{})
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *OIDCProviderServer) Issuer() string {
+	return p.URL
+}
+
+// SetIdentity selects the user signed in by the next authorization requests.
+func (p *OIDCProviderServer) SetIdentity(identity OIDCIdentity) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.identity = identity
+}
+
+// Authorize plays the browser: it visits the authorization URL and returns the code and state
+// of the redirect, or the error query parameter if the request was refused.
+func (p *OIDCProviderServer) Authorize(authorizationURL string) (code, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authorizationURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", "", err
+	}
+	query := location.Query()
+	if e := query.Get("error"); e != "" {
+		return "", query.Get("state"), fmt.Errorf("authorization refused: %s", e)
+	}
+	return query.Get("code"), query.Get("state"), nil
+}
+
+func (p *OIDCProviderServer) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{utils.JWTAlgorithmEdDSA},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *OIDCProviderServer) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": p.keys.PublicJWKs()})
+}
+
+func (p *OIDCProviderServer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	back := redirectURI.Query()
+	back.Set("state", query.Get("state"))
+	switch {
+	case query.Get("client_id") != p.ClientID:
+		back.Set("error", "unauthorized_client")
+	case query.Get("response_type") != "code":
+		back.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		back.Set("error", "invalid_request")
+	default:
+		code, err := utils.GenerateSecureToken(16)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		p.mu.Lock()
+		p.codes[code] = pendingOIDCCode{
+			identity:      p.identity,
+			clientID:      query.Get("client_id"),
+			redirectURI:   query.Get("redirect_uri"),
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+		}
+		p.mu.Unlock()
+		back.Set("code", code)
+	}
+
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *OIDCProviderServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if p.ClientSecret != "" {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != p.ClientID || secret != p.ClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+	}
+
+	p.mu.Lock()
+	code := r.PostForm.Get("code")
+	pending, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok ||
+		pending.clientID != r.PostForm.Get("client_id") ||
+		pending.redirectURI != r.PostForm.Get("redirect_uri") ||
+		pending.codeChallenge != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now().Unix()
+	claims := jwt.MapClaims{
+		"sub":    pending.identity.Subject,
+		"email":  pending.identity.Email,
+		"groups": pending.identity.Groups,
+		"nonce":  pending.nonce,
+		"iat":    now,
+		"exp":    now + 300,
+	}
+	if p.TamperClaims != nil {
+		p.TamperClaims(claims)
+	}
+
+	idToken, err := (&utils.JWTTokenGenerator{Keys: p.keys}).GenerateToken(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id_token":     idToken,
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,41 @@
+package models
+
+// Scopes that can be granted to an API key.
+const (
+	ScopeSongsRead      = "songs:read"
+	ScopeSongsWrite     = "songs:write"
+	ScopeDocumentsRead  = "documents:read"
+	ScopeDocumentsWrite = "documents:write"
+	ScopeGenresWrite    = "genres:write"
+)
+
+// States of an API key.
+const (
+	APIKeyStatusActive  = "active"
+	APIKeyStatusRevoked = "revoked"
+)
+
+// IsValidScope reports whether scope is one of the known API key scopes.
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeSongsRead, ScopeSongsWrite, ScopeDocumentsRead, ScopeDocumentsWrite, ScopeGenresWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey is a long-lived credential for automation clients, sent in the X-API-Key header.
+// The key itself is shown once at creation; only its SHA-256 digest is stored.
+type APIKey struct {
+	ID         string   `json:"id" dynamodbav:"id" dynamo:"id"`                                                             // Unique identifier, also the prefix of the key
+	Name       string   `json:"name" dynamodbav:"name" dynamo:"name"`                                                       // Human-readable label (e.g., "nightly import")
+	KeyHash    string   `json:"-" dynamodbav:"key_hash" dynamo:"key_hash"`                                                  // SHA-256 of the key
+	Scopes     []string `json:"scopes" dynamodbav:"scopes,stringset" dynamo:"scopes,set"`                                   // Granted scopes (string set), e.g. ScopeSongsWrite
+	Status     string   `json:"status" dynamodbav:"status" dynamo:"status"`                                                 // One of APIKeyStatusActive or APIKeyStatusRevoked
+	CreatedBy  string   `json:"created_by" dynamodbav:"created_by" dynamo:"created_by"`                                     // Username of the admin who created the key
+	ExpiresAt  int64    `json:"expires_at,omitempty" dynamodbav:"expires_at,omitempty" dynamo:"expires_at,omitempty"`       // Unix time after which the key is rejected; zero means never
+	LastUsedAt string   `json:"last_used_at,omitempty" dynamodbav:"last_used_at,omitempty" dynamo:"last_used_at,omitempty"` // ISO timestamp of the last authenticated request
+	CreatedAt  string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                                     // ISO timestamp of creation
+	UpdatedAt  string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                                     // ISO timestamp of last update
+}
@@ -0,0 +1,44 @@
+package models
+
+// Entities recorded in the audit trail.
+const (
+	AuditEntitySong     = "song"
+	AuditEntityDocument = "document"
+)
+
+// Actions recorded in the audit trail.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"  // Moved to the trash
+	AuditActionRestore = "restore" // Brought back from the trash
+	AuditActionPurge   = "purge"   // Removed from the trash for good
+)
+
+// Actor identifies who requested a change to the catalogue and the HTTP request that carried it.
+type Actor struct {
+	Username  string // Username from the access token, or "api-key:<id>" for automation clients
+	RequestID string // Value of the X-Request-ID header
+}
+
+// FieldChange is the value of one field before and after a change.
+// Before is absent for creations and After is absent for deletions.
+type FieldChange struct {
+	Field  string      `json:"field" dynamodbav:"field" dynamo:"field"`                                  // Attribute name as exposed by the API (e.g., "title")
+	Before interface{} `json:"before,omitempty" dynamodbav:"before,omitempty" dynamo:"before,omitempty"` // Previous value
+	After  interface{} `json:"after,omitempty" dynamodbav:"after,omitempty" dynamo:"after,omitempty"`    // New value
+}
+
+// AuditEntry is an append-only record of one create, update or delete of a song or a document.
+// Entries are written in the same transaction as the change they describe and never modified.
+type AuditEntry struct {
+	ID        string        `json:"id" dynamodbav:"id" dynamo:"id"`                         // Unique identifier of the entry
+	Entity    string        `json:"entity" dynamodbav:"entity" dynamo:"entity"`             // AuditEntitySong or AuditEntityDocument
+	EntityID  string        `json:"entity_id" dynamodbav:"entity_id" dynamo:"entity_id"`    // ID of the song or document changed
+	SongID    string        `json:"song_id" dynamodbav:"song_id" dynamo:"song_id"`          // Song the entity belongs to; equals EntityID for songs
+	Action    string        `json:"action" dynamodbav:"action" dynamo:"action"`             // AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore or AuditActionPurge
+	Actor     string        `json:"actor" dynamodbav:"actor" dynamo:"actor"`                // Username that requested the change
+	RequestID string        `json:"request_id" dynamodbav:"request_id" dynamo:"request_id"` // X-Request-ID of the request that carried the change
+	Timestamp string        `json:"timestamp" dynamodbav:"timestamp" dynamo:"timestamp"`    // ISO timestamp of the change
+	Changes   []FieldChange `json:"changes" dynamodbav:"changes" dynamo:"changes"`          // Fields that changed, sorted by name
+}
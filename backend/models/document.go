@@ -2,13 +2,18 @@ package models
 
 // Document represents a musical score or tablature associated with a song.
 type Document struct {
-	ID              string   `json:"id" dynamodbav:"id" dynamo:"id"`                                // Unique identifier for the document
-	SongID          string   `json:"song_id" dynamodbav:"song_id" dynamo:"song_id"`                 // Foreign key referencing the associated song
-	TitleNormalized string   `json:"-" dynamodbav:"title_normalized" dynamo:"title_normalized"`     // Normalized title (inherited from the song) used for search and pagination
-	Type            string   `json:"type" dynamodbav:"type" dynamo:"type"`                          // Document type: "score" or "tablature"
-	Instrument      []string `json:"instrument" dynamodbav:"instrument" dynamo:"instrument"`        // Target instruments or voices (e.g., "guitar", "soprano")
-	PDFURL          string   `json:"pdf_url" dynamodbav:"pdf_url" dynamo:"pdf_url"`                 // URL to the PDF file stored in S3
-	AudioURL        string   `json:"audio_url,omitempty" dynamodbav:"audio_url" dynamo:"audio_url"` // Optional URL to an accompanying audio file
-	CreatedAt       string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`        // ISO timestamp of creation
-	UpdatedAt       string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`        // ISO timestamp of last update
+	ID              string   `json:"id" dynamodbav:"id" dynamo:"id"`                                                  // Unique identifier for the document
+	SongID          string   `json:"song_id" dynamodbav:"song_id" dynamo:"song_id"`                                   // Foreign key referencing the associated song
+	Title           string   `json:"-" dynamodbav:"title" dynamo:"title"`                                             // Title inherited from the song, as written; search highlights are computed against it
+	TitleNormalized string   `json:"-" dynamodbav:"title_normalized" dynamo:"title_normalized"`                       // Normalized title (inherited from the song) used for search and pagination
+	Type            string   `json:"type" dynamodbav:"type" dynamo:"type"`                                            // Document type: "score" or "tablature"
+	Instrument      []string `json:"instrument" dynamodbav:"instrument" dynamo:"instrument"`                          // Target instruments or voices (e.g., "guitar", "soprano")
+	PDFURL          string   `json:"pdf_url" dynamodbav:"pdf_url" dynamo:"pdf_url"`                                   // URL to the PDF file stored in S3
+	AudioURL        string   `json:"audio_url,omitempty" dynamodbav:"audio_url" dynamo:"audio_url"`                   // Optional URL to an accompanying audio file
+	CreatedAt       string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                          // ISO timestamp of creation
+	UpdatedAt       string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                          // ISO timestamp of last update
+	Version         int      `json:"-" dynamodbav:"version" dynamo:"version"`                                         // Incremented on every update; exposed as the ETag of the document
+	DeletedAt       string   `json:"-" dynamodbav:"deleted_at,omitempty" dynamo:"deleted_at,omitempty"`               // ISO timestamp of when the document was moved to the trash; empty while live
+	DeletedBy       string   `json:"-" dynamodbav:"deleted_by,omitempty" dynamo:"deleted_by,omitempty"`               // Username that moved the document to the trash
+	DeletedWithSong bool     `json:"-" dynamodbav:"deleted_with_song,omitempty" dynamo:"deleted_with_song,omitempty"` // Whether the document went to the trash together with its song and is restored with it
 }
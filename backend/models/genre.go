@@ -0,0 +1,12 @@
+package models
+
+// Genre represents an entry of the managed genre catalogue that songs are classified with.
+// Genres form a hierarchy through ParentID (e.g., "Flamenco" under "Folk").
+type Genre struct {
+	ID             string `json:"id" dynamodbav:"id" dynamo:"id"`                                                    // Unique identifier for the genre
+	Name           string `json:"name" dynamodbav:"name" dynamo:"name"`                                              // Display name, stored as-is in Song.Genres
+	NameNormalized string `json:"-" dynamodbav:"name_normalized" dynamo:"name_normalized"`                           // Lowercased, accent-stripped name used to enforce uniqueness and match input
+	ParentID       string `json:"parent_id,omitempty" dynamodbav:"parent_id,omitempty" dynamo:"parent_id,omitempty"` // Optional identifier of the parent genre
+	CreatedAt      string `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                            // ISO timestamp of creation
+	UpdatedAt      string `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                            // ISO timestamp of last update
+}
@@ -0,0 +1,20 @@
+package models
+
+// IdempotencyKey records a create request sent with an Idempotency-Key header, so that retries of it
+// are answered with the response of the first attempt instead of creating the item again.
+// The record is locked while the first attempt runs and holds its response once it succeeded.
+type IdempotencyKey struct {
+	ID          string          `json:"id" dynamodbav:"id" dynamo:"id"`                                                             // "<actor>:<key>", so clients cannot replay each other's responses
+	Fingerprint string          `json:"fingerprint" dynamodbav:"fingerprint" dynamo:"fingerprint"`                                  // Hash of the method, path and body of the first request
+	Response    *StoredResponse `json:"response,omitempty" dynamodbav:"response,omitempty" dynamo:"response,omitempty"`             // Response of the first request; nil while it is still running
+	CreatedAt   string          `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                                     // Creation timestamp
+	LockedUntil int64           `json:"locked_until,omitempty" dynamodbav:"locked_until,omitempty" dynamo:"locked_until,omitempty"` // Unix time after which an unfinished first attempt is considered abandoned
+	ExpiresAt   int64           `json:"expires_at" dynamodbav:"expires_at" dynamo:"expires_at"`                                     // Unix time after which the key can be reused (DynamoDB TTL)
+}
+
+// StoredResponse is an HTTP response kept to be replayed.
+type StoredResponse struct {
+	StatusCode int               `json:"status_code" dynamodbav:"status_code" dynamo:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty" dynamodbav:"headers,omitempty" dynamo:"headers,omitempty"`
+	Body       string            `json:"body" dynamodbav:"body" dynamo:"body"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Instrument represents an entry of the canonical instrument catalogue used to classify documents.
+type Instrument struct {
+	ID      string            `json:"id" dynamodbav:"id" dynamo:"id"`                // Canonical identifier stored in Document.Instrument (e.g., "guitar")
+	Names   map[string]string `json:"names" dynamodbav:"names" dynamo:"names"`       // Localized display names keyed by language code ("es", "en")
+	Aliases []string          `json:"aliases" dynamodbav:"aliases" dynamo:"aliases"` // Alternative spellings and abbreviations accepted as input (e.g., "gtr")
+	Family  string            `json:"family" dynamodbav:"family" dynamo:"family"`    // Identifier of the family the instrument belongs to (e.g., "strings")
+}
+
+// InstrumentFamily groups related instruments or voices so that searches can match every member.
+type InstrumentFamily struct {
+	ID    string            `json:"id" dynamodbav:"id" dynamo:"id"`          // Canonical identifier (e.g., "voices")
+	Names map[string]string `json:"names" dynamodbav:"names" dynamo:"names"` // Localized display names keyed by language code ("es", "en")
+}
@@ -0,0 +1,11 @@
+package models
+
+// LoginAttempt tracks recent failed logins for one throttling key: a username or a client IP.
+// The record is dropped once no failure happened for a whole lockout period.
+type LoginAttempt struct {
+	ID            string `json:"id" dynamodbav:"id" dynamo:"id"`                                                             // Throttling key, "user:<username>" or "ip:<address>"
+	Failures      int    `json:"failures" dynamodbav:"failures" dynamo:"failures"`                                           // Consecutive failed attempts
+	LastFailureAt int64  `json:"last_failure_at" dynamodbav:"last_failure_at" dynamo:"last_failure_at"`                      // Unix time of the last failed attempt
+	LockedUntil   int64  `json:"locked_until,omitempty" dynamodbav:"locked_until,omitempty" dynamo:"locked_until,omitempty"` // Unix time until which logins are refused; zero if not locked
+	ExpiresAt     int64  `json:"expires_at" dynamodbav:"expires_at" dynamo:"expires_at"`                                     // Unix time after which the record can be discarded (DynamoDB TTL)
+}
@@ -0,0 +1,11 @@
+package models
+
+// OIDCLogin is a pending authorization-code login with the organisation's OpenID provider.
+// It is created when the login starts and consumed by the callback, so each state is used once.
+type OIDCLogin struct {
+	ID           string `json:"id" dynamodbav:"id" dynamo:"id"`                                  // State parameter sent to the provider
+	CodeVerifier string `json:"code_verifier" dynamodbav:"code_verifier" dynamo:"code_verifier"` // PKCE verifier whose S256 challenge was sent to the provider
+	Nonce        string `json:"nonce" dynamodbav:"nonce" dynamo:"nonce"`                         // Nonce the ID token must carry
+	CreatedAt    string `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`          // Creation timestamp
+	ExpiresAt    int64  `json:"expires_at" dynamodbav:"expires_at" dynamo:"expires_at"`          // Unix time after which the login can no longer complete (DynamoDB TTL)
+}
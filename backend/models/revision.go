@@ -0,0 +1,15 @@
+package models
+
+// Revision is an immutable copy of a song or one of its documents as it was right before an update.
+// Revisions of a song and of its documents share one sequence, numbered from 1 per song.
+type Revision struct {
+	SongID    string    `json:"song_id" dynamodbav:"song_id" dynamo:"song_id"`                                  // Song the revision belongs to (partition key)
+	Revision  int       `json:"revision" dynamodbav:"revision" dynamo:"revision"`                               // Sequence number within the song (sort key)
+	Entity    string    `json:"entity" dynamodbav:"entity" dynamo:"entity"`                                     // AuditEntitySong or AuditEntityDocument
+	EntityID  string    `json:"entity_id" dynamodbav:"entity_id" dynamo:"entity_id"`                            // ID of the song or document copied
+	Song      *Song     `json:"song,omitempty" dynamodbav:"song,omitempty" dynamo:"song,omitempty"`             // Copy of the song, for song revisions
+	Document  *Document `json:"document,omitempty" dynamodbav:"document,omitempty" dynamo:"document,omitempty"` // Copy of the document, for document revisions
+	Actor     string    `json:"actor" dynamodbav:"actor" dynamo:"actor"`                                        // Username whose update replaced this version
+	RequestID string    `json:"request_id" dynamodbav:"request_id" dynamo:"request_id"`                         // X-Request-ID of that update
+	CreatedAt string    `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                         // ISO timestamp of that update
+}
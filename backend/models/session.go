@@ -0,0 +1,34 @@
+package models
+
+// States of a login session.
+const (
+	SessionStatusActive  = "active"
+	SessionStatusRevoked = "revoked"
+
+	// SessionStatusMFAPending marks the short-lived challenge left after a correct password
+	// when a second factor is still required. It never issues access tokens.
+	SessionStatusMFAPending = "mfa_pending"
+)
+
+// How the owner of a session signed in, which decides where its role is read again on refresh.
+const (
+	SessionSourceUsers       = "users"        // Account of the users store
+	SessionSourceLegacyAdmin = "legacy_admin" // Admin stored as a secret
+	SessionSourceExternal    = "external"     // Identity verified by an external provider, such as OIDC
+)
+
+// Session is a login session: one refresh token family created at login and rotated on every refresh.
+// Access tokens carry the session ID so that revoking the session also invalidates them.
+type Session struct {
+	ID                 string   `json:"id" dynamodbav:"id" dynamo:"id"`                                                                      // Unique identifier, stored as the "sid" claim
+	Username           string   `json:"username" dynamodbav:"username" dynamo:"username"`                                                    // Owner of the session
+	Role               string   `json:"role" dynamodbav:"role" dynamo:"role"`                                                                // Role granted at login
+	Source             string   `json:"source,omitempty" dynamodbav:"source,omitempty" dynamo:"source,omitempty"`                            // One of the SessionSource constants; empty for sessions opened before it was recorded
+	TokenHash          string   `json:"-" dynamodbav:"token_hash" dynamo:"token_hash"`                                                       // SHA-256 of the current refresh token
+	RotatedTokenHashes []string `json:"-" dynamodbav:"rotated_token_hashes,stringset,omitempty" dynamo:"rotated_token_hashes,set,omitempty"` // SHA-256 of refresh tokens already exchanged (string set), used to detect reuse
+	Status             string   `json:"status" dynamodbav:"status" dynamo:"status"`                                                          // One of SessionStatusActive, SessionStatusRevoked or SessionStatusMFAPending
+	RevokedReason      string   `json:"revoked_reason,omitempty" dynamodbav:"revoked_reason,omitempty" dynamo:"revoked_reason,omitempty"`    // Why the session was revoked (e.g., "logout", "reuse_detected")
+	ExpiresAt          int64    `json:"expires_at" dynamodbav:"expires_at" dynamo:"expires_at"`                                              // Unix time after which the refresh token is no longer accepted
+	CreatedAt          string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                                              // ISO timestamp of login
+	UpdatedAt          string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                                              // ISO timestamp of last rotation or revocation
+}
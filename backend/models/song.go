@@ -2,12 +2,27 @@ package models
 
 // Song represents a musical track with metadata used for display and search purposes.
 type Song struct {
-	ID              string   `json:"id" dynamodbav:"id" dynamo:"id"`                                      // Unique identifier for the song
-	Title           string   `json:"title" dynamodbav:"title" dynamo:"title"`                             // Original title as entered by the user
-	TitleNormalized string   `json:"-" dynamodbav:"title_normalized" dynamo:"title_normalized"`           // Lowercased, accent-stripped version of the title for search optimization
-	Author          string   `json:"author" dynamodbav:"author" dynamo:"author"`                          // Author or composer of the song
-	Genres          []string `json:"genres" dynamodbav:"genres" dynamo:"genres"`                          // List of associated genres (e.g., classical, rock)
-	YoutubeURL      string   `json:"youtube_url,omitempty" dynamodbav:"youtube_url" dynamo:"youtube_url"` // Optional link to a YouTube video
-	CreatedAt       string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`              // ISO timestamp of creation
-	UpdatedAt       string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`              // ISO timestamp of last update
+	ID              string   `json:"id" dynamodbav:"id" dynamo:"id"`                                          // Unique identifier for the song
+	Title           string   `json:"title" dynamodbav:"title" dynamo:"title"`                                 // Original title as entered by the user
+	TitleNormalized string   `json:"-" dynamodbav:"title_normalized" dynamo:"title_normalized"`               // Lowercased, accent-stripped version of the title for search optimization
+	Author          string   `json:"author" dynamodbav:"author" dynamo:"author"`                              // Author or composer of the song
+	Genres          []string `json:"genres" dynamodbav:"genres" dynamo:"genres"`                              // List of associated genres (e.g., classical, rock)
+	YoutubeURL      string   `json:"youtube_url,omitempty" dynamodbav:"youtube_url" dynamo:"youtube_url"`     // Optional link to a YouTube video
+	CreatedAt       string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                  // ISO timestamp of creation
+	UpdatedAt       string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                  // ISO timestamp of last update
+	Version         int      `json:"-" dynamodbav:"version" dynamo:"version"`                                 // Incremented on every update; exposed as the ETag of the song
+	DeletedAt       string   `json:"-" dynamodbav:"deleted_at,omitempty" dynamo:"deleted_at,omitempty"`       // ISO timestamp of when the song was moved to the trash; empty while live
+	DeletedBy       string   `json:"-" dynamodbav:"deleted_by,omitempty" dynamo:"deleted_by,omitempty"`       // Username that moved the song to the trash
+	Pending         string   `json:"-" dynamodbav:"pending,omitempty" dynamo:"pending,omitempty"`             // SongPending* write still in progress on the song and its documents; empty once complete
+	PendingSince    string   `json:"-" dynamodbav:"pending_since,omitempty" dynamo:"pending_since,omitempty"` // ISO timestamp of when the pending write started
 }
+
+// Writes recorded in Song.Pending when a song and its documents do not fit in a single DynamoDB
+// transaction and are written in several. A pending song is hidden from every read until the write
+// completes, and an interrupted write is finished, or for a creation rolled back, by reconciliation.
+const (
+	SongPendingCreate  = "create"
+	SongPendingTrash   = "trash"
+	SongPendingRestore = "restore"
+	SongPendingPurge   = "purge"
+)
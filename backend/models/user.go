@@ -0,0 +1,49 @@
+package models
+
+// Roles that can be assigned to a user.
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Account states of a user.
+const (
+	UserStatusInvited  = "invited"
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+)
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleEditor, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// User represents an account allowed to sign in to the back office.
+type User struct {
+	Username        string `json:"username" dynamodbav:"username" dynamo:"username"`                                     // Unique, lowercased login name
+	PasswordHash    string `json:"-" dynamodbav:"password_hash,omitempty" dynamo:"password_hash,omitempty"`              // bcrypt hash of the password; empty until the invitation is accepted
+	Role            string `json:"role" dynamodbav:"role" dynamo:"role"`                                                 // One of RoleAdmin, RoleEditor or RoleViewer
+	Status          string `json:"status" dynamodbav:"status" dynamo:"status"`                                           // One of UserStatusInvited, UserStatusActive or UserStatusDisabled
+	InviteTokenHash string `json:"-" dynamodbav:"invite_token_hash,omitempty" dynamo:"invite_token_hash,omitempty"`      // SHA-256 of the pending invitation token
+	InviteExpiresAt int64  `json:"-" dynamodbav:"invite_expires_at,omitempty" dynamo:"invite_expires_at,omitempty"`      // Unix time after which the invitation can no longer be accepted
+	InvitedBy       string `json:"invited_by,omitempty" dynamodbav:"invited_by,omitempty" dynamo:"invited_by,omitempty"` // Username of the admin who sent the invitation
+
+	PasswordResetTokenHash string `json:"-" dynamodbav:"password_reset_token_hash,omitempty" dynamo:"password_reset_token_hash,omitempty"`                 // SHA-256 of the pending password reset token
+	PasswordResetExpiresAt int64  `json:"-" dynamodbav:"password_reset_expires_at,omitempty" dynamo:"password_reset_expires_at,omitempty"`                 // Unix time after which the reset token can no longer be used
+	PasswordChangedAt      string `json:"password_changed_at,omitempty" dynamodbav:"password_changed_at,omitempty" dynamo:"password_changed_at,omitempty"` // ISO timestamp of the last password change or reset
+
+	TOTPEnabled               bool     `json:"totp_enabled" dynamodbav:"totp_enabled,omitempty" dynamo:"totp_enabled,omitempty"`                                    // Whether logins require a one-time password
+	TOTPSecret                string   `json:"-" dynamodbav:"totp_secret,omitempty" dynamo:"totp_secret,omitempty"`                                                 // Encrypted TOTP seed (utils.SecretCipher)
+	TOTPLastStep              int64    `json:"-" dynamodbav:"totp_last_step,omitempty" dynamo:"totp_last_step,omitempty"`                                           // Last accepted time step, so a code cannot be replayed
+	RecoveryCodeHashes        []string `json:"-" dynamodbav:"recovery_code_hashes,stringset,omitempty" dynamo:"recovery_code_hashes,set,omitempty"`                 // SHA-256 of the unused recovery codes (string set)
+	TOTPPendingSecret         string   `json:"-" dynamodbav:"totp_pending_secret,omitempty" dynamo:"totp_pending_secret,omitempty"`                                 // Encrypted seed of an enrolment awaiting its first code
+	PendingRecoveryCodeHashes []string `json:"-" dynamodbav:"pending_recovery_code_hashes,stringset,omitempty" dynamo:"pending_recovery_code_hashes,set,omitempty"` // Recovery codes of the pending enrolment
+	CreatedAt                 string   `json:"created_at" dynamodbav:"created_at" dynamo:"created_at"`                                                              // ISO timestamp of creation
+	UpdatedAt                 string   `json:"updated_at" dynamodbav:"updated_at" dynamo:"updated_at"`                                                              // ISO timestamp of last update
+}
@@ -0,0 +1,39 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// APIKeyRepository defines operations for storing API keys.
+type APIKeyRepository interface {
+
+	// CreateAPIKey stores a new API key.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if persistence fails
+	CreateAPIKey(key models.APIKey) error
+
+	// GetAPIKeyByID retrieves an API key by its ID.
+	// Returns:
+	//   - (*models.APIKey, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the key does not exist
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetAPIKeyByID(id string) (*models.APIKey, error)
+
+	// GetAllAPIKeys returns every API key, active or revoked.
+	// Returns:
+	//   - ([]models.APIKey, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetAllAPIKeys() ([]models.APIKey, error)
+
+	// RevokeAPIKey marks an existing API key as revoked.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the key does not exist
+	//   - errors.ErrInternalServer if the update fails
+	RevokeAPIKey(id, updatedAt string) error
+
+	// TouchAPIKey records the time an API key was last used.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the update fails
+	TouchAPIKey(id, lastUsedAt string) error
+}
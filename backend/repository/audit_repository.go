@@ -0,0 +1,25 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// AuditFilter narrows down the audit entries returned by AuditRepository.ListAuditEntries.
+// Empty fields do not filter.
+type AuditFilter struct {
+	Entity string // Only entries of this entity (e.g., models.AuditEntitySong)
+	Actor  string // Only entries of changes requested by this username
+	Since  string // Only entries whose timestamp is at or after this ISO timestamp
+}
+
+// AuditRepository defines read access to the audit trail of catalogue changes.
+// Entries are written by SongRepository and DocumentRepository in the same transaction
+// as the change they describe, so there is no separate write operation.
+type AuditRepository interface {
+
+	// ListAuditEntries returns one page of the audit entries matching the filter, newest first, and the
+	// token of the next page, empty on the last one. Only opts.Limit and opts.Cursor are used.
+	// Returns:
+	//   - ([]models.AuditEntry, next token, nil) on success
+	//   - errors.ErrBadRequest if the cursor is malformed
+	//   - errors.ErrInternalServer if the query fails
+	ListAuditEntries(filter AuditFilter, opts ListOptions) ([]models.AuditEntry, string, error)
+}
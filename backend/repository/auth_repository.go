@@ -14,4 +14,10 @@ type AuthRepository interface {
 	//   - (*AuthCredentials, nil) on success
 	//   - (nil, errors.ErrInternalServer) if retrieval or parsing fails
 	GetAuthCredentials() (*AuthCredentials, error)
+
+	// UpdateAuthCredentials replaces the stored admin username and hashed password.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the credentials cannot be stored
+	UpdateAuthCredentials(creds AuthCredentials) error
 }
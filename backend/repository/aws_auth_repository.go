@@ -45,22 +45,12 @@ func NewAWSAuthRepository(env string) AuthRepository {
 //   - (*AuthCredentials, nil) on success
 //   - (nil, errors.ErrInternalServer) if the secret cannot be fetched or parsed
 func (a *AWSAuthRepository) GetAuthCredentials() (*AuthCredentials, error) {
-	region := os.Getenv("AWS_REGION")
-	secretName := os.Getenv("AUTH_SECRET_NAME")
-	if secretName == "" {
-		secretName = "rendalla/auth_credentials"
-	}
-
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(region),
-	}))
-	svc := secretsmanager.New(sess)
-
+	secretName := authSecretName()
 	input := &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretName),
 	}
 
-	result, err := svc.GetSecretValue(input)
+	result, err := secretsClient().GetSecretValue(input)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"operation":   "get_auth_credentials",
@@ -86,3 +76,48 @@ func (a *AWSAuthRepository) GetAuthCredentials() (*AuthCredentials, error) {
 
 	return &credentials, nil
 }
+
+// UpdateAuthCredentials stores new admin credentials as a new version of the secret.
+// Secrets Manager keeps the previous version labelled AWSPREVIOUS.
+// Returns:
+//   - nil on success
+//   - errors.ErrInternalServer if the secret cannot be written
+func (a *AWSAuthRepository) UpdateAuthCredentials(creds AuthCredentials) error {
+	secretName := authSecretName()
+	value, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("encoding credentials: %w", errors.ErrInternalServer)
+	}
+
+	_, err = secretsClient().PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretName),
+		SecretString: aws.String(string(value)),
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation":   "update_auth_credentials",
+			"secret_name": secretName,
+		}).WithError(err).Error("Failed to update secret in Secrets Manager")
+		return fmt.Errorf("updating secret %s in Secrets Manager: %w", secretName, errors.ErrInternalServer)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operation":   "update_auth_credentials",
+		"secret_name": secretName,
+	}).Info("Auth credentials updated successfully")
+	return nil
+}
+
+func authSecretName() string {
+	if name := os.Getenv("AUTH_SECRET_NAME"); name != "" {
+		return name
+	}
+	return "rendalla/auth_credentials"
+}
+
+func secretsClient() *secretsmanager.SecretsManager {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	}))
+	return secretsmanager.New(sess)
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/gob"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SharedCache is a cache shared by every instance of the backend, such as Redis or Memcached.
+// RepositoryCache consults it when an entry is not in memory and deletes from it on writes, so
+// instances do not each read DynamoDB. Implementations must be safe for concurrent use; their
+// errors are logged and treated as misses.
+type SharedCache interface {
+	// Get returns the value stored under key, and false if there is none.
+	Get(key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the given keys. Missing keys are not an error.
+	Delete(keys ...string) error
+}
+
+// CacheStats counts how the reads of the cached repositories were served since startup.
+type CacheStats struct {
+	Hits       uint64 // Served from memory
+	SharedHits uint64 // Served from the shared cache
+	Misses     uint64 // Read from DynamoDB
+	Evictions  uint64 // Entries dropped from memory to stay within the size limit
+	Entries    int    // Entries currently in memory
+}
+
+// RepositoryCache is the read-through cache behind CachedSongRepository and CachedDocumentRepository.
+// Entries live in an in-process LRU of at most maxEntries for ttl and, when a SharedCache is given,
+// in the shared cache for the same ttl. Every write to the catalogue invalidates the entries it
+// affects; other instances only see the change once their own copy expires, so ttl bounds how stale
+// a read can be. Values are stored encoded, so callers never share what they get back.
+type RepositoryCache struct {
+	ttl        time.Duration
+	maxEntries int
+	shared     SharedCache
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // Front is the most recently used entry
+	epoch   uint64     // Incremented by every invalidation
+	settled time.Time  // End of the settleWindow of the latest invalidation
+
+	hits       atomic.Uint64
+	sharedHits atomic.Uint64
+	misses     atomic.Uint64
+	evictions  atomic.Uint64
+}
+
+// settleWindow is how long after a write values read from DynamoDB are not cached. The reads are
+// eventually consistent, so they may still return the item as it was before the write.
+const settleWindow = 2 * time.Second
+
+type cacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewRepositoryCache returns a cache keeping up to maxEntries values in memory for ttl.
+// shared may be nil to cache in memory only.
+func NewRepositoryCache(maxEntries int, ttl time.Duration, shared SharedCache) *RepositoryCache {
+	return &RepositoryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		shared:     shared,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Stats returns the hit and miss counters of the cache.
+func (c *RepositoryCache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:       c.hits.Load(),
+		SharedHits: c.sharedHits.Load(),
+		Misses:     c.misses.Load(),
+		Evictions:  c.evictions.Load(),
+		Entries:    entries,
+	}
+}
+
+// Invalidate removes the given keys from memory and from the shared cache.
+func (c *RepositoryCache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	c.epoch++
+	c.settled = c.now().Add(settleWindow)
+	for _, key := range keys {
+		if el, ok := c.entries[key]; ok {
+			c.lru.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if c.shared != nil && len(keys) > 0 {
+		if err := c.shared.Delete(keys...); err != nil {
+			logrus.WithField("keys", keys).WithError(err).Warn("Failed to invalidate shared cache entries")
+		}
+	}
+}
+
+// cachedRead returns the value cached under key, or calls fetch and caches its result.
+// Errors are never cached. A value fetched while an invalidation happened, or within the
+// settleWindow of one, is returned but not cached, since it may predate the write that caused it.
+func cachedRead[T any](c *RepositoryCache, key string, fetch func() (T, error)) (T, error) {
+	var value T
+	if raw, ok := c.getLocal(key); ok && decodeCached(raw, &value) {
+		c.hits.Add(1)
+		return value, nil
+	}
+
+	c.mu.Lock()
+	epoch := c.epoch
+	c.mu.Unlock()
+
+	if raw, ok := c.getShared(key); ok && decodeCached(raw, &value) {
+		c.sharedHits.Add(1)
+		c.setLocal(key, raw, epoch)
+		return value, nil
+	}
+
+	c.misses.Add(1)
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		logrus.WithField("key", key).WithError(err).Warn("Failed to encode cache entry")
+		return value, nil
+	}
+	if c.setLocal(key, buf.Bytes(), epoch) && c.shared != nil {
+		if err := c.shared.Set(key, buf.Bytes(), c.ttl); err != nil {
+			logrus.WithField("key", key).WithError(err).Warn("Failed to write shared cache entry")
+		}
+	}
+	return value, nil
+}
+
+func (c *RepositoryCache) getLocal(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *RepositoryCache) getShared(key string) ([]byte, bool) {
+	if c.shared == nil {
+		return nil, false
+	}
+	raw, ok, err := c.shared.Get(key)
+	if err != nil {
+		logrus.WithField("key", key).WithError(err).Warn("Failed to read shared cache entry")
+		return nil, false
+	}
+	return raw, ok
+}
+
+// setLocal stores value under key unless an invalidation happened since epoch or is still settling,
+// and reports whether it did. The least recently used entries are evicted beyond maxEntries.
+func (c *RepositoryCache) setLocal(key string, value []byte, epoch uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch != epoch || c.now().Before(c.settled) {
+		return false
+	}
+
+	entry := &cacheEntry{key: key, value: value, expiresAt: c.now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return true
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions.Add(1)
+	}
+	return true
+}
+
+func decodeCached(raw []byte, dest interface{}) bool {
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(dest); err != nil {
+		logrus.WithError(err).Warn("Ignoring undecodable cache entry")
+		return false
+	}
+	return true
+}
+
+// Cache keys of the catalogue entries.
+func songsCacheKey() string                        { return "songs" }
+func songCacheKey(songID string) string            { return "song:" + songID }
+func documentsCacheKey(songID string) string       { return "documents:" + songID }
+func documentCacheKey(songID, docID string) string { return "document:" + songID + "/" + docID }
@@ -0,0 +1,78 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// Ensure CachedDocumentRepository implements DocumentRepository.
+var _ DocumentRepository = (*CachedDocumentRepository)(nil)
+
+// CachedDocumentRepository decorates a DocumentRepository with a RepositoryCache.
+// GetDocumentsBySongID and GetDocumentByID are read through the cache, and every write
+// invalidates the entries it affects, whether it succeeds or not.
+type CachedDocumentRepository struct {
+	next  DocumentRepository
+	cache *RepositoryCache
+}
+
+// NewCachedDocumentRepository returns next wrapped in cache.
+func NewCachedDocumentRepository(next DocumentRepository, cache *RepositoryCache) *CachedDocumentRepository {
+	return &CachedDocumentRepository{next: next, cache: cache}
+}
+
+// CreateDocument stores the document, then invalidates the document list of its song.
+func (r *CachedDocumentRepository) CreateDocument(doc models.Document, audit models.AuditEntry) error {
+	defer r.cache.Invalidate(documentsCacheKey(doc.SongID), documentCacheKey(doc.SongID, doc.ID))
+	return r.next.CreateDocument(doc, audit)
+}
+
+// GetDocumentsBySongID returns the live documents of a song, from the cache when possible.
+func (r *CachedDocumentRepository) GetDocumentsBySongID(songID string) ([]models.Document, error) {
+	return cachedRead(r.cache, documentsCacheKey(songID), func() ([]models.Document, error) {
+		return r.next.GetDocumentsBySongID(songID)
+	})
+}
+
+// ListDocumentsBySongID reads a page from storage. Pages are not cached, since every write would have to
+// invalidate each page of the listing.
+func (r *CachedDocumentRepository) ListDocumentsBySongID(songID string, opts ListOptions) ([]models.Document, string, error) {
+	return r.next.ListDocumentsBySongID(songID, opts)
+}
+
+// GetDocumentByID returns a live document, from the cache when possible. Missing documents are not cached.
+func (r *CachedDocumentRepository) GetDocumentByID(songID string, documentID string) (*models.Document, error) {
+	return cachedRead(r.cache, documentCacheKey(songID, documentID), func() (*models.Document, error) {
+		return r.next.GetDocumentByID(songID, documentID)
+	})
+}
+
+// GetDocumentByIDUncached reads the document from storage and drops its cached copy, which may be stale.
+func (r *CachedDocumentRepository) GetDocumentByIDUncached(songID string, documentID string) (*models.Document, error) {
+	r.cache.Invalidate(documentCacheKey(songID, documentID))
+	return r.next.GetDocumentByIDUncached(songID, documentID)
+}
+
+// UpdateDocument updates the document, then invalidates it and the document list of its song.
+func (r *CachedDocumentRepository) UpdateDocument(songID string, documentID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
+	defer r.cache.Invalidate(documentsCacheKey(songID), documentCacheKey(songID, documentID))
+	return r.next.UpdateDocument(songID, documentID, version, updates, audit, revision)
+}
+
+// TrashDocument moves the document to the trash, then invalidates it and the document list of its song.
+func (r *CachedDocumentRepository) TrashDocument(songID string, documentID string, version int, audit models.AuditEntry) error {
+	defer r.cache.Invalidate(documentsCacheKey(songID), documentCacheKey(songID, documentID))
+	return r.next.TrashDocument(songID, documentID, version, audit)
+}
+
+// ScanDocuments reads the whole table from storage, bypassing the cache.
+func (r *CachedDocumentRepository) ScanDocuments(visit func(models.Document)) error {
+	return r.next.ScanDocuments(visit)
+}
+
+// SetDocumentTitles updates the documents, then invalidates them and the document list of their song.
+func (r *CachedDocumentRepository) SetDocumentTitles(songID string, documents []models.Document, title string, audit models.AuditEntry) error {
+	keys := []string{documentsCacheKey(songID)}
+	for _, doc := range documents {
+		keys = append(keys, documentCacheKey(songID, doc.ID))
+	}
+	defer r.cache.Invalidate(keys...)
+	return r.next.SetDocumentTitles(songID, documents, title, audit)
+}
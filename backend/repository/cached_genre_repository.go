@@ -0,0 +1,26 @@
+package repository
+
+// Ensure CachedGenreRepository implements GenreRepository.
+var _ GenreRepository = (*CachedGenreRepository)(nil)
+
+// CachedGenreRepository decorates a GenreRepository so that genre batches, which rewrite the
+// genres of songs, invalidate the entries of CachedSongRepository.
+type CachedGenreRepository struct {
+	GenreRepository
+	cache *RepositoryCache
+}
+
+// NewCachedGenreRepository returns next wrapped so that it invalidates cache.
+func NewCachedGenreRepository(next GenreRepository, cache *RepositoryCache) *CachedGenreRepository {
+	return &CachedGenreRepository{GenreRepository: next, cache: cache}
+}
+
+// ApplyGenreBatch applies the batch, then invalidates the rewritten songs and the song list.
+func (r *CachedGenreRepository) ApplyGenreBatch(batch GenreBatch) (int, error) {
+	keys := []string{songsCacheKey()}
+	for _, song := range batch.Songs {
+		keys = append(keys, songCacheKey(song.ID))
+	}
+	defer r.cache.Invalidate(keys...)
+	return r.GenreRepository.ApplyGenreBatch(batch)
+}
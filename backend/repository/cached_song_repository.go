@@ -0,0 +1,80 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// Ensure CachedSongRepository implements SongRepository.
+var _ SongRepository = (*CachedSongRepository)(nil)
+
+// CachedSongRepository decorates a SongRepository with a RepositoryCache.
+// GetAllSongs and GetSongByID are read through the cache, and every write invalidates the entries
+// it affects, whether it succeeds or not, so a rejected conditional write is retried on fresh data.
+type CachedSongRepository struct {
+	next  SongRepository
+	docs  DocumentRepository
+	cache *RepositoryCache
+}
+
+// NewCachedSongRepository returns next wrapped in cache. docs lists the documents whose entries
+// are invalidated when a song goes to the trash with them.
+func NewCachedSongRepository(next SongRepository, docs DocumentRepository, cache *RepositoryCache) *CachedSongRepository {
+	return &CachedSongRepository{next: next, docs: docs, cache: cache}
+}
+
+// CreateSongWithDocuments stores the song and its documents, then invalidates the song list.
+func (r *CachedSongRepository) CreateSongWithDocuments(song models.Song, documents []models.Document, audit models.AuditEntry) error {
+	defer r.cache.Invalidate(songsCacheKey(), songCacheKey(song.ID), documentsCacheKey(song.ID))
+	return r.next.CreateSongWithDocuments(song, documents, audit)
+}
+
+// GetAllSongs returns the live songs, from the cache when possible.
+func (r *CachedSongRepository) GetAllSongs() ([]models.Song, error) {
+	return cachedRead(r.cache, songsCacheKey(), r.next.GetAllSongs)
+}
+
+// ListSongs reads a page from storage. Pages are not cached, since every write would have to
+// invalidate each page of the listing.
+func (r *CachedSongRepository) ListSongs(opts ListOptions) ([]models.Song, string, error) {
+	return r.next.ListSongs(opts)
+}
+
+// GetSongByID returns a live song, from the cache when possible. Missing songs are not cached.
+func (r *CachedSongRepository) GetSongByID(songID string) (*models.Song, error) {
+	return cachedRead(r.cache, songCacheKey(songID), func() (*models.Song, error) {
+		return r.next.GetSongByID(songID)
+	})
+}
+
+// GetSongByIDUncached reads the song from storage and drops its cached copy, which may be stale.
+func (r *CachedSongRepository) GetSongByIDUncached(songID string) (*models.Song, error) {
+	r.cache.Invalidate(songCacheKey(songID))
+	return r.next.GetSongByIDUncached(songID)
+}
+
+// UpdateSong updates the song, then invalidates it and the song list. A rename also invalidates
+// the documents of the song, which carry its title.
+func (r *CachedSongRepository) UpdateSong(songID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
+	keys := []string{songsCacheKey(), songCacheKey(songID)}
+	if _, renamed := updates["title_normalized"]; renamed {
+		keys = append(keys, r.documentKeys(songID)...)
+	}
+	defer r.cache.Invalidate(keys...)
+	return r.next.UpdateSong(songID, version, updates, audit, revision)
+}
+
+// TrashSongWithDocuments moves the song and its documents to the trash, then invalidates them.
+func (r *CachedSongRepository) TrashSongWithDocuments(songID string, version int, audit models.AuditEntry) error {
+	keys := append([]string{songsCacheKey(), songCacheKey(songID)}, r.documentKeys(songID)...)
+	defer r.cache.Invalidate(keys...)
+	return r.next.TrashSongWithDocuments(songID, version, audit)
+}
+
+// documentKeys returns the cache keys of the document list of a song and of its live documents.
+func (r *CachedSongRepository) documentKeys(songID string) []string {
+	keys := []string{documentsCacheKey(songID)}
+	if documents, err := r.docs.GetDocumentsBySongID(songID); err == nil {
+		for _, doc := range documents {
+			keys = append(keys, documentCacheKey(songID, doc.ID))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,52 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// Ensure CachedTrashRepository implements TrashRepository.
+var _ TrashRepository = (*CachedTrashRepository)(nil)
+
+// CachedTrashRepository decorates a TrashRepository so that restores and completed pending writes invalidate the entries of
+// CachedSongRepository and CachedDocumentRepository. Purges need no invalidation: trashed items
+// already left the cache when they were moved to the trash.
+type CachedTrashRepository struct {
+	TrashRepository
+	cache *RepositoryCache
+}
+
+// NewCachedTrashRepository returns next wrapped so that it invalidates cache.
+func NewCachedTrashRepository(next TrashRepository, cache *RepositoryCache) *CachedTrashRepository {
+	return &CachedTrashRepository{TrashRepository: next, cache: cache}
+}
+
+// RestoreSong restores the song and its documents, then invalidates them.
+func (r *CachedTrashRepository) RestoreSong(songID string, documentIDs []string, audit models.AuditEntry) error {
+	keys := []string{songsCacheKey(), songCacheKey(songID), documentsCacheKey(songID)}
+	for _, docID := range documentIDs {
+		keys = append(keys, documentCacheKey(songID, docID))
+	}
+	defer r.cache.Invalidate(keys...)
+	return r.TrashRepository.RestoreSong(songID, documentIDs, audit)
+}
+
+// RestoreDocument restores the document, then invalidates it and the document list of its song.
+func (r *CachedTrashRepository) RestoreDocument(songID string, documentID string, audit models.AuditEntry) error {
+	defer r.cache.Invalidate(documentsCacheKey(songID), documentCacheKey(songID, documentID))
+	return r.TrashRepository.RestoreDocument(songID, documentID, audit)
+}
+
+// CompletePendingSong finishes the pending write, then invalidates the song and its documents,
+// including each document a completed trash moved out of the live catalogue.
+func (r *CachedTrashRepository) CompletePendingSong(song models.Song) error {
+	err := r.TrashRepository.CompletePendingSong(song)
+
+	keys := []string{songsCacheKey(), songCacheKey(song.ID), documentsCacheKey(song.ID)}
+	if song.Pending == models.SongPendingTrash {
+		if documents, listErr := r.TrashRepository.GetTrashedDocumentsBySongID(song.ID); listErr == nil {
+			for _, doc := range documents {
+				keys = append(keys, documentCacheKey(song.ID, doc.ID))
+			}
+		}
+	}
+	r.cache.Invalidate(keys...)
+	return err
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	stdErrors "errors"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+)
+
+// liveSongFilter matches the songs outside the trash that no multi-step write is in progress on.
+const liveSongFilter = "attribute_not_exists(deleted_at) AND attribute_not_exists(pending)"
+
+// txOp adds one item to a dynamo.WriteTx. Writes are collected as ops so that those larger than
+// maxTransactItems can be split over several transactions.
+type txOp func(tx *dynamo.WriteTx) *dynamo.WriteTx
+
+func updateOp(update *dynamo.Update) txOp {
+	return func(tx *dynamo.WriteTx) *dynamo.WriteTx { return tx.Update(update) }
+}
+
+func putOp(put *dynamo.Put) txOp {
+	return func(tx *dynamo.WriteTx) *dynamo.WriteTx { return tx.Put(put) }
+}
+
+func deleteOp(del *dynamo.Delete) txOp {
+	return func(tx *dynamo.WriteTx) *dynamo.WriteTx { return tx.Delete(del) }
+}
+
+// runInChunks runs ops as consecutive transactions of at most maxTransactItems items each and
+// returns how many ops were committed, along with the unwrapped error of the failed transaction.
+// Each transaction is atomic but the sequence is not: callers order their ops so that a failure
+// between two transactions leaves a state that can be resumed.
+func runInChunks(db *dynamo.DB, ops []txOp) (int, error) {
+	for start := 0; start < len(ops); start += maxTransactItems {
+		end := min(start+maxTransactItems, len(ops))
+
+		tx := db.WriteTx()
+		for _, op := range ops[start:end] {
+			tx = op(tx)
+		}
+		if err := tx.Run(); err != nil {
+			return start, err
+		}
+	}
+	return len(ops), nil
+}
+
+// runGroupsInChunks is runInChunks for groups of ops that must commit together, such as a write and the
+// audit entry describing it: each transaction takes as many whole groups as fit in maxTransactItems.
+// Returns how many groups were committed, along with the unwrapped error of the failed transaction.
+func runGroupsInChunks(db *dynamo.DB, groups [][]txOp) (int, error) {
+	for start := 0; start < len(groups); {
+		end, items := start, 0
+		for end < len(groups) && (end == start || items+len(groups[end]) <= maxTransactItems) {
+			items += len(groups[end])
+			end++
+		}
+
+		tx := db.WriteTx()
+		for _, group := range groups[start:end] {
+			for _, op := range group {
+				tx = op(tx)
+			}
+		}
+		if err := tx.Run(); err != nil {
+			return start, err
+		}
+		start = end
+	}
+	return len(groups), nil
+}
+
+// markPending returns update also recording pending as the write in progress on the song, started at since.
+func markPending(update *dynamo.Update, pending, since string) *dynamo.Update {
+	return update.Set("pending", pending).Set("pending_since", since)
+}
+
+// clearPendingOp returns the op ending the pending write on a song, provided it is still the one in progress.
+func clearPendingOp(db *dynamo.DB, songID, pending string) txOp {
+	return updateOp(db.Table(bootstrap.SongTableName).
+		Update("id", songID).
+		Remove("pending", "pending_since").
+		If("pending = ?", pending))
+}
+
+// songPending reports whether a multi-step write is in progress on the song, whose documents are then
+// hidden like the song itself. A song that does not exist has none.
+func songPending(db *dynamo.DB, songID string) (bool, error) {
+	var song models.Song
+	err := db.Table(bootstrap.SongTableName).Get("id", songID).Project("id", "pending").One(&song)
+	if stdErrors.Is(err, dynamo.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return song.Pending != "", nil
+}
+
+// withoutPendingSongs drops from documents those whose song has a multi-step write in progress,
+// reading the songs they belong to in a single batch.
+func withoutPendingSongs(db *dynamo.DB, documents []models.Document) ([]models.Document, error) {
+	var keys []dynamo.Keyed
+	seen := make(map[string]bool)
+	for _, doc := range documents {
+		if !seen[doc.SongID] {
+			seen[doc.SongID] = true
+			keys = append(keys, dynamo.Keys{doc.SongID})
+		}
+	}
+	if len(keys) == 0 {
+		return documents, nil
+	}
+
+	var songs []models.Song
+	err := db.Table(bootstrap.SongTableName).Batch("id").Get(keys...).Project("id", "pending").All(&songs)
+	if err != nil && !stdErrors.Is(err, dynamo.ErrNotFound) {
+		return nil, err
+	}
+	pending := make(map[string]bool)
+	for _, song := range songs {
+		if song.Pending != "" {
+			pending[song.ID] = true
+		}
+	}
+	if len(pending) == 0 {
+		return documents, nil
+	}
+
+	live := make([]models.Document, 0, len(documents))
+	for _, doc := range documents {
+		if !pending[doc.SongID] {
+			live = append(live, doc)
+		}
+	}
+	return live, nil
+}
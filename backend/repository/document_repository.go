@@ -3,36 +3,71 @@ package repository
 import "github.com/CristinaRendaLopez/rendalla-backend/models"
 
 // DocumentRepository defines operations for managing musical documents (scores or tablatures) stored with a composite key (song_id, id) in the database.
+// Every write also appends the given audit entry, atomically with the change.
+// Documents in the trash are hidden from every read; see TrashRepository to reach them.
 type DocumentRepository interface {
 
-	// CreateDocument stores a new document.
+	// CreateDocument stores a new document and the audit entry.
 	// Returns:
 	//   - nil on success
 	//   - errors.ErrInternalServer if marshalling or persistence fails
-	CreateDocument(doc models.Document) error
+	CreateDocument(doc models.Document, audit models.AuditEntry) error
 
-	// GetDocumentsBySongID returns all documents linked to a given song.
+	// GetDocumentsBySongID returns all documents linked to a given song, except those in the trash.
 	// Returns:
 	//   - ([]models.Document, nil) on success
 	//   - (nil, errors.ErrInternalServer) if the query fails
 	GetDocumentsBySongID(songID string) ([]models.Document, error)
 
+	// ListDocumentsBySongID returns one page of the documents of a song, except those in the trash,
+	// and the token of the next page. opts.Sort may be "type", "created_at" or "updated_at", or empty
+	// for storage order. The documents of a song with a pending write are hidden.
+	// Returns:
+	//   - ([]models.Document, next token, nil) on success; the token is empty on the last page
+	//   - errors.ErrBadRequest if opts.Cursor was not issued for this listing
+	//   - errors.ErrInternalServer if the query fails
+	ListDocumentsBySongID(songID string, opts ListOptions) ([]models.Document, string, error)
+
 	// GetDocumentByID retrieves a document by its song ID and document ID.
 	// Returns:
 	//   - (*models.Document, nil) if found
-	//   - (nil, errors.ErrNotFound) if the document does not exist
+	//   - (nil, errors.ErrNotFound) if the document does not exist, is in the trash or its song has a pending write
 	//   - (nil, errors.ErrInternalServer) if retrieval fails
 	GetDocumentByID(songID string, documentID string) (*models.Document, error)
 
-	// UpdateDocument applies partial updates to a document by its song ID and document ID.
+	// GetDocumentByIDUncached retrieves a document like GetDocumentByID but always from storage, for
+	// writes that must not refuse a client on a stale copy.
+	GetDocumentByIDUncached(songID string, documentID string) (*models.Document, error)
+
+	// UpdateDocument applies partial updates to a document by its song ID and document ID, provided it is
+	// still at version, and stores the audit entry and the revision keeping the replaced version.
+	// The document moves to version+1.
 	// Returns:
 	//   - nil on success
+	//   - errors.ErrPreconditionFailed if the document is no longer at version
+	//   - errors.ErrConflict if the revision number is already taken by a concurrent update
 	//   - errors.ErrInternalServer if the update operation fails
-	UpdateDocument(songID string, documentID string, updates map[string]interface{}) error
+	UpdateDocument(songID string, documentID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error
+
+	// TrashDocument moves a document still at version to the trash and stores the audit entry.
+	// The trash marks record audit.Timestamp and audit.Actor.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrPreconditionFailed if the document no longer exists outside the trash at version
+	//   - errors.ErrInternalServer if the operation fails
+	TrashDocument(songID string, documentID string, version int, audit models.AuditEntry) error
+
+	// ScanDocuments calls visit with every document, live or trashed, as the table is read.
+	// Returns:
+	//   - nil once every document was visited
+	//   - errors.ErrInternalServer if the scan fails
+	ScanDocuments(visit func(models.Document)) error
 
-	// DeleteDocument removes a document by its song ID and document ID.
+	// SetDocumentTitles copies title, the title of the song, to the given documents of it, as written and normalized,
+	// each together with an audit entry derived from audit, the entry of the change that set the title.
+	// Documents deleted in the meantime are skipped.
 	// Returns:
 	//   - nil on success
-	//   - errors.ErrInternalServer if the deletion fails
-	DeleteDocument(songID string, documentID string) error
+	//   - errors.ErrInternalServer if the update fails
+	SetDocumentTitles(songID string, documents []models.Document, title string, audit models.AuditEntry) error
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoAPIKeyRepository implements APIKeyRepository using DynamoDB as backend.
+// Keys are stored in the "APIKeyTable" keyed by key ID.
+type DynamoAPIKeyRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoAPIKeyRepository returns a new instance of DynamoAPIKeyRepository.
+func NewDynamoAPIKeyRepository(db *dynamo.DB) *DynamoAPIKeyRepository {
+	return &DynamoAPIKeyRepository{db: db}
+}
+
+// CreateAPIKey inserts a new API key.
+// Returns errors.ErrInternalServer if the write fails.
+func (d *DynamoAPIKeyRepository) CreateAPIKey(key models.APIKey) error {
+	if err := d.db.Table(bootstrap.APIKeyTableName).Put(key).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": key.ID,
+			"operation":  "create_api_key",
+		}).WithError(err).Error("Failed to create API key")
+		return fmt.Errorf("creating API key %s: %w", key.ID, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// GetAPIKeyByID retrieves an API key by its ID.
+// Returns:
+//   - (*models.APIKey, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if the key does not exist
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoAPIKeyRepository) GetAPIKeyByID(id string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := d.db.Table(bootstrap.APIKeyTableName).Get("id", id).One(&key)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+			"operation":  "get_api_key",
+		}).WithError(err).Debug("API key not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving API key %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return &key, nil
+}
+
+// GetAllAPIKeys retrieves all API keys from the APIKeyTable.
+// Returns a list of keys or an internal error if the scan fails.
+func (d *DynamoAPIKeyRepository) GetAllAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := d.db.Table(bootstrap.APIKeyTableName).Scan().All(&keys)
+	if err != nil {
+		logrus.WithField("operation", "get_all_api_keys").WithError(err).Error("Failed to retrieve API keys")
+		return nil, fmt.Errorf("retrieving all API keys: %w", errors.HandleDynamoError(err))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey marks an existing API key as revoked.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the key does not exist
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoAPIKeyRepository) RevokeAPIKey(id, updatedAt string) error {
+	err := d.db.Table(bootstrap.APIKeyTableName).Update("id", id).
+		Set("status", models.APIKeyStatusRevoked).
+		Set("updated_at", updatedAt).
+		If("attribute_exists(id)").
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+			"operation":  "revoke_api_key",
+		}).WithError(err).Warn("Failed to revoke API key")
+		return fmt.Errorf("revoking API key %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// TouchAPIKey sets the last used timestamp of an API key.
+// Returns errors.ErrInternalServer if the update fails.
+func (d *DynamoAPIKeyRepository) TouchAPIKey(id, lastUsedAt string) error {
+	err := d.db.Table(bootstrap.APIKeyTableName).Update("id", id).
+		Set("last_used_at", lastUsedAt).
+		If("attribute_exists(id)").
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"api_key_id": id,
+			"operation":  "touch_api_key",
+		}).WithError(err).Warn("Failed to record API key usage")
+		return fmt.Errorf("recording usage of API key %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return nil
+}
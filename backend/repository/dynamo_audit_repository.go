@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// AuditTimestampIndex is the global secondary index of the AuditTable partitioned by entity and sorted by
+// timestamp, which lets the trail be read newest first without scanning the table.
+const AuditTimestampIndex = "entity-timestamp-index"
+
+// DynamoAuditRepository implements AuditRepository using DynamoDB as backend.
+// Entries are stored in the "AuditTable" keyed by entry ID and indexed by AuditTimestampIndex.
+type DynamoAuditRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoAuditRepository returns a new instance of DynamoAuditRepository.
+func NewDynamoAuditRepository(db *dynamo.DB) *DynamoAuditRepository {
+	return &DynamoAuditRepository{db: db}
+}
+
+// ListAuditEntries queries AuditTimestampIndex newest first, in the partition of the filtered entity or
+// in those of every entity, and merges what it reads into one page. Each partition is read only until
+// it has contributed a full page after the cursor, which resumes at the timestamp and ID of the last
+// entry returned. Returns the page and its next token, or an internal error if a query fails.
+func (d *DynamoAuditRepository) ListAuditEntries(filter AuditFilter, opts ListOptions) ([]models.AuditEntry, string, error) {
+	opts.Sort, opts.Order = "timestamp", SortOrderDesc
+	cursor, err := decodeListCursor(opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding audit cursor: %w", err)
+	}
+
+	entities := []string{models.AuditEntitySong, models.AuditEntityDocument}
+	if filter.Entity != "" {
+		entities = []string{filter.Entity}
+	}
+
+	collector := newSortedPageCollector(opts, cursor, auditPosition)
+	for _, entity := range entities {
+		if err := d.collectAuditEntries(entity, filter, cursor, collector); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"entity":    entity,
+				"actor":     filter.Actor,
+				"operation": "list_audit_entries",
+			}).WithError(err).Error("Failed to retrieve audit entries")
+			return nil, "", fmt.Errorf("retrieving audit entries: %w", errors.HandleDynamoError(err))
+		}
+	}
+
+	page, next := collector.page(opts)
+	return page, next, nil
+}
+
+// collectAuditEntries feeds collector the entries of one entity partition, newest first, until it has
+// read more than a page after the cursor. Entries sharing the timestamp of the last one counted are
+// read as well, since the index leaves them in no particular order and the page breaks ties by ID.
+func (d *DynamoAuditRepository) collectAuditEntries(entity string, filter AuditFilter, cursor *listCursor, collector *sortedPageCollector[models.AuditEntry]) error {
+	query := d.db.Table(bootstrap.AuditTableName).Get("entity", entity).Index(AuditTimestampIndex).Order(dynamo.Descending)
+	switch {
+	case cursor != nil && filter.Since != "":
+		if cursor.Value < filter.Since {
+			return nil
+		}
+		query = query.Range("timestamp", dynamo.Between, filter.Since, cursor.Value)
+	case cursor != nil:
+		query = query.Range("timestamp", dynamo.LessOrEqual, cursor.Value)
+	case filter.Since != "":
+		query = query.Range("timestamp", dynamo.GreaterOrEqual, filter.Since)
+	}
+	if filter.Actor != "" {
+		query = query.Filter("actor = ?", filter.Actor)
+	}
+
+	iter := query.Iter()
+	counted, last := 0, ""
+	var entry models.AuditEntry
+	for iter.Next(&entry) {
+		if counted > collector.limit && entry.Timestamp != last {
+			break
+		}
+		if collector.after == nil || collector.after.before(auditPosition(entry), true) {
+			counted++
+			last = entry.Timestamp
+		}
+		collector.add(entry)
+		entry = models.AuditEntry{}
+	}
+	return iter.Err()
+}
+
+// auditPosition places an entry in the trail by its timestamp, then its ID.
+func auditPosition(entry models.AuditEntry) listPosition {
+	return listPosition{value: entry.Timestamp, id: entry.ID}
+}
+
+// documentTitleAudit derives from audit, the entry of the change that set the title of a song, the entry
+// recording that doc received the new normalized title. Its ID is derived from both, so that it is unique
+// and a retried write cannot append it twice.
+func documentTitleAudit(audit models.AuditEntry, songID string, doc models.Document, title string) models.AuditEntry {
+	return models.AuditEntry{
+		ID:        audit.ID + "/" + doc.ID,
+		Entity:    models.AuditEntityDocument,
+		EntityID:  doc.ID,
+		SongID:    songID,
+		Action:    models.AuditActionUpdate,
+		Actor:     audit.Actor,
+		RequestID: audit.RequestID,
+		Timestamp: audit.Timestamp,
+		Changes: []models.FieldChange{
+			{Field: "title", Before: doc.Title, After: title},
+			{Field: "title_normalized", Before: doc.TitleNormalized, After: utils.Normalize(title)},
+		},
+	}
+}
+
+// auditPut returns the put that appends entry to the AuditTable, for use in a dynamo.WriteTx.
+func auditPut(db *dynamo.DB, entry models.AuditEntry) *dynamo.Put {
+	return db.Table(bootstrap.AuditTableName).Put(entry).If("attribute_not_exists(id)")
+}
@@ -8,8 +8,6 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/guregu/dynamo"
 	"github.com/sirupsen/logrus"
@@ -18,6 +16,7 @@ import (
 // DynamoDocumentRepository implements DocumentRepository using Amazon DynamoDB as the storage layer.
 // Documents are stored in a table with a composite primary key: (song_id, id).
 // This allows efficient access to all documents for a given song, and supports direct lookup by document ID.
+// Every write appends its entry to the "AuditTable" in the same transaction.
 type DynamoDocumentRepository struct {
 	db *dynamo.DB
 }
@@ -27,11 +26,11 @@ func NewDynamoDocumentRepository(db *dynamo.DB) *DynamoDocumentRepository {
 	return &DynamoDocumentRepository{db: db}
 }
 
-// CreateDocument inserts a new document into the DocumentTable.
+// CreateDocument inserts a new document into the DocumentTable and appends the audit entry in the same transaction.
 // It generates a UUID for the document ID and sets creation/update timestamps.
 // Returns:
 //   - errors.ErrInternalServer if marshalling fails or the write operation fails.
-func (d *DynamoDocumentRepository) CreateDocument(doc models.Document) error {
+func (d *DynamoDocumentRepository) CreateDocument(doc models.Document, audit models.AuditEntry) error {
 
 	if doc.ID == "" {
 		logrus.WithField("operation", "create").Error("Missing document ID")
@@ -51,12 +50,10 @@ func (d *DynamoDocumentRepository) CreateDocument(doc models.Document) error {
 		return fmt.Errorf("failed to marshal document %s: %w", doc.ID, errors.ErrInternalServer)
 	}
 
-	input := &dynamodb.PutItemInput{
-		TableName: aws.String(bootstrap.DocumentTableName),
-		Item:      docItem,
-	}
-
-	_, err = d.db.Client().PutItem(input)
+	err = d.db.WriteTx().
+		Put(d.db.Table(bootstrap.DocumentTableName).Put(docItem)).
+		Put(auditPut(d.db, audit)).
+		Run()
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"document_id": doc.ID,
@@ -74,7 +71,7 @@ func (d *DynamoDocumentRepository) CreateDocument(doc models.Document) error {
 	return nil
 }
 
-// GetDocumentsBySongID retrieves all documents associated with the specified song ID.
+// GetDocumentsBySongID retrieves all documents associated with the specified song ID, skipping those in the trash.
 // Returns:
 //   - ([]models.Document, nil) on success
 //   - (nil, errors.ErrInternalServer) if the query fails
@@ -83,6 +80,7 @@ func (d *DynamoDocumentRepository) GetDocumentsBySongID(songID string) ([]models
 
 	err := d.db.Table(bootstrap.DocumentTableName).
 		Get("song_id", songID).
+		Filter("attribute_not_exists(deleted_at)").
 		All(&documents)
 
 	if err != nil {
@@ -100,10 +98,91 @@ func (d *DynamoDocumentRepository) GetDocumentsBySongID(songID string) ([]models
 	return documents, nil
 }
 
+// ListDocumentsBySongID returns one page of the live documents of a song. Without opts.Sort the
+// page follows the key order of the song's partition and only the items of the page are read.
+// Sorted pages stream the partition, keeping only opts.Limit+1 documents in memory.
+// While a multi-step write is in progress on the song its documents are hidden and the page is empty.
+// Returns errors.ErrBadRequest for a cursor that does not belong to the listing, errors.ErrInternalServer if the query fails.
+func (d *DynamoDocumentRepository) ListDocumentsBySongID(songID string, opts ListOptions) ([]models.Document, string, error) {
+	cursor, err := decodeListCursor(opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding documents cursor: %w", err)
+	}
+
+	pending, err := songPending(d.db, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "list_documents",
+		}).WithError(err).Error("Failed to check the song of the documents")
+		return nil, "", fmt.Errorf("checking song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	if pending {
+		return []models.Document{}, "", nil
+	}
+
+	query := d.db.Table(bootstrap.DocumentTableName).
+		Get("song_id", songID).
+		Filter("attribute_not_exists(deleted_at)")
+	if opts.Sort == "" {
+		query = query.Limit(int64(opts.Limit))
+		if cursor != nil {
+			query = query.StartFrom(cursor.pagingKey())
+		}
+	}
+
+	iter := query.Iter()
+	collector := newSortedPageCollector(opts, cursor, func(doc models.Document) listPosition {
+		return listPosition{value: documentSortValue(doc, opts.Sort), id: doc.ID}
+	})
+	documents := []models.Document{}
+	var doc models.Document
+	for iter.Next(&doc) {
+		if opts.Sort == "" {
+			documents = append(documents, doc)
+		} else {
+			collector.add(doc)
+		}
+		doc = models.Document{}
+	}
+	if err := iter.Err(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "list_documents",
+		}).WithError(err).Error("Failed to list documents")
+		return nil, "", fmt.Errorf("listing documents for song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+
+	if opts.Sort != "" {
+		page, next := collector.page(opts)
+		return page, next, nil
+	}
+	var next string
+	if len(documents) == opts.Limit {
+		if key := iter.LastEvaluatedKey(); key != nil {
+			next = pagingKeyCursor(key)
+		}
+	}
+	return documents, next, nil
+}
+
+// documentSortValue returns the attribute of doc that ListDocumentsBySongID sorts by.
+func documentSortValue(doc models.Document, field string) string {
+	switch field {
+	case "type":
+		return doc.Type
+	case "created_at":
+		return doc.CreatedAt
+	case "updated_at":
+		return doc.UpdatedAt
+	}
+	return ""
+}
+
 // GetDocumentByID retrieves a specific document by song ID and document ID.
 // Returns:
 //   - (*models.Document, nil) on success
-//   - (nil, errors.ErrResourceNotFound) if the document does not exist
+//   - (nil, errors.ErrResourceNotFound) if the document does not exist, is in the trash or its song has a pending write
 //   - (nil, errors.ErrInternalServer) if retrieval or unmarshalling fails
 func (d *DynamoDocumentRepository) GetDocumentByID(songID string, docID string) (*models.Document, error) {
 	var document models.Document
@@ -128,6 +207,21 @@ func (d *DynamoDocumentRepository) GetDocumentByID(songID string, docID string)
 
 		return nil, fmt.Errorf("retrieving document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
 	}
+	if document.DeletedAt != "" {
+		return nil, fmt.Errorf("document %s for song %s is in the trash: %w", docID, songID, errors.ErrResourceNotFound)
+	}
+	pending, err := songPending(d.db, songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"operation":   "get_by_id",
+		}).WithError(err).Error("Failed to check the song of the document")
+		return nil, fmt.Errorf("checking song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	if pending {
+		return nil, fmt.Errorf("song %s of document %s has a pending write: %w", songID, docID, errors.ErrResourceNotFound)
+	}
 
 	logrus.WithFields(logrus.Fields{
 		"document_id": docID,
@@ -138,20 +232,52 @@ func (d *DynamoDocumentRepository) GetDocumentByID(songID string, docID string)
 	return &document, nil
 }
 
-// UpdateDocument applies a partial update to the document identified by song ID and document ID.
-// Automatically updates the updated_at timestamp.
+// GetDocumentByIDUncached is GetDocumentByID; this repository does not cache.
+func (d *DynamoDocumentRepository) GetDocumentByIDUncached(songID string, docID string) (*models.Document, error) {
+	return d.GetDocumentByID(songID, docID)
+}
+
+// UpdateDocument applies a partial update to the document identified by song ID and document ID and,
+// in the same transaction, appends the audit entry and stores the revision keeping the replaced version.
+// Automatically updates the updated_at timestamp and moves the document to version+1.
+// The update only applies while the document is live and still at version.
 // Returns:
 //   - nil on success
+//   - errors.ErrPreconditionFailed if the document changed since it was read
+//   - errors.ErrConflict if the revision number is already taken by a concurrent update
 //   - errors.ErrInternalServer if the update operation fails
-func (d *DynamoDocumentRepository) UpdateDocument(songID, docID string, updates map[string]interface{}) error {
+func (d *DynamoDocumentRepository) UpdateDocument(songID, docID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
 	updates["updated_at"] = time.Now().UTC().Format(time.RFC3339)
-	update := d.db.Table(bootstrap.DocumentTableName).Update("song_id", songID).Range("id", docID)
+	updates["version"] = version + 1
+	update := ifLiveAtVersion(d.db.Table(bootstrap.DocumentTableName).Update("song_id", songID).Range("id", docID), version)
 
 	for key, value := range updates {
 		update = update.Set(key, value)
 	}
 
-	err := update.Run()
+	err := d.db.WriteTx().
+		Update(update).
+		Put(auditPut(d.db, audit)).
+		Put(revisionPut(d.db, revision)).
+		Run()
+	if errors.ConditionFailedAt(err, 0) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"version":     version,
+			"operation":   "update",
+		}).Warn("Document changed since it was read")
+		return fmt.Errorf("updating document %s for song %s at version %d: %w", docID, songID, version, errors.ErrPreconditionFailed)
+	}
+	if errors.ConditionFailedAt(err, revisionIndex) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"revision":    revision.Revision,
+			"operation":   "update",
+		}).Warn("Revision number taken by a concurrent update")
+		return fmt.Errorf("updating document %s for song %s: revision %d already exists: %w", docID, songID, revision.Revision, errors.ErrConflict)
+	}
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":     songID,
@@ -169,29 +295,89 @@ func (d *DynamoDocumentRepository) UpdateDocument(songID, docID string, updates
 	return nil
 }
 
-// DeleteDocument removes a document identified by song ID and document ID from the DocumentTable.
+// TrashDocument marks a document identified by song ID and document ID as trashed and appends the
+// audit entry in the same transaction. The document must still be live and at version.
 // Returns:
 //   - nil on success
-//   - errors.ErrInternalServer if the delete operation fails
-func (d *DynamoDocumentRepository) DeleteDocument(songID string, docID string) error {
-	err := d.db.Table(bootstrap.DocumentTableName).
-		Delete("song_id", songID).
-		Range("id", docID).
-		Run()
+//   - errors.ErrPreconditionFailed if the document changed, or went to the trash, since it was read
+//   - errors.ErrInternalServer if the operation fails
+func (d *DynamoDocumentRepository) TrashDocument(songID string, docID string, version int, audit models.AuditEntry) error {
+	update := ifLiveAtVersion(d.db.Table(bootstrap.DocumentTableName).Update("song_id", songID).Range("id", docID), version).
+		Set("deleted_at", audit.Timestamp).
+		Set("deleted_by", audit.Actor)
+
+	err := d.db.WriteTx().Update(update).Put(auditPut(d.db, audit)).Run()
 
+	if errors.ConditionFailedAt(err, 0) {
+		logrus.WithFields(logrus.Fields{
+			"document_id": docID,
+			"song_id":     songID,
+			"version":     version,
+			"operation":   "trash",
+		}).Warn("Document changed since it was read")
+		return fmt.Errorf("trashing document %s for song %s at version %d: %w", docID, songID, version, errors.ErrPreconditionFailed)
+	}
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"document_id": docID,
 			"song_id":     songID,
-			"operation":   "delete",
-		}).WithError(err).Error("Failed to delete document")
-		return fmt.Errorf("deleting document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
+			"operation":   "trash",
+		}).WithError(err).Error("Failed to trash document")
+		return fmt.Errorf("trashing document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"document_id": docID,
 		"song_id":     songID,
-		"operation":   "delete_document",
-	}).Info("Document deleted successfully")
+		"operation":   "trash_document",
+	}).Info("Document moved to the trash")
+	return nil
+}
+
+// ScanDocuments streams the whole DocumentTable through visit, including the trash, without holding it in memory.
+func (d *DynamoDocumentRepository) ScanDocuments(visit func(models.Document)) error {
+	iter := d.db.Table(bootstrap.DocumentTableName).Scan().Iter()
+	var doc models.Document
+	for iter.Next(&doc) {
+		visit(doc)
+		doc = models.Document{}
+	}
+	if err := iter.Err(); err != nil {
+		logrus.WithField("operation", "scan_documents").WithError(err).Error("Failed to scan documents")
+		return fmt.Errorf("scanning documents: %w", errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// SetDocumentTitles updates title and title_normalized on each of the given documents, in a transaction of its own
+// that also appends its audit entry. The updates are independent and not versioned: the title is copied
+// from the song rather than edited, and a document that no longer exists fails its condition and is skipped.
+func (d *DynamoDocumentRepository) SetDocumentTitles(songID string, documents []models.Document, title string, audit models.AuditEntry) error {
+	updated := 0
+	for _, doc := range documents {
+		err := d.db.WriteTx().
+			Update(documentTitleUpdate(d.db, songID, doc.ID, title)).
+			Put(auditPut(d.db, documentTitleAudit(audit, songID, doc, title))).
+			Run()
+		if errors.ConditionFailedAt(err, 0) {
+			continue
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id":     songID,
+				"document_id": doc.ID,
+				"updated":     updated,
+				"operation":   "set_document_titles",
+			}).WithError(err).Error("Failed to update document title")
+			return fmt.Errorf("updating title of document %s for song %s: %w", doc.ID, songID, errors.HandleDynamoError(err))
+		}
+		updated++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   songID,
+		"documents": updated,
+		"operation": "set_document_titles",
+	}).Info("Document titles updated")
 	return nil
 }
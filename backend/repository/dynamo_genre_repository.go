@@ -0,0 +1,136 @@
+package repository
+
+import (
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// maxTransactItems is the maximum number of items DynamoDB accepts in a single TransactWriteItems call.
+const maxTransactItems = 100
+
+// DynamoGenreRepository implements GenreRepository using DynamoDB as backend.
+// Genres are stored in the "GenreTable" keyed by id; songs reference genres by name.
+type DynamoGenreRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoGenreRepository returns a new instance of DynamoGenreRepository.
+func NewDynamoGenreRepository(db *dynamo.DB) *DynamoGenreRepository {
+	return &DynamoGenreRepository{db: db}
+}
+
+// GetAllGenres retrieves all genres from the GenreTable.
+// Returns a list of genres or an internal error if the query fails.
+func (d *DynamoGenreRepository) GetAllGenres() ([]models.Genre, error) {
+	var genres []models.Genre
+	err := d.db.Table(bootstrap.GenreTableName).Scan().All(&genres)
+	if err != nil {
+		logrus.WithField("operation", "get_all_genres").WithError(err).Error("Failed to retrieve genres")
+		return nil, fmt.Errorf("retrieving all genres: %w", errors.HandleDynamoError(err))
+	}
+	return genres, nil
+}
+
+// CreateGenre inserts a new genre into the GenreTable.
+// The write is conditional on the ID not being in use.
+// Returns errors.ErrInternalServer if the write fails.
+func (d *DynamoGenreRepository) CreateGenre(genre models.Genre) error {
+	err := d.db.Table(bootstrap.GenreTableName).Put(genre).If("attribute_not_exists(id)").Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"genre_id":  genre.ID,
+			"operation": "create_genre",
+		}).WithError(err).Error("Failed to create genre")
+		return fmt.Errorf("creating genre %s: %w", genre.ID, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genre_id":  genre.ID,
+		"operation": "create_genre",
+	}).Info("Genre created successfully")
+	return nil
+}
+
+// ScanAllSongs reads every song straight from the SongTable, including songs in the trash and songs
+// with a write in progress, so that genre batches rewrite them too.
+// Returns an internal error if the scan fails.
+func (d *DynamoGenreRepository) ScanAllSongs() ([]models.Song, error) {
+	var songs []models.Song
+	if err := d.db.Table(bootstrap.SongTableName).Scan().All(&songs); err != nil {
+		logrus.WithField("operation", "scan_all_songs").WithError(err).Error("Failed to scan songs")
+		return nil, fmt.Errorf("scanning songs: %w", errors.HandleDynamoError(err))
+	}
+	return songs, nil
+}
+
+// ApplyGenreBatch executes a rename or merge job as a sequence of transactional writes of
+// at most maxTransactItems items each.
+//
+// Song rewrites are written first and genre changes last, so that if the job fails halfway
+// the catalogue still holds the original genre and the same operation can simply be retried:
+// songs already rewritten no longer match and the remaining ones are picked up again.
+// Every rewritten song moves to its next version, so writes based on its previous ETag are rejected,
+// and is committed together with its audit entry, so the trail never misses a rewrite that happened.
+// A song is only rewritten while it is at its scanned version and still in or out of the trash as it was
+// scanned; otherwise the batch stops with errors.ErrPreconditionFailed before the genre changes, and the
+// caller rescans the songs to retry.
+// Returns the number of songs rewritten, and errors.ErrInternalServer on any other write failure.
+func (d *DynamoGenreRepository) ApplyGenreBatch(batch GenreBatch) (int, error) {
+	groups := make([][]txOp, 0, len(batch.Songs)+len(batch.Genres)+len(batch.DeletedIDs))
+	for _, song := range batch.Songs {
+		update := d.db.Table(bootstrap.SongTableName).
+			Update("id", song.ID).
+			Set("genres", song.Genres).
+			Set("updated_at", batch.UpdatedAt).
+			Add("version", 1)
+		update = ifAtVersion(update.If("attribute_exists(id)"), song.Version)
+		if song.DeletedAt == "" {
+			update = update.If("attribute_not_exists(deleted_at)")
+		} else {
+			update = update.If("deleted_at = ?", song.DeletedAt)
+		}
+
+		group := []txOp{updateOp(update)}
+		if audit, ok := batch.Audits[song.ID]; ok {
+			group = append(group, putOp(auditPut(d.db, audit)))
+		}
+		groups = append(groups, group)
+	}
+	for _, genre := range batch.Genres {
+		groups = append(groups, []txOp{putOp(d.db.Table(bootstrap.GenreTableName).Put(genre))})
+	}
+	for _, genreID := range batch.DeletedIDs {
+		groups = append(groups, []txOp{deleteOp(d.db.Table(bootstrap.GenreTableName).Delete("id", genreID))})
+	}
+
+	written, err := runGroupsInChunks(d.db, groups)
+	songsWritten := min(written, len(batch.Songs))
+	if err != nil {
+		// Only song rewrites carry a condition, so a failed one means a song changed since the scan.
+		mapped := errors.HandleDynamoError(err)
+		if stdErrors.Is(mapped, errors.ErrOperationNotAllowed) {
+			mapped = errors.ErrPreconditionFailed
+		}
+		logrus.WithFields(logrus.Fields{
+			"songs":     len(batch.Songs),
+			"written":   written,
+			"total":     len(groups),
+			"operation": "apply_genre_batch",
+		}).WithError(err).Error("Failed to apply genre batch")
+		return songsWritten, fmt.Errorf("applying genre batch (%d of %d items written): %w", written, len(groups), mapped)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"songs":     len(batch.Songs),
+		"genres":    len(batch.Genres),
+		"deleted":   len(batch.DeletedIDs),
+		"operation": "apply_genre_batch",
+	}).Info("Genre batch applied successfully")
+	return songsWritten, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoIdempotencyRepository implements IdempotencyRepository using DynamoDB as backend.
+// Records are stored in the "IdempotencyTable" keyed by actor and key; "expires_at" can be
+// configured as the table TTL attribute so expired keys are removed automatically.
+type DynamoIdempotencyRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoIdempotencyRepository returns a new instance of DynamoIdempotencyRepository.
+func NewDynamoIdempotencyRepository(db *dynamo.DB) *DynamoIdempotencyRepository {
+	return &DynamoIdempotencyRepository{db: db}
+}
+
+// ReserveIdempotencyKey puts the record with a condition, so that of two concurrent requests with the
+// same key only one runs. Expired records still waiting for the TTL sweep are overwritten.
+func (d *DynamoIdempotencyRepository) ReserveIdempotencyKey(key models.IdempotencyKey, now int64) error {
+	err := d.db.Table(bootstrap.IdempotencyTableName).
+		Put(key).
+		If("attribute_not_exists(id) OR expires_at <= ? OR (attribute_not_exists(response) AND locked_until <= ?)", now, now).
+		Run()
+	if err != nil {
+		mapped := errors.HandleDynamoError(err)
+		if !stdErrors.Is(mapped, errors.ErrOperationNotAllowed) {
+			logrus.WithFields(logrus.Fields{
+				"operation": "reserve_idempotency_key",
+			}).WithError(err).Error("Failed to reserve idempotency key")
+		}
+		return fmt.Errorf("reserving idempotency key: %w", mapped)
+	}
+	return nil
+}
+
+// GetIdempotencyKey retrieves a record by ID.
+// Returns:
+//   - (*models.IdempotencyKey, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if no record exists
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoIdempotencyRepository) GetIdempotencyKey(id string) (*models.IdempotencyKey, error) {
+	var key models.IdempotencyKey
+	err := d.db.Table(bootstrap.IdempotencyTableName).Get("id", id).Consistent(true).One(&key)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "get_idempotency_key",
+		}).WithError(err).Debug("Idempotency key not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving idempotency key: %w", errors.HandleDynamoError(err))
+	}
+	return &key, nil
+}
+
+// CompleteIdempotencyKey sets the response and removes the lock, provided the record still belongs
+// to the request with fingerprint and has no response yet.
+func (d *DynamoIdempotencyRepository) CompleteIdempotencyKey(id string, fingerprint string, response models.StoredResponse) error {
+	err := d.db.Table(bootstrap.IdempotencyTableName).
+		Update("id", id).
+		Set("response", response).
+		Remove("locked_until").
+		If("fingerprint = ? AND attribute_not_exists(response)", fingerprint).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "complete_idempotency_key",
+		}).WithError(err).Error("Failed to store idempotent response")
+		return fmt.Errorf("completing idempotency key: %w", errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey deletes the record if it still belongs to the unfinished request with fingerprint.
+func (d *DynamoIdempotencyRepository) ReleaseIdempotencyKey(id string, fingerprint string) error {
+	err := d.db.Table(bootstrap.IdempotencyTableName).
+		Delete("id", id).
+		If("fingerprint = ? AND attribute_not_exists(response)", fingerprint).
+		Run()
+	if stdErrors.Is(errors.HandleDynamoError(err), errors.ErrOperationNotAllowed) {
+		return nil
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "release_idempotency_key",
+		}).WithError(err).Error("Failed to release idempotency key")
+		return fmt.Errorf("releasing idempotency key: %w", errors.HandleDynamoError(err))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoLoginAttemptRepository implements LoginAttemptRepository using DynamoDB as backend.
+// Records are stored in the "LoginAttemptTable" keyed by throttling key; "expires_at" can be
+// configured as the table TTL attribute so stale records are removed automatically.
+type DynamoLoginAttemptRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoLoginAttemptRepository returns a new instance of DynamoLoginAttemptRepository.
+func NewDynamoLoginAttemptRepository(db *dynamo.DB) *DynamoLoginAttemptRepository {
+	return &DynamoLoginAttemptRepository{db: db}
+}
+
+// GetLoginAttempt retrieves the failures recorded for a throttling key.
+// Returns:
+//   - (*models.LoginAttempt, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if no failure is recorded
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoLoginAttemptRepository) GetLoginAttempt(id string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := d.db.Table(bootstrap.LoginAttemptTableName).Get("id", id).One(&attempt)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":       id,
+			"operation": "get_login_attempt",
+		}).WithError(err).Debug("Login attempt not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving login attempts of %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return &attempt, nil
+}
+
+// RecordLoginFailure increments the failure counter with an atomic ADD, so concurrent
+// failures are all counted, and returns the updated record.
+// Returns errors.ErrInternalServer if the update fails.
+func (d *DynamoLoginAttemptRepository) RecordLoginFailure(id string, failedAt, expiresAt int64) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := d.db.Table(bootstrap.LoginAttemptTableName).Update("id", id).
+		Add("failures", 1).
+		Set("last_failure_at", failedAt).
+		Set("expires_at", expiresAt).
+		Value(&attempt)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":       id,
+			"operation": "record_login_failure",
+		}).WithError(err).Error("Failed to record login failure")
+		return nil, fmt.Errorf("recording login failure of %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return &attempt, nil
+}
+
+// LockLoginAttempt sets the lockout deadline of a key.
+// Returns errors.ErrInternalServer if the update fails.
+func (d *DynamoLoginAttemptRepository) LockLoginAttempt(id string, lockedUntil, expiresAt int64) error {
+	err := d.db.Table(bootstrap.LoginAttemptTableName).Update("id", id).
+		Set("locked_until", lockedUntil).
+		Set("expires_at", expiresAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":       id,
+			"operation": "lock_login_attempt",
+		}).WithError(err).Error("Failed to lock login")
+		return fmt.Errorf("locking logins of %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// DeleteLoginAttempt removes the record of a key. Deleting a missing record is not an error.
+// Returns errors.ErrInternalServer if the delete fails.
+func (d *DynamoLoginAttemptRepository) DeleteLoginAttempt(id string) error {
+	if err := d.db.Table(bootstrap.LoginAttemptTableName).Delete("id", id).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"key":       id,
+			"operation": "delete_login_attempt",
+		}).WithError(err).Error("Failed to delete login attempts")
+		return fmt.Errorf("deleting login attempts of %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoOIDCLoginRepository implements OIDCLoginRepository using DynamoDB as backend.
+// Pending logins are stored in the "OIDCLoginTable" keyed by state; "expires_at" can be
+// configured as the table TTL attribute so abandoned logins are removed automatically.
+type DynamoOIDCLoginRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoOIDCLoginRepository returns a new instance of DynamoOIDCLoginRepository.
+func NewDynamoOIDCLoginRepository(db *dynamo.DB) *DynamoOIDCLoginRepository {
+	return &DynamoOIDCLoginRepository{db: db}
+}
+
+// CreateOIDCLogin inserts a pending login.
+// Returns errors.ErrInternalServer if the write fails.
+func (d *DynamoOIDCLoginRepository) CreateOIDCLogin(login models.OIDCLogin) error {
+	if err := d.db.Table(bootstrap.OIDCLoginTableName).Put(login).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "create_oidc_login",
+		}).WithError(err).Error("Failed to create OIDC login")
+		return fmt.Errorf("creating OIDC login: %w", errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// ConsumeOIDCLogin deletes a pending login and returns the deleted item.
+// Returns:
+//   - (*models.OIDCLogin, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if no login is pending for the state
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoOIDCLoginRepository) ConsumeOIDCLogin(id string) (*models.OIDCLogin, error) {
+	var login models.OIDCLogin
+	err := d.db.Table(bootstrap.OIDCLoginTableName).Delete("id", id).OldValue(&login)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "consume_oidc_login",
+		}).WithError(err).Debug("OIDC login not found or failed to consume")
+		return nil, fmt.Errorf("consuming OIDC login: %w", errors.HandleDynamoError(err))
+	}
+	return &login, nil
+}
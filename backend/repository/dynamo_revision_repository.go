@@ -0,0 +1,99 @@
+package repository
+
+import (
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoRevisionRepository implements RevisionRepository using DynamoDB as backend.
+// Revisions are stored in the "RevisionTable" with a composite primary key: (song_id, revision).
+type DynamoRevisionRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoRevisionRepository returns a new instance of DynamoRevisionRepository.
+func NewDynamoRevisionRepository(db *dynamo.DB) *DynamoRevisionRepository {
+	return &DynamoRevisionRepository{db: db}
+}
+
+// GetRevisionsBySongID queries the RevisionTable for the revisions of a song, newest first.
+// Returns the revisions or an internal error if the query fails.
+func (d *DynamoRevisionRepository) GetRevisionsBySongID(songID string) ([]models.Revision, error) {
+	var revisions []models.Revision
+	err := d.db.Table(bootstrap.RevisionTableName).
+		Get("song_id", songID).
+		Order(dynamo.Descending).
+		All(&revisions)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "get_revisions",
+		}).WithError(err).Error("Failed to retrieve revisions")
+		return nil, fmt.Errorf("retrieving revisions of song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision by song ID and revision number.
+// Returns:
+//   - (*models.Revision, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if the revision does not exist
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoRevisionRepository) GetRevision(songID string, revision int) (*models.Revision, error) {
+	var rev models.Revision
+	err := d.db.Table(bootstrap.RevisionTableName).
+		Get("song_id", songID).
+		Range("revision", dynamo.Equal, revision).
+		One(&rev)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"revision":  revision,
+			"operation": "get_revision",
+		}).WithError(err).Warn("Revision not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving revision %d of song %s: %w", revision, songID, errors.HandleDynamoError(err))
+	}
+	return &rev, nil
+}
+
+// GetLatestRevisionNumber reads the newest revision of a song with a strongly consistent read, so that
+// a revision written by the previous update is always seen and its number is not handed out again.
+// Returns 0 if the song has no revisions, or an internal error if the query fails.
+func (d *DynamoRevisionRepository) GetLatestRevisionNumber(songID string) (int, error) {
+	var rev models.Revision
+	err := d.db.Table(bootstrap.RevisionTableName).
+		Get("song_id", songID).
+		Order(dynamo.Descending).
+		Limit(1).
+		Consistent(true).
+		One(&rev)
+	if stdErrors.Is(err, dynamo.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "get_latest_revision",
+		}).WithError(err).Error("Failed to retrieve latest revision")
+		return 0, fmt.Errorf("retrieving latest revision of song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	return rev.Revision, nil
+}
+
+// revisionPut returns the put that stores revision in the RevisionTable, for use in a dynamo.WriteTx.
+// The condition makes two concurrent updates claiming the same revision number fail instead of
+// overwriting each other's copy; writers put it third in their transaction and report the failure
+// as errors.ErrConflict (see revisionIndex).
+func revisionPut(db *dynamo.DB, revision models.Revision) *dynamo.Put {
+	return db.Table(bootstrap.RevisionTableName).Put(revision).If("attribute_not_exists(revision)")
+}
+
+// revisionIndex is the position of revisionPut in the transaction of an update: after the update
+// itself and its audit entry.
+const revisionIndex = 2
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
@@ -14,17 +17,14 @@ import (
 
 // DynamoSearchRepository implements SearchRepository using DynamoDB to filter and list songs and documents.
 // Supports optional filters by title, instrument, and type, and applies sorting and pagination client-side.
+// Songs and documents in the trash are never returned.
 type DynamoSearchRepository struct {
-	db      *dynamo.DB
-	docRepo DocumentRepository
+	db *dynamo.DB
 }
 
 // NewDynamoSearchRepository returns a new instance of DynamoSearchRepository.
-func NewDynamoSearchRepository(db *dynamo.DB, docRepo DocumentRepository) *DynamoSearchRepository {
-	return &DynamoSearchRepository{
-		db:      db,
-		docRepo: docRepo,
-	}
+func NewDynamoSearchRepository(db *dynamo.DB) *DynamoSearchRepository {
+	return &DynamoSearchRepository{db: db}
 }
 
 // ListSongs returns a paginated and optionally filtered list of songs from DynamoDB.
@@ -43,7 +43,8 @@ func NewDynamoSearchRepository(db *dynamo.DB, docRepo DocumentRepository) *Dynam
 func (d *DynamoSearchRepository) ListSongs(title, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Song, PagingKey, error) {
 	var songs []models.Song
 
-	query := d.db.Table(bootstrap.SongTableName).Scan().Limit(int64(limit))
+	query := d.db.Table(bootstrap.SongTableName).Scan().Limit(int64(limit)).
+		Filter(liveSongFilter)
 
 	if title != "" {
 		normalizedTitle := utils.Normalize(title)
@@ -99,10 +100,11 @@ func (d *DynamoSearchRepository) ListSongs(title, sortField, sortOrder string, l
 }
 
 // ListDocuments returns a paginated and optionally filtered list of documents from DynamoDB.
-// Supports filters by normalized title, instrument, and document type, plus sorting and pagination.
+// Supports filters by normalized title, instruments, and document type, plus sorting and pagination.
+// Documents in the trash or of a song with a pending write are skipped.
 // Parameters:
 //   - title: optional search term, matched on title_normalized
-//   - instrument: optional filter by instrument
+//   - instruments: optional list of instrument values, matched with "contains" and combined with OR
 //   - docType: optional filter by "type" field
 //   - sortField: "title" (based on title_normalized) or "created_at"
 //   - sortOrder: "asc" or "desc"
@@ -113,17 +115,24 @@ func (d *DynamoSearchRepository) ListSongs(title, sortField, sortOrder string, l
 //   - A slice of Document models
 //   - A pagination key for the next request (if applicable)
 //   - An error if the query fails
-func (d *DynamoSearchRepository) ListDocuments(title, instrument, docType, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Document, PagingKey, error) {
+func (d *DynamoSearchRepository) ListDocuments(title string, instruments []string, docType, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Document, PagingKey, error) {
 	var documents []models.Document
 
-	query := d.db.Table(bootstrap.DocumentTableName).Scan().Limit(int64(limit))
+	query := d.db.Table(bootstrap.DocumentTableName).Scan().Limit(int64(limit)).
+		Filter("attribute_not_exists(deleted_at)")
 
 	if title != "" {
 		normalizedTitle := utils.Normalize(title)
 		query = query.Filter("contains(title_normalized, ?)", normalizedTitle)
 	}
-	if instrument != "" {
-		query = query.Filter("contains(instrument, ?)", instrument)
+	if len(instruments) > 0 {
+		conditions := make([]string, len(instruments))
+		args := make([]interface{}, len(instruments))
+		for i, instrument := range instruments {
+			conditions[i] = "contains(instrument, ?)"
+			args[i] = instrument
+		}
+		query = query.Filter("("+strings.Join(conditions, " OR ")+")", args...)
 	}
 	if docType != "" {
 		query = query.Filter("'type' = ?", docType)
@@ -137,13 +146,20 @@ func (d *DynamoSearchRepository) ListDocuments(title, instrument, docType, sortF
 	nextKey, err := query.AllWithLastEvaluatedKey(&documents)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
-			"title":      title,
-			"instrument": instrument,
-			"type":       docType,
-			"operation":  "list_documents",
+			"title":       title,
+			"instruments": instruments,
+			"type":        docType,
+			"operation":   "list_documents",
 		}).WithError(err).Error("Failed to list documents")
 		return nil, nil, fmt.Errorf("listing documents: %w", errors.HandleDynamoError(err))
 	}
+	documents, err = withoutPendingSongs(d.db, documents)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"operation": "list_documents",
+		}).WithError(err).Error("Failed to check the songs of the documents")
+		return nil, nil, fmt.Errorf("checking songs of listed documents: %w", errors.HandleDynamoError(err))
+	}
 
 	if sortField == "" {
 		sortField = "created_at"
@@ -177,3 +193,135 @@ func (d *DynamoSearchRepository) ListDocuments(title, instrument, docType, sortF
 
 	return documents, nextKey, nil
 }
+
+// searchScanRequests is how many requests SearchCatalog sends to each table per call at most, which
+// bounds the items read by one search whatever the size of the catalogue and the number of matches.
+const searchScanRequests = 4
+
+// searchCursor is the decoded form of a SearchCatalog token: where to resume the scan of each table.
+type searchCursor struct {
+	SongsDone     bool              `json:"sd,omitempty"` // The songs table was read to the end
+	SongsKey      map[string]string `json:"sk,omitempty"` // LastEvaluatedKey in the songs table; empty to read it from the start
+	DocumentsDone bool              `json:"dd,omitempty"` // The documents table was read to the end
+	DocumentsKey  map[string]string `json:"dk,omitempty"` // LastEvaluatedKey in the documents table; empty to read it from the start
+}
+
+func encodeSearchCursor(cursor searchCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeSearchCursor parses a token produced by encodeSearchCursor. An empty token starts both tables.
+// Returns errors.ErrBadRequest if it is malformed.
+func decodeSearchCursor(token string) (searchCursor, error) {
+	var cursor searchCursor
+	if token == "" {
+		return cursor, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errors.ErrBadRequest
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, errors.ErrBadRequest
+	}
+	return cursor, nil
+}
+
+// SearchCatalog scans the songs table and the documents table side by side for items whose normalized
+// title contains the query, so that every call returns hits of both types to be ranked together. Each
+// table is read in at most searchScanRequests requests per call and up to maxHits matches; the returned
+// token resumes each of them from the last item read. Documents of a song with a pending write are
+// dropped from the matches.
+func (d *DynamoSearchRepository) SearchCatalog(query string, maxHits int, cursor string) ([]models.Song, []models.Document, string, error) {
+	position, err := decodeSearchCursor(cursor)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("decoding search cursor: %w", err)
+	}
+
+	normalizedQuery := utils.Normalize(query)
+	songs := []models.Song{}
+	documents := []models.Document{}
+	next := searchCursor{SongsDone: true, DocumentsDone: true}
+
+	if !position.SongsDone {
+		scan := d.db.Table(bootstrap.SongTableName).Scan().Filter(liveSongFilter)
+		if normalizedQuery != "" {
+			scan = scan.Filter("contains(title_normalized, ?)", normalizedQuery)
+		}
+		var key dynamo.PagingKey
+		songs, key, err = scanMatches[models.Song](scan, position.SongsKey, maxHits)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"query":     query,
+				"operation": "search_catalog",
+			}).WithError(err).Error("Failed to search songs")
+			return nil, nil, "", fmt.Errorf("searching songs: %w", errors.HandleDynamoError(err))
+		}
+		if key != nil {
+			next.SongsDone, next.SongsKey = false, pagingKeyValues(key)
+		}
+	}
+
+	if !position.DocumentsDone {
+		scan := d.db.Table(bootstrap.DocumentTableName).Scan().Filter("attribute_not_exists(deleted_at)")
+		if normalizedQuery != "" {
+			scan = scan.Filter("contains(title_normalized, ?)", normalizedQuery)
+		}
+		var key dynamo.PagingKey
+		documents, key, err = scanMatches[models.Document](scan, position.DocumentsKey, maxHits)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"query":     query,
+				"operation": "search_catalog",
+			}).WithError(err).Error("Failed to search documents")
+			return nil, nil, "", fmt.Errorf("searching documents: %w", errors.HandleDynamoError(err))
+		}
+		documents, err = withoutPendingSongs(d.db, documents)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"query":     query,
+				"operation": "search_catalog",
+			}).WithError(err).Error("Failed to check the songs of the documents found")
+			return nil, nil, "", fmt.Errorf("checking songs of documents found: %w", errors.HandleDynamoError(err))
+		}
+		if key != nil {
+			next.DocumentsDone, next.DocumentsKey = false, pagingKeyValues(key)
+		}
+	}
+
+	var token string
+	if !next.SongsDone || !next.DocumentsDone {
+		token = encodeSearchCursor(next)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operation":      "search_catalog",
+		"song_count":     len(songs),
+		"document_count": len(documents),
+		"next_token?":    token != "",
+	}).Info("Catalog searched successfully")
+
+	return songs, documents, token, nil
+}
+
+// scanMatches reads up to limit items matching scan, in at most searchScanRequests requests and
+// starting after the key start. Returns the items and the key to resume from, nil once the table was read to the end.
+func scanMatches[T any](scan *dynamo.Scan, start map[string]string, limit int) ([]T, dynamo.PagingKey, error) {
+	scan = scan.Limit(int64(limit)).RequestLimit(searchScanRequests)
+	if len(start) > 0 {
+		scan = scan.StartFrom((&listCursor{Key: start}).pagingKey())
+	}
+
+	iter := scan.Iter()
+	items := []T{}
+	var item T
+	for iter.Next(&item) {
+		items = append(items, item)
+		item = *new(T)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, nil, err
+	}
+	return items, iter.LastEvaluatedKey(), nil
+}
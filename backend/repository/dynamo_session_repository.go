@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoSessionRepository implements SessionRepository using DynamoDB as backend.
+// Sessions are stored in the "SessionTable" keyed by session ID.
+type DynamoSessionRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoSessionRepository returns a new instance of DynamoSessionRepository.
+func NewDynamoSessionRepository(db *dynamo.DB) *DynamoSessionRepository {
+	return &DynamoSessionRepository{db: db}
+}
+
+// CreateSession inserts a new session.
+// Returns errors.ErrInternalServer if the write fails.
+func (d *DynamoSessionRepository) CreateSession(session models.Session) error {
+	if err := d.db.Table(bootstrap.SessionTableName).Put(session).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": session.ID,
+			"username":   session.Username,
+			"operation":  "create_session",
+		}).WithError(err).Error("Failed to create session")
+		return fmt.Errorf("creating session for %s: %w", session.Username, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// GetSessionByID retrieves a session by its ID.
+// Returns:
+//   - (*models.Session, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if the session does not exist
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoSessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	var session models.Session
+	err := d.db.Table(bootstrap.SessionTableName).Get("id", id).One(&session)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": id,
+			"operation":  "get_session",
+		}).WithError(err).Debug("Session not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving session %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return &session, nil
+}
+
+// GetSessionsByUsername scans the SessionTable for the sessions of a user.
+// Returns the sessions or an internal error if the scan fails.
+func (d *DynamoSessionRepository) GetSessionsByUsername(username string) ([]models.Session, error) {
+	var sessions []models.Session
+	err := d.db.Table(bootstrap.SessionTableName).Scan().Filter("username = ?", username).All(&sessions)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "get_sessions_by_username",
+		}).WithError(err).Error("Failed to retrieve sessions")
+		return nil, fmt.Errorf("retrieving sessions of %s: %w", username, errors.HandleDynamoError(err))
+	}
+	return sessions, nil
+}
+
+// RotateSessionToken swaps the refresh token hash of an active session in a single conditional update.
+// The condition makes concurrent refreshes with the same token fail instead of forking the family.
+// Returns errors.ErrOperationNotAllowed if the condition fails.
+func (d *DynamoSessionRepository) RotateSessionToken(id, oldHash, newHash string, expiresAt int64, updatedAt string) error {
+	err := d.db.Table(bootstrap.SessionTableName).Update("id", id).
+		Set("token_hash", newHash).
+		Set("expires_at", expiresAt).
+		Set("updated_at", updatedAt).
+		AddStringsToSet("rotated_token_hashes", oldHash).
+		If("token_hash = ? AND 'status' = ?", oldHash, models.SessionStatusActive).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": id,
+			"operation":  "rotate_session_token",
+		}).WithError(err).Warn("Failed to rotate refresh token")
+		return fmt.Errorf("rotating refresh token of session %s: %w", id, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// RevokeSession marks a session as revoked.
+// Returns errors.ErrInternalServer if the update fails.
+func (d *DynamoSessionRepository) RevokeSession(id, reason, updatedAt string) error {
+	err := d.db.Table(bootstrap.SessionTableName).Update("id", id).
+		Set("status", models.SessionStatusRevoked).
+		Set("revoked_reason", reason).
+		Set("updated_at", updatedAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": id,
+			"operation":  "revoke_session",
+		}).WithError(err).Error("Failed to revoke session")
+		return fmt.Errorf("revoking session %s: %w", id, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"session_id": id,
+		"reason":     reason,
+		"operation":  "revoke_session",
+	}).Info("Session revoked")
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -16,7 +17,8 @@ import (
 
 // DynamoSongRepository implements SongRepository using DynamoDB as backend.
 // Songs are stored in the "SongTable", and documents are stored separately in the "DocumentTable".
-// Each song can be created or deleted transactionally along with its associated documents.
+// Each song can be created or moved to the trash transactionally along with its associated documents.
+// Every write appends its entry to the "AuditTable" in the same transaction.
 type DynamoSongRepository struct {
 	db      *dynamo.DB
 	docRepo DocumentRepository
@@ -30,11 +32,12 @@ func NewDynamoSongRepository(db *dynamo.DB, docRepo DocumentRepository) *DynamoS
 	}
 }
 
-// CreateSongWithDocuments stores a new song and its associated documents in a single transactional write.
+// CreateSongWithDocuments stores a new song, its associated documents and the audit entry in a single transactional write.
+// When they exceed maxTransactItems the song is written first, marked models.SongPendingCreate so that
+// it stays hidden, then its documents in further transactions, and the mark is removed last.
+// An interrupted creation is rolled back by TrashRepository.RollBackSongCreation.
 // Returns errors.ErrInternalServer on marshalling errors or any write failure.
-func (d *DynamoSongRepository) CreateSongWithDocuments(song models.Song, documents []models.Document) error {
-	var transactItems []*dynamodb.TransactWriteItem
-
+func (d *DynamoSongRepository) CreateSongWithDocuments(song models.Song, documents []models.Document, audit models.AuditEntry) error {
 	songItem, err := dynamodbattribute.MarshalMap(song)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -44,12 +47,16 @@ func (d *DynamoSongRepository) CreateSongWithDocuments(song models.Song, documen
 		return fmt.Errorf("failed to marshal song %s: %w", song.ID, errors.ErrInternalServer)
 	}
 
-	transactItems = append(transactItems, &dynamodb.TransactWriteItem{
-		Put: &dynamodb.Put{
-			TableName: aws.String(bootstrap.SongTableName),
-			Item:      songItem,
-		},
-	})
+	chunked := len(documents)+2 > maxTransactItems
+	if chunked {
+		songItem["pending"] = &dynamodb.AttributeValue{S: aws.String(models.SongPendingCreate)}
+		songItem["pending_since"] = &dynamodb.AttributeValue{S: aws.String(audit.Timestamp)}
+	}
+
+	ops := []txOp{
+		putOp(d.db.Table(bootstrap.SongTableName).Put(songItem)),
+		putOp(auditPut(d.db, audit)),
+	}
 
 	for i, doc := range documents {
 		docItem, err := dynamodbattribute.MarshalMap(doc)
@@ -63,39 +70,41 @@ func (d *DynamoSongRepository) CreateSongWithDocuments(song models.Song, documen
 			return fmt.Errorf("failed to marshal document %d (doc_id=%s) for song %s: %w", i, doc.ID, song.ID, errors.ErrInternalServer)
 		}
 
-		transactItems = append(transactItems, &dynamodb.TransactWriteItem{
-			Put: &dynamodb.Put{
-				TableName: aws.String(bootstrap.DocumentTableName),
-				Item:      docItem,
-			},
-		})
+		ops = append(ops, putOp(d.db.Table(bootstrap.DocumentTableName).Put(docItem)))
 	}
 
-	input := &dynamodb.TransactWriteItemsInput{
-		TransactItems: transactItems,
+	if chunked {
+		ops = append(ops, clearPendingOp(d.db, song.ID, models.SongPendingCreate))
 	}
 
-	_, err = d.db.Client().TransactWriteItems(input)
+	written, err := runInChunks(d.db, ops)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":   song.ID,
+			"written":   written,
+			"total":     len(ops),
 			"operation": "create",
 		}).WithError(err).Error("Failed to execute transactional write")
-		return fmt.Errorf("transactional creation of song %s failed: %w", song.ID, errors.HandleDynamoError(err))
+		return fmt.Errorf("transactional creation of song %s failed (%d of %d items written): %w", song.ID, written, len(ops), errors.HandleDynamoError(err))
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"song_id":   song.ID,
+		"documents": len(documents),
+		"chunked":   chunked,
 		"operation": "create_song",
 	}).Info("Song and documents created transactionally")
 	return nil
 }
 
-// GetAllSongs retrieves all songs from the SongTable.
+// GetAllSongs retrieves all songs from the SongTable, skipping those in the trash or with a pending write.
 // Returns a list of songs or an internal error if the query fails.
 func (d *DynamoSongRepository) GetAllSongs() ([]models.Song, error) {
 	var songs []models.Song
-	err := d.db.Table(bootstrap.SongTableName).Scan().All(&songs)
+	err := d.db.Table(bootstrap.SongTableName).
+		Scan().
+		Filter(liveSongFilter).
+		All(&songs)
 	if err != nil {
 		logrus.WithField("operation", "get_all").WithError(err).Error("Failed to retrieve songs")
 		return nil, fmt.Errorf("retrieving all songs: %w", errors.HandleDynamoError(err))
@@ -103,10 +112,73 @@ func (d *DynamoSongRepository) GetAllSongs() ([]models.Song, error) {
 	return songs, nil
 }
 
+// ListSongs returns one page of the live songs. Without opts.Sort the page follows the table's
+// storage order and only the items of the page are read. Sorted pages stream the whole scan,
+// keeping only opts.Limit+1 songs in memory.
+// Returns errors.ErrBadRequest for a cursor that does not belong to the listing, errors.ErrInternalServer if the scan fails.
+func (d *DynamoSongRepository) ListSongs(opts ListOptions) ([]models.Song, string, error) {
+	cursor, err := decodeListCursor(opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding songs cursor: %w", err)
+	}
+
+	scan := d.db.Table(bootstrap.SongTableName).Scan().Filter(liveSongFilter)
+	if opts.Sort == "" {
+		scan = scan.Limit(int64(opts.Limit))
+		if cursor != nil {
+			scan = scan.StartFrom(cursor.pagingKey())
+		}
+	}
+
+	iter := scan.Iter()
+	collector := newSortedPageCollector(opts, cursor, func(song models.Song) listPosition {
+		return listPosition{value: songSortValue(song, opts.Sort), id: song.ID}
+	})
+	songs := []models.Song{}
+	var song models.Song
+	for iter.Next(&song) {
+		if opts.Sort == "" {
+			songs = append(songs, song)
+		} else {
+			collector.add(song)
+		}
+		song = models.Song{}
+	}
+	if err := iter.Err(); err != nil {
+		logrus.WithField("operation", "list_songs").WithError(err).Error("Failed to list songs")
+		return nil, "", fmt.Errorf("listing songs: %w", errors.HandleDynamoError(err))
+	}
+
+	if opts.Sort != "" {
+		page, next := collector.page(opts)
+		return page, next, nil
+	}
+	var next string
+	if len(songs) == opts.Limit {
+		if key := iter.LastEvaluatedKey(); key != nil {
+			next = pagingKeyCursor(key)
+		}
+	}
+	return songs, next, nil
+}
+
+// songSortValue returns the attribute of song that ListSongs sorts by.
+func songSortValue(song models.Song, field string) string {
+	switch field {
+	case "title":
+		return song.TitleNormalized
+	case "created_at":
+		return song.CreatedAt
+	case "updated_at":
+		return song.UpdatedAt
+	}
+	return ""
+}
+
 // GetSongByID retrieves a song by its ID.
 // Returns:
 //   - (*models.Song, nil) on success
-//   - (nil, errors.ErrResourceNotFound) if the song does not exist
+//   - (nil, errors.ErrResourceNotFound) if the song does not exist or is in the trash
 //   - (nil, errors.ErrInternalServer) for marshalling or database access errors
 func (d *DynamoSongRepository) GetSongByID(id string) (*models.Song, error) {
 	var song models.Song
@@ -118,6 +190,12 @@ func (d *DynamoSongRepository) GetSongByID(id string) (*models.Song, error) {
 		}).WithError(err).Warn("Song not found or failed to retrieve")
 		return nil, fmt.Errorf("retrieving song %s: %w", id, errors.HandleDynamoError(err))
 	}
+	if song.DeletedAt != "" {
+		return nil, fmt.Errorf("song %s is in the trash: %w", id, errors.ErrResourceNotFound)
+	}
+	if song.Pending != "" {
+		return nil, fmt.Errorf("song %s has a pending %s: %w", id, song.Pending, errors.ErrResourceNotFound)
+	}
 	logrus.WithFields(logrus.Fields{
 		"song_id":   id,
 		"operation": "get_song",
@@ -125,92 +203,227 @@ func (d *DynamoSongRepository) GetSongByID(id string) (*models.Song, error) {
 	return &song, nil
 }
 
-// UpdateSong applies partial updates to a song by its ID.
-// Automatically sets the updated_at field to the current timestamp.
-// Returns an error if the update fails or the item does not exist.
-func (d *DynamoSongRepository) UpdateSong(id string, updates map[string]interface{}) error {
+// GetSongByIDUncached is GetSongByID; this repository does not cache.
+func (d *DynamoSongRepository) GetSongByIDUncached(id string) (*models.Song, error) {
+	return d.GetSongByID(id)
+}
+
+// UpdateSong applies partial updates to a song by its ID and, in the same transaction, appends the
+// audit entry and stores the revision keeping the replaced version.
+// Automatically sets the updated_at field to the current timestamp and moves the song to version+1.
+// The update only applies while the song is live and still at version.
+// A new title and title_normalized are copied to every document of the song, live or trashed, in the same
+// transaction, each copy with an audit entry of the document derived from audit. Beyond maxTransactItems the remaining documents follow in further transactions; if one
+// of those fails the song update still stands and the documents left behind are updated one by one,
+// skipping those deleted meanwhile. Should that fail too, the error is returned and the title
+// consistency check (see services.ConsistencyService) repairs them later.
+// Returns errors.ErrPreconditionFailed if the song changed since it was read, errors.ErrConflict if one
+// of its documents was deleted during the rename or a concurrent update took the revision number, or
+// another error if the update fails.
+func (d *DynamoSongRepository) UpdateSong(id string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error {
+	var documents []models.Document
+	title, renamed := updates["title"].(string)
+	if renamed {
+		if err := d.db.Table(bootstrap.DocumentTableName).Get("song_id", id).All(&documents); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"song_id":   id,
+				"operation": "update",
+			}).WithError(err).Error("Failed to retrieve documents before renaming song")
+			return fmt.Errorf("retrieving documents for song %s: %w", id, errors.HandleDynamoError(err))
+		}
+	}
+
 	updates["updated_at"] = time.Now().UTC().Format(time.RFC3339)
-	update := d.db.Table(bootstrap.SongTableName).Update("id", id)
+	updates["version"] = version + 1
+	update := ifLiveAtVersion(d.db.Table(bootstrap.SongTableName).Update("id", id), version)
 
 	for key, value := range updates {
 		update = update.Set(key, value)
 	}
 
-	err := update.Run()
-	if err != nil {
+	groups := [][]txOp{{
+		updateOp(update),
+		putOp(auditPut(d.db, audit)),
+		putOp(revisionPut(d.db, revision)),
+	}}
+	for _, doc := range documents {
+		groups = append(groups, []txOp{
+			updateOp(documentTitleUpdate(d.db, id, doc.ID, title)),
+			putOp(auditPut(d.db, documentTitleAudit(audit, id, doc, title))),
+		})
+	}
+
+	written, err := runGroupsInChunks(d.db, groups)
+	if written == 0 && errors.ConditionFailedAt(err, 0) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   id,
+			"version":   version,
+			"operation": "update",
+		}).Warn("Song changed since it was read")
+		return fmt.Errorf("updating song %s at version %d: %w", id, version, errors.ErrPreconditionFailed)
+	}
+	if written == 0 && errors.ConditionFailedAt(err, revisionIndex) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   id,
+			"revision":  revision.Revision,
+			"operation": "update",
+		}).Warn("Revision number taken by a concurrent update")
+		return fmt.Errorf("updating song %s: revision %d already exists: %w", id, revision.Revision, errors.ErrConflict)
+	}
+	if written == 0 && documentConditionFailed(err, len(documents)) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   id,
+			"operation": "update",
+		}).Warn("A document of the song was deleted during the rename")
+		return fmt.Errorf("renaming song %s: a document was deleted concurrently: %w", id, errors.ErrConflict)
+	}
+	if written == 0 && err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":   id,
 			"operation": "update",
 		}).WithError(err).Error("Failed to update song")
 		return fmt.Errorf("updating song %s: %w", id, errors.HandleDynamoError(err))
 	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   id,
+			"written":   written,
+			"total":     len(groups),
+			"operation": "update",
+		}).WithError(err).Warn("Song renamed but not all of its documents; updating the rest one by one")
+
+		if err := d.docRepo.SetDocumentTitles(id, documents[written-1:], title, audit); err != nil {
+			return fmt.Errorf("song %s renamed but not all of its documents: %w", id, err)
+		}
+	}
 
 	logrus.WithFields(logrus.Fields{
 		"song_id":   id,
+		"documents": len(documents),
 		"operation": "update_song",
 	}).Info("Song updated successfully")
 	return nil
 }
 
-// DeleteSongWithDocuments removes a song and all of its associated documents in a single transaction.
+// documentConditionFailed reports whether err is the canceled first transaction of a rename in which the
+// title update of one of the documents failed its condition. The song update, its audit entry and its
+// revision come first, then each document takes its title update and its audit entry.
+func documentConditionFailed(err error, documents int) bool {
+	const firstDocumentOp = 3
+	for i := 0; i < documents; i++ {
+		if errors.ConditionFailedAt(err, firstDocumentOp+2*i) {
+			return true
+		}
+	}
+	return false
+}
+
+// documentTitleUpdate returns the update copying the title of its song, as written and normalized, to a document.
+func documentTitleUpdate(db *dynamo.DB, songID, docID, title string) *dynamo.Update {
+	return db.Table(bootstrap.DocumentTableName).
+		Update("song_id", songID).
+		Range("id", docID).
+		Set("title", title).
+		Set("title_normalized", utils.Normalize(title)).
+		If("attribute_exists(id)")
+}
+
+// TrashSongWithDocuments moves a song still at version and all of its live documents to the trash in a
+// single transaction, which also appends the audit entry. Documents already in the trash keep their own marks,
+// so restoring the song later brings back only the documents trashed with it.
+// When the song and its documents exceed maxTransactItems, the song goes to the trash first, marked
+// models.SongPendingTrash, then its documents in further transactions, and the mark is removed last.
+// An interrupted run is finished by TrashRepository.CompletePendingSong.
 // Returns:
-//   - errors.ErrResourceNotFound if the song does not exist
-//   - errors.ErrInternalServer if deletion fails at any point
-func (d *DynamoSongRepository) DeleteSongWithDocuments(songID string) error {
+//   - errors.ErrResourceNotFound if the song does not exist or is already in the trash
+//   - errors.ErrPreconditionFailed if the song changed since it was read
+//   - errors.ErrOperationNotAllowed if a document changed state concurrently
+//   - errors.ErrInternalServer if the operation fails at any point
+func (d *DynamoSongRepository) TrashSongWithDocuments(songID string, version int, audit models.AuditEntry) error {
 	_, err := d.GetSongByID(songID)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":   songID,
-			"operation": "delete",
-		}).WithError(err).Warn("Cannot delete song: not found or retrieval failed")
-		return fmt.Errorf("verifying existence of song %s before delete: %w", songID, err)
+			"operation": "trash",
+		}).WithError(err).Warn("Cannot trash song: not found or retrieval failed")
+		return fmt.Errorf("verifying existence of song %s before trashing: %w", songID, err)
 	}
 
 	documents, err := d.docRepo.GetDocumentsBySongID(songID)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":   songID,
-			"operation": "delete",
-		}).WithError(err).Error("Failed to retrieve documents before deletion")
+			"operation": "trash",
+		}).WithError(err).Error("Failed to retrieve documents before trashing")
 		return fmt.Errorf("retrieving documents for song %s: %w", songID, errors.HandleDynamoError(err))
 	}
 
-	var transactItems []*dynamodb.TransactWriteItem
-
-	transactItems = append(transactItems, &dynamodb.TransactWriteItem{
-		Delete: &dynamodb.Delete{
-			TableName: aws.String(bootstrap.SongTableName),
-			Key:       map[string]*dynamodb.AttributeValue{"id": {S: aws.String(songID)}},
-		},
-	})
+	chunked := len(documents)+2 > maxTransactItems
+	songUpdate := ifLiveAtVersion(d.db.Table(bootstrap.SongTableName).Update("id", songID), version).
+		Set("deleted_at", audit.Timestamp).
+		Set("deleted_by", audit.Actor)
+	if chunked {
+		songUpdate = markPending(songUpdate, models.SongPendingTrash, audit.Timestamp)
+	}
 
-	if len(documents) > 0 {
-		for _, doc := range documents {
-			transactItems = append(transactItems, &dynamodb.TransactWriteItem{
-				Delete: &dynamodb.Delete{
-					TableName: aws.String(bootstrap.DocumentTableName),
-					Key: map[string]*dynamodb.AttributeValue{
-						"song_id": {S: aws.String(doc.SongID)},
-						"id":      {S: aws.String(doc.ID)},
-					},
-				},
-			})
-		}
+	ops := []txOp{updateOp(songUpdate), putOp(auditPut(d.db, audit))}
+	for _, doc := range documents {
+		ops = append(ops, updateOp(trashWithSongUpdate(d.db, doc.SongID, doc.ID, audit.Timestamp, audit.Actor)))
+	}
+	if chunked {
+		ops = append(ops, clearPendingOp(d.db, songID, models.SongPendingTrash))
 	}
 
-	input := &dynamodb.TransactWriteItemsInput{TransactItems: transactItems}
-	_, err = d.db.Client().TransactWriteItems(input)
+	written, err := runInChunks(d.db, ops)
+	if written == 0 && errors.ConditionFailedAt(err, 0) {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"version":   version,
+			"operation": "trash",
+		}).Warn("Song changed since it was read")
+		return fmt.Errorf("trashing song %s at version %d: %w", songID, version, errors.ErrPreconditionFailed)
+	}
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"song_id":   songID,
-			"operation": "delete",
-		}).WithError(err).Error("Failed to delete song and documents")
-		return fmt.Errorf("deleting song %s transactionally: %w", songID, errors.HandleDynamoError(err))
+			"written":   written,
+			"total":     len(ops),
+			"operation": "trash",
+		}).WithError(err).Error("Failed to trash song and documents")
+		return fmt.Errorf("trashing song %s transactionally (%d of %d items written): %w", songID, written, len(ops), errors.HandleDynamoError(err))
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"song_id":   songID,
-		"operation": "delete_song",
-	}).Info("Song and associated documents deleted successfully")
+		"documents": len(documents),
+		"chunked":   chunked,
+		"operation": "trash_song",
+	}).Info("Song and associated documents moved to the trash")
 	return nil
 }
+
+// trashWithSongUpdate returns the update moving a live document to the trash together with its song.
+func trashWithSongUpdate(db *dynamo.DB, songID, docID, deletedAt, deletedBy string) *dynamo.Update {
+	return db.Table(bootstrap.DocumentTableName).
+		Update("song_id", songID).
+		Range("id", docID).
+		Set("deleted_at", deletedAt).
+		Set("deleted_by", deletedBy).
+		Set("deleted_with_song", true).
+		If("attribute_exists(id) AND attribute_not_exists(deleted_at)")
+}
+
+// ifLiveAtVersion makes update conditional on the item existing outside the trash at the given version,
+// with no multi-step write in progress on it.
+func ifLiveAtVersion(update *dynamo.Update, version int) *dynamo.Update {
+	return ifAtVersion(update.If("attribute_exists(id) AND attribute_not_exists(deleted_at) AND attribute_not_exists(pending)"), version)
+}
+
+// ifAtVersion makes update conditional on the item still being at the given version.
+// Items written before versioning was introduced have no version attribute and count as version 0.
+func ifAtVersion(update *dynamo.Update, version int) *dynamo.Update {
+	if version == 0 {
+		return update.If("attribute_not_exists($)", "version")
+	}
+	return update.If("$ = ?", "version", version)
+}
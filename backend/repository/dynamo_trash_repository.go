@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoTrashRepository implements TrashRepository on the "SongTable" and "DocumentTable".
+// Items in the trash are the ones carrying a deleted_at attribute.
+// Every write appends its entry to the "AuditTable" in the same transaction.
+type DynamoTrashRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoTrashRepository returns a new instance of DynamoTrashRepository.
+func NewDynamoTrashRepository(db *dynamo.DB) *DynamoTrashRepository {
+	return &DynamoTrashRepository{db: db}
+}
+
+// GetTrashedSongs scans the SongTable for songs in the trash.
+func (d *DynamoTrashRepository) GetTrashedSongs() ([]models.Song, error) {
+	var songs []models.Song
+	err := d.db.Table(bootstrap.SongTableName).
+		Scan().
+		Filter("attribute_exists(deleted_at)").
+		All(&songs)
+	if err != nil {
+		logrus.WithField("operation", "get_trashed_songs").WithError(err).Error("Failed to retrieve trashed songs")
+		return nil, fmt.Errorf("retrieving trashed songs: %w", errors.HandleDynamoError(err))
+	}
+	return songs, nil
+}
+
+// GetTrashedDocuments scans the DocumentTable for documents in the trash.
+func (d *DynamoTrashRepository) GetTrashedDocuments() ([]models.Document, error) {
+	var documents []models.Document
+	err := d.db.Table(bootstrap.DocumentTableName).
+		Scan().
+		Filter("attribute_exists(deleted_at)").
+		All(&documents)
+	if err != nil {
+		logrus.WithField("operation", "get_trashed_documents").WithError(err).Error("Failed to retrieve trashed documents")
+		return nil, fmt.Errorf("retrieving trashed documents: %w", errors.HandleDynamoError(err))
+	}
+	return documents, nil
+}
+
+// GetTrashedSongByID retrieves a song by its ID if it is in the trash.
+// Returns errors.ErrResourceNotFound for live or unknown songs.
+func (d *DynamoTrashRepository) GetTrashedSongByID(songID string) (*models.Song, error) {
+	var song models.Song
+	err := d.db.Table(bootstrap.SongTableName).Get("id", songID).One(&song)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "get_trashed_song",
+		}).WithError(err).Warn("Trashed song not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	if song.DeletedAt == "" {
+		return nil, fmt.Errorf("song %s is not in the trash: %w", songID, errors.ErrResourceNotFound)
+	}
+	return &song, nil
+}
+
+// GetTrashedDocumentsBySongID queries the documents of a song that are in the trash.
+func (d *DynamoTrashRepository) GetTrashedDocumentsBySongID(songID string) ([]models.Document, error) {
+	var documents []models.Document
+	err := d.db.Table(bootstrap.DocumentTableName).
+		Get("song_id", songID).
+		Filter("attribute_exists(deleted_at)").
+		All(&documents)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "get_trashed_documents",
+		}).WithError(err).Error("Failed to retrieve trashed documents")
+		return nil, fmt.Errorf("retrieving trashed documents of song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+	return documents, nil
+}
+
+// GetTrashedDocumentByID retrieves a document by song ID and document ID if it is in the trash.
+// Returns errors.ErrResourceNotFound for live or unknown documents.
+func (d *DynamoTrashRepository) GetTrashedDocumentByID(songID string, docID string) (*models.Document, error) {
+	var document models.Document
+	err := d.db.Table(bootstrap.DocumentTableName).
+		Get("song_id", songID).
+		Range("id", dynamo.Equal, docID).
+		One(&document)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"operation":   "get_trashed_document",
+		}).WithError(err).Warn("Trashed document not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
+	}
+	if document.DeletedAt == "" {
+		return nil, fmt.Errorf("document %s for song %s is not in the trash: %w", docID, songID, errors.ErrResourceNotFound)
+	}
+	return &document, nil
+}
+
+// RestoreSong removes the trash marks of a song and the given documents in a single transaction,
+// which also appends the audit entry. When they exceed maxTransactItems the song leaves the trash
+// first, marked models.SongPendingRestore so that it stays hidden, then its documents in further
+// transactions, and the mark is removed last. An interrupted run is finished by CompletePendingSong.
+func (d *DynamoTrashRepository) RestoreSong(songID string, documentIDs []string, audit models.AuditEntry) error {
+	chunked := len(documentIDs)+2 > maxTransactItems
+	songUpdate := d.db.Table(bootstrap.SongTableName).Update("id", songID).
+		Remove("deleted_at", "deleted_by").
+		If("attribute_exists(deleted_at) AND attribute_not_exists(pending)")
+	if chunked {
+		songUpdate = markPending(songUpdate, models.SongPendingRestore, audit.Timestamp)
+	}
+
+	ops := []txOp{updateOp(songUpdate), putOp(auditPut(d.db, audit))}
+	for _, docID := range documentIDs {
+		ops = append(ops, updateOp(d.restoreDocumentUpdate(songID, docID)))
+	}
+	if chunked {
+		ops = append(ops, clearPendingOp(d.db, songID, models.SongPendingRestore))
+	}
+
+	if written, err := runInChunks(d.db, ops); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"written":   written,
+			"total":     len(ops),
+			"operation": "restore",
+		}).WithError(err).Error("Failed to restore song from the trash")
+		return fmt.Errorf("restoring song %s (%d of %d items written): %w", songID, written, len(ops), errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   songID,
+		"documents": len(documentIDs),
+		"chunked":   chunked,
+		"operation": "restore_song",
+	}).Info("Song and documents restored from the trash")
+	return nil
+}
+
+// RestoreDocument removes the trash marks of a document and appends the audit entry in the same transaction.
+func (d *DynamoTrashRepository) RestoreDocument(songID string, docID string, audit models.AuditEntry) error {
+	err := d.db.WriteTx().Update(d.restoreDocumentUpdate(songID, docID)).Put(auditPut(d.db, audit)).Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"operation":   "restore",
+		}).WithError(err).Error("Failed to restore document from the trash")
+		return fmt.Errorf("restoring document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":     songID,
+		"document_id": docID,
+		"operation":   "restore_document",
+	}).Info("Document restored from the trash")
+	return nil
+}
+
+// PurgeSong deletes a trashed song and every document of it, live or trashed, in a single transaction
+// which also appends the audit entry. When they exceed maxTransactItems the song is first marked
+// models.SongPendingPurge, so that it can no longer be restored, then its documents are deleted in
+// further transactions and the song last. An interrupted run is finished by CompletePendingSong.
+// A song with a pending write is not purged, so that the purge cannot race reconciliation finishing it.
+func (d *DynamoTrashRepository) PurgeSong(songID string, audit models.AuditEntry) error {
+	documents, err := d.allDocumentsOf(songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "purge",
+		}).WithError(err).Error("Failed to retrieve documents before purging")
+		return fmt.Errorf("retrieving documents for song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+
+	songs := d.db.Table(bootstrap.SongTableName)
+	chunked := len(documents)+2 > maxTransactItems
+	const purgeable = "attribute_exists(deleted_at) AND attribute_not_exists(pending)"
+	var ops []txOp
+	if chunked {
+		ops = append(ops, updateOp(markPending(songs.Update("id", songID), models.SongPendingPurge, audit.Timestamp).If(purgeable)))
+	} else {
+		ops = append(ops, deleteOp(songs.Delete("id", songID).If(purgeable)))
+	}
+	ops = append(ops, putOp(auditPut(d.db, audit)))
+	ops = append(ops, d.documentDeleteOps(songID, documents)...)
+	if chunked {
+		ops = append(ops, deleteOp(songs.Delete("id", songID).If("pending = ?", models.SongPendingPurge)))
+	}
+
+	if written, err := runInChunks(d.db, ops); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"written":   written,
+			"total":     len(ops),
+			"operation": "purge",
+		}).WithError(err).Error("Failed to purge song")
+		return fmt.Errorf("purging song %s (%d of %d items written): %w", songID, written, len(ops), errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   songID,
+		"documents": len(documents),
+		"chunked":   chunked,
+		"operation": "purge_song",
+	}).Info("Song and documents purged from the trash")
+	return nil
+}
+
+// PurgeDocument deletes a trashed document and appends the audit entry in the same transaction.
+func (d *DynamoTrashRepository) PurgeDocument(songID string, docID string, audit models.AuditEntry) error {
+	del := d.db.Table(bootstrap.DocumentTableName).
+		Delete("song_id", songID).
+		Range("id", docID).
+		If("attribute_exists(deleted_at)")
+
+	if err := d.db.WriteTx().Delete(del).Put(auditPut(d.db, audit)).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":     songID,
+			"document_id": docID,
+			"operation":   "purge",
+		}).WithError(err).Error("Failed to purge document")
+		return fmt.Errorf("purging document %s for song %s: %w", docID, songID, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":     songID,
+		"document_id": docID,
+		"operation":   "purge_document",
+	}).Info("Document purged from the trash")
+	return nil
+}
+
+// GetPendingSongs scans the SongTable for songs marked with a pending multi-step write.
+func (d *DynamoTrashRepository) GetPendingSongs() ([]models.Song, error) {
+	var songs []models.Song
+	err := d.db.Table(bootstrap.SongTableName).
+		Scan().
+		Filter("attribute_exists(pending)").
+		All(&songs)
+	if err != nil {
+		logrus.WithField("operation", "get_pending_songs").WithError(err).Error("Failed to retrieve pending songs")
+		return nil, fmt.Errorf("retrieving pending songs: %w", errors.HandleDynamoError(err))
+	}
+	return songs, nil
+}
+
+// CompletePendingSong finishes the interrupted trash, restore or purge recorded in song.Pending.
+// Each step is idempotent: the documents still to be written are read again and the ones already
+// written are left alone, so an interrupted completion can itself be completed later.
+// Returns errors.ErrOperationNotAllowed for a pending creation, which is rolled back instead.
+func (d *DynamoTrashRepository) CompletePendingSong(song models.Song) error {
+	var documents []models.Document
+	var err error
+	switch song.Pending {
+	case models.SongPendingTrash:
+		err = d.db.Table(bootstrap.DocumentTableName).
+			Get("song_id", song.ID).
+			Filter("attribute_not_exists(deleted_at)").
+			All(&documents)
+	case models.SongPendingRestore:
+		err = d.db.Table(bootstrap.DocumentTableName).
+			Get("song_id", song.ID).
+			Filter("deleted_with_song = ?", true).
+			All(&documents)
+	case models.SongPendingPurge:
+		documents, err = d.allDocumentsOf(song.ID)
+	case "":
+		return nil
+	default:
+		return fmt.Errorf("song %s has a pending %s, which cannot be completed: %w", song.ID, song.Pending, errors.ErrOperationNotAllowed)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   song.ID,
+			"pending":   song.Pending,
+			"operation": "complete_pending",
+		}).WithError(err).Error("Failed to retrieve documents of pending song")
+		return fmt.Errorf("retrieving documents for pending %s of song %s: %w", song.Pending, song.ID, errors.HandleDynamoError(err))
+	}
+
+	var ops []txOp
+	for _, doc := range documents {
+		switch song.Pending {
+		case models.SongPendingTrash:
+			ops = append(ops, updateOp(trashWithSongUpdate(d.db, song.ID, doc.ID, song.DeletedAt, song.DeletedBy)))
+		case models.SongPendingRestore:
+			ops = append(ops, updateOp(d.restoreDocumentUpdate(song.ID, doc.ID)))
+		}
+	}
+	if song.Pending == models.SongPendingPurge {
+		ops = append(d.documentDeleteOps(song.ID, documents),
+			deleteOp(d.db.Table(bootstrap.SongTableName).Delete("id", song.ID).If("pending = ?", models.SongPendingPurge)))
+	} else {
+		ops = append(ops, clearPendingOp(d.db, song.ID, song.Pending))
+	}
+
+	if written, err := runInChunks(d.db, ops); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   song.ID,
+			"pending":   song.Pending,
+			"written":   written,
+			"total":     len(ops),
+			"operation": "complete_pending",
+		}).WithError(err).Error("Failed to complete pending song write")
+		return fmt.Errorf("completing pending %s of song %s (%d of %d items written): %w", song.Pending, song.ID, written, len(ops), errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   song.ID,
+		"pending":   song.Pending,
+		"documents": len(documents),
+		"operation": "complete_pending",
+	}).Info("Pending song write completed")
+	return nil
+}
+
+// RollBackSongCreation deletes a song left marked models.SongPendingCreate by an interrupted creation,
+// and the documents written for it. The song is deleted last, together with the audit entry.
+func (d *DynamoTrashRepository) RollBackSongCreation(songID string, audit models.AuditEntry) error {
+	documents, err := d.allDocumentsOf(songID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"operation": "roll_back_creation",
+		}).WithError(err).Error("Failed to retrieve documents before rolling back")
+		return fmt.Errorf("retrieving documents for song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+
+	written, err := runInChunks(d.db, d.documentDeleteOps(songID, documents))
+	if err == nil {
+		err = d.db.WriteTx().
+			Delete(d.db.Table(bootstrap.SongTableName).Delete("id", songID).If("pending = ?", models.SongPendingCreate)).
+			Put(auditPut(d.db, audit)).
+			Run()
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":   songID,
+			"written":   written,
+			"operation": "roll_back_creation",
+		}).WithError(err).Error("Failed to roll back song creation")
+		return fmt.Errorf("rolling back creation of song %s: %w", songID, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":   songID,
+		"documents": len(documents),
+		"operation": "roll_back_creation",
+	}).Info("Interrupted song creation rolled back")
+	return nil
+}
+
+// allDocumentsOf returns every document of a song, live or trashed.
+func (d *DynamoTrashRepository) allDocumentsOf(songID string) ([]models.Document, error) {
+	var documents []models.Document
+	err := d.db.Table(bootstrap.DocumentTableName).Get("song_id", songID).All(&documents)
+	return documents, err
+}
+
+// documentDeleteOps returns the ops deleting the given documents of a song.
+func (d *DynamoTrashRepository) documentDeleteOps(songID string, documents []models.Document) []txOp {
+	ops := make([]txOp, len(documents))
+	for i, doc := range documents {
+		ops[i] = deleteOp(d.db.Table(bootstrap.DocumentTableName).Delete("song_id", songID).Range("id", doc.ID))
+	}
+	return ops
+}
+
+// restoreDocumentUpdate returns the update removing the trash marks of a document, for use in a dynamo.WriteTx.
+func (d *DynamoTrashRepository) restoreDocumentUpdate(songID, docID string) *dynamo.Update {
+	return d.db.Table(bootstrap.DocumentTableName).
+		Update("song_id", songID).
+		Range("id", docID).
+		Remove("deleted_at", "deleted_by", "deleted_with_song").
+		If("attribute_exists(deleted_at)")
+}
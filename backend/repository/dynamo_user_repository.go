@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/bootstrap"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/guregu/dynamo"
+	"github.com/sirupsen/logrus"
+)
+
+// DynamoUserRepository implements UserRepository using DynamoDB as backend.
+// Users are stored in the "UserTable" keyed by username.
+type DynamoUserRepository struct {
+	db *dynamo.DB
+}
+
+// NewDynamoUserRepository returns a new instance of DynamoUserRepository.
+func NewDynamoUserRepository(db *dynamo.DB) *DynamoUserRepository {
+	return &DynamoUserRepository{db: db}
+}
+
+// GetUserByUsername retrieves a user by its username.
+// Returns:
+//   - (*models.User, nil) on success
+//   - (nil, errors.ErrResourceNotFound) if the user does not exist
+//   - (nil, errors.ErrInternalServer) for database access errors
+func (d *DynamoUserRepository) GetUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	err := d.db.Table(bootstrap.UserTableName).Get("username", username).One(&user)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "get_user",
+		}).WithError(err).Debug("User not found or failed to retrieve")
+		return nil, fmt.Errorf("retrieving user %s: %w", username, errors.HandleDynamoError(err))
+	}
+	return &user, nil
+}
+
+// GetAllUsers retrieves all users from the UserTable.
+// Returns a list of users or an internal error if the query fails.
+func (d *DynamoUserRepository) GetAllUsers() ([]models.User, error) {
+	var users []models.User
+	err := d.db.Table(bootstrap.UserTableName).Scan().All(&users)
+	if err != nil {
+		logrus.WithField("operation", "get_all_users").WithError(err).Error("Failed to retrieve users")
+		return nil, fmt.Errorf("retrieving all users: %w", errors.HandleDynamoError(err))
+	}
+	return users, nil
+}
+
+// CreateUser inserts a new user, failing if the username is already taken.
+// Returns:
+//   - errors.ErrOperationNotAllowed if a user with the same username exists
+//   - errors.ErrInternalServer if the write fails
+func (d *DynamoUserRepository) CreateUser(user models.User) error {
+	err := d.db.Table(bootstrap.UserTableName).Put(user).If("attribute_not_exists(username)").Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  user.Username,
+			"operation": "create_user",
+		}).WithError(err).Error("Failed to create user")
+		return fmt.Errorf("creating user %s: %w", user.Username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  user.Username,
+		"role":      user.Role,
+		"operation": "create_user",
+	}).Info("User created successfully")
+	return nil
+}
+
+// UpdateUser applies partial updates to an existing user.
+// Fields mapped to nil are removed from the item.
+// Returns an error if the user does not exist or the update fails.
+func (d *DynamoUserRepository) UpdateUser(username string, updates map[string]interface{}) error {
+	update := d.db.Table(bootstrap.UserTableName).Update("username", username).If("attribute_exists(username)")
+	for key, value := range updates {
+		if value == nil {
+			update = update.Remove(key)
+			continue
+		}
+		update = update.Set(key, value)
+	}
+
+	if err := update.Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "update_user",
+		}).WithError(err).Error("Failed to update user")
+		return fmt.Errorf("updating user %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "update_user",
+	}).Info("User updated successfully")
+	return nil
+}
+
+// DeleteUser removes a user from the UserTable.
+// Returns errors.ErrInternalServer if the deletion fails.
+func (d *DynamoUserRepository) DeleteUser(username string) error {
+	if err := d.db.Table(bootstrap.UserTableName).Delete("username", username).Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "delete_user",
+		}).WithError(err).Error("Failed to delete user")
+		return fmt.Errorf("deleting user %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "delete_user",
+	}).Info("User deleted successfully")
+	return nil
+}
+
+// ResetPassword replaces the password hash with a conditional update on the reset token hash,
+// removing the token in the same write.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the token hash does not match
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) ResetPassword(username, tokenHash, passwordHash, updatedAt string) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("'password_reset_token_hash' = ?", tokenHash).
+		Set("password_hash", passwordHash).
+		Set("password_changed_at", updatedAt).
+		Remove("password_reset_token_hash", "password_reset_expires_at").
+		Set("updated_at", updatedAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "reset_password",
+		}).WithError(err).Warn("Password reset rejected or failed")
+		return fmt.Errorf("resetting password of %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "reset_password",
+	}).Info("Password reset successfully")
+	return nil
+}
+
+// SaveTOTPEnrollment sets the pending TOTP fields of an existing user.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the user does not exist
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) SaveTOTPEnrollment(username, encryptedSecret string, recoveryCodeHashes []string, updatedAt string) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("attribute_exists(username)").
+		Set("totp_pending_secret", encryptedSecret).
+		SetSet("pending_recovery_code_hashes", recoveryCodeHashes).
+		Set("updated_at", updatedAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "save_totp_enrollment",
+		}).WithError(err).Error("Failed to save TOTP enrolment")
+		return fmt.Errorf("saving TOTP enrolment of %s: %w", username, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// EnableTOTP promotes the pending enrolment to the active second factor. The update is
+// conditional on the pending seed, so a concurrent re-enrolment is not activated by mistake.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the condition fails
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) EnableTOTP(username, encryptedSecret string, recoveryCodeHashes []string, step int64, updatedAt string) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("'totp_pending_secret' = ?", encryptedSecret).
+		Set("totp_enabled", true).
+		Set("totp_secret", encryptedSecret).
+		Set("totp_last_step", step).
+		SetSet("recovery_code_hashes", recoveryCodeHashes).
+		Remove("totp_pending_secret", "pending_recovery_code_hashes").
+		Set("updated_at", updatedAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "enable_totp",
+		}).WithError(err).Error("Failed to enable TOTP")
+		return fmt.Errorf("enabling TOTP of %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "enable_totp",
+	}).Info("TOTP enabled successfully")
+	return nil
+}
+
+// DisableTOTP removes every TOTP attribute of an existing user.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the user does not exist
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) DisableTOTP(username, updatedAt string) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("attribute_exists(username)").
+		Remove("totp_enabled", "totp_secret", "totp_last_step", "recovery_code_hashes", "totp_pending_secret", "pending_recovery_code_hashes").
+		Set("updated_at", updatedAt).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "disable_totp",
+		}).WithError(err).Error("Failed to disable TOTP")
+		return fmt.Errorf("disabling TOTP of %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "disable_totp",
+	}).Info("TOTP disabled successfully")
+	return nil
+}
+
+// RecordTOTPStep stores the last used time step with a conditional update, so two requests
+// presenting the same code cannot both succeed.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the step is not later than the stored one
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) RecordTOTPStep(username string, step int64) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("attribute_exists(username) AND (attribute_not_exists(totp_last_step) OR totp_last_step < ?)", step).
+		Set("totp_last_step", step).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "record_totp_step",
+		}).WithError(err).Warn("TOTP step rejected or failed to record")
+		return fmt.Errorf("recording TOTP step of %s: %w", username, errors.HandleDynamoError(err))
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode deletes a hash from the recovery code set if it is present.
+// Returns:
+//   - errors.ErrOperationNotAllowed if the hash is not in the set
+//   - errors.ErrInternalServer if the update fails
+func (d *DynamoUserRepository) ConsumeRecoveryCode(username, codeHash string) error {
+	err := d.db.Table(bootstrap.UserTableName).Update("username", username).
+		If("contains(recovery_code_hashes, ?)", codeHash).
+		DeleteStringsFromSet("recovery_code_hashes", codeHash).
+		Run()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"username":  username,
+			"operation": "consume_recovery_code",
+		}).WithError(err).Warn("Recovery code rejected or failed to consume")
+		return fmt.Errorf("consuming recovery code of %s: %w", username, errors.HandleDynamoError(err))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":  username,
+		"operation": "consume_recovery_code",
+	}).Info("Recovery code used")
+	return nil
+}
@@ -1,9 +1,22 @@
 package repository
 
+import "sync"
+
 type FakeAuthRepository struct {
 	Credentials AuthCredentials
+	mu          sync.RWMutex
 }
 
 func (f *FakeAuthRepository) GetAuthCredentials() (*AuthCredentials, error) {
-	return &f.Credentials, nil
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	creds := f.Credentials
+	return &creds, nil
+}
+
+func (f *FakeAuthRepository) UpdateAuthCredentials(creds AuthCredentials) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Credentials = creds
+	return nil
 }
@@ -0,0 +1,44 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// GenreBatch describes a set of changes to the genre catalogue together with the
+// song rewrites they imply (e.g., after a rename or a merge).
+type GenreBatch struct {
+	Genres     []models.Genre               // Genres to create or replace
+	DeletedIDs []string                     // IDs of genres to remove
+	Songs      []models.Song                // Affected songs as scanned, holding their new genre list; rewritten in this order
+	Audits     map[string]models.AuditEntry // Audit entry of each song rewrite, keyed by song ID
+	UpdatedAt  string                       // Timestamp written to the updated_at field of every rewritten song
+}
+
+// GenreRepository defines operations for accessing and manipulating the genre catalogue.
+type GenreRepository interface {
+
+	// GetAllGenres returns every genre of the catalogue.
+	// Returns:
+	//   - ([]models.Genre, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetAllGenres() ([]models.Genre, error)
+
+	// CreateGenre stores a new genre.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if marshalling or persistence fails
+	CreateGenre(genre models.Genre) error
+
+	// ScanAllSongs returns every song, live, in the trash or with a write in progress, read from storage.
+	// Returns:
+	//   - ([]models.Song, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the scan fails
+	ScanAllSongs() ([]models.Song, error)
+
+	// ApplyGenreBatch rewrites the affected songs, each in the same transaction as its audit entry,
+	// and then applies the genre changes. A song is only rewritten while it is still at the version
+	// and in the trash state it was scanned with, so that a concurrent edit is never overwritten.
+	// Returns:
+	//   - the number of songs rewritten, the first ones of batch.Songs, whether or not the batch failed
+	//   - errors.ErrPreconditionFailed if a song changed since it was scanned; the genre changes are not applied
+	//   - errors.ErrInternalServer if any write fails
+	ApplyGenreBatch(batch GenreBatch) (int, error)
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// maxOIDCResponseBytes bounds the size of the documents read from the provider.
+const maxOIDCResponseBytes = 1 << 20
+
+// HTTPOIDCProviderRepository implements OIDCProviderRepository over HTTP.
+type HTTPOIDCProviderRepository struct {
+	client *http.Client
+}
+
+// NewHTTPOIDCProviderRepository returns a new instance of HTTPOIDCProviderRepository.
+// A nil client uses an http.Client with a 10 second timeout.
+func NewHTTPOIDCProviderRepository(client *http.Client) *HTTPOIDCProviderRepository {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPOIDCProviderRepository{client: client}
+}
+
+// GetDiscovery fetches and decodes the provider configuration document.
+// Returns errors.ErrInternalServer if the request fails or required endpoints are missing.
+func (h *HTTPOIDCProviderRepository) GetDiscovery(issuer string) (*OIDCDiscovery, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	var discovery OIDCDiscovery
+	if err := h.getJSON(discoveryURL, &discovery); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"issuer":    issuer,
+			"operation": "oidc_discovery",
+		}).WithError(err).Error("Failed to fetch OIDC discovery document")
+		return nil, fmt.Errorf("fetching OIDC discovery of %s: %w", issuer, errors.ErrInternalServer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery of %s lacks required endpoints: %w", issuer, errors.ErrInternalServer)
+	}
+	return &discovery, nil
+}
+
+// GetJWKS fetches and decodes the provider key set.
+// Returns errors.ErrInternalServer if the request fails.
+func (h *HTTPOIDCProviderRepository) GetJWKS(jwksURI string) ([]utils.JWK, error) {
+	var set struct {
+		Keys []utils.JWK `json:"keys"`
+	}
+	if err := h.getJSON(jwksURI, &set); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"jwks_uri":  jwksURI,
+			"operation": "oidc_jwks",
+		}).WithError(err).Error("Failed to fetch OIDC signing keys")
+		return nil, fmt.Errorf("fetching OIDC signing keys: %w", errors.ErrInternalServer)
+	}
+	return set.Keys, nil
+}
+
+// ExchangeCode posts the authorization-code grant to the token endpoint.
+// The client authenticates with HTTP basic auth when it has a secret.
+// Returns:
+//   - errors.ErrInvalidCredentials if the provider answers 400 or 401 (e.g. invalid_grant)
+//   - errors.ErrInternalServer for any other failure
+func (h *HTTPOIDCProviderRepository) ExchangeCode(tokenEndpoint string, exchange OIDCCodeExchange) (*OIDCTokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {exchange.Code},
+		"code_verifier": {exchange.CodeVerifier},
+		"redirect_uri":  {exchange.RedirectURI},
+		"client_id":     {exchange.ClientID},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("building OIDC token request: %w", errors.ErrInternalServer)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if exchange.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(exchange.ClientID), url.QueryEscape(exchange.ClientSecret))
+	}
+
+	logFields := logrus.Fields{"token_endpoint": tokenEndpoint, "operation": "oidc_exchange_code"}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		logrus.WithFields(logFields).WithError(err).Error("Failed to reach OIDC token endpoint")
+		return nil, fmt.Errorf("exchanging OIDC code: %w", errors.ErrInternalServer)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOIDCResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading OIDC token response: %w", errors.ErrInternalServer)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &oauthErr)
+		logrus.WithFields(logFields).WithField("oauth_error", oauthErr.Error).Warn("OIDC provider rejected authorization code")
+		return nil, fmt.Errorf("OIDC provider rejected code (%s): %w", oauthErr.Error, errors.ErrInvalidCredentials)
+	case resp.StatusCode != http.StatusOK:
+		logrus.WithFields(logFields).WithField("status", resp.StatusCode).Error("Unexpected OIDC token endpoint status")
+		return nil, fmt.Errorf("OIDC token endpoint answered %d: %w", resp.StatusCode, errors.ErrInternalServer)
+	}
+
+	var tokens OIDCTokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("decoding OIDC token response: %w", errors.ErrInternalServer)
+	}
+	return &tokens, nil
+}
+
+// getJSON fetches a JSON document and decodes it into out.
+func (h *HTTPOIDCProviderRepository) getJSON(target string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxOIDCResponseBytes)).Decode(out)
+}
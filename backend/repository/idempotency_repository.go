@@ -0,0 +1,37 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// IdempotencyRepository defines operations for the Idempotency-Key records of create requests.
+type IdempotencyRepository interface {
+
+	// ReserveIdempotencyKey stores a record for a new request, unless its ID is held by another record
+	// that has neither expired nor, while unfinished, outlived its lock at the Unix time now.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the key is held
+	//   - errors.ErrInternalServer if the write fails
+	ReserveIdempotencyKey(key models.IdempotencyKey, now int64) error
+
+	// GetIdempotencyKey retrieves a record by ID.
+	// Returns:
+	//   - (*models.IdempotencyKey, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if no record exists
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetIdempotencyKey(id string) (*models.IdempotencyKey, error)
+
+	// CompleteIdempotencyKey stores the response of the request holding the key with fingerprint
+	// and lifts its lock.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the key is no longer held by that request
+	//   - errors.ErrInternalServer if the update fails
+	CompleteIdempotencyKey(id string, fingerprint string, response models.StoredResponse) error
+
+	// ReleaseIdempotencyKey deletes the record of an unfinished request with fingerprint, so the key can
+	// be used again. Records held by another request or already completed are kept.
+	// Returns:
+	//   - nil on success, including when the record was kept
+	//   - errors.ErrInternalServer if the delete fails
+	ReleaseIdempotencyKey(id string, fingerprint string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// InstrumentRepository defines read access to the canonical instrument catalogue.
+type InstrumentRepository interface {
+
+	// GetAllInstruments returns every instrument of the catalogue.
+	// Returns:
+	//   - ([]models.Instrument, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the catalogue cannot be read
+	GetAllInstruments() ([]models.Instrument, error)
+
+	// GetAllInstrumentFamilies returns every instrument family of the catalogue.
+	// Returns:
+	//   - ([]models.InstrumentFamily, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the catalogue cannot be read
+	GetAllInstrumentFamilies() ([]models.InstrumentFamily, error)
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// Sort orders accepted by ListOptions.
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// ListOptions selects one page of a listing.
+type ListOptions struct {
+	Limit  int    // Maximum number of items in the page
+	Cursor string // NextToken of the previous page; empty for the first page
+	Sort   string // Attribute to sort by; empty keeps the storage order
+	Order  string // SortOrderAsc or SortOrderDesc; ignored without Sort
+}
+
+// listCursor is the decoded form of a page token. Pages in storage order continue from the DynamoDB
+// LastEvaluatedKey; sorted pages continue after the sort value and ID of the last item returned.
+type listCursor struct {
+	Key   map[string]string `json:"k,omitempty"`
+	Sort  string            `json:"s,omitempty"`
+	Order string            `json:"o,omitempty"`
+	Value string            `json:"v,omitempty"`
+	ID    string            `json:"i,omitempty"`
+}
+
+func encodeListCursor(cursor listCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeListCursor parses the cursor of opts, checking that it was issued for the same sort.
+// Returns errors.ErrBadRequest if it is malformed or belongs to another sort.
+func decodeListCursor(opts ListOptions) (*listCursor, error) {
+	if opts.Cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+	if err != nil {
+		return nil, errors.ErrBadRequest
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, errors.ErrBadRequest
+	}
+	if cursor.Sort != opts.Sort || (opts.Sort != "" && cursor.Order != opts.Order) || (opts.Sort == "" && len(cursor.Key) == 0) {
+		return nil, errors.ErrBadRequest
+	}
+	return &cursor, nil
+}
+
+// pagingKeyCursor returns the token continuing after a DynamoDB LastEvaluatedKey, whose
+// attributes are all strings in the songs and documents tables.
+func pagingKeyCursor(key dynamo.PagingKey) string {
+	return encodeListCursor(listCursor{Key: pagingKeyValues(key)})
+}
+
+// pagingKeyValues returns the string attributes of a DynamoDB LastEvaluatedKey.
+func pagingKeyValues(key dynamo.PagingKey) map[string]string {
+	values := make(map[string]string, len(key))
+	for name, value := range key {
+		values[name] = aws.StringValue(value.S)
+	}
+	return values
+}
+
+func (c *listCursor) pagingKey() dynamo.PagingKey {
+	key := make(dynamo.PagingKey, len(c.Key))
+	for name, value := range c.Key {
+		key[name] = &dynamodb.AttributeValue{S: aws.String(value)}
+	}
+	return key
+}
+
+// listPosition is where an item falls in a sorted listing: its sort value, then its ID to break ties.
+type listPosition struct {
+	value string
+	id    string
+}
+
+func (p listPosition) before(other listPosition, desc bool) bool {
+	if p.value != other.value {
+		return (p.value < other.value) != desc
+	}
+	return (p.id < other.id) != desc
+}
+
+// sortedPageCollector receives the items of a scan one at a time and keeps only the first limit+1
+// in sort order that come after the cursor, so a sorted page is cut without buffering the table.
+type sortedPageCollector[T any] struct {
+	limit    int
+	desc     bool
+	after    *listPosition
+	position func(T) listPosition
+	items    []T
+}
+
+func newSortedPageCollector[T any](opts ListOptions, cursor *listCursor, position func(T) listPosition) *sortedPageCollector[T] {
+	c := &sortedPageCollector[T]{limit: opts.Limit, desc: opts.Order == SortOrderDesc, position: position}
+	if cursor != nil {
+		c.after = &listPosition{value: cursor.Value, id: cursor.ID}
+	}
+	return c
+}
+
+func (c *sortedPageCollector[T]) add(item T) {
+	pos := c.position(item)
+	if c.after != nil && !c.after.before(pos, c.desc) {
+		return
+	}
+	i := sort.Search(len(c.items), func(i int) bool { return pos.before(c.position(c.items[i]), c.desc) })
+	if i > c.limit {
+		return
+	}
+	c.items = slices.Insert(c.items, i, item)
+	if len(c.items) > c.limit+1 {
+		c.items = c.items[:c.limit+1]
+	}
+}
+
+// page returns the collected page and the token of the next one, empty on the last page.
+func (c *sortedPageCollector[T]) page(opts ListOptions) ([]T, string) {
+	if len(c.items) <= c.limit {
+		return c.items, ""
+	}
+	last := c.position(c.items[c.limit-1])
+	return c.items[:c.limit], encodeListCursor(listCursor{Sort: opts.Sort, Order: opts.Order, Value: last.value, ID: last.id})
+}
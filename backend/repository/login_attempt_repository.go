@@ -0,0 +1,32 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// LoginAttemptRepository defines operations for tracking failed logins per username and per client IP.
+type LoginAttemptRepository interface {
+
+	// GetLoginAttempt retrieves the failures recorded for a throttling key.
+	// Returns:
+	//   - (*models.LoginAttempt, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if no failure is recorded
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetLoginAttempt(id string) (*models.LoginAttempt, error)
+
+	// RecordLoginFailure atomically increments the failures of a key, creating the record if needed.
+	// Returns:
+	//   - (*models.LoginAttempt, nil) with the updated record on success
+	//   - (nil, errors.ErrInternalServer) if the update fails
+	RecordLoginFailure(id string, failedAt, expiresAt int64) (*models.LoginAttempt, error)
+
+	// LockLoginAttempt refuses logins for the key until the given Unix time.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the update fails
+	LockLoginAttempt(id string, lockedUntil, expiresAt int64) error
+
+	// DeleteLoginAttempt forgets the failures of a key, lifting any lockout.
+	// Returns:
+	//   - nil on success, including when no failure was recorded
+	//   - errors.ErrInternalServer if the delete fails
+	DeleteLoginAttempt(id string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// OIDCLoginRepository defines operations for the pending logins of the OpenID Connect flow.
+type OIDCLoginRepository interface {
+
+	// CreateOIDCLogin stores a pending login until its callback arrives.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the write fails
+	CreateOIDCLogin(login models.OIDCLogin) error
+
+	// ConsumeOIDCLogin atomically removes a pending login and returns it, so a state
+	// cannot be redeemed twice.
+	// Returns:
+	//   - (*models.OIDCLogin, nil) if the login was pending
+	//   - (nil, errors.ErrResourceNotFound) if the state is unknown or already consumed
+	//   - (nil, errors.ErrInternalServer) if the delete fails
+	ConsumeOIDCLogin(id string) (*models.OIDCLogin, error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/utils"
+
+// OIDCDiscovery holds the fields of an OpenID provider configuration document used by the login flow.
+type OIDCDiscovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// OIDCCodeExchange is the authorization-code grant sent to the token endpoint.
+// ClientSecret is left empty for public clients, which rely on PKCE alone.
+type OIDCCodeExchange struct {
+	Code         string
+	CodeVerifier string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+}
+
+// OIDCTokenResponse holds the fields of a token endpoint response used by the login flow.
+type OIDCTokenResponse struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// OIDCProviderRepository defines the calls made to the organisation's OpenID provider.
+type OIDCProviderRepository interface {
+
+	// GetDiscovery fetches the provider configuration from <issuer>/.well-known/openid-configuration.
+	// Returns:
+	//   - (*OIDCDiscovery, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the provider cannot be reached or answers with an invalid document
+	GetDiscovery(issuer string) (*OIDCDiscovery, error)
+
+	// GetJWKS fetches the public signing keys of the provider.
+	// Returns:
+	//   - the published keys on success
+	//   - errors.ErrInternalServer if the keys cannot be fetched or parsed
+	GetJWKS(jwksURI string) ([]utils.JWK, error)
+
+	// ExchangeCode redeems an authorization code at the token endpoint.
+	// Returns:
+	//   - (*OIDCTokenResponse, nil) on success
+	//   - (nil, errors.ErrInvalidCredentials) if the provider rejects the code or the verifier
+	//   - (nil, errors.ErrInternalServer) if the provider cannot be reached or answers unexpectedly
+	ExchangeCode(tokenEndpoint string, exchange OIDCCodeExchange) (*OIDCTokenResponse, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// RevisionRepository defines read access to the previous versions of songs and documents.
+// Revisions are written by SongRepository.UpdateSong and DocumentRepository.UpdateDocument in the
+// same transaction as the update that replaces them, so there is no separate write operation.
+type RevisionRepository interface {
+
+	// GetRevisionsBySongID returns every revision of a song and its documents, newest first.
+	// Returns:
+	//   - ([]models.Revision, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetRevisionsBySongID(songID string) ([]models.Revision, error)
+
+	// GetRevision retrieves one revision of a song or of one of its documents.
+	// Returns:
+	//   - (*models.Revision, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the revision does not exist
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetRevision(songID string, revision int) (*models.Revision, error)
+
+	// GetLatestRevisionNumber returns the highest revision number of a song, or 0 if it has none.
+	// Returns:
+	//   - (int, nil) on success
+	//   - (0, errors.ErrInternalServer) if the query fails
+	GetLatestRevisionNumber(songID string) (int, error)
+}
@@ -19,10 +19,11 @@ type SearchRepository interface {
 	//   - (nil, nil, error) if the query fails
 	ListSongs(title, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Song, PagingKey, error)
 
-	// ListDocuments returns a paginated list of documents filtered by title, instrument, and type.
+	// ListDocuments returns a paginated list of documents filtered by title, instruments, and type.
+	// Documents in the trash or of a song with a pending write are left out.
 	// Parameters:
 	//   - title: optional string to filter by normalized title
-	//   - instrument: optional filter by instrument
+	//   - instruments: optional list of instrument values; a document matches if it contains any of them
 	//   - docType: optional filter by document type
 	//   - sortField: "title" or "created_at"
 	//   - sortOrder: "asc" or "desc"
@@ -31,5 +32,21 @@ type SearchRepository interface {
 	// Returns:
 	//   - ([]models.Document, PagingKey, nil) on success
 	//   - (nil, nil, error) if the query fails
-	ListDocuments(title, instrument, docType, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Document, PagingKey, error)
+	ListDocuments(title string, instruments []string, docType, sortField, sortOrder string, limit int, nextToken PagingKey) ([]models.Document, PagingKey, error)
+
+	// SearchCatalog reads the songs and the documents whose normalized title contains the query, both
+	// tables side by side, resuming at cursor and stopping in each table once maxHits items matched or
+	// the read budget of a call is spent.
+	// Documents of a song with a pending write are left out, like the song itself.
+	// Results are unsorted; ranking and paging are applied by the caller.
+	// Parameters:
+	//   - query: search term (normalized internally); an empty query matches everything
+	//   - maxHits: maximum number of songs, and of documents, returned
+	//   - cursor: token returned by the previous call; empty to start from the beginning of both tables
+	// Returns:
+	//   - ([]models.Song, []models.Document, token resuming the scan, nil) on success; the token is empty
+	//     once both tables were read to the end
+	//   - errors.ErrBadRequest if the cursor is malformed
+	//   - error if either scan fails
+	SearchCatalog(query string, maxHits int, cursor string) ([]models.Song, []models.Document, string, error)
 }
@@ -0,0 +1,41 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// SessionRepository defines operations for storing login sessions and their refresh tokens.
+type SessionRepository interface {
+
+	// CreateSession stores a new session.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if persistence fails
+	CreateSession(session models.Session) error
+
+	// GetSessionByID retrieves a session by its ID.
+	// Returns:
+	//   - (*models.Session, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the session does not exist
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetSessionByID(id string) (*models.Session, error)
+
+	// GetSessionsByUsername returns every session of a user, active or revoked.
+	// Returns:
+	//   - ([]models.Session, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetSessionsByUsername(username string) ([]models.Session, error)
+
+	// RotateSessionToken replaces the current refresh token hash of an active session,
+	// keeping the previous hash to detect reuse. The update only applies if the session is
+	// still active and its current hash is oldHash.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the session was revoked or rotated concurrently
+	//   - errors.ErrInternalServer if the update fails
+	RotateSessionToken(id, oldHash, newHash string, expiresAt int64, updatedAt string) error
+
+	// RevokeSession marks a session as revoked with the given reason.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the update fails
+	RevokeSession(id, reason, updatedAt string) error
+}
@@ -3,37 +3,58 @@ package repository
 import "github.com/CristinaRendaLopez/rendalla-backend/models"
 
 // SongRepository defines operations for accessing and manipulating songs in storage.
+// Every write also appends the given audit entry, atomically with the change.
+// Songs in the trash are hidden from every read; see TrashRepository to reach them.
 type SongRepository interface {
 
-	// CreateSongWithDocuments stores a new song along with its associated documents.
+	// CreateSongWithDocuments stores a new song along with its associated documents and the audit entry.
 	// Returns:
 	//   - nil on success
 	//   - errors.ErrInternalServer if marshalling or persistence fails
-	CreateSongWithDocuments(song models.Song, documents []models.Document) error
+	CreateSongWithDocuments(song models.Song, documents []models.Document, audit models.AuditEntry) error
 
-	// GetAllSongs returns a list of all songs in the database.
+	// GetAllSongs returns a list of all songs in the database, except those in the trash.
 	// Returns:
 	//   - ([]models.Song, nil) on success
 	//   - (nil, errors.ErrInternalServer) if the query fails
 	GetAllSongs() ([]models.Song, error)
 
+	// ListSongs returns one page of the songs, except those in the trash, and the token of the next page.
+	// opts.Sort may be "title", "created_at" or "updated_at", or empty for storage order.
+	// Returns:
+	//   - ([]models.Song, next token, nil) on success; the token is empty on the last page
+	//   - errors.ErrBadRequest if opts.Cursor was not issued for this listing
+	//   - errors.ErrInternalServer if the query fails
+	ListSongs(opts ListOptions) ([]models.Song, string, error)
+
 	// GetSongByID retrieves a song by its unique identifier.
 	// Returns:
 	//   - (*models.Song, nil) if found
-	//   - (nil, errors.ErrNotFound) if the song does not exist
+	//   - (nil, errors.ErrNotFound) if the song does not exist or is in the trash
 	//   - (nil, errors.ErrInternalServer) if retrieval fails
 	GetSongByID(songID string) (*models.Song, error)
 
-	// UpdateSong applies partial updates to a song by its ID.
+	// GetSongByIDUncached retrieves a song like GetSongByID but always from storage, for writes that
+	// must not refuse a client on a stale copy.
+	GetSongByIDUncached(songID string) (*models.Song, error)
+
+	// UpdateSong applies partial updates to a song by its ID, provided it is still at version, and
+	// stores the audit entry and the revision keeping the replaced version. The song moves to version+1.
+	// A new title_normalized is also copied to every document of the song.
 	// Returns:
 	//   - nil on success
+	//   - errors.ErrPreconditionFailed if the song is no longer at version
+	//   - errors.ErrConflict if a document of the song is deleted while it is renamed, or if the revision
+	//     number is already taken by a concurrent update
 	//   - errors.ErrInternalServer if the update fails
-	UpdateSong(songID string, updates map[string]interface{}) error
+	UpdateSong(songID string, version int, updates map[string]interface{}, audit models.AuditEntry, revision models.Revision) error
 
-	// DeleteSongWithDocuments removes a song and all related documents.
+	// TrashSongWithDocuments moves a song still at version and all of its live documents to the trash
+	// and stores the audit entry. The trash marks record audit.Timestamp and audit.Actor.
 	// Returns:
 	//   - nil on success
-	//   - errors.ErrNotFound if the song does not exist
-	//   - errors.ErrInternalServer if the deletion fails
-	DeleteSongWithDocuments(songID string) error
+	//   - errors.ErrNotFound if the song does not exist or is already in the trash
+	//   - errors.ErrPreconditionFailed if the song is no longer at version
+	//   - errors.ErrInternalServer if the operation fails
+	TrashSongWithDocuments(songID string, version int, audit models.AuditEntry) error
 }
@@ -0,0 +1,123 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// StaticInstrumentRepository implements InstrumentRepository with a catalogue maintained in code.
+// Changes to the catalogue are reviewed and deployed like any other change, which keeps the
+// canonical IDs stored in documents stable across environments.
+type StaticInstrumentRepository struct {
+	Instruments []models.Instrument
+	Families    []models.InstrumentFamily
+}
+
+// NewStaticInstrumentRepository returns a StaticInstrumentRepository loaded with the default catalogue.
+func NewStaticInstrumentRepository() *StaticInstrumentRepository {
+	return &StaticInstrumentRepository{
+		Instruments: DefaultInstruments,
+		Families:    DefaultInstrumentFamilies,
+	}
+}
+
+// GetAllInstruments returns every instrument of the catalogue.
+func (r *StaticInstrumentRepository) GetAllInstruments() ([]models.Instrument, error) {
+	return r.Instruments, nil
+}
+
+// GetAllInstrumentFamilies returns every instrument family of the catalogue.
+func (r *StaticInstrumentRepository) GetAllInstrumentFamilies() ([]models.InstrumentFamily, error) {
+	return r.Families, nil
+}
+
+// Instrument family identifiers of the default catalogue.
+const (
+	FamilyStrings    = "strings"
+	FamilyKeyboards  = "keyboards"
+	FamilyVoices     = "voices"
+	FamilyWoodwinds  = "woodwinds"
+	FamilyBrass      = "brass"
+	FamilyPercussion = "percussion"
+)
+
+// DefaultInstrumentFamilies is the default list of instrument families.
+var DefaultInstrumentFamilies = []models.InstrumentFamily{
+	{ID: FamilyStrings, Names: map[string]string{"es": "Cuerdas", "en": "Strings"}},
+	{ID: FamilyKeyboards, Names: map[string]string{"es": "Teclados", "en": "Keyboards"}},
+	{ID: FamilyVoices, Names: map[string]string{"es": "Voces", "en": "Voices"}},
+	{ID: FamilyWoodwinds, Names: map[string]string{"es": "Viento madera", "en": "Woodwinds"}},
+	{ID: FamilyBrass, Names: map[string]string{"es": "Viento metal", "en": "Brass"}},
+	{ID: FamilyPercussion, Names: map[string]string{"es": "Percusión", "en": "Percussion"}},
+}
+
+// DefaultInstruments is the default instrument catalogue.
+// Aliases are matched ignoring case and accents, so they only need to list distinct spellings.
+var DefaultInstruments = []models.Instrument{
+	// Strings
+	{ID: "guitar", Family: FamilyStrings, Names: map[string]string{"es": "Guitarra", "en": "Guitar"},
+		Aliases: []string{"gtr", "acoustic guitar", "classical guitar", "spanish guitar", "guitarra acústica", "guitarra clásica", "guitarra española"}},
+	{ID: "electric_guitar", Family: FamilyStrings, Names: map[string]string{"es": "Guitarra eléctrica", "en": "Electric guitar"},
+		Aliases: []string{"e-guitar", "electric gtr"}},
+	{ID: "bass_guitar", Family: FamilyStrings, Names: map[string]string{"es": "Bajo eléctrico", "en": "Bass guitar"},
+		Aliases: []string{"bajo", "electric bass"}},
+	{ID: "ukulele", Family: FamilyStrings, Names: map[string]string{"es": "Ukelele", "en": "Ukulele"},
+		Aliases: []string{"uke"}},
+	{ID: "violin", Family: FamilyStrings, Names: map[string]string{"es": "Violín", "en": "Violin"},
+		Aliases: []string{"vln", "fiddle"}},
+	{ID: "viola", Family: FamilyStrings, Names: map[string]string{"es": "Viola", "en": "Viola"},
+		Aliases: []string{"vla"}},
+	{ID: "cello", Family: FamilyStrings, Names: map[string]string{"es": "Violonchelo", "en": "Cello"},
+		Aliases: []string{"vc", "violoncello", "chelo"}},
+	{ID: "double_bass", Family: FamilyStrings, Names: map[string]string{"es": "Contrabajo", "en": "Double bass"},
+		Aliases: []string{"upright bass", "cb"}},
+	{ID: "harp", Family: FamilyStrings, Names: map[string]string{"es": "Arpa", "en": "Harp"}},
+	{ID: "mandolin", Family: FamilyStrings, Names: map[string]string{"es": "Mandolina", "en": "Mandolin"}},
+	{ID: "bandurria", Family: FamilyStrings, Names: map[string]string{"es": "Bandurria", "en": "Bandurria"}},
+
+	// Keyboards
+	{ID: "piano", Family: FamilyKeyboards, Names: map[string]string{"es": "Piano", "en": "Piano"},
+		Aliases: []string{"pno", "pianoforte"}},
+	{ID: "organ", Family: FamilyKeyboards, Names: map[string]string{"es": "Órgano", "en": "Organ"}},
+	{ID: "keyboard", Family: FamilyKeyboards, Names: map[string]string{"es": "Teclado", "en": "Keyboard"},
+		Aliases: []string{"keys", "synth"}},
+	{ID: "accordion", Family: FamilyKeyboards, Names: map[string]string{"es": "Acordeón", "en": "Accordion"}},
+
+	// Voices
+	{ID: "voice", Family: FamilyVoices, Names: map[string]string{"es": "Voz", "en": "Voice"},
+		Aliases: []string{"vocals", "vocal", "vox", "canto"}},
+	{ID: "soprano", Family: FamilyVoices, Names: map[string]string{"es": "Soprano", "en": "Soprano"},
+		Aliases: []string{"s"}},
+	{ID: "alto", Family: FamilyVoices, Names: map[string]string{"es": "Contralto", "en": "Alto"},
+		Aliases: []string{"a"}},
+	{ID: "tenor", Family: FamilyVoices, Names: map[string]string{"es": "Tenor", "en": "Tenor"},
+		Aliases: []string{"t"}},
+	{ID: "baritone", Family: FamilyVoices, Names: map[string]string{"es": "Barítono", "en": "Baritone"}},
+	{ID: "bass", Family: FamilyVoices, Names: map[string]string{"es": "Bajo (voz)", "en": "Bass"},
+		Aliases: []string{"b", "basso", "bass voice"}},
+
+	// Woodwinds
+	{ID: "flute", Family: FamilyWoodwinds, Names: map[string]string{"es": "Flauta travesera", "en": "Flute"},
+		Aliases: []string{"flauta", "fl"}},
+	{ID: "recorder", Family: FamilyWoodwinds, Names: map[string]string{"es": "Flauta dulce", "en": "Recorder"}},
+	{ID: "clarinet", Family: FamilyWoodwinds, Names: map[string]string{"es": "Clarinete", "en": "Clarinet"},
+		Aliases: []string{"cl"}},
+	{ID: "oboe", Family: FamilyWoodwinds, Names: map[string]string{"es": "Oboe", "en": "Oboe"}},
+	{ID: "bassoon", Family: FamilyWoodwinds, Names: map[string]string{"es": "Fagot", "en": "Bassoon"}},
+	{ID: "saxophone", Family: FamilyWoodwinds, Names: map[string]string{"es": "Saxofón", "en": "Saxophone"},
+		Aliases: []string{"sax", "saxo"}},
+
+	// Brass
+	{ID: "trumpet", Family: FamilyBrass, Names: map[string]string{"es": "Trompeta", "en": "Trumpet"},
+		Aliases: []string{"tpt"}},
+	{ID: "trombone", Family: FamilyBrass, Names: map[string]string{"es": "Trombón", "en": "Trombone"},
+		Aliases: []string{"tbn"}},
+	{ID: "french_horn", Family: FamilyBrass, Names: map[string]string{"es": "Trompa", "en": "French horn"},
+		Aliases: []string{"horn"}},
+	{ID: "tuba", Family: FamilyBrass, Names: map[string]string{"es": "Tuba", "en": "Tuba"}},
+
+	// Percussion
+	{ID: "drums", Family: FamilyPercussion, Names: map[string]string{"es": "Batería", "en": "Drums"},
+		Aliases: []string{"drum kit", "drum set"}},
+	{ID: "percussion", Family: FamilyPercussion, Names: map[string]string{"es": "Percusión", "en": "Percussion"},
+		Aliases: []string{"perc"}},
+	{ID: "cajon", Family: FamilyPercussion, Names: map[string]string{"es": "Cajón", "en": "Cajon"}},
+	{ID: "timpani", Family: FamilyPercussion, Names: map[string]string{"es": "Timbales", "en": "Timpani"}},
+}
@@ -0,0 +1,92 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// TrashRepository defines access to songs and documents that were moved to the trash.
+// Every write also appends the given audit entry, atomically with the change.
+type TrashRepository interface {
+
+	// GetTrashedSongs returns every song in the trash.
+	// Returns:
+	//   - ([]models.Song, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the scan fails
+	GetTrashedSongs() ([]models.Song, error)
+
+	// GetTrashedDocuments returns every document in the trash, including those trashed with their song.
+	// Returns:
+	//   - ([]models.Document, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the scan fails
+	GetTrashedDocuments() ([]models.Document, error)
+
+	// GetTrashedSongByID retrieves a song in the trash.
+	// Returns:
+	//   - (*models.Song, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the song does not exist or is not in the trash
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetTrashedSongByID(songID string) (*models.Song, error)
+
+	// GetTrashedDocumentsBySongID returns the documents of a song that are in the trash.
+	// Returns:
+	//   - ([]models.Document, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetTrashedDocumentsBySongID(songID string) ([]models.Document, error)
+
+	// GetTrashedDocumentByID retrieves a document in the trash.
+	// Returns:
+	//   - (*models.Document, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the document does not exist or is not in the trash
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetTrashedDocumentByID(songID string, documentID string) (*models.Document, error)
+
+	// RestoreSong brings a song and the given documents of it back from the trash, in one transaction
+	// when they fit in it and otherwise as a models.SongPendingRestore write.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the song or a document left the trash concurrently, or the song has a pending write
+	//   - errors.ErrInternalServer if the operation fails
+	RestoreSong(songID string, documentIDs []string, audit models.AuditEntry) error
+
+	// RestoreDocument brings a document back from the trash.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the document left the trash concurrently
+	//   - errors.ErrInternalServer if the operation fails
+	RestoreDocument(songID string, documentID string, audit models.AuditEntry) error
+
+	// PurgeSong permanently deletes a song in the trash together with every document of it, in one
+	// transaction when they fit in it and otherwise as a models.SongPendingPurge write.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the song left the trash concurrently or has a pending write
+	//   - errors.ErrInternalServer if the deletion fails
+	PurgeSong(songID string, audit models.AuditEntry) error
+
+	// PurgeDocument permanently deletes a document in the trash.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the document left the trash concurrently
+	//   - errors.ErrInternalServer if the deletion fails
+	PurgeDocument(songID string, documentID string, audit models.AuditEntry) error
+
+	// GetPendingSongs returns the songs with a multi-step write in progress or interrupted, whatever
+	// their state. See models.SongPendingCreate.
+	// Returns:
+	//   - ([]models.Song, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the scan fails
+	GetPendingSongs() ([]models.Song, error)
+
+	// CompletePendingSong finishes the interrupted trash, restore or purge of a song returned by GetPendingSongs.
+	// Returns:
+	//   - nil on success, or if the song has no pending write
+	//   - errors.ErrOperationNotAllowed if the pending write is a creation, or the song changed concurrently
+	//   - errors.ErrInternalServer if the operation fails
+	CompletePendingSong(song models.Song) error
+
+	// RollBackSongCreation deletes a song whose creation was interrupted, with the documents written for it,
+	// and stores the audit entry.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the song has no pending creation
+	//   - errors.ErrInternalServer if the deletion fails
+	RollBackSongCreation(songID string, audit models.AuditEntry) error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// UserRepository defines operations for accessing and manipulating user accounts in storage.
+type UserRepository interface {
+
+	// GetUserByUsername retrieves a user by its username.
+	// Returns:
+	//   - (*models.User, nil) if found
+	//   - (nil, errors.ErrResourceNotFound) if the user does not exist
+	//   - (nil, errors.ErrInternalServer) if retrieval fails
+	GetUserByUsername(username string) (*models.User, error)
+
+	// GetAllUsers returns every user account.
+	// Returns:
+	//   - ([]models.User, nil) on success
+	//   - (nil, errors.ErrInternalServer) if the query fails
+	GetAllUsers() ([]models.User, error)
+
+	// CreateUser stores a new user.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the username is already taken
+	//   - errors.ErrInternalServer if persistence fails
+	CreateUser(user models.User) error
+
+	// UpdateUser applies partial updates to an existing user.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the user does not exist
+	//   - errors.ErrInternalServer if the update fails
+	UpdateUser(username string, updates map[string]interface{}) error
+
+	// ResetPassword stores a new password hash if tokenHash is still the pending reset token,
+	// and clears the token so it cannot be used twice.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the token is no longer pending
+	//   - errors.ErrInternalServer if the update fails
+	ResetPassword(username, tokenHash, passwordHash, updatedAt string) error
+
+	// SaveTOTPEnrollment stores the encrypted seed and recovery code hashes of an enrolment
+	// until it is confirmed with a first code. An earlier pending enrolment is replaced.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the user does not exist
+	//   - errors.ErrInternalServer if the update fails
+	SaveTOTPEnrollment(username, encryptedSecret string, recoveryCodeHashes []string, updatedAt string) error
+
+	// EnableTOTP activates the pending enrolment holding encryptedSecret, recording step as the
+	// last used time step.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the user does not exist or the pending enrolment changed
+	//   - errors.ErrInternalServer if the update fails
+	EnableTOTP(username, encryptedSecret string, recoveryCodeHashes []string, step int64, updatedAt string) error
+
+	// DisableTOTP removes the second factor of a user, including any pending enrolment.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the user does not exist
+	//   - errors.ErrInternalServer if the update fails
+	DisableTOTP(username, updatedAt string) error
+
+	// RecordTOTPStep marks a time step as used, provided it is later than the last one used.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the step was already used
+	//   - errors.ErrInternalServer if the update fails
+	RecordTOTPStep(username string, step int64) error
+
+	// ConsumeRecoveryCode removes a recovery code hash, provided it is still unused.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the code is unknown or already used
+	//   - errors.ErrInternalServer if the update fails
+	ConsumeRecoveryCode(username, codeHash string) error
+
+	// DeleteUser removes a user.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrInternalServer if the deletion fails
+	DeleteUser(username string) error
+}
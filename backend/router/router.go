@@ -5,37 +5,100 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/middleware"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
+// SourceIPHeader carries the client IP when the router runs behind API Gateway in Lambda.
+// The Lambda entry point overwrites it with the source IP of the request context, so clients
+// cannot set it themselves.
+const SourceIPHeader = "X-Rendalla-Source-Ip"
+
 // RouterOptions allows enabling or disabling middleware features when setting up the router.
 type RouterOptions struct {
 	EnableCORS     bool
 	EnableLogger   bool
 	EnableRecovery bool
+	RequireIfMatch bool
+	CachePolicies  map[string]string
+	TrustedProxies []string
+	ClientIPHeader string
+}
+
+// DefaultCachePolicies are the Cache-Control policies of the public catalogue reads, keyed by route.
+// Shared caches may keep responses for a minute and must then revalidate them with the ETag.
+var DefaultCachePolicies = map[string]string{
+	"/songs":                            "public, max-age=60, must-revalidate",
+	"/songs/:song_id":                   "public, max-age=60, must-revalidate",
+	"/songs/:song_id/documents":         "public, max-age=60, must-revalidate",
+	"/songs/:song_id/documents/:doc_id": "public, max-age=60, must-revalidate",
 }
 
 // SetupRouter configures and returns a new Gin router instance.
 // It registers all public and protected routes, applying middleware as needed.
+// Every request gets an X-Request-ID (see middleware.RequestIDMiddleware).
+// Protected routes declare the permission they require; the role to permission policy
+// lives in middleware.RolePermissions.
 //
 // Handlers:
 //   - songHandler: handles song-related endpoints
 //   - documentHandler: handles document-related endpoints
 //   - searchHandler: handles search functionality for songs and documents
 //   - authHandler: handles authentication endpoints
+//   - autocompleteHandler: handles search-as-you-type suggestions
+//   - instrumentHandler: exposes the canonical instrument catalogue
+//   - genreHandler: handles the managed genre catalogue
+//   - userHandler: handles back-office user management
+//   - jwksHandler: publishes the public token verification keys
+//   - apiKeyHandler: handles API keys of automation clients
+//   - oidcHandler: handles the login through the organisation's OpenID provider
+//   - auditHandler: exposes the audit trail of song and document changes
+//   - revisionHandler: lists, compares and restores previous versions of songs and documents
+//   - trashHandler: restores and purges deleted songs and documents, and reconciles interrupted song writes
+//   - cacheHandler: reports the hits and misses of the song and document cache
+//   - consistencyHandler: checks and repairs the song titles copied to documents
+//   - authMiddleware: authenticates protected routes (see middleware.JWTAuthMiddleware)
+//   - idempotencyMiddleware: replays the responses of create requests retried with an Idempotency-Key (see middleware.Idempotency)
 //
 // RouterOptions:
 //   - EnableCORS: enables CORS middleware if true
 //   - EnableLogger: enables Gin's logging middleware if true
 //   - EnableRecovery: enables panic recovery middleware if true
+//   - RequireIfMatch: rejects updates, deletions and revision restores of songs and documents without If-Match if true
+//   - CachePolicies: overrides DefaultCachePolicies per route; an empty policy sends no Cache-Control
+//   - TrustedProxies: proxies whose X-Forwarded-For gives the client IP; none are trusted when empty
+//   - ClientIPHeader: header taken as the client IP before anything else, such as SourceIPHeader in Lambda;
+//     it must only be set by the platform in front of the router
 func SetupRouter(
 	songHandler *handlers.SongHandler,
 	documentHandler *handlers.DocumentHandler,
 	searchHandler *handlers.SearchHandler,
 	authHandler *handlers.AuthHandler,
+	autocompleteHandler *handlers.AutocompleteHandler,
+	instrumentHandler *handlers.InstrumentHandler,
+	genreHandler *handlers.GenreHandler,
+	userHandler *handlers.UserHandler,
+	jwksHandler *handlers.JWKSHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	oidcHandler *handlers.OIDCHandler,
+	auditHandler *handlers.AuditHandler,
+	revisionHandler *handlers.RevisionHandler,
+	trashHandler *handlers.TrashHandler,
+	cacheHandler *handlers.CacheHandler,
+	consistencyHandler *handlers.ConsistencyHandler,
+	authMiddleware gin.HandlerFunc,
+	idempotencyMiddleware gin.HandlerFunc,
 	opts RouterOptions,
 ) *gin.Engine {
 
 	r := gin.New()
+	// Login throttling is keyed on the client IP, so forwarded headers are only believed from
+	// known proxies.
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		logrus.WithError(err).Error("Invalid trusted proxies, trusting none")
+		_ = r.SetTrustedProxies(nil)
+	}
+	r.TrustedPlatform = opts.ClientIPHeader
+	r.Use(middleware.RequestIDMiddleware())
 
 	if opts.EnableCORS {
 		r.Use(cors.Default())
@@ -55,34 +118,107 @@ func SetupRouter(
 		})
 	})
 
+	cache := func(route string) gin.HandlerFunc {
+		policy, ok := opts.CachePolicies[route]
+		if !ok {
+			policy = DefaultCachePolicies[route]
+		}
+		return middleware.CacheControl(policy)
+	}
+
 	// Public routes (no authentication required)
 	public := r.Group("/")
 	{
-		public.GET("/songs", songHandler.GetAllSongsHandler)
-		public.GET("/songs/:song_id", songHandler.GetSongByIDHandler)
+		public.GET("/songs", cache("/songs"), songHandler.GetAllSongsHandler)
+		public.GET("/songs/:song_id", cache("/songs/:song_id"), songHandler.GetSongByIDHandler)
 
-		public.GET("/songs/:song_id/documents", documentHandler.GetAllDocumentsBySongIDHandler)
-		public.GET("/songs/:song_id/documents/:doc_id", documentHandler.GetDocumentByIDHandler)
+		public.GET("/songs/:song_id/documents", cache("/songs/:song_id/documents"), documentHandler.GetAllDocumentsBySongIDHandler)
+		public.GET("/songs/:song_id/documents/:doc_id", cache("/songs/:song_id/documents/:doc_id"), documentHandler.GetDocumentByIDHandler)
 
 		public.GET("/songs/search", searchHandler.ListSongsHandler)
 		public.GET("/documents/search", searchHandler.ListDocumentsHandler)
+		public.GET("/search", searchHandler.SearchCatalogHandler)
+		public.GET("/autocomplete", autocompleteHandler.SuggestHandler)
+		public.GET("/instruments", instrumentHandler.ListInstrumentsHandler)
+		public.GET("/genres", genreHandler.ListGenresHandler)
+
+		public.GET("/.well-known/jwks.json", jwksHandler.GetJWKSHandler)
 
 		public.POST("/auth/login", authHandler.LoginHandler)
+		public.POST("/auth/login/2fa", authHandler.LoginSecondFactorHandler)
+		public.POST("/auth/login/2fa/enroll", authHandler.LoginEnrollTOTPHandler)
+		public.POST("/auth/refresh", authHandler.RefreshHandler)
+		public.POST("/auth/password/reset", authHandler.ResetPasswordHandler)
+		public.POST("/auth/invite/accept", userHandler.AcceptInviteHandler)
+		public.GET("/auth/oidc/login", oidcHandler.LoginHandler)
+		public.GET("/auth/oidc/callback", oidcHandler.CallbackHandler)
 	}
 
-	// Protected routes (authentication required)
+	// Protected routes (authentication required, permission declared per route)
 	auth := r.Group("/")
-	auth.Use(middleware.JWTAuthMiddleware())
+	auth.Use(authMiddleware)
+	ifMatch := middleware.RequireIfMatch(opts.RequireIfMatch)
 	{
-		auth.POST("/songs", songHandler.CreateSongHandler)
-		auth.PUT("/songs/:song_id", songHandler.UpdateSongHandler)
-		auth.DELETE("/songs/:song_id", songHandler.DeleteSongWithDocumentsHandler)
+		auth.POST("/songs", middleware.RequirePermission(middleware.PermSongsCreate), idempotencyMiddleware, songHandler.CreateSongHandler)
+		auth.PUT("/songs/:song_id", middleware.RequirePermission(middleware.PermSongsUpdate), ifMatch, songHandler.UpdateSongHandler)
+		auth.DELETE("/songs/:song_id", middleware.RequirePermission(middleware.PermSongsDelete), ifMatch, songHandler.DeleteSongWithDocumentsHandler)
+
+		auth.POST("/songs/:song_id/documents", middleware.RequirePermission(middleware.PermDocumentsCreate), idempotencyMiddleware, documentHandler.CreateDocumentHandler)
+		auth.PUT("/songs/:song_id/documents/:doc_id", middleware.RequirePermission(middleware.PermDocumentsUpdate), ifMatch, documentHandler.UpdateDocumentHandler)
+		auth.DELETE("/songs/:song_id/documents/:doc_id", middleware.RequirePermission(middleware.PermDocumentsDelete), ifMatch, documentHandler.DeleteDocumentHandler)
+
+		auth.GET("/songs/:song_id/revisions", middleware.RequirePermission(middleware.PermSongsUpdate), revisionHandler.ListRevisionsHandler)
+		auth.GET("/songs/:song_id/revisions/diff", middleware.RequirePermission(middleware.PermSongsUpdate), revisionHandler.DiffRevisionsHandler)
+		auth.POST("/songs/:song_id/revisions/:rev/restore",
+			middleware.RequirePermission(middleware.PermSongsUpdate),
+			middleware.RequirePermission(middleware.PermDocumentsUpdate),
+			ifMatch,
+			revisionHandler.RestoreRevisionHandler)
 
-		auth.POST("/songs/:song_id/documents", documentHandler.CreateDocumentHandler)
-		auth.PUT("/songs/:song_id/documents/:doc_id", documentHandler.UpdateDocumentHandler)
-		auth.DELETE("/songs/:song_id/documents/:doc_id", documentHandler.DeleteDocumentHandler)
+		auth.POST("/genres", middleware.RequirePermission(middleware.PermGenresManage), idempotencyMiddleware, genreHandler.CreateGenreHandler)
+		auth.PUT("/genres/:genre_id", middleware.RequirePermission(middleware.PermGenresManage), genreHandler.UpdateGenreHandler)
+		auth.POST("/genres/:genre_id/merge", middleware.RequirePermission(middleware.PermGenresManage), genreHandler.MergeGenresHandler)
 
 		auth.GET("/auth/me", authHandler.MeHandler)
+		auth.POST("/auth/logout", authHandler.LogoutHandler)
+		auth.POST("/auth/logout-all", authHandler.LogoutAllHandler)
+		auth.POST("/auth/password", authHandler.ChangePasswordHandler)
+		auth.POST("/auth/2fa/enroll", authHandler.EnrollTOTPHandler)
+		auth.POST("/auth/2fa/confirm", authHandler.ConfirmTOTPHandler)
+		auth.POST("/auth/2fa/disable", authHandler.DisableTOTPHandler)
+	}
+
+	// Admin routes (authentication and user management permission required)
+	admin := r.Group("/admin")
+	admin.Use(authMiddleware, middleware.RequirePermission(middleware.PermUsersManage))
+	{
+		admin.GET("/users", userHandler.ListUsersHandler)
+		admin.POST("/users", userHandler.InviteUserHandler)
+		admin.POST("/users/:username/disable", userHandler.DisableUserHandler)
+		admin.POST("/users/:username/enable", userHandler.EnableUserHandler)
+		admin.POST("/users/:username/unlock", authHandler.UnlockAccountHandler)
+		admin.DELETE("/users/:username/2fa", authHandler.ResetTOTPHandler)
+		admin.POST("/users/:username/password-reset", authHandler.IssuePasswordResetHandler)
+		admin.DELETE("/users/:username", userHandler.DeleteUserHandler)
+
+		admin.GET("/api-keys", apiKeyHandler.ListAPIKeysHandler)
+		admin.POST("/api-keys", apiKeyHandler.CreateAPIKeyHandler)
+		admin.DELETE("/api-keys/:key_id", apiKeyHandler.RevokeAPIKeyHandler)
+
+		admin.GET("/audit", auditHandler.ListAuditEntriesHandler)
+
+		admin.GET("/trash", trashHandler.ListTrashHandler)
+		admin.POST("/trash/purge", trashHandler.PurgeExpiredHandler)
+		admin.POST("/trash/reconcile", trashHandler.ReconcilePendingSongsHandler)
+		admin.POST("/trash/songs/:song_id/restore", trashHandler.RestoreSongHandler)
+		admin.DELETE("/trash/songs/:song_id", trashHandler.PurgeSongHandler)
+		admin.POST("/trash/songs/:song_id/documents/:doc_id/restore", trashHandler.RestoreDocumentHandler)
+		admin.DELETE("/trash/songs/:song_id/documents/:doc_id", trashHandler.PurgeDocumentHandler)
+
+		admin.GET("/cache", cacheHandler.GetCacheStatsHandler)
+
+		admin.GET("/consistency/titles", consistencyHandler.CheckDocumentTitlesHandler)
+		admin.POST("/consistency/titles/repair", consistencyHandler.RepairDocumentTitlesHandler)
 	}
 
 	return r
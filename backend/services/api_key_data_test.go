@@ -0,0 +1,38 @@
+package services_test
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+const APIKeyID = "key-1"
+const RawAPIKey = "rk_" + APIKeyID + ".secret"
+
+var ActiveAPIKey = models.APIKey{
+	ID:        APIKeyID,
+	Name:      "nightly import",
+	KeyHash:   utils.HashToken(RawAPIKey),
+	Scopes:    []string{models.ScopeSongsWrite},
+	Status:    models.APIKeyStatusActive,
+	CreatedBy: "admin",
+	ExpiresAt: 5000,
+}
+
+var NonExpiringAPIKey = models.APIKey{
+	ID:      APIKeyID,
+	KeyHash: utils.HashToken(RawAPIKey),
+	Scopes:  []string{models.ScopeDocumentsRead},
+	Status:  models.APIKeyStatusActive,
+}
+
+var RevokedAPIKey = models.APIKey{
+	ID:      APIKeyID,
+	KeyHash: utils.HashToken(RawAPIKey),
+	Scopes:  []string{models.ScopeSongsWrite},
+	Status:  models.APIKeyStatusRevoked,
+}
+
+var StoredAPIKeys = []models.APIKey{
+	{ID: "old", Name: "old import", Scopes: []string{models.ScopeSongsWrite, models.ScopeDocumentsWrite}, CreatedAt: "2024-01-01T00:00:00Z"},
+	{ID: "new", Name: "new import", Scopes: []string{models.ScopeSongsRead}, CreatedAt: "2025-01-01T00:00:00Z"},
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// APIKeyServiceInterface defines the management and verification of API keys used by automation clients.
+type APIKeyServiceInterface interface {
+
+	// ListAPIKeys returns every API key, newest first. Keys are never returned in clear.
+	// Returns:
+	//   - ([]dto.APIKeyResponseItem, nil) on success
+	//   - error if the keys cannot be read
+	ListAPIKeys() ([]dto.APIKeyResponseItem, error)
+
+	// CreateAPIKey generates a new key with the requested scopes and optional expiry.
+	// Returns:
+	//   - the key in clear, shown only once, on success
+	//   - errors.ErrValidationFailed if the name, scopes or expiry are invalid
+	//   - error if persistence fails
+	CreateAPIKey(actor string, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+
+	// RevokeAPIKey permanently disables a key.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrResourceNotFound if the key does not exist
+	//   - error if persistence fails
+	RevokeAPIKey(actor, id string) error
+
+	// AuthenticateAPIKey verifies a key sent by a client and records its use.
+	// Returns:
+	//   - (*models.APIKey, nil) for an active, unexpired key
+	//   - errors.ErrInvalidCredentials if the key is unknown, revoked or expired
+	//   - error if the key cannot be read
+	AuthenticateAPIKey(rawKey string) (*models.APIKey, error)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"crypto/subtle"
+	stdErrors "errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// apiKeyPrefix marks Rendalla API keys so they are easy to recognise in configuration and secret scanners.
+const apiKeyPrefix = "rk_"
+
+// Ensure APIKeyService implements APIKeyServiceInterface.
+var _ APIKeyServiceInterface = (*APIKeyService)(nil)
+
+// APIKeyService manages API keys for automation clients.
+// A key has the form "rk_<id>.<secret>": the ID locates the stored record and only the
+// SHA-256 digest of the whole key is kept, so a leaked table does not leak usable keys.
+type APIKeyService struct {
+	repo         repository.APIKeyRepository
+	idGen        utils.IDGenerator
+	timeProvider utils.TimeProvider
+}
+
+// NewAPIKeyService returns a new instance of APIKeyService.
+func NewAPIKeyService(repo repository.APIKeyRepository, idGen utils.IDGenerator, timeProvider utils.TimeProvider) *APIKeyService {
+	return &APIKeyService{
+		repo:         repo,
+		idGen:        idGen,
+		timeProvider: timeProvider,
+	}
+}
+
+// ListAPIKeys returns every API key ordered by creation time, newest first.
+func (s *APIKeyService) ListAPIKeys() ([]dto.APIKeyResponseItem, error) {
+	keys, err := s.repo.GetAllAPIKeys()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving API keys: %w", err)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt > keys[j].CreatedAt
+	})
+	return dto.ToAPIKeyResponseList(keys), nil
+}
+
+// CreateAPIKey validates the request and stores a new active key.
+// The generated key is returned in clear exactly once.
+func (s *APIKeyService) CreateAPIKey(actor string, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	if err := dto.ValidateCreateAPIKeyRequest(req); err != nil {
+		return nil, fmt.Errorf("validating API key: %w", err)
+	}
+
+	id := s.idGen.NewID()
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generating API key: %w", errors.ErrInternalServer)
+	}
+	rawKey := apiKeyPrefix + id + "." + secret
+
+	scopes := slices.Clone(req.Scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	var expiresAt int64
+	if req.ExpiresInDays > 0 {
+		expiresAt = s.timeProvider.NowUnix() + int64(req.ExpiresInDays)*24*3600
+	}
+
+	now := s.timeProvider.Now()
+	key := models.APIKey{
+		ID:        id,
+		Name:      strings.TrimSpace(req.Name),
+		KeyHash:   utils.HashToken(rawKey),
+		Scopes:    scopes,
+		Status:    models.APIKeyStatusActive,
+		CreatedBy: actor,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateAPIKey(key); err != nil {
+		return nil, fmt.Errorf("creating API key %s: %w", id, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_key_id": id,
+		"scopes":     scopes,
+		"created_by": actor,
+	}).Info("API key created")
+
+	return &dto.CreateAPIKeyResponse{
+		Message:   "API key created successfully",
+		ID:        id,
+		Key:       rawKey,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RevokeAPIKey marks the key as revoked. Revoking an already revoked key succeeds.
+func (s *APIKeyService) RevokeAPIKey(actor, id string) error {
+	if _, err := s.repo.GetAPIKeyByID(id); err != nil {
+		return fmt.Errorf("retrieving API key %s: %w", id, err)
+	}
+	if err := s.repo.RevokeAPIKey(id, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("revoking API key %s: %w", id, err)
+	}
+
+	logrus.WithFields(logrus.Fields{"api_key_id": id, "actor": actor}).Info("API key revoked")
+	return nil
+}
+
+// AuthenticateAPIKey checks the key against the stored digest, its status and its expiry,
+// then records the time of use. A failure to record the use does not reject the request.
+func (s *APIKeyService) AuthenticateAPIKey(rawKey string) (*models.APIKey, error) {
+	id, _, ok := strings.Cut(strings.TrimPrefix(rawKey, apiKeyPrefix), ".")
+	if !ok || id == "" || !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	key, err := s.repo.GetAPIKeyByID(id)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("retrieving API key %s: %w", id, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(rawKey)), []byte(key.KeyHash)) != 1 {
+		return nil, errors.ErrInvalidCredentials
+	}
+	if key.Status != models.APIKeyStatusActive {
+		return nil, fmt.Errorf("API key %s is %s: %w", id, key.Status, errors.ErrInvalidCredentials)
+	}
+	if key.ExpiresAt != 0 && s.timeProvider.NowUnix() >= key.ExpiresAt {
+		return nil, fmt.Errorf("API key %s expired: %w", id, errors.ErrInvalidCredentials)
+	}
+
+	now := s.timeProvider.Now()
+	if err := s.repo.TouchAPIKey(id, now); err != nil {
+		logrus.WithError(err).WithField("api_key_id", id).Warn("Failed to record API key usage")
+	} else {
+		key.LastUsedAt = now
+	}
+	return key, nil
+}
@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAPIKeyServiceTest(nowUnix int64) (*services.APIKeyService, *mocks.MockAPIKeyRepository) {
+	repo := new(mocks.MockAPIKeyRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProvider := new(mocks.MockTimeProvider)
+	idGen.On("NewID").Return(APIKeyID).Maybe()
+	timeProvider.On("Now").Return("now").Maybe()
+	timeProvider.On("NowUnix").Return(nowUnix).Maybe()
+	return services.NewAPIKeyService(repo, idGen, timeProvider), repo
+}
+
+func TestListAPIKeys(t *testing.T) {
+	service, repo := setupAPIKeyServiceTest(1000)
+	repo.On("GetAllAPIKeys").Return(append([]models.APIKey{}, StoredAPIKeys...), nil)
+
+	keys, err := service.ListAPIKeys()
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "new", keys[0].ID)
+	assert.Equal(t, []string{models.ScopeDocumentsWrite, models.ScopeSongsWrite}, keys[1].Scopes)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         dto.CreateAPIKeyRequest
+		expectCreate    bool
+		mockCreateErr   error
+		expectScopes    []string
+		expectExpiresAt int64
+		expectError     error
+	}{
+		{
+			name:            "success with expiry",
+			request:         dto.CreateAPIKeyRequest{Name: " nightly import ", Scopes: []string{"songs:write", "documents:read", "songs:write"}, ExpiresInDays: 30},
+			expectCreate:    true,
+			expectScopes:    []string{models.ScopeDocumentsRead, models.ScopeSongsWrite},
+			expectExpiresAt: 1000 + 30*24*3600,
+		},
+		{
+			name:         "success without expiry",
+			request:      dto.CreateAPIKeyRequest{Name: "reader", Scopes: []string{"songs:read"}},
+			expectCreate: true,
+			expectScopes: []string{models.ScopeSongsRead},
+		},
+		{
+			name:        "unknown scope",
+			request:     dto.CreateAPIKeyRequest{Name: "nightly import", Scopes: []string{"users:manage"}},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "no scopes",
+			request:     dto.CreateAPIKeyRequest{Name: "nightly import"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "name too short",
+			request:     dto.CreateAPIKeyRequest{Name: "ab", Scopes: []string{"songs:read"}},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "negative expiry",
+			request:     dto.CreateAPIKeyRequest{Name: "nightly import", Scopes: []string{"songs:read"}, ExpiresInDays: -1},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:          "repository error",
+			request:       dto.CreateAPIKeyRequest{Name: "nightly import", Scopes: []string{"songs:read"}},
+			expectCreate:  true,
+			mockCreateErr: errors.ErrInternalServer,
+			expectError:   errors.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupAPIKeyServiceTest(1000)
+			var created models.APIKey
+			if tt.expectCreate {
+				repo.On("CreateAPIKey", mock.Anything).Run(func(args mock.Arguments) {
+					created = args.Get(0).(models.APIKey)
+				}).Return(tt.mockCreateErr)
+			}
+
+			resp, err := service.CreateAPIKey("admin", tt.request)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(resp.Key, "rk_"+APIKeyID+"."))
+				assert.Equal(t, tt.expectScopes, resp.Scopes)
+				assert.Equal(t, tt.expectExpiresAt, resp.ExpiresAt)
+
+				assert.Equal(t, strings.TrimSpace(tt.request.Name), created.Name)
+				assert.Equal(t, utils.HashToken(resp.Key), created.KeyHash)
+				assert.Equal(t, tt.expectScopes, created.Scopes)
+				assert.Equal(t, models.APIKeyStatusActive, created.Status)
+				assert.Equal(t, "admin", created.CreatedBy)
+				assert.Equal(t, tt.expectExpiresAt, created.ExpiresAt)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		getErr       error
+		revokeErr    error
+		expectRevoke bool
+		expectError  error
+	}{
+		{name: "success", expectRevoke: true},
+		{name: "not found", getErr: errors.ErrResourceNotFound, expectError: errors.ErrResourceNotFound},
+		{name: "repository error", expectRevoke: true, revokeErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupAPIKeyServiceTest(1000)
+			if tt.getErr != nil {
+				repo.On("GetAPIKeyByID", APIKeyID).Return(nil, tt.getErr)
+			} else {
+				repo.On("GetAPIKeyByID", APIKeyID).Return(&ActiveAPIKey, nil)
+			}
+			if tt.expectRevoke {
+				repo.On("RevokeAPIKey", APIKeyID, "now").Return(tt.revokeErr)
+			}
+
+			err := service.RevokeAPIKey("admin", APIKeyID)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawKey      string
+		stored      *models.APIKey
+		getErr      error
+		nowUnix     int64
+		touchErr    error
+		expectTouch bool
+		expectError error
+	}{
+		{name: "valid key", rawKey: RawAPIKey, stored: &ActiveAPIKey, nowUnix: 1000, expectTouch: true},
+		{name: "valid non-expiring key", rawKey: RawAPIKey, stored: &NonExpiringAPIKey, nowUnix: 1 << 40, expectTouch: true},
+		{name: "usage cannot be recorded", rawKey: RawAPIKey, stored: &ActiveAPIKey, nowUnix: 1000, expectTouch: true, touchErr: errors.ErrInternalServer},
+		{name: "malformed key", rawKey: "not-a-key", expectError: errors.ErrInvalidCredentials},
+		{name: "missing prefix", rawKey: APIKeyID + ".secret", expectError: errors.ErrInvalidCredentials},
+		{name: "unknown key", rawKey: RawAPIKey, getErr: errors.ErrResourceNotFound, expectError: errors.ErrInvalidCredentials},
+		{name: "wrong secret", rawKey: "rk_" + APIKeyID + ".guess", stored: &ActiveAPIKey, nowUnix: 1000, expectError: errors.ErrInvalidCredentials},
+		{name: "revoked key", rawKey: RawAPIKey, stored: &RevokedAPIKey, nowUnix: 1000, expectError: errors.ErrInvalidCredentials},
+		{name: "expired key", rawKey: RawAPIKey, stored: &ActiveAPIKey, nowUnix: 5000, expectError: errors.ErrInvalidCredentials},
+		{name: "repository error", rawKey: RawAPIKey, getErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupAPIKeyServiceTest(tt.nowUnix)
+			if tt.stored != nil {
+				stored := *tt.stored
+				repo.On("GetAPIKeyByID", APIKeyID).Return(&stored, nil)
+			} else if tt.getErr != nil {
+				repo.On("GetAPIKeyByID", APIKeyID).Return(nil, tt.getErr)
+			}
+			if tt.expectTouch {
+				repo.On("TouchAPIKey", APIKeyID, "now").Return(tt.touchErr)
+			}
+
+			key, err := service.AuthenticateAPIKey(tt.rawKey)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, key)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, APIKeyID, key.ID)
+			}
+			if !tt.expectTouch {
+				repo.AssertNotCalled(t, "TouchAPIKey", mock.Anything, mock.Anything)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
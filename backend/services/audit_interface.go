@@ -0,0 +1,15 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// AuditServiceInterface defines read access to the audit trail of catalogue changes.
+type AuditServiceInterface interface {
+
+	// ListAuditEntries returns one page of the audit entries matching the query, newest first.
+	// Returns:
+	//   - (dto.AuditListResponse, nil) on success
+	//   - errors.ErrValidationFailed if the entity, the since timestamp or the limit are invalid
+	//   - errors.ErrBadRequest if the cursor is malformed
+	//   - error if the entries cannot be read
+	ListAuditEntries(query dto.AuditQuery) (dto.AuditListResponse, error)
+}
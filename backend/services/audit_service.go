@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+// auditIgnoredFields are left out of audit diffs: the entry timestamp already records when the change happened.
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// songAuditView is the audited form of a song: its fields plus the IDs of its documents,
+// so that creating or deleting a song with documents is described by a single entry.
+type songAuditView struct {
+	models.Song
+	Documents []string `json:"documents,omitempty"`
+}
+
+// newSongAuditView returns the audited form of song and its documents.
+func newSongAuditView(song models.Song, documents []models.Document) *songAuditView {
+	ids := make([]string, len(documents))
+	for i, doc := range documents {
+		ids[i] = doc.ID
+	}
+	return &songAuditView{Song: song, Documents: ids}
+}
+
+// Ensure AuditService implements AuditServiceInterface.
+var _ AuditServiceInterface = (*AuditService)(nil)
+
+// AuditService exposes the audit trail written by SongService and DocumentService.
+type AuditService struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService returns a new instance of AuditService.
+func NewAuditService(repo repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// ListAuditEntries returns one page of the entries matching the query ordered by timestamp, newest first.
+func (s *AuditService) ListAuditEntries(query dto.AuditQuery) (dto.AuditListResponse, error) {
+	query, err := dto.NormalizeAuditQuery(query)
+	if err != nil {
+		return dto.AuditListResponse{}, fmt.Errorf("validating audit query: %w", err)
+	}
+
+	filter := repository.AuditFilter{
+		Entity: query.Entity,
+		Actor:  query.Actor,
+		Since:  query.Since,
+	}
+	entries, next, err := s.repo.ListAuditEntries(filter, repository.ListOptions{Limit: query.Limit, Cursor: query.Cursor})
+	if err != nil {
+		return dto.AuditListResponse{}, fmt.Errorf("retrieving audit entries: %w", err)
+	}
+	return dto.AuditListResponse{
+		Data:      dto.ToAuditEntryResponseList(entries),
+		Count:     len(entries),
+		NextToken: next,
+	}, nil
+}
+
+// newAuditEntry describes a change of an entity from before to after, either of which may be nil.
+// Both are compared through their JSON form, so fields hidden from the API are not recorded.
+func newAuditEntry(idGen utils.IDGenerator, actor models.Actor, timestamp, entity, entityID, songID, action string, before, after interface{}) models.AuditEntry {
+	return models.AuditEntry{
+		ID:        idGen.NewID(),
+		Entity:    entity,
+		EntityID:  entityID,
+		SongID:    songID,
+		Action:    action,
+		Actor:     actor.Username,
+		RequestID: actor.RequestID,
+		Timestamp: timestamp,
+		Changes:   diffFields(before, after),
+	}
+}
+
+// diffFields returns the fields whose value differs between before and after, sorted by name.
+func diffFields(before, after interface{}) []models.FieldChange {
+	beforeFields := auditFields(before)
+	afterFields := auditFields(after)
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []models.FieldChange{}
+	for _, name := range names {
+		if auditIgnoredFields[name] || reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{
+			Field:  name,
+			Before: beforeFields[name],
+			After:  afterFields[name],
+		})
+	}
+	return changes
+}
+
+// auditFields returns the JSON fields of v, or nil if v is a nil pointer.
+func auditFields(v interface{}) map[string]interface{} {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
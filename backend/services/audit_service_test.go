@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListAuditEntries(t *testing.T) {
+	stored := []models.AuditEntry{
+		{ID: "a2", Entity: models.AuditEntitySong, Actor: "maria", Timestamp: "2025-01-03T10:00:00Z"},
+		{ID: "a3", Entity: models.AuditEntitySong, Actor: "maria", Timestamp: "2025-01-02T10:00:00Z"},
+		{ID: "a1", Entity: models.AuditEntitySong, Actor: "maria", Timestamp: "2025-01-01T10:00:00Z"},
+	}
+	firstPage := repository.ListOptions{Limit: dto.DefaultListLimit}
+
+	tests := []struct {
+		name         string
+		query        dto.AuditQuery
+		expectFilter repository.AuditFilter
+		expectOpts   repository.ListOptions
+		repoNext     string
+		repoErr      error
+		expectError  error
+	}{
+		{name: "no filter", query: dto.AuditQuery{}, expectFilter: repository.AuditFilter{}, expectOpts: firstPage},
+		{
+			name:         "all filters",
+			query:        dto.AuditQuery{Entity: " song ", Actor: "maria", Since: "2025-01-01T12:00:00+02:00"},
+			expectFilter: repository.AuditFilter{Entity: "song", Actor: "maria", Since: "2025-01-01T10:00:00Z"},
+			expectOpts:   firstPage,
+		},
+		{
+			name:         "next page",
+			query:        dto.AuditQuery{Limit: 3, Cursor: "abc"},
+			expectFilter: repository.AuditFilter{},
+			expectOpts:   repository.ListOptions{Limit: 3, Cursor: "abc"},
+			repoNext:     "def",
+		},
+		{name: "unknown entity", query: dto.AuditQuery{Entity: "genre"}, expectError: errors.ErrValidationFailed},
+		{name: "malformed since", query: dto.AuditQuery{Since: "yesterday"}, expectError: errors.ErrValidationFailed},
+		{name: "limit too large", query: dto.AuditQuery{Limit: dto.MaxListLimit + 1}, expectError: errors.ErrValidationFailed},
+		{name: "repository error", query: dto.AuditQuery{}, expectFilter: repository.AuditFilter{}, expectOpts: firstPage, repoErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockAuditRepository)
+			service := services.NewAuditService(repo)
+			if tt.expectError == nil || tt.repoErr != nil {
+				if tt.repoErr != nil {
+					repo.On("ListAuditEntries", tt.expectFilter, tt.expectOpts).Return(nil, "", tt.repoErr)
+				} else {
+					repo.On("ListAuditEntries", tt.expectFilter, tt.expectOpts).Return(append([]models.AuditEntry(nil), stored...), tt.repoNext, nil)
+				}
+			}
+
+			page, err := service.ListAuditEntries(tt.query)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Empty(t, page.Data)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 3, page.Count)
+				assert.Equal(t, tt.repoNext, page.NextToken)
+				if assert.Len(t, page.Data, 3) {
+					assert.Equal(t, []string{"a2", "a3", "a1"}, []string{page.Data[0].ID, page.Data[1].ID, page.Data[2].ID})
+				}
+			}
+			repo.AssertExpectations(t)
+			if tt.repoErr == nil && tt.expectError != nil {
+				repo.AssertNotCalled(t, "ListAuditEntries", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package services_test
 
 import (
+	"time"
+
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -29,3 +34,128 @@ var InvalidPasswordInput = dto.LoginRequest{
 }
 
 var GeneratedToken = "mocked.jwt.token"
+
+var EditorLoginInput = dto.LoginRequest{
+	Username: "Maria",
+	Password: "secret",
+}
+
+var ActiveEditorUser = models.User{
+	Username:     "maria",
+	PasswordHash: string(HashedSecretPassword),
+	Role:         models.RoleEditor,
+	Status:       models.UserStatusActive,
+}
+
+var DisabledEditorUser = models.User{
+	Username:     "maria",
+	PasswordHash: string(HashedSecretPassword),
+	Role:         models.RoleEditor,
+	Status:       models.UserStatusDisabled,
+}
+
+var InvitedViewerUser = models.User{
+	Username:        "maria",
+	Role:            models.RoleViewer,
+	Status:          models.UserStatusInvited,
+	InviteTokenHash: "pending",
+}
+
+const SessionID = "sess-1"
+const CurrentRefreshToken = SessionID + ".current"
+const RotatedRefreshToken = SessionID + ".rotated"
+
+var ActiveSession = models.Session{
+	ID:                 SessionID,
+	Username:           "admin",
+	Role:               models.RoleAdmin,
+	TokenHash:          utils.HashToken(CurrentRefreshToken),
+	RotatedTokenHashes: []string{utils.HashToken(RotatedRefreshToken)},
+	Status:             models.SessionStatusActive,
+	ExpiresAt:          5000,
+}
+
+var RevokedSession = models.Session{
+	ID:        SessionID,
+	Username:  "admin",
+	Role:      models.RoleAdmin,
+	TokenHash: utils.HashToken(CurrentRefreshToken),
+	Status:    models.SessionStatusRevoked,
+	ExpiresAt: 5000,
+}
+
+var ExpiredSession = models.Session{
+	ID:        SessionID,
+	Username:  "admin",
+	Role:      models.RoleAdmin,
+	TokenHash: utils.HashToken(CurrentRefreshToken),
+	Status:    models.SessionStatusActive,
+	ExpiresAt: 1000,
+}
+
+// RFC 6238 test seed; its code for t=59 is 287082 (the last six digits of 94287082).
+var TOTPSeed = []byte("12345678901234567890")
+
+var TOTPCipher, _ = utils.NewSecretCipher([]byte("0123456789abcdef0123456789abcdef"))
+
+var EncryptedTOTPSeed, _ = TOTPCipher.Encrypt(TOTPSeed, "ana")
+
+var TwoFactorTestConfig = services.TwoFactorConfig{
+	Issuer:        "Rendalla",
+	Cipher:        TOTPCipher,
+	RequiredRoles: []string{models.RoleAdmin},
+	ChallengeTTL:  5 * time.Minute,
+}
+
+const MFAToken = SessionID + ".challenge"
+
+const RecoveryCode = "abcd-efgh"
+
+var AdminWithTOTP = models.User{
+	Username:           "ana",
+	PasswordHash:       string(HashedSecretPassword),
+	Role:               models.RoleAdmin,
+	Status:             models.UserStatusActive,
+	TOTPEnabled:        true,
+	TOTPSecret:         EncryptedTOTPSeed,
+	RecoveryCodeHashes: []string{utils.HashToken("abcdefgh"), utils.HashToken("ijklmnop")},
+}
+
+var AdminEnrolling = models.User{
+	Username:                  "ana",
+	PasswordHash:              string(HashedSecretPassword),
+	Role:                      models.RoleAdmin,
+	Status:                    models.UserStatusActive,
+	TOTPPendingSecret:         EncryptedTOTPSeed,
+	PendingRecoveryCodeHashes: []string{utils.HashToken("abcdefgh")},
+}
+
+var AdminWithoutTOTP = models.User{
+	Username:     "ana",
+	PasswordHash: string(HashedSecretPassword),
+	Role:         models.RoleAdmin,
+	Status:       models.UserStatusActive,
+}
+
+var EditorWithTOTP = models.User{
+	Username:     "ana",
+	PasswordHash: string(HashedSecretPassword),
+	Role:         models.RoleEditor,
+	Status:       models.UserStatusActive,
+	TOTPEnabled:  true,
+	TOTPSecret:   EncryptedTOTPSeed,
+}
+
+var PendingMFASession = models.Session{
+	ID:        SessionID,
+	Username:  "ana",
+	Role:      models.RoleAdmin,
+	TokenHash: utils.HashToken(MFAToken),
+	Status:    models.SessionStatusMFAPending,
+	ExpiresAt: 30300,
+}
+
+// StrongPassword meets the password strength policy.
+const StrongPassword = "Tr0mbone-Solo"
+
+const ResetToken = "reset-token"
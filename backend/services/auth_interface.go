@@ -1,16 +1,139 @@
 package services
 
-import "github.com/CristinaRendaLopez/rendalla-backend/repository"
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+)
 
-// AuthServiceInterface defines authentication-related operations for the admin user.
+// AuthServiceInterface defines authentication-related operations for back-office users.
 type AuthServiceInterface interface {
 
-	// AuthenticateUser verifies the provided username and password against stored credentials.
+	// AuthenticateUser verifies the provided username and password against the users store,
+	// falling back to the single admin credentials, and opens a new session.
+	// Failed attempts are counted per username and per client IP.
+	// Accounts that need a second factor get an MFA token instead of the tokens.
 	// Returns:
-	//   - a signed JWT token string on successful authentication
+	//   - a short-lived access token carrying the user's role and a refresh token, or an MFA challenge, on success
 	//   - errors.ErrInvalidCredentials if authentication fails
+	//   - errors.ErrThroughputExceeded if the username or client IP is backing off or locked
 	//   - errors.ErrInternalServer if token generation or credential retrieval fails
-	AuthenticateUser(username, password string) (string, error)
+	AuthenticateUser(username, password, clientIP string) (*dto.AuthResponse, error)
+
+	// CompleteSecondFactor finishes a login with a TOTP code or a recovery code and opens the session.
+	// Returns:
+	//   - the access and refresh tokens on success
+	//   - errors.ErrInvalidCredentials if the MFA token or the code is invalid
+	//   - errors.ErrBadRequest if the account must enrol a second factor first
+	//   - errors.ErrThroughputExceeded if the username or client IP is backing off or locked
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	CompleteSecondFactor(mfaToken, code, clientIP string) (*dto.AuthResponse, error)
+
+	// BeginTOTPEnrollmentForLogin starts the TOTP enrolment required by the role during a login.
+	// Returns:
+	//   - the seed, its otpauth URI and the recovery codes on success
+	//   - errors.ErrInvalidCredentials if the MFA token is invalid
+	//   - errors.ErrOperationNotAllowed if the account already has a second factor
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	BeginTOTPEnrollmentForLogin(mfaToken string) (*dto.TOTPEnrollmentResponse, error)
+
+	// BeginTOTPEnrollment starts a TOTP enrolment for a signed-in user.
+	// Returns:
+	//   - the seed, its otpauth URI and the recovery codes on success
+	//   - errors.ErrOperationNotAllowed if the user is not in the users store
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	BeginTOTPEnrollment(username string) (*dto.TOTPEnrollmentResponse, error)
+
+	// ConfirmTOTPEnrollment activates the pending enrolment of a signed-in user with a first code.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrBadRequest if no enrolment is pending
+	//   - errors.ErrInvalidCredentials if the code is wrong
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	ConfirmTOTPEnrollment(username, code string) error
+
+	// DisableTOTP removes the second factor of a signed-in user after checking a code.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrBadRequest if the user has no second factor
+	//   - errors.ErrOperationNotAllowed if the role requires a second factor
+	//   - errors.ErrInvalidCredentials if the code is wrong
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	DisableTOTP(username, code string) error
+
+	// ResetTOTP removes the second factor of a user on behalf of an admin.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrValidationFailed if the username is empty
+	//   - errors.ErrOperationNotAllowed if the user does not exist
+	//   - errors.ErrResourceNotFound if second factors are not configured
+	ResetTOTP(actor, username string) error
+
+	// OpenExternalSession opens a session for an identity verified by an external identity provider.
+	// Returns:
+	//   - the access and refresh tokens on success
+	//   - errors.ErrValidationFailed if the username or role is empty
+	//   - errors.ErrInternalServer if token generation or session creation fails
+	OpenExternalSession(username, role string) (*dto.AuthResponse, error)
+
+	// RefreshSession exchanges a refresh token for new tokens, rotating the refresh token.
+	// Reusing an exchanged refresh token revokes the whole session.
+	// Returns:
+	//   - the new access and refresh tokens on success
+	//   - errors.ErrInvalidCredentials if the token is unknown, expired, reused or revoked
+	//   - errors.ErrInternalServer if the session cannot be read or updated
+	RefreshSession(refreshToken string) (*dto.AuthResponse, error)
+
+	// Logout revokes the given session.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrBadRequest if no session ID is given
+	//   - errors.ErrInternalServer if the session cannot be revoked
+	Logout(sessionID string) error
+
+	// LogoutAll revokes every active session of the user.
+	// Returns:
+	//   - the number of sessions revoked on success
+	//   - errors.ErrInternalServer if the sessions cannot be read or revoked
+	LogoutAll(username string) (int, error)
+
+	// IsSessionRevoked reports whether access tokens of the session must be rejected.
+	// Returns:
+	//   - (true, nil) if the session is revoked or unknown
+	//   - (false, nil) if the session is active
+	//   - (false, errors.ErrInternalServer) if the session cannot be read
+	IsSessionRevoked(sessionID string) (bool, error)
+
+	// ChangePassword replaces the password of a signed-in user after checking the current one
+	// and revokes every session of the user.
+	// Returns:
+	//   - the number of sessions revoked on success
+	//   - errors.ErrValidationFailed if the new password does not meet the strength policy
+	//   - errors.ErrInvalidCredentials if the current password is wrong
+	//   - errors.ErrThroughputExceeded if the username or client IP is backing off or locked
+	//   - errors.ErrInternalServer if the password cannot be stored or the sessions revoked
+	ChangePassword(username, currentPassword, newPassword, clientIP string) (int, error)
+
+	// IssuePasswordReset creates a single-use, expiring password reset token for a user.
+	// Returns:
+	//   - the token on success
+	//   - errors.ErrValidationFailed if the username is empty
+	//   - errors.ErrResourceNotFound if the user does not exist
+	//   - errors.ErrOperationNotAllowed if the user is not active
+	IssuePasswordReset(actor, username string) (*dto.PasswordResetResponse, error)
+
+	// ResetPassword sets a new password with a reset token and revokes every session of the user.
+	// Returns:
+	//   - the number of sessions revoked on success
+	//   - errors.ErrValidationFailed if the new password does not meet the strength policy
+	//   - errors.ErrInvalidCredentials if the token is unknown, expired or already used
+	ResetPassword(req dto.ResetPasswordRequest) (int, error)
+
+	// UnlockAccount lifts the lockout of a username and forgets its failed logins.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrValidationFailed if the username is empty
+	//   - errors.ErrInternalServer if the attempts cannot be deleted
+	UnlockAccount(actor, username string) error
 
 	// GetAuthCredentials retrieves the stored admin credentials from the repository.
 	// Returns:
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"crypto/subtle"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	revokeReasonPasswordChanged = "password_changed"
+
+	// passwordResetTTLSeconds is how long a reset token issued by an admin can be used.
+	passwordResetTTLSeconds = 3600
+)
+
+// ChangePassword replaces the password of a signed-in user after checking the current one,
+// and revokes every session of the user, including the one making the request.
+// The legacy admin changes the password stored in the credentials secret.
+// Wrong current passwords count as failed logins of the username and the client IP.
+// Returns:
+//   - the number of sessions revoked on success
+//   - errors.ErrValidationFailed if the new password does not meet the strength policy or equals the current one
+//   - errors.ErrInvalidCredentials if the current password is wrong
+//   - errors.ErrThroughputExceeded if the username or client IP must wait before trying again
+//   - other repository errors if the password cannot be stored or the sessions revoked
+func (s *AuthService) ChangePassword(username, currentPassword, newPassword, clientIP string) (int, error) {
+	if err := dto.ValidatePasswordStrength(username, newPassword); err != nil {
+		return 0, err
+	}
+	if newPassword == currentPassword {
+		return 0, fmt.Errorf("new password must differ from the current one: %w", errors.ErrValidationFailed)
+	}
+
+	keys := s.throttleKeys(username, clientIP)
+	if err := s.checkLoginAllowed(keys); err != nil {
+		return 0, err
+	}
+
+	user, err := s.verifyStoredUser(username, currentPassword)
+	legacy := stdErrors.Is(err, errors.ErrResourceNotFound)
+	if legacy {
+		_, _, err = s.verifyLegacyAdmin(username, currentPassword)
+	}
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrInvalidCredentials) {
+			s.recordLoginFailure(username, clientIP, keys)
+		}
+		return 0, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, fmt.Errorf("hashing password: %w", errors.ErrInternalServer)
+	}
+
+	if legacy {
+		err = s.repo.UpdateAuthCredentials(repository.AuthCredentials{Username: username, Password: string(hash)})
+	} else {
+		now := s.timeProvider.Now()
+		err = s.users.UpdateUser(user.Username, map[string]interface{}{
+			"password_hash":             string(hash),
+			"password_changed_at":       now,
+			"password_reset_token_hash": nil,
+			"password_reset_expires_at": nil,
+			"updated_at":                now,
+		})
+		username = user.Username
+	}
+	if err != nil {
+		return 0, fmt.Errorf("storing new password of %s: %w", username, err)
+	}
+	s.resetLoginAttempts(username, keys[0].id)
+
+	logrus.WithFields(logrus.Fields{"event": "password_changed", "username": username}).Info("Password changed")
+	return s.revokeAllSessions(username, revokeReasonPasswordChanged)
+}
+
+// IssuePasswordReset creates a single-use token that lets an active user of the users store
+// set a new password with ResetPassword. A new token replaces the previous one.
+// The legacy admin changes its password with ChangePassword instead.
+// Returns:
+//   - the token, returned in clear exactly once, on success
+//   - errors.ErrValidationFailed if the username is empty
+//   - errors.ErrResourceNotFound if the user does not exist
+//   - errors.ErrOperationNotAllowed if the user is not active
+//   - other repository errors if the token cannot be stored
+func (s *AuthService) IssuePasswordReset(actor, username string) (*dto.PasswordResetResponse, error) {
+	username = dto.NormalizeUsername(username)
+	if username == "" {
+		return nil, fmt.Errorf("username is required: %w", errors.ErrValidationFailed)
+	}
+
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, fmt.Errorf("user %s is %s: %w", username, user.Status, errors.ErrOperationNotAllowed)
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generating reset token: %w", errors.ErrInternalServer)
+	}
+
+	expiresAt := s.timeProvider.NowUnix() + passwordResetTTLSeconds
+	updates := map[string]interface{}{
+		"password_reset_token_hash": utils.HashToken(token),
+		"password_reset_expires_at": expiresAt,
+		"updated_at":                s.timeProvider.Now(),
+	}
+	if err := s.users.UpdateUser(username, updates); err != nil {
+		return nil, fmt.Errorf("storing reset token of %s: %w", username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":    "password_reset_issued",
+		"actor":    actor,
+		"username": username,
+	}).Info("Password reset token issued")
+
+	return &dto.PasswordResetResponse{
+		Message:    "Password reset token issued successfully",
+		Username:   username,
+		ResetToken: token,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// ResetPassword sets a new password with a token issued by IssuePasswordReset. The token is
+// consumed, every session of the user is revoked and failed logins of the username are forgotten.
+// Returns:
+//   - the number of sessions revoked on success
+//   - errors.ErrValidationFailed if the new password does not meet the strength policy
+//   - errors.ErrInvalidCredentials if the token is unknown, expired or already used
+//   - other repository errors if the password cannot be stored or the sessions revoked
+func (s *AuthService) ResetPassword(req dto.ResetPasswordRequest) (int, error) {
+	username := dto.NormalizeUsername(req.Username)
+	if username == "" || utils.IsEmptyString(req.Token) {
+		return 0, errors.ErrValidationFailed
+	}
+	if err := dto.ValidatePasswordStrength(username, req.NewPassword); err != nil {
+		return 0, err
+	}
+
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return 0, errors.ErrInvalidCredentials
+		}
+		return 0, fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+
+	tokenHash := utils.HashToken(req.Token)
+	if user.Status != models.UserStatusActive || user.PasswordResetTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(tokenHash), []byte(user.PasswordResetTokenHash)) != 1 {
+		return 0, errors.ErrInvalidCredentials
+	}
+	if s.timeProvider.NowUnix() > user.PasswordResetExpiresAt {
+		return 0, fmt.Errorf("reset token of %s expired: %w", username, errors.ErrInvalidCredentials)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, fmt.Errorf("hashing password: %w", errors.ErrInternalServer)
+	}
+
+	if err := s.users.ResetPassword(username, tokenHash, string(hash), s.timeProvider.Now()); err != nil {
+		if stdErrors.Is(err, errors.ErrOperationNotAllowed) {
+			// Another request used the token first.
+			return 0, fmt.Errorf("reset token of %s already used: %w", username, errors.ErrInvalidCredentials)
+		}
+		return 0, fmt.Errorf("resetting password of %s: %w", username, err)
+	}
+	s.resetLoginAttempts(username, userThrottleKey(username))
+
+	logrus.WithFields(logrus.Fields{"event": "password_reset", "username": username}).Info("Password reset")
+	return s.revokeAllSessions(username, revokeReasonPasswordChanged)
+}
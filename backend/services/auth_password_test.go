@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordHashOf matches a bcrypt hash of password.
+func passwordHashOf(password string) interface{} {
+	return mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	const nowUnix int64 = 1000
+
+	tests := []struct {
+		name          string
+		username      string
+		current       string
+		newPassword   string
+		storedUser    *models.User
+		legacy        bool
+		expectStore   bool
+		expectFailure bool
+		expectError   error
+	}{
+		{name: "stored user", username: "maria", current: "secret", newPassword: StrongPassword, storedUser: &ActiveEditorUser, expectStore: true},
+		{name: "legacy admin", username: "admin", current: "secret", newPassword: StrongPassword, legacy: true, expectStore: true},
+		{name: "passphrase", username: "maria", current: "secret", newPassword: "correct horse battery staple", storedUser: &ActiveEditorUser, expectStore: true},
+		{name: "wrong current password", username: "maria", current: "wrong", newPassword: StrongPassword, storedUser: &ActiveEditorUser, expectFailure: true, expectError: errors.ErrInvalidCredentials},
+		{name: "too short", username: "maria", current: "secret", newPassword: "Ab1!", expectError: errors.ErrValidationFailed},
+		{name: "single character class", username: "maria", current: "secret", newPassword: "onlylowercase", expectError: errors.ErrValidationFailed},
+		{name: "contains username", username: "maria", current: "secret", newPassword: "Maria-2024!x", expectError: errors.ErrValidationFailed},
+		{name: "common password", username: "maria", current: "secret", newPassword: "Password123", expectError: errors.ErrValidationFailed},
+		{name: "too long for bcrypt", username: "maria", current: "secret", newPassword: string(make([]byte, 73)), expectError: errors.ErrValidationFailed},
+		{name: "same as current", username: "maria", current: StrongPassword, newPassword: StrongPassword, expectError: errors.ErrValidationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			allowLoginAttempts(m)
+			m.clock.On("NowUnix").Return(nowUnix).Maybe()
+
+			if tt.storedUser != nil {
+				m.userRepo.On("GetUserByUsername", tt.username).Return(tt.storedUser, nil)
+			}
+			if tt.legacy {
+				m.userRepo.On("GetUserByUsername", tt.username).Return(nil, errors.ErrResourceNotFound)
+				m.authRepo.On("GetAuthCredentials").Return(&ValidStoredCredentials, nil)
+			}
+			if tt.expectStore {
+				if tt.legacy {
+					m.authRepo.On("UpdateAuthCredentials", mock.MatchedBy(func(c repository.AuthCredentials) bool {
+						return c.Username == "admin" && bcrypt.CompareHashAndPassword([]byte(c.Password), []byte(tt.newPassword)) == nil
+					})).Return(nil)
+				} else {
+					m.userRepo.On("UpdateUser", tt.username, mock.MatchedBy(func(u map[string]interface{}) bool {
+						hash, _ := u["password_hash"].(string)
+						_, clearsReset := u["password_reset_token_hash"]
+						return bcrypt.CompareHashAndPassword([]byte(hash), []byte(tt.newPassword)) == nil &&
+							u["password_changed_at"] == "now" && clearsReset
+					})).Return(nil)
+				}
+				m.sessionRepo.On("GetSessionsByUsername", tt.username).Return([]models.Session{
+					{ID: "s1", Status: models.SessionStatusActive},
+					{ID: "s2", Status: models.SessionStatusRevoked},
+					{ID: "s3", Status: models.SessionStatusMFAPending},
+				}, nil)
+				m.sessionRepo.On("RevokeSession", "s1", "password_changed", "now").Return(nil)
+				m.sessionRepo.On("RevokeSession", "s3", "password_changed", "now").Return(nil)
+			}
+
+			revoked, err := service.ChangePassword(tt.username, tt.current, tt.newPassword, testClientIP)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				m.userRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+				m.authRepo.AssertNotCalled(t, "UpdateAuthCredentials", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, revoked)
+				m.attemptRepo.AssertCalled(t, "DeleteLoginAttempt", "user:"+tt.username)
+			}
+			if tt.expectFailure {
+				m.attemptRepo.AssertCalled(t, "RecordLoginFailure", "user:"+tt.username, nowUnix, mock.Anything)
+			}
+			m.userRepo.AssertExpectations(t)
+			m.authRepo.AssertExpectations(t)
+			m.sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuePasswordReset(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		user        *models.User
+		userErr     error
+		expectError error
+	}{
+		{name: "active user", username: " Maria ", user: &ActiveEditorUser},
+		{name: "disabled user", username: "maria", user: &DisabledEditorUser, expectError: errors.ErrOperationNotAllowed},
+		{name: "unknown user", username: "maria", userErr: errors.ErrResourceNotFound, expectError: errors.ErrResourceNotFound},
+		{name: "empty username", username: " ", expectError: errors.ErrValidationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			m.clock.On("NowUnix").Return(int64(1000)).Maybe()
+			if tt.user != nil || tt.userErr != nil {
+				m.userRepo.On("GetUserByUsername", "maria").Return(tt.user, tt.userErr)
+			}
+
+			var stored map[string]interface{}
+			if tt.expectError == nil {
+				m.userRepo.On("UpdateUser", "maria", mock.Anything).Run(func(args mock.Arguments) {
+					stored = args.Get(1).(map[string]interface{})
+				}).Return(nil)
+			}
+
+			resp, err := service.IssuePasswordReset("admin", tt.username)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "maria", resp.Username)
+				assert.NotEmpty(t, resp.ResetToken)
+				assert.Equal(t, int64(1000+3600), resp.ExpiresAt)
+				assert.Equal(t, utils.HashToken(resp.ResetToken), stored["password_reset_token_hash"])
+				assert.Equal(t, resp.ExpiresAt, stored["password_reset_expires_at"])
+			}
+			m.userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	withToken := ActiveEditorUser
+	withToken.PasswordResetTokenHash = utils.HashToken(ResetToken)
+	withToken.PasswordResetExpiresAt = 2000
+
+	expired := withToken
+	expired.PasswordResetExpiresAt = 999
+
+	disabled := withToken
+	disabled.Status = models.UserStatusDisabled
+
+	valid := dto.ResetPasswordRequest{Username: "Maria", Token: ResetToken, NewPassword: StrongPassword}
+
+	tests := []struct {
+		name        string
+		req         dto.ResetPasswordRequest
+		user        *models.User
+		userErr     error
+		resetErr    error
+		expectReset bool
+		expectError error
+	}{
+		{name: "valid token", req: valid, user: &withToken, expectReset: true},
+		{name: "wrong token", req: dto.ResetPasswordRequest{Username: "maria", Token: "other", NewPassword: StrongPassword}, user: &withToken, expectError: errors.ErrInvalidCredentials},
+		{name: "no token issued", req: valid, user: &ActiveEditorUser, expectError: errors.ErrInvalidCredentials},
+		{name: "expired token", req: valid, user: &expired, expectError: errors.ErrInvalidCredentials},
+		{name: "disabled user", req: valid, user: &disabled, expectError: errors.ErrInvalidCredentials},
+		{name: "unknown user", req: valid, userErr: errors.ErrResourceNotFound, expectError: errors.ErrInvalidCredentials},
+		{name: "token used concurrently", req: valid, user: &withToken, resetErr: errors.ErrOperationNotAllowed, expectReset: true, expectError: errors.ErrInvalidCredentials},
+		{name: "weak password", req: dto.ResetPasswordRequest{Username: "maria", Token: ResetToken, NewPassword: "weak"}, expectError: errors.ErrValidationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			allowLoginAttempts(m)
+			m.clock.On("NowUnix").Return(int64(1000)).Maybe()
+			if tt.user != nil || tt.userErr != nil {
+				m.userRepo.On("GetUserByUsername", "maria").Return(tt.user, tt.userErr)
+			}
+			if tt.expectReset {
+				m.userRepo.On("ResetPassword", "maria", utils.HashToken(ResetToken), passwordHashOf(StrongPassword), "now").Return(tt.resetErr)
+			}
+			if tt.expectError == nil {
+				m.sessionRepo.On("GetSessionsByUsername", "maria").Return([]models.Session{{ID: "s1", Status: models.SessionStatusActive}}, nil)
+				m.sessionRepo.On("RevokeSession", "s1", "password_changed", "now").Return(nil)
+			}
+
+			revoked, err := service.ResetPassword(tt.req)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				m.sessionRepo.AssertNotCalled(t, "GetSessionsByUsername", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, revoked)
+				m.attemptRepo.AssertCalled(t, "DeleteLoginAttempt", "user:maria")
+			}
+			m.userRepo.AssertExpectations(t)
+			m.sessionRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package services
 
 import (
+	stdErrors "errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/golang-jwt/jwt/v5"
@@ -11,12 +17,42 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// AuthService provides authentication logic for the admin user.
-// It verifies credentials and generates JWT tokens for access.
+// Reasons recorded when a session is revoked.
+const (
+	revokeReasonLogout        = "logout"
+	revokeReasonLogoutAll     = "logout_all"
+	revokeReasonReuseDetected = "reuse_detected"
+	revokeReasonUserInactive  = "user_inactive"
+	revokeReasonUserNotFound  = "user_not_found"
+	revokeReasonUserDisabled  = "user_disabled"
+	revokeReasonUserDeleted   = "user_deleted"
+)
+
+// AuthService provides authentication logic for back-office users.
+// Accounts in the users store are checked first; the single admin stored as a secret
+// is still accepted so that deployments without a users table keep working.
+//
+// A login opens a session that issues short-lived access tokens carrying the user's role
+// and a refresh token that is rotated on every use. Only the hash of the current refresh
+// token is stored; presenting an already rotated token revokes the whole session.
+//
+// Failed logins are counted per username and per client IP; repeated failures impose an
+// exponential backoff and then a temporary lockout (see LoginThrottlePolicy).
+//
+// Accounts with a TOTP second factor, and accounts whose role requires one, complete the
+// login in a second step (see TwoFactorConfig).
 type AuthService struct {
 	repo           repository.AuthRepository
+	users          repository.UserRepository
+	sessions       repository.SessionRepository
+	attempts       repository.LoginAttemptRepository
+	idGen          utils.IDGenerator
 	timeProvider   utils.TimeProvider
 	tokenGenerator utils.TokenGenerator
+	accessTTL      time.Duration
+	refreshTTL     time.Duration
+	throttle       LoginThrottlePolicy
+	twoFactor      *TwoFactorConfig
 }
 
 // Ensure AuthService implements AuthServiceInterface.
@@ -25,50 +61,397 @@ var _ AuthServiceInterface = (*AuthService)(nil)
 // NewAuthService returns a new instance of AuthService with its required dependencies.
 func NewAuthService(
 	repo repository.AuthRepository,
+	users repository.UserRepository,
+	sessions repository.SessionRepository,
+	attempts repository.LoginAttemptRepository,
+	idGen utils.IDGenerator,
 	timeProvider utils.TimeProvider,
 	tokenGenerator utils.TokenGenerator,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+	throttle LoginThrottlePolicy,
+	twoFactor *TwoFactorConfig,
 ) *AuthService {
 	return &AuthService{
 		repo:           repo,
+		users:          users,
+		sessions:       sessions,
+		attempts:       attempts,
+		idGen:          idGen,
 		timeProvider:   timeProvider,
 		tokenGenerator: tokenGenerator,
+		accessTTL:      accessTTL,
+		refreshTTL:     refreshTTL,
+		throttle:       throttle,
+		twoFactor:      twoFactor,
 	}
 }
 
-// AuthenticateUser verifies the given username and password against stored credentials.
-// If valid, it generates a signed JWT token valid for 72 hours.
+// AuthenticateUser verifies the given username and password and opens a new session.
+// Active accounts of the users store are checked first; if the username is unknown there,
+// the legacy admin secret is used and the session is opened with the admin role.
+// Attempts are refused without checking the password while the username or the client IP
+// is backing off or locked after previous failures.
+// If a second factor is needed, no session is opened yet: the response carries an MFA token
+// for CompleteSecondFactor instead of the tokens. The legacy admin cannot enrol a factor, so
+// it is refused when its role requires one.
 // Returns:
-//   - the signed JWT token string on success
-//   - errors.ErrInvalidCredentials if the credentials are incorrect
+//   - the access and refresh tokens, or an MFA challenge, on success
+//   - errors.ErrInvalidCredentials if the credentials are incorrect or the account is not active
+//   - errors.ErrOperationNotAllowed if the legacy admin signs in while its role requires a second factor
+//   - errors.ErrThroughputExceeded if the username or client IP must wait before trying again
 //   - errors.ErrTokenGenerationFailed if token signing fails
-//   - other repository errors if credential retrieval fails
-func (s *AuthService) AuthenticateUser(username, password string) (string, error) {
-	creds, err := s.repo.GetAuthCredentials()
+//   - other repository errors if credential retrieval or session creation fails
+func (s *AuthService) AuthenticateUser(username, password, clientIP string) (*dto.AuthResponse, error) {
+	keys := s.throttleKeys(username, clientIP)
+	if err := s.checkLoginAllowed(keys); err != nil {
+		return nil, err
+	}
+
+	var subject, role string
+	user, err := s.verifyStoredUser(username, password)
+	if err == nil {
+		subject, role = user.Username, user.Role
+	} else if stdErrors.Is(err, errors.ErrResourceNotFound) {
+		subject, role, err = s.verifyLegacyAdmin(username, password)
+		if err == nil && s.roleRequiresSecondFactor(role) {
+			logrus.WithField("username", subject).Warn("Legacy admin login refused: its role requires a second factor")
+			return nil, fmt.Errorf("legacy admin cannot satisfy the second factor required for role %s: %w", role, errors.ErrOperationNotAllowed)
+		}
+	}
 	if err != nil {
-		return "", fmt.Errorf("retrieving auth credentials: %w", err)
+		if stdErrors.Is(err, errors.ErrInvalidCredentials) {
+			s.recordLoginFailure(username, clientIP, keys)
+		}
+		return nil, err
 	}
 
-	if username != creds.Username {
-		return "", errors.ErrInvalidCredentials
+	// Failed attempts are only forgotten once the second factor is verified as well,
+	// so a known password does not reset the throttling of code guesses.
+	if user != nil {
+		if required, enroll := s.secondFactorRequired(user); required {
+			return s.openMFAChallenge(user, enroll)
+		}
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(creds.Password), []byte(password)); err != nil {
-		return "", errors.ErrInvalidCredentials
+	source := models.SessionSourceLegacyAdmin
+	if user != nil {
+		source = models.SessionSourceUsers
+	}
+	s.resetLoginAttempts(subject, keys[0].id)
+	return s.openSession(subject, role, source)
+}
+
+// OpenExternalSession opens a session for an identity already verified by an external
+// identity provider, such as an OIDC login. The username is expected to be namespaced
+// so it cannot collide with accounts of the users store.
+// Returns:
+//   - the access and refresh tokens on success
+//   - errors.ErrValidationFailed if the username or role is empty
+//   - errors.ErrTokenGenerationFailed if token signing fails
+//   - other repository errors if session creation fails
+func (s *AuthService) OpenExternalSession(username, role string) (*dto.AuthResponse, error) {
+	if utils.IsEmptyString(username) || utils.IsEmptyString(role) {
+		return nil, fmt.Errorf("external session needs a username and a role: %w", errors.ErrValidationFailed)
+	}
+	return s.openSession(username, role, models.SessionSourceExternal)
+}
+
+// resetLoginAttempts forgets the failed logins of a username after a successful login.
+func (s *AuthService) resetLoginAttempts(username, key string) {
+	if err := s.attempts.DeleteLoginAttempt(key); err != nil {
+		logrus.WithError(err).WithField("username", username).Error("Failed to reset login attempts")
+	}
+}
+
+// openSession stores a new session for the subject, signed in through source, and issues its first tokens.
+func (s *AuthService) openSession(subject, role, source string) (*dto.AuthResponse, error) {
+	sessionID := s.idGen.NewID()
+	refreshToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.timeProvider.Now()
+	session := models.Session{
+		ID:        sessionID,
+		Username:  subject,
+		Role:      role,
+		Source:    source,
+		TokenHash: utils.HashToken(refreshToken),
+		Status:    models.SessionStatusActive,
+		ExpiresAt: s.timeProvider.NowUnix() + int64(s.refreshTTL.Seconds()),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("opening session for %s: %w", subject, err)
+	}
+
+	return s.issueTokens(session.ID, subject, role, refreshToken)
+}
+
+// RefreshSession exchanges a refresh token for a new access token and a new refresh token.
+// The presented token is invalidated. Presenting a token that was already exchanged means it
+// leaked or was replayed, so the whole session is revoked.
+// The role is read again from the users store so that role changes and disabled accounts apply
+// from the next refresh.
+// Returns:
+//   - the new access and refresh tokens on success
+//   - errors.ErrInvalidCredentials if the token is unknown, expired, reused or the session is revoked
+//   - errors.ErrTokenGenerationFailed if token signing fails
+//   - other repository errors if the session cannot be read or updated
+func (s *AuthService) RefreshSession(refreshToken string) (*dto.AuthResponse, error) {
+	sessionID, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	session, err := s.sessions.GetSessionByID(sessionID)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("retrieving session %s: %w", sessionID, err)
+	}
+
+	if session.Status != models.SessionStatusActive {
+		return nil, fmt.Errorf("session %s is revoked: %w", sessionID, errors.ErrInvalidCredentials)
+	}
+
+	tokenHash := utils.HashToken(refreshToken)
+	if tokenHash != session.TokenHash {
+		if slices.Contains(session.RotatedTokenHashes, tokenHash) {
+			return nil, s.revokeOnReuse(session)
+		}
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if s.timeProvider.NowUnix() > session.ExpiresAt {
+		return nil, fmt.Errorf("session %s expired: %w", sessionID, errors.ErrInvalidCredentials)
+	}
+
+	role, err := s.currentRole(session)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.timeProvider.NowUnix() + int64(s.refreshTTL.Seconds())
+	if err := s.sessions.RotateSessionToken(sessionID, tokenHash, utils.HashToken(newToken), expiresAt, s.timeProvider.Now()); err != nil {
+		if stdErrors.Is(err, errors.ErrOperationNotAllowed) {
+			// Another request exchanged the same token first.
+			return nil, s.revokeOnReuse(session)
+		}
+		return nil, fmt.Errorf("rotating refresh token: %w", err)
+	}
+
+	return s.issueTokens(sessionID, session.Username, role, newToken)
+}
+
+// Logout revokes a single session.
+// Returns:
+//   - nil on success
+//   - errors.ErrBadRequest if the access token does not belong to a session
+//   - error if the session cannot be revoked
+func (s *AuthService) Logout(sessionID string) error {
+	if utils.IsEmptyString(sessionID) {
+		return fmt.Errorf("token has no session: %w", errors.ErrBadRequest)
+	}
+	if err := s.sessions.RevokeSession(sessionID, revokeReasonLogout, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("logging out session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// LogoutAll revokes every active session of the user.
+// Returns:
+//   - the number of sessions revoked on success
+//   - error if the sessions cannot be read or revoked
+func (s *AuthService) LogoutAll(username string) (int, error) {
+	return s.revokeAllSessions(username, revokeReasonLogoutAll)
+}
+
+// revokeAllSessions revokes every session of the user that is not revoked yet, including
+// pending second factor challenges, and returns how many were revoked.
+func (s *AuthService) revokeAllSessions(username, reason string) (int, error) {
+	return revokeUserSessions(s.sessions, username, reason, s.timeProvider.Now())
+}
+
+// revokeUserSessions revokes, at now, every session of the user that is not revoked yet and
+// returns how many were revoked.
+func revokeUserSessions(repo repository.SessionRepository, username, reason, now string) (int, error) {
+	sessions, err := repo.GetSessionsByUsername(username)
+	if err != nil {
+		return 0, fmt.Errorf("retrieving sessions of %s: %w", username, err)
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.Status == models.SessionStatusRevoked {
+			continue
+		}
+		if err := repo.RevokeSession(session.ID, reason, now); err != nil {
+			return revoked, fmt.Errorf("revoking session %s: %w", session.ID, err)
+		}
+		revoked++
 	}
 
-	exp := s.timeProvider.NowUnix() + 72*3600
+	logrus.WithFields(logrus.Fields{"username": username, "sessions": revoked, "reason": reason}).Info("All sessions revoked")
+	return revoked, nil
+}
+
+// IsSessionRevoked reports whether access tokens of the session must be rejected.
+// Unknown sessions are reported as revoked.
+func (s *AuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	session, err := s.sessions.GetSessionByID(sessionID)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return true, nil
+		}
+		return false, fmt.Errorf("retrieving session %s: %w", sessionID, err)
+	}
+	return session.Status != models.SessionStatusActive, nil
+}
+
+// issueTokens signs an access token for the session and bundles it with the refresh token.
+func (s *AuthService) issueTokens(sessionID, username, role, refreshToken string) (*dto.AuthResponse, error) {
 	claims := jwt.MapClaims{
 		"username": username,
-		"exp":      exp,
+		"role":     role,
+		"sid":      sessionID,
+		"exp":      s.timeProvider.NowUnix() + int64(s.accessTTL.Seconds()),
 	}
 
 	token, err := s.tokenGenerator.GenerateToken(claims)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to generate JWT token")
-		return "", fmt.Errorf("generating JWT token: %w", errors.ErrTokenGenerationFailed)
+		return nil, fmt.Errorf("generating JWT token: %w", errors.ErrTokenGenerationFailed)
 	}
 
-	return token, nil
+	return &dto.AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// currentRole returns the role to put in a refreshed access token.
+// Stored users must still exist and be active; otherwise the session is revoked. Sessions of the
+// legacy admin and of external identities, which have no stored account, keep their role.
+func (s *AuthService) currentRole(session *models.Session) (string, error) {
+	user, err := s.users.GetUserByUsername(session.Username)
+	if stdErrors.Is(err, errors.ErrResourceNotFound) {
+		keep, err := s.keepsSessionRole(session)
+		if err != nil {
+			return "", err
+		}
+		if keep {
+			return session.Role, nil
+		}
+		if err := s.sessions.RevokeSession(session.ID, revokeReasonUserNotFound, s.timeProvider.Now()); err != nil {
+			return "", fmt.Errorf("revoking session %s: %w", session.ID, err)
+		}
+		return "", fmt.Errorf("user %s no longer exists: %w", session.Username, errors.ErrInvalidCredentials)
+	}
+	if err != nil {
+		return "", fmt.Errorf("retrieving user %s: %w", session.Username, err)
+	}
+
+	if user.Status != models.UserStatusActive {
+		if err := s.sessions.RevokeSession(session.ID, revokeReasonUserInactive, s.timeProvider.Now()); err != nil {
+			return "", fmt.Errorf("revoking session %s: %w", session.ID, err)
+		}
+		return "", fmt.Errorf("user %s is %s: %w", user.Username, user.Status, errors.ErrInvalidCredentials)
+	}
+	return user.Role, nil
+}
+
+// keepsSessionRole reports whether a session whose owner is not in the users store may keep its role:
+// sessions of external identities, and sessions of the legacy admin while it keeps its username.
+func (s *AuthService) keepsSessionRole(session *models.Session) (bool, error) {
+	switch session.Source {
+	case models.SessionSourceExternal:
+		return true, nil
+	case models.SessionSourceUsers:
+		return false, nil
+	}
+
+	creds, err := s.repo.GetAuthCredentials()
+	if err != nil {
+		return false, fmt.Errorf("retrieving auth credentials: %w", err)
+	}
+	return session.Username == creds.Username, nil
+}
+
+// revokeOnReuse revokes the session after a rotated refresh token was presented again.
+// Returns errors.ErrInvalidCredentials, or the revocation error if it fails.
+func (s *AuthService) revokeOnReuse(session *models.Session) error {
+	logrus.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"username":   session.Username,
+	}).Warn("Refresh token reuse detected, revoking session")
+
+	if err := s.sessions.RevokeSession(session.ID, revokeReasonReuseDetected, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("revoking session %s: %w", session.ID, err)
+	}
+	return fmt.Errorf("refresh token reused for session %s: %w", session.ID, errors.ErrInvalidCredentials)
+}
+
+// newRefreshToken returns a random refresh token prefixed with its session ID.
+func newRefreshToken(sessionID string) (string, error) {
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", fmt.Errorf("generating refresh token: %w", errors.ErrTokenGenerationFailed)
+	}
+	return sessionID + "." + secret, nil
+}
+
+// verifyStoredUser checks the credentials against the users store and returns the stored user.
+// Returns errors.ErrResourceNotFound when the username is not in the store.
+func (s *AuthService) verifyStoredUser(username, password string) (*models.User, error) {
+	user, err := s.users.GetUserByUsername(dto.NormalizeUsername(username))
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, errors.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+
+	if user.Status != models.UserStatusActive {
+		logrus.WithFields(logrus.Fields{
+			"username": user.Username,
+			"status":   user.Status,
+		}).Warn("Login attempt for inactive user")
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, errors.ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// verifyLegacyAdmin checks the credentials against the single admin secret.
+func (s *AuthService) verifyLegacyAdmin(username, password string) (string, string, error) {
+	creds, err := s.repo.GetAuthCredentials()
+	if err != nil {
+		return "", "", fmt.Errorf("retrieving auth credentials: %w", err)
+	}
+
+	if username != creds.Username {
+		return "", "", errors.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(creds.Password), []byte(password)); err != nil {
+		return "", "", errors.ErrInvalidCredentials
+	}
+	return creds.Username, models.RoleAdmin, nil
 }
 
 // GetAuthCredentials retrieves the current admin credentials from the repository.
@@ -1,24 +1,89 @@
 package services_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func setupAuthServiceTest() (*services.AuthService, *mocks.MockAuthRepository, *mocks.MockTimeProvider, *mocks.MockTokenGenerator) {
-	authRepo := new(mocks.MockAuthRepository)
-	timeProvider := new(mocks.MockTimeProvider)
-	tokenGen := new(mocks.MockTokenGenerator)
-	service := services.NewAuthService(authRepo, timeProvider, tokenGen)
-	return service, authRepo, timeProvider, tokenGen
+const (
+	testAccessTTL  = 15 * time.Minute
+	testRefreshTTL = 24 * time.Hour
+	testClientIP   = "203.0.113.7"
+)
+
+var testThrottle = services.LoginThrottlePolicy{
+	MaxUserFailures: 5,
+	MaxIPFailures:   20,
+	FreeFailures:    2,
+	BaseBackoff:     time.Second,
+	Lockout:         15 * time.Minute,
+}
+
+type authServiceMocks struct {
+	authRepo    *mocks.MockAuthRepository
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	attemptRepo *mocks.MockLoginAttemptRepository
+	clock       *mocks.MockTimeProvider
+	tokenGen    *mocks.MockTokenGenerator
+}
+
+func (m authServiceMocks) assertExpectations(t *testing.T) {
+	m.authRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.sessionRepo.AssertExpectations(t)
+	m.attemptRepo.AssertExpectations(t)
+	m.clock.AssertExpectations(t)
+	m.tokenGen.AssertExpectations(t)
+}
+
+func setupAuthServiceTest() (*services.AuthService, authServiceMocks) {
+	return setupAuthServiceTestWithTwoFactor(nil)
+}
+
+func setupAuthServiceTestWithTwoFactor(twoFactor *services.TwoFactorConfig) (*services.AuthService, authServiceMocks) {
+	m := authServiceMocks{
+		authRepo:    new(mocks.MockAuthRepository),
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		attemptRepo: new(mocks.MockLoginAttemptRepository),
+		clock:       new(mocks.MockTimeProvider),
+		tokenGen:    new(mocks.MockTokenGenerator),
+	}
+	idGen := new(mocks.MockIDGenerator)
+	idGen.On("NewID").Return(SessionID).Maybe()
+	m.clock.On("Now").Return("now").Maybe()
+	service := services.NewAuthService(m.authRepo, m.userRepo, m.sessionRepo, m.attemptRepo, idGen, m.clock, m.tokenGen, testAccessTTL, testRefreshTTL, testThrottle, twoFactor)
+	return service, m
+}
+
+// allowLoginAttempts lets the login throttle run without recorded failures.
+func allowLoginAttempts(m authServiceMocks) {
+	m.attemptRepo.On("GetLoginAttempt", mock.Anything).Return(nil, errors.ErrResourceNotFound).Maybe()
+	m.attemptRepo.On("RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil).Maybe()
+	m.attemptRepo.On("DeleteLoginAttempt", mock.Anything).Return(nil).Maybe()
+}
+
+func accessClaims(username, role string, now int64) jwt.MapClaims {
+	return jwt.MapClaims{
+		"username": username,
+		"role":     role,
+		"sid":      SessionID,
+		"exp":      now + int64(testAccessTTL.Seconds()),
+	}
 }
 
 func TestAuthenticateUser(t *testing.T) {
@@ -38,16 +103,56 @@ func TestAuthenticateUser(t *testing.T) {
 		mockToken      string
 		mockTokenError error
 		mockCredsError error
+		storedUser     *models.User
+		userError      error
+		expectUsername string
+		expectRole     string
 		expectToken    string
 		expectError    error
 	}{
 		{
-			name:        "valid credentials",
-			input:       ValidLoginInput,
-			creds:       &ValidStoredCredentials,
-			mockNow:     nowUnix,
-			mockToken:   GeneratedToken,
-			expectToken: GeneratedToken,
+			name:           "valid credentials",
+			input:          ValidLoginInput,
+			creds:          &ValidStoredCredentials,
+			mockNow:        nowUnix,
+			mockToken:      GeneratedToken,
+			expectUsername: "admin",
+			expectRole:     models.RoleAdmin,
+			expectToken:    GeneratedToken,
+		},
+		{
+			name:           "stored editor",
+			input:          EditorLoginInput,
+			storedUser:     &ActiveEditorUser,
+			mockNow:        nowUnix,
+			mockToken:      GeneratedToken,
+			expectUsername: "maria",
+			expectRole:     models.RoleEditor,
+			expectToken:    GeneratedToken,
+		},
+		{
+			name:        "stored user with wrong password",
+			input:       dto.LoginRequest{Username: "maria", Password: "wrongpass"},
+			storedUser:  &ActiveEditorUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "disabled user",
+			input:       EditorLoginInput,
+			storedUser:  &DisabledEditorUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "invited user",
+			input:       EditorLoginInput,
+			storedUser:  &InvitedViewerUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "users store error",
+			input:       EditorLoginInput,
+			userError:   errors.ErrInternalServer,
+			expectError: errors.ErrInternalServer,
 		},
 		{
 			name:        "invalid username",
@@ -73,45 +178,234 @@ func TestAuthenticateUser(t *testing.T) {
 			creds:          &ValidStoredCredentials,
 			mockNow:        nowUnix,
 			mockTokenError: errors.ErrTokenGenerationFailed,
+			expectUsername: "admin",
+			expectRole:     models.RoleAdmin,
 			expectError:    errors.ErrTokenGenerationFailed,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, authRepo, clock, tokenGen := setupAuthServiceTest()
+			service, m := setupAuthServiceTest()
+			authRepo, userRepo, clock, tokenGen := m.authRepo, m.userRepo, m.clock, m.tokenGen
+			allowLoginAttempts(m)
+			clock.On("NowUnix").Return(nowUnix)
 
-			if tt.mockCredsError != nil {
-				authRepo.On("GetAuthCredentials").Return(nil, tt.mockCredsError)
-			} else {
-				authRepo.On("GetAuthCredentials").Return(tt.creds, nil)
+			switch {
+			case tt.storedUser != nil:
+				userRepo.On("GetUserByUsername", tt.storedUser.Username).Return(tt.storedUser, nil)
+			case tt.userError != nil:
+				userRepo.On("GetUserByUsername", dto.NormalizeUsername(tt.input.Username)).Return(nil, tt.userError)
+			default:
+				userRepo.On("GetUserByUsername", dto.NormalizeUsername(tt.input.Username)).Return(nil, errors.ErrResourceNotFound)
+				if tt.mockCredsError != nil {
+					authRepo.On("GetAuthCredentials").Return(nil, tt.mockCredsError)
+				} else {
+					authRepo.On("GetAuthCredentials").Return(tt.creds, nil)
+				}
 			}
 
 			if tt.mockNow != 0 {
-				clock.On("NowUnix").Return(tt.mockNow)
+				m.sessionRepo.On("CreateSession", mock.MatchedBy(func(s models.Session) bool {
+					return s.ID == SessionID && s.Username == tt.expectUsername && s.Role == tt.expectRole &&
+						s.Status == models.SessionStatusActive && s.TokenHash != "" &&
+						s.ExpiresAt == tt.mockNow+int64(testRefreshTTL.Seconds())
+				})).Return(nil)
 			}
 
 			if tt.mockToken != "" || tt.mockTokenError != nil {
-				claims := jwt.MapClaims{
-					"username": tt.input.Username,
-					"exp":      tt.mockNow + 72*3600,
+				tokenGen.On("GenerateToken", accessClaims(tt.expectUsername, tt.expectRole, tt.mockNow)).Return(tt.mockToken, tt.mockTokenError)
+			}
+
+			resp, err := service.AuthenticateUser(tt.input.Username, tt.input.Password, testClientIP)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectToken, resp.Token)
+				assert.True(t, strings.HasPrefix(resp.RefreshToken, SessionID+"."))
+				assert.Equal(t, int64(testAccessTTL.Seconds()), resp.ExpiresIn)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestAuthenticateUser_Throttling(t *testing.T) {
+	const nowUnix int64 = 10000
+	const lockout = int64(15 * 60)
+	userKey, ipKey := "user:maria", "ip:"+testClientIP
+
+	tests := []struct {
+		name          string
+		input         dto.LoginRequest
+		userAttempt   *models.LoginAttempt
+		ipAttempt     *models.LoginAttempt
+		attemptErr    error
+		expectVerify  bool
+		recorded      *models.LoginAttempt
+		expectLock    bool
+		expectReset   bool
+		expectSuccess bool
+		expectError   error
+	}{
+		{
+			name:        "locked username is refused without checking the password",
+			input:       EditorLoginInput,
+			userAttempt: &models.LoginAttempt{ID: userKey, Failures: 5, LastFailureAt: nowUnix - 60, LockedUntil: nowUnix + 60},
+			expectError: errors.ErrThroughputExceeded,
+		},
+		{
+			name:        "username still backing off",
+			input:       EditorLoginInput,
+			userAttempt: &models.LoginAttempt{ID: userKey, Failures: 4, LastFailureAt: nowUnix - 1},
+			expectError: errors.ErrThroughputExceeded,
+		},
+		{
+			name:        "locked client IP",
+			input:       EditorLoginInput,
+			ipAttempt:   &models.LoginAttempt{ID: ipKey, Failures: 20, LastFailureAt: nowUnix - 60, LockedUntil: nowUnix + 60},
+			expectError: errors.ErrThroughputExceeded,
+		},
+		{
+			name:          "failures from the client IP do not slow it down",
+			input:         EditorLoginInput,
+			ipAttempt:     &models.LoginAttempt{ID: ipKey, Failures: 10, LastFailureAt: nowUnix},
+			expectVerify:  true,
+			expectSuccess: true,
+		},
+		{
+			name:          "free failures do not slow the username down",
+			input:         EditorLoginInput,
+			userAttempt:   &models.LoginAttempt{ID: userKey, Failures: 2, LastFailureAt: nowUnix},
+			expectVerify:  true,
+			expectSuccess: true,
+		},
+		{
+			name:         "backoff elapsed and wrong password counts a failure",
+			input:        dto.LoginRequest{Username: "maria", Password: "wrongpass"},
+			userAttempt:  &models.LoginAttempt{ID: userKey, Failures: 3, LastFailureAt: nowUnix - 5},
+			expectVerify: true,
+			recorded:     &models.LoginAttempt{Failures: 4},
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "reaching the limit locks the username",
+			input:        dto.LoginRequest{Username: "maria", Password: "wrongpass"},
+			userAttempt:  &models.LoginAttempt{ID: userKey, Failures: 4, LastFailureAt: nowUnix - 60},
+			expectVerify: true,
+			recorded:     &models.LoginAttempt{Failures: 5},
+			expectLock:   true,
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "old failures are forgotten",
+			input:        dto.LoginRequest{Username: "maria", Password: "wrongpass"},
+			userAttempt:  &models.LoginAttempt{ID: userKey, Failures: 4, LastFailureAt: nowUnix - lockout - 1},
+			expectVerify: true,
+			recorded:     &models.LoginAttempt{Failures: 1},
+			expectReset:  true,
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:          "success clears the failures of the username",
+			input:         EditorLoginInput,
+			userAttempt:   &models.LoginAttempt{ID: userKey, Failures: 2, LastFailureAt: nowUnix - 60},
+			expectVerify:  true,
+			expectSuccess: true,
+		},
+		{
+			name:        "attempts store error",
+			input:       EditorLoginInput,
+			attemptErr:  errors.ErrInternalServer,
+			expectError: errors.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			m.clock.On("NowUnix").Return(nowUnix)
+
+			for key, attempt := range map[string]*models.LoginAttempt{userKey: tt.userAttempt, ipKey: tt.ipAttempt} {
+				switch {
+				case tt.attemptErr != nil:
+					m.attemptRepo.On("GetLoginAttempt", key).Return(nil, tt.attemptErr).Maybe()
+				case attempt != nil:
+					m.attemptRepo.On("GetLoginAttempt", key).Return(attempt, nil)
+				default:
+					m.attemptRepo.On("GetLoginAttempt", key).Return(nil, errors.ErrResourceNotFound).Maybe()
 				}
-				tokenGen.On("GenerateToken", claims).Return(tt.mockToken, tt.mockTokenError)
 			}
 
-			token, err := service.AuthenticateUser(tt.input.Username, tt.input.Password)
+			if tt.expectVerify {
+				m.userRepo.On("GetUserByUsername", "maria").Return(&ActiveEditorUser, nil)
+			}
+			if tt.recorded != nil {
+				m.attemptRepo.On("RecordLoginFailure", userKey, nowUnix, nowUnix+lockout).Return(tt.recorded, nil)
+				m.attemptRepo.On("RecordLoginFailure", ipKey, nowUnix, nowUnix+lockout).Return(&models.LoginAttempt{Failures: 1}, nil)
+			}
+			if tt.expectLock {
+				m.attemptRepo.On("LockLoginAttempt", userKey, nowUnix+lockout, nowUnix+2*lockout).Return(nil)
+			}
+			if tt.expectReset || tt.expectSuccess {
+				m.attemptRepo.On("DeleteLoginAttempt", userKey).Return(nil)
+			}
+			if tt.expectSuccess {
+				m.sessionRepo.On("CreateSession", mock.Anything).Return(nil)
+				m.tokenGen.On("GenerateToken", mock.Anything).Return(GeneratedToken, nil)
+			}
+
+			resp, err := service.AuthenticateUser(tt.input.Username, tt.input.Password, testClientIP)
 
 			if tt.expectError != nil {
 				assert.ErrorIs(t, err, tt.expectError)
-				assert.Empty(t, token)
+				assert.Nil(t, resp)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectToken, token)
+				assert.Equal(t, GeneratedToken, resp.Token)
+			}
+			if !tt.expectVerify {
+				m.userRepo.AssertNotCalled(t, "GetUserByUsername", mock.Anything)
 			}
+			if !tt.expectLock {
+				m.attemptRepo.AssertNotCalled(t, "LockLoginAttempt", mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
 
-			authRepo.AssertExpectations(t)
-			clock.AssertExpectations(t)
-			tokenGen.AssertExpectations(t)
+func TestUnlockAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		deleteErr   error
+		expectError error
+	}{
+		{name: "success", username: " Maria "},
+		{name: "empty username", username: "  ", expectError: errors.ErrValidationFailed},
+		{name: "repository error", username: "maria", deleteErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			if tt.expectError != errors.ErrValidationFailed {
+				m.attemptRepo.On("DeleteLoginAttempt", "user:maria").Return(tt.deleteErr)
+			}
+
+			err := service.UnlockAccount("admin", tt.username)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.assertExpectations(t)
 		})
 	}
 }
@@ -141,7 +435,8 @@ func TestGetAuthCredentials(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, authRepo, _, _ := setupAuthServiceTest()
+			service, m := setupAuthServiceTest()
+			authRepo := m.authRepo
 
 			authRepo.On("GetAuthCredentials").Return(tt.mockCreds, tt.mockError)
 
@@ -159,3 +454,266 @@ func TestGetAuthCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshSession(t *testing.T) {
+	const nowUnix int64 = 2000
+
+	tests := []struct {
+		name         string
+		token        string
+		session      *models.Session
+		sessionErr   error
+		storedUser   *models.User
+		rotateErr    error
+		expectRotate bool
+		expectRevoke string
+		expectRole   string
+		expectError  error
+	}{
+		{
+			name:         "rotates token for legacy admin",
+			token:        CurrentRefreshToken,
+			session:      &ActiveSession,
+			expectRotate: true,
+			expectRole:   models.RoleAdmin,
+		},
+		{
+			name:         "uses current role of stored user",
+			token:        CurrentRefreshToken,
+			session:      &ActiveSession,
+			storedUser:   &models.User{Username: "admin", Role: models.RoleEditor, Status: models.UserStatusActive},
+			expectRotate: true,
+			expectRole:   models.RoleEditor,
+		},
+		{
+			name:         "disabled user revokes session",
+			token:        CurrentRefreshToken,
+			session:      &ActiveSession,
+			storedUser:   &models.User{Username: "admin", Role: models.RoleEditor, Status: models.UserStatusDisabled},
+			expectRevoke: "user_inactive",
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "deleted user revokes session",
+			token:        CurrentRefreshToken,
+			session:      sessionWithSource(models.SessionSourceUsers),
+			expectRevoke: "user_not_found",
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "session of a former legacy admin username revokes session",
+			token:        CurrentRefreshToken,
+			session:      &renamedAdminSession,
+			expectRevoke: "user_not_found",
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "external identity keeps its role",
+			token:        CurrentRefreshToken,
+			session:      sessionWithSource(models.SessionSourceExternal),
+			expectRotate: true,
+			expectRole:   models.RoleAdmin,
+		},
+		{
+			name:         "reuse of rotated token revokes family",
+			token:        RotatedRefreshToken,
+			session:      &ActiveSession,
+			expectRevoke: "reuse_detected",
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "concurrent rotation revokes family",
+			token:        CurrentRefreshToken,
+			session:      &ActiveSession,
+			expectRotate: true,
+			rotateErr:    errors.ErrOperationNotAllowed,
+			expectRevoke: "reuse_detected",
+			expectError:  errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "unknown token of existing session",
+			token:       SessionID + ".forged",
+			session:     &ActiveSession,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "revoked session",
+			token:       CurrentRefreshToken,
+			session:     &RevokedSession,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "expired session",
+			token:       CurrentRefreshToken,
+			session:     &ExpiredSession,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "unknown session",
+			token:       CurrentRefreshToken,
+			sessionErr:  errors.ErrResourceNotFound,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "malformed token",
+			token:       "no-separator",
+			expectError: errors.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			m.clock.On("NowUnix").Return(nowUnix).Maybe()
+			m.authRepo.On("GetAuthCredentials").Return(&ValidStoredCredentials, nil).Maybe()
+			m.userRepo.On("GetUserByUsername", "former-admin").Return(nil, errors.ErrResourceNotFound).Maybe()
+
+			if tt.session != nil {
+				m.sessionRepo.On("GetSessionByID", SessionID).Return(tt.session, nil)
+			} else if tt.sessionErr != nil {
+				m.sessionRepo.On("GetSessionByID", SessionID).Return(nil, tt.sessionErr)
+			}
+			if tt.storedUser != nil {
+				m.userRepo.On("GetUserByUsername", "admin").Return(tt.storedUser, nil)
+			} else {
+				m.userRepo.On("GetUserByUsername", "admin").Return(nil, errors.ErrResourceNotFound).Maybe()
+			}
+			if tt.expectRotate {
+				m.sessionRepo.On("RotateSessionToken", SessionID, utils.HashToken(CurrentRefreshToken), mock.AnythingOfType("string"),
+					nowUnix+int64(testRefreshTTL.Seconds()), "now").Return(tt.rotateErr)
+			}
+			if tt.expectRevoke != "" {
+				m.sessionRepo.On("RevokeSession", SessionID, tt.expectRevoke, "now").Return(nil)
+			}
+			if tt.expectError == nil {
+				m.tokenGen.On("GenerateToken", accessClaims("admin", tt.expectRole, nowUnix)).Return(GeneratedToken, nil)
+			}
+
+			resp, err := service.RefreshSession(tt.token)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, GeneratedToken, resp.Token)
+				assert.NotEqual(t, tt.token, resp.RefreshToken)
+				assert.True(t, strings.HasPrefix(resp.RefreshToken, SessionID+"."))
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+// sessionWithSource returns ActiveSession as opened through source.
+func sessionWithSource(source string) *models.Session {
+	session := ActiveSession
+	session.Source = source
+	return &session
+}
+
+// renamedAdminSession was opened by the legacy admin before its username changed.
+var renamedAdminSession = func() models.Session {
+	session := ActiveSession
+	session.Username = "former-admin"
+	session.Source = models.SessionSourceLegacyAdmin
+	return session
+}()
+
+func TestOpenExternalSession(t *testing.T) {
+	t.Run("opens session with mapped role", func(t *testing.T) {
+		service, m := setupAuthServiceTest()
+		m.clock.On("NowUnix").Return(int64(1000))
+		m.sessionRepo.On("CreateSession", mock.MatchedBy(func(s models.Session) bool {
+			return s.ID == SessionID && s.Username == "oidc:ext-1" && s.Role == models.RoleEditor && s.Source == models.SessionSourceExternal &&
+				s.Status == models.SessionStatusActive && s.ExpiresAt == 1000+int64(testRefreshTTL.Seconds())
+		})).Return(nil)
+		m.tokenGen.On("GenerateToken", accessClaims("oidc:ext-1", models.RoleEditor, 1000)).Return("access", nil)
+
+		resp, err := service.OpenExternalSession("oidc:ext-1", models.RoleEditor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", resp.Token)
+		assert.True(t, strings.HasPrefix(resp.RefreshToken, SessionID+"."))
+		m.assertExpectations(t)
+	})
+
+	t.Run("missing role", func(t *testing.T) {
+		service, m := setupAuthServiceTest()
+
+		_, err := service.OpenExternalSession("oidc:ext-1", "")
+
+		assert.ErrorIs(t, err, errors.ErrValidationFailed)
+		m.assertExpectations(t)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	t.Run("revokes session", func(t *testing.T) {
+		service, m := setupAuthServiceTest()
+		m.sessionRepo.On("RevokeSession", SessionID, "logout", "now").Return(nil)
+
+		assert.NoError(t, service.Logout(SessionID))
+		m.assertExpectations(t)
+	})
+
+	t.Run("token without session", func(t *testing.T) {
+		service, m := setupAuthServiceTest()
+
+		assert.ErrorIs(t, service.Logout(""), errors.ErrBadRequest)
+		m.assertExpectations(t)
+	})
+}
+
+func TestLogoutAll(t *testing.T) {
+	service, m := setupAuthServiceTest()
+	m.sessionRepo.On("GetSessionsByUsername", "admin").Return([]models.Session{
+		{ID: "s1", Status: models.SessionStatusActive},
+		{ID: "s2", Status: models.SessionStatusRevoked},
+		{ID: "s3", Status: models.SessionStatusActive},
+	}, nil)
+	m.sessionRepo.On("RevokeSession", "s1", "logout_all", "now").Return(nil)
+	m.sessionRepo.On("RevokeSession", "s3", "logout_all", "now").Return(nil)
+
+	revoked, err := service.LogoutAll("admin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+	m.assertExpectations(t)
+}
+
+func TestIsSessionRevoked(t *testing.T) {
+	tests := []struct {
+		name          string
+		session       *models.Session
+		sessionErr    error
+		expectRevoked bool
+		expectError   error
+	}{
+		{name: "active session", session: &ActiveSession},
+		{name: "revoked session", session: &RevokedSession, expectRevoked: true},
+		{name: "unknown session", sessionErr: errors.ErrResourceNotFound, expectRevoked: true},
+		{name: "repository error", sessionErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupAuthServiceTest()
+			if tt.session != nil {
+				m.sessionRepo.On("GetSessionByID", SessionID).Return(tt.session, nil)
+			} else {
+				m.sessionRepo.On("GetSessionByID", SessionID).Return(nil, tt.sessionErr)
+			}
+
+			revoked, err := service.IsSessionRevoked(SessionID)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectRevoked, revoked)
+			m.assertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/sirupsen/logrus"
+)
+
+// LoginThrottlePolicy controls how failed logins slow down and lock out further attempts.
+//
+// A username may fail FreeFailures times without delay; after that the next attempt must wait
+// BaseBackoff, doubled for every further consecutive failure and capped at Lockout. Reaching the
+// maximum number of failures locks the username, or the client IP, for Lockout. Client IPs are
+// shared by many users so they are only locked, never slowed down. Failures are forgotten after
+// a successful login of the user, or once no failure happened for a whole Lockout period.
+type LoginThrottlePolicy struct {
+	MaxUserFailures int           // Failures of one username before it is locked
+	MaxIPFailures   int           // Failures from one client IP, for any username, before it is locked
+	FreeFailures    int           // Failures of one username allowed before backoff starts
+	BaseBackoff     time.Duration // Wait imposed after the first failure beyond FreeFailures
+	Lockout         time.Duration // Duration of a lockout, and cap of the backoff
+}
+
+// DefaultLoginThrottlePolicy returns the policy used when none is configured.
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxUserFailures: 5,
+		MaxIPFailures:   20,
+		FreeFailures:    2,
+		BaseBackoff:     time.Second,
+		Lockout:         15 * time.Minute,
+	}
+}
+
+// backoff returns how long a username must wait after the given number of consecutive failures.
+func (p LoginThrottlePolicy) backoff(failures int) time.Duration {
+	if failures <= p.FreeFailures {
+		return 0
+	}
+	wait := p.BaseBackoff
+	for i := p.FreeFailures + 1; i < failures && wait < p.Lockout; i++ {
+		wait *= 2
+	}
+	return min(wait, p.Lockout)
+}
+
+// throttleKey is a throttling key with the number of failures that locks it.
+type throttleKey struct {
+	id          string
+	maxFailures int
+	backoff     bool
+}
+
+func userThrottleKey(username string) string {
+	return "user:" + dto.NormalizeUsername(username)
+}
+
+// throttleKeys returns the keys a login attempt is counted against. The IP key is skipped when
+// the client IP is unknown.
+func (s *AuthService) throttleKeys(username, clientIP string) []throttleKey {
+	keys := []throttleKey{{id: userThrottleKey(username), maxFailures: s.throttle.MaxUserFailures, backoff: true}}
+	if clientIP != "" {
+		keys = append(keys, throttleKey{id: "ip:" + clientIP, maxFailures: s.throttle.MaxIPFailures})
+	}
+	return keys
+}
+
+// checkLoginAllowed refuses the attempt while any of its keys is locked or backing off.
+// Returns:
+//   - nil if the attempt may proceed
+//   - errors.ErrThroughputExceeded if it must wait
+//   - repository errors if the attempts cannot be read
+func (s *AuthService) checkLoginAllowed(keys []throttleKey) error {
+	now := s.timeProvider.NowUnix()
+	for _, key := range keys {
+		attempt, err := s.attempts.GetLoginAttempt(key.id)
+		if err != nil {
+			if stdErrors.Is(err, errors.ErrResourceNotFound) {
+				continue
+			}
+			return fmt.Errorf("checking login attempts: %w", err)
+		}
+		if s.isStale(attempt, now) {
+			continue
+		}
+
+		retryAt := attempt.LockedUntil
+		if key.backoff {
+			retryAt = max(retryAt, attempt.LastFailureAt+int64(s.throttle.backoff(attempt.Failures).Seconds()))
+		}
+		if now < retryAt {
+			return fmt.Errorf("login for %s refused for %ds: %w", key.id, retryAt-now, errors.ErrThroughputExceeded)
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against every key and locks the keys that reached
+// their limit. The failure is written to the audit log. Storage errors are logged but do not
+// change the outcome of the login.
+func (s *AuthService) recordLoginFailure(username, clientIP string, keys []throttleKey) {
+	now := s.timeProvider.NowUnix()
+	lockout := int64(s.throttle.Lockout.Seconds())
+
+	for _, key := range keys {
+		if existing, err := s.attempts.GetLoginAttempt(key.id); err == nil && s.isStale(existing, now) {
+			if err := s.attempts.DeleteLoginAttempt(key.id); err != nil {
+				logrus.WithError(err).WithField("key", key.id).Error("Failed to reset stale login attempts")
+			}
+		}
+
+		attempt, err := s.attempts.RecordLoginFailure(key.id, now, now+lockout)
+		if err != nil {
+			logrus.WithError(err).WithField("key", key.id).Error("Failed to record login failure")
+			continue
+		}
+
+		fields := logrus.Fields{
+			"event":     "login_failed",
+			"username":  username,
+			"client_ip": clientIP,
+			"key":       key.id,
+			"failures":  attempt.Failures,
+		}
+		if attempt.Failures >= key.maxFailures {
+			lockedUntil := now + lockout
+			if err := s.attempts.LockLoginAttempt(key.id, lockedUntil, lockedUntil+lockout); err != nil {
+				logrus.WithError(err).WithField("key", key.id).Error("Failed to lock login")
+			}
+			fields["locked_until"] = lockedUntil
+			logrus.WithFields(fields).Warn("Login locked after repeated failures")
+			continue
+		}
+		logrus.WithFields(fields).Warn("Failed login attempt")
+	}
+}
+
+// isStale reports whether the failures of a record are old enough to be forgotten.
+func (s *AuthService) isStale(attempt *models.LoginAttempt, now int64) bool {
+	return now >= attempt.LockedUntil && now >= attempt.LastFailureAt+int64(s.throttle.Lockout.Seconds())
+}
+
+// UnlockAccount lifts the lockout and forgets the failed logins of a username.
+// Lockouts of client IPs are not affected; they expire on their own.
+// Returns:
+//   - nil on success, including when the account was not locked
+//   - errors.ErrValidationFailed if the username is empty
+//   - repository errors if the attempts cannot be deleted
+func (s *AuthService) UnlockAccount(actor, username string) error {
+	if dto.NormalizeUsername(username) == "" {
+		return fmt.Errorf("username is required: %w", errors.ErrValidationFailed)
+	}
+	if err := s.attempts.DeleteLoginAttempt(userThrottleKey(username)); err != nil {
+		return fmt.Errorf("unlocking %s: %w", username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":    "login_unlocked",
+		"actor":    actor,
+		"username": dto.NormalizeUsername(username),
+	}).Info("Account unlocked")
+	return nil
+}
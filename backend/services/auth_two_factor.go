@@ -0,0 +1,469 @@
+package services
+
+import (
+	"crypto/hmac"
+	"encoding/base32"
+	stdErrors "errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	revokeReasonMFACompleted = "mfa_completed"
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5 // 8 base32 characters, shown as "xxxx-xxxx"
+
+	// totpSkew is the number of time steps accepted on either side of the current one,
+	// to tolerate clock drift between the server and the authenticator app.
+	totpSkew = 1
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorConfig enables TOTP second factors for accounts of the users store.
+// The legacy admin secret, OIDC logins and API keys are not affected: their second factor,
+// if any, is up to the identity provider or the key holder.
+type TwoFactorConfig struct {
+	Issuer        string              // Name shown by authenticator apps
+	Cipher        *utils.SecretCipher // Encrypts TOTP seeds at rest
+	RequiredRoles []string            // Roles that cannot sign in without a second factor
+	ChallengeTTL  time.Duration       // Lifetime of the MFA token between the two login steps
+}
+
+// DefaultTwoFactorChallengeTTL is used when TwoFactorConfig.ChallengeTTL is zero.
+const DefaultTwoFactorChallengeTTL = 5 * time.Minute
+
+// roleRequiresSecondFactor reports whether accounts with the role must use a second factor.
+func (s *AuthService) roleRequiresSecondFactor(role string) bool {
+	return s.twoFactor != nil && slices.Contains(s.twoFactor.RequiredRoles, role)
+}
+
+// secondFactorRequired reports whether the login of a user needs a second step, and whether
+// the user must enrol first because the role requires a factor the account does not have.
+func (s *AuthService) secondFactorRequired(user *models.User) (required, enroll bool) {
+	if s.twoFactor == nil {
+		return false, false
+	}
+	if user.TOTPEnabled {
+		return true, false
+	}
+	if s.roleRequiresSecondFactor(user.Role) {
+		return true, true
+	}
+	return false, false
+}
+
+func (s *AuthService) challengeTTL() time.Duration {
+	if s.twoFactor.ChallengeTTL > 0 {
+		return s.twoFactor.ChallengeTTL
+	}
+	return DefaultTwoFactorChallengeTTL
+}
+
+// openMFAChallenge stores a pending session for a user whose password was verified and
+// returns the MFA token that identifies it in the second step.
+func (s *AuthService) openMFAChallenge(user *models.User, enroll bool) (*dto.AuthResponse, error) {
+	sessionID := s.idGen.NewID()
+	mfaToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.challengeTTL()
+	now := s.timeProvider.Now()
+	session := models.Session{
+		ID:        sessionID,
+		Username:  user.Username,
+		Role:      user.Role,
+		TokenHash: utils.HashToken(mfaToken),
+		Status:    models.SessionStatusMFAPending,
+		ExpiresAt: s.timeProvider.NowUnix() + int64(ttl.Seconds()),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("opening MFA challenge for %s: %w", user.Username, err)
+	}
+
+	return &dto.AuthResponse{
+		MFARequired:           true,
+		MFAToken:              mfaToken,
+		MFAEnrollmentRequired: enroll,
+		ExpiresIn:             int64(ttl.Seconds()),
+	}, nil
+}
+
+// pendingChallenge returns the pending session and the active user behind an MFA token.
+// Returns errors.ErrInvalidCredentials if the token is unknown, already used or expired.
+func (s *AuthService) pendingChallenge(mfaToken string) (*models.Session, *models.User, error) {
+	sessionID, _, ok := strings.Cut(mfaToken, ".")
+	if !ok || sessionID == "" {
+		return nil, nil, errors.ErrInvalidCredentials
+	}
+
+	session, err := s.sessions.GetSessionByID(sessionID)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, nil, errors.ErrInvalidCredentials
+		}
+		return nil, nil, fmt.Errorf("retrieving session %s: %w", sessionID, err)
+	}
+
+	if session.Status != models.SessionStatusMFAPending ||
+		!hmac.Equal([]byte(session.TokenHash), []byte(utils.HashToken(mfaToken))) ||
+		s.timeProvider.NowUnix() > session.ExpiresAt {
+		return nil, nil, fmt.Errorf("MFA challenge %s is not pending: %w", sessionID, errors.ErrInvalidCredentials)
+	}
+
+	user, err := s.users.GetUserByUsername(session.Username)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, nil, errors.ErrInvalidCredentials
+		}
+		return nil, nil, fmt.Errorf("retrieving user %s: %w", session.Username, err)
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, nil, fmt.Errorf("user %s is %s: %w", user.Username, user.Status, errors.ErrInvalidCredentials)
+	}
+	return session, user, nil
+}
+
+// CompleteSecondFactor finishes a login started by AuthenticateUser with a TOTP code or a
+// recovery code, and opens the session. While the account is being enrolled, the code must
+// come from the pending enrolment, which it activates.
+// Wrong codes count as failed logins for the username and the client IP.
+// Returns:
+//   - the access and refresh tokens on success
+//   - errors.ErrInvalidCredentials if the MFA token or the code is invalid
+//   - errors.ErrBadRequest if the account must enrol a second factor first
+//   - errors.ErrThroughputExceeded if the username or client IP must wait before trying again
+//   - errors.ErrResourceNotFound if second factors are not configured
+//   - other repository errors if the challenge cannot be completed
+func (s *AuthService) CompleteSecondFactor(mfaToken, code, clientIP string) (*dto.AuthResponse, error) {
+	if s.twoFactor == nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
+	session, user, err := s.pendingChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := s.throttleKeys(user.Username, clientIP)
+	if err := s.checkLoginAllowed(keys); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case user.TOTPEnabled:
+		err = s.verifySecondFactor(user, code)
+	case user.TOTPPendingSecret != "":
+		err = s.confirmEnrollment(user, code)
+	default:
+		return nil, fmt.Errorf("user %s has no second factor enrolled: %w", user.Username, errors.ErrBadRequest)
+	}
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrInvalidCredentials) {
+			s.recordLoginFailure(user.Username, clientIP, keys)
+		}
+		return nil, err
+	}
+
+	if err := s.sessions.RevokeSession(session.ID, revokeReasonMFACompleted, s.timeProvider.Now()); err != nil {
+		return nil, fmt.Errorf("closing MFA challenge %s: %w", session.ID, err)
+	}
+	s.resetLoginAttempts(user.Username, keys[0].id)
+	return s.openSession(user.Username, user.Role, models.SessionSourceUsers)
+}
+
+// BeginTOTPEnrollmentForLogin starts the enrolment that a role requires, during a login whose
+// password was already verified. Accounts that already have a second factor cannot use it, so
+// a leaked password is not enough to replace the factor.
+// Returns:
+//   - the seed, its otpauth URI and the recovery codes on success
+//   - errors.ErrInvalidCredentials if the MFA token is invalid
+//   - errors.ErrOperationNotAllowed if the account already has a second factor
+//   - errors.ErrResourceNotFound if second factors are not configured
+//   - other repository errors if the enrolment cannot be stored
+func (s *AuthService) BeginTOTPEnrollmentForLogin(mfaToken string) (*dto.TOTPEnrollmentResponse, error) {
+	if s.twoFactor == nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
+	_, user, err := s.pendingChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("user %s already has a second factor: %w", user.Username, errors.ErrOperationNotAllowed)
+	}
+	return s.beginEnrollment(user)
+}
+
+// BeginTOTPEnrollment starts a TOTP enrolment for a signed-in user. An existing factor stays
+// in use until the new one is confirmed with ConfirmTOTPEnrollment.
+// Returns:
+//   - the seed, its otpauth URI and the recovery codes on success
+//   - errors.ErrOperationNotAllowed if the user is not in the users store
+//   - errors.ErrResourceNotFound if second factors are not configured
+//   - other repository errors if the enrolment cannot be stored
+func (s *AuthService) BeginTOTPEnrollment(username string) (*dto.TOTPEnrollmentResponse, error) {
+	if s.twoFactor == nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
+	user, err := s.storedUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(user)
+}
+
+// ConfirmTOTPEnrollment activates the pending enrolment of a signed-in user with a first code.
+// The previous factor and recovery codes, if any, are replaced.
+// Returns:
+//   - nil on success
+//   - errors.ErrBadRequest if no enrolment is pending
+//   - errors.ErrInvalidCredentials if the code is wrong
+//   - errors.ErrOperationNotAllowed if the user is not in the users store or the enrolment changed meanwhile
+//   - errors.ErrResourceNotFound if second factors are not configured
+func (s *AuthService) ConfirmTOTPEnrollment(username, code string) error {
+	if s.twoFactor == nil {
+		return errors.ErrResourceNotFound
+	}
+
+	user, err := s.storedUser(username)
+	if err != nil {
+		return err
+	}
+	if user.TOTPPendingSecret == "" {
+		return fmt.Errorf("no TOTP enrolment pending for %s: %w", user.Username, errors.ErrBadRequest)
+	}
+	return s.confirmEnrollment(user, code)
+}
+
+// DisableTOTP removes the second factor of a signed-in user after checking a current code or
+// a recovery code. Wrong codes count as failed logins of the username.
+// Returns:
+//   - nil on success
+//   - errors.ErrBadRequest if the user has no second factor
+//   - errors.ErrOperationNotAllowed if the role of the user requires a second factor
+//   - errors.ErrInvalidCredentials if the code is wrong
+//   - errors.ErrThroughputExceeded if the username must wait before trying again
+//   - errors.ErrResourceNotFound if second factors are not configured
+func (s *AuthService) DisableTOTP(username, code string) error {
+	if s.twoFactor == nil {
+		return errors.ErrResourceNotFound
+	}
+
+	user, err := s.storedUser(username)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return fmt.Errorf("user %s has no second factor: %w", user.Username, errors.ErrBadRequest)
+	}
+	if s.roleRequiresSecondFactor(user.Role) {
+		return fmt.Errorf("role %s requires a second factor: %w", user.Role, errors.ErrOperationNotAllowed)
+	}
+
+	keys := s.throttleKeys(user.Username, "")
+	if err := s.checkLoginAllowed(keys); err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(user, code); err != nil {
+		if stdErrors.Is(err, errors.ErrInvalidCredentials) {
+			s.recordLoginFailure(user.Username, "", keys)
+		}
+		return err
+	}
+
+	if err := s.users.DisableTOTP(user.Username, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("disabling TOTP for %s: %w", user.Username, err)
+	}
+	logrus.WithFields(logrus.Fields{"event": "totp_disabled", "username": user.Username}).Info("Second factor disabled")
+	return nil
+}
+
+// ResetTOTP removes the second factor of a user who lost both the device and the recovery
+// codes. If the role requires a second factor, the user enrols again at the next login.
+// Returns:
+//   - nil on success
+//   - errors.ErrValidationFailed if the username is empty
+//   - errors.ErrOperationNotAllowed if the user does not exist
+//   - errors.ErrResourceNotFound if second factors are not configured
+func (s *AuthService) ResetTOTP(actor, username string) error {
+	if s.twoFactor == nil {
+		return errors.ErrResourceNotFound
+	}
+	username = dto.NormalizeUsername(username)
+	if username == "" {
+		return fmt.Errorf("username is required: %w", errors.ErrValidationFailed)
+	}
+
+	if err := s.users.DisableTOTP(username, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("resetting TOTP for %s: %w", username, err)
+	}
+	logrus.WithFields(logrus.Fields{
+		"event":    "totp_reset",
+		"actor":    actor,
+		"username": username,
+	}).Warn("Second factor reset by admin")
+	return nil
+}
+
+// storedUser returns a user of the users store for second factor management.
+// Identities outside the store (legacy admin, OIDC, API keys) cannot enrol.
+func (s *AuthService) storedUser(username string) (*models.User, error) {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, fmt.Errorf("user %s is not in the users store: %w", username, errors.ErrOperationNotAllowed)
+		}
+		return nil, fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+	return user, nil
+}
+
+// beginEnrollment generates a seed and recovery codes and stores them encrypted and hashed
+// as the pending enrolment of the user.
+func (s *AuthService) beginEnrollment(user *models.User) (*dto.TOTPEnrollmentResponse, error) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generating TOTP secret: %w", errors.ErrInternalServer)
+	}
+	encrypted, err := s.twoFactor.Cipher.Encrypt(secret, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting TOTP secret: %w", errors.ErrInternalServer)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.SaveTOTPEnrollment(user.Username, encrypted, hashes, s.timeProvider.Now()); err != nil {
+		return nil, fmt.Errorf("saving TOTP enrolment for %s: %w", user.Username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{"event": "totp_enrollment_started", "username": user.Username}).Info("Second factor enrolment started")
+	return &dto.TOTPEnrollmentResponse{
+		Secret:        utils.EncodeTOTPSecret(secret),
+		OTPAuthURI:    utils.TOTPURI(s.twoFactor.Issuer, user.Username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// confirmEnrollment checks a code against the pending seed and activates it.
+func (s *AuthService) confirmEnrollment(user *models.User, code string) error {
+	secret, err := s.twoFactor.Cipher.Decrypt(user.TOTPPendingSecret, user.Username)
+	if err != nil {
+		logrus.WithError(err).WithField("username", user.Username).Error("Failed to decrypt pending TOTP secret")
+		return fmt.Errorf("decrypting TOTP secret: %w", errors.ErrInternalServer)
+	}
+
+	step, ok := s.matchTOTP(secret, code, 0)
+	if !ok {
+		return fmt.Errorf("wrong TOTP code for %s: %w", user.Username, errors.ErrInvalidCredentials)
+	}
+
+	if err := s.users.EnableTOTP(user.Username, user.TOTPPendingSecret, user.PendingRecoveryCodeHashes, step, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("enabling TOTP for %s: %w", user.Username, err)
+	}
+	logrus.WithFields(logrus.Fields{"event": "totp_enabled", "username": user.Username}).Info("Second factor enabled")
+	return nil
+}
+
+// verifySecondFactor accepts a TOTP code of the active seed, once per time step, or an unused
+// recovery code, which is consumed.
+func (s *AuthService) verifySecondFactor(user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) != utils.TOTPDigits {
+		return s.consumeRecoveryCode(user, code)
+	}
+
+	secret, err := s.twoFactor.Cipher.Decrypt(user.TOTPSecret, user.Username)
+	if err != nil {
+		logrus.WithError(err).WithField("username", user.Username).Error("Failed to decrypt TOTP secret")
+		return fmt.Errorf("decrypting TOTP secret: %w", errors.ErrInternalServer)
+	}
+
+	step, ok := s.matchTOTP(secret, code, user.TOTPLastStep)
+	if !ok {
+		return fmt.Errorf("wrong TOTP code for %s: %w", user.Username, errors.ErrInvalidCredentials)
+	}
+	if err := s.users.RecordTOTPStep(user.Username, step); err != nil {
+		if stdErrors.Is(err, errors.ErrOperationNotAllowed) {
+			return fmt.Errorf("TOTP code replayed for %s: %w", user.Username, errors.ErrInvalidCredentials)
+		}
+		return fmt.Errorf("recording TOTP step for %s: %w", user.Username, err)
+	}
+	return nil
+}
+
+func (s *AuthService) consumeRecoveryCode(user *models.User, code string) error {
+	hash := utils.HashToken(normalizeRecoveryCode(code))
+	if err := s.users.ConsumeRecoveryCode(user.Username, hash); err != nil {
+		if stdErrors.Is(err, errors.ErrOperationNotAllowed) {
+			return fmt.Errorf("wrong recovery code for %s: %w", user.Username, errors.ErrInvalidCredentials)
+		}
+		return fmt.Errorf("consuming recovery code for %s: %w", user.Username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":     "recovery_code_used",
+		"username":  user.Username,
+		"remaining": len(user.RecoveryCodeHashes) - 1,
+	}).Warn("Recovery code used")
+	return nil
+}
+
+// matchTOTP returns the time step whose code matches, looking at the current step and its
+// neighbours. Steps up to lastStep were already used and are skipped.
+func (s *AuthService) matchTOTP(secret []byte, code string, lastStep int64) (int64, bool) {
+	current := utils.TOTPStep(s.timeProvider.NowUnix())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(utils.TOTPCode(secret, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns fresh recovery codes and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw, err := utils.GenerateTOTPSecret()
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating recovery codes: %w", errors.ErrInternalServer)
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw[:recoveryCodeBytes]))
+		codes = append(codes, encoded[:4]+"-"+encoded[4:])
+		hashes = append(hashes, utils.HashToken(encoded))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes, as users type codes back loosely.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
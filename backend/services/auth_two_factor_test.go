@@ -0,0 +1,456 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// twoFactorNow is the mocked Unix time of the two-factor tests: TOTP step 1000.
+const twoFactorNow int64 = 30000
+
+func setupTwoFactorTest() (*services.AuthService, authServiceMocks) {
+	cfg := TwoFactorTestConfig
+	service, m := setupAuthServiceTestWithTwoFactor(&cfg)
+	m.clock.On("NowUnix").Return(twoFactorNow).Maybe()
+	return service, m
+}
+
+func TestTOTPCode_RFC6238Vector(t *testing.T) {
+	assert.Equal(t, "287082", utils.TOTPCode(TOTPSeed, utils.TOTPStep(59)))
+	assert.Equal(t, "081804", utils.TOTPCode(TOTPSeed, utils.TOTPStep(1111111109)))
+}
+
+func TestAuthenticateUser_SecondFactor(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         models.User
+		expectEnroll bool
+	}{
+		{name: "user with TOTP", user: EditorWithTOTP},
+		{name: "admin must enrol", user: AdminWithoutTOTP, expectEnroll: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupTwoFactorTest()
+			m.attemptRepo.On("GetLoginAttempt", mock.Anything).Return(nil, errors.ErrResourceNotFound)
+			m.userRepo.On("GetUserByUsername", "ana").Return(&tt.user, nil)
+
+			var challenge models.Session
+			m.sessionRepo.On("CreateSession", mock.Anything).Run(func(args mock.Arguments) {
+				challenge = args.Get(0).(models.Session)
+			}).Return(nil)
+
+			resp, err := service.AuthenticateUser("ana", "secret", testClientIP)
+
+			require.NoError(t, err)
+			assert.True(t, resp.MFARequired)
+			assert.Equal(t, tt.expectEnroll, resp.MFAEnrollmentRequired)
+			assert.Empty(t, resp.Token)
+			assert.Empty(t, resp.RefreshToken)
+			assert.True(t, strings.HasPrefix(resp.MFAToken, SessionID+"."))
+			assert.Equal(t, int64(300), resp.ExpiresIn)
+			assert.Equal(t, models.SessionStatusMFAPending, challenge.Status)
+			assert.Equal(t, utils.HashToken(resp.MFAToken), challenge.TokenHash)
+			assert.Equal(t, twoFactorNow+300, challenge.ExpiresAt)
+
+			// Failed attempts are kept until the second factor succeeds.
+			m.attemptRepo.AssertNotCalled(t, "DeleteLoginAttempt", mock.Anything)
+			m.tokenGen.AssertNotCalled(t, "GenerateToken", mock.Anything)
+			m.assertExpectations(t)
+		})
+	}
+
+	t.Run("legacy admin is refused when its role requires a factor", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		allowLoginAttempts(m)
+		m.userRepo.On("GetUserByUsername", "admin").Return(nil, errors.ErrResourceNotFound)
+		m.authRepo.On("GetAuthCredentials").Return(&ValidStoredCredentials, nil)
+
+		resp, err := service.AuthenticateUser("admin", "secret", testClientIP)
+
+		assert.ErrorIs(t, err, errors.ErrOperationNotAllowed)
+		assert.Nil(t, resp)
+		m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything)
+		m.tokenGen.AssertNotCalled(t, "GenerateToken", mock.Anything)
+	})
+
+	t.Run("legacy admin signs in when its role needs no factor", func(t *testing.T) {
+		cfg := TwoFactorTestConfig
+		cfg.RequiredRoles = []string{models.RoleEditor}
+		service, m := setupAuthServiceTestWithTwoFactor(&cfg)
+		m.clock.On("NowUnix").Return(twoFactorNow).Maybe()
+		allowLoginAttempts(m)
+		m.userRepo.On("GetUserByUsername", "admin").Return(nil, errors.ErrResourceNotFound)
+		m.authRepo.On("GetAuthCredentials").Return(&ValidStoredCredentials, nil)
+		m.sessionRepo.On("CreateSession", mock.MatchedBy(func(s models.Session) bool {
+			return s.Status == models.SessionStatusActive
+		})).Return(nil)
+		m.tokenGen.On("GenerateToken", mock.Anything).Return(GeneratedToken, nil)
+
+		resp, err := service.AuthenticateUser("admin", "secret", testClientIP)
+
+		require.NoError(t, err)
+		assert.False(t, resp.MFARequired)
+		assert.Equal(t, GeneratedToken, resp.Token)
+	})
+}
+
+func TestCompleteSecondFactor(t *testing.T) {
+	currentCode := utils.TOTPCode(TOTPSeed, 1000)
+	previousCode := utils.TOTPCode(TOTPSeed, 999)
+	staleCode := utils.TOTPCode(TOTPSeed, 990)
+
+	usedStep := AdminWithTOTP
+	usedStep.TOTPLastStep = 1000
+
+	revoked := PendingMFASession
+	revoked.Status = models.SessionStatusRevoked
+
+	expired := PendingMFASession
+	expired.ExpiresAt = twoFactorNow - 1
+
+	tests := []struct {
+		name          string
+		token         string
+		session       *models.Session
+		user          *models.User
+		code          string
+		recordStep    int64
+		recordErr     error
+		recoveryHash  string
+		recoveryErr   error
+		enable        bool
+		expectFailure bool
+		expectError   error
+	}{
+		{
+			name:       "current TOTP code",
+			user:       &AdminWithTOTP,
+			code:       currentCode,
+			recordStep: 1000,
+		},
+		{
+			name:       "previous step within skew",
+			user:       &AdminWithTOTP,
+			code:       " " + previousCode + " ",
+			recordStep: 999,
+		},
+		{
+			name:          "code outside the window",
+			user:          &AdminWithTOTP,
+			code:          staleCode,
+			expectFailure: true,
+			expectError:   errors.ErrInvalidCredentials,
+		},
+		{
+			name:          "step already used",
+			user:          &usedStep,
+			code:          currentCode,
+			expectFailure: true,
+			expectError:   errors.ErrInvalidCredentials,
+		},
+		{
+			name:          "concurrent replay",
+			user:          &AdminWithTOTP,
+			code:          currentCode,
+			recordStep:    1000,
+			recordErr:     errors.ErrOperationNotAllowed,
+			expectFailure: true,
+			expectError:   errors.ErrInvalidCredentials,
+		},
+		{
+			name:         "recovery code typed loosely",
+			user:         &AdminWithTOTP,
+			code:         "ABCD EFGH",
+			recoveryHash: utils.HashToken("abcdefgh"),
+		},
+		{
+			name:          "used recovery code",
+			user:          &AdminWithTOTP,
+			code:          RecoveryCode,
+			recoveryHash:  utils.HashToken("abcdefgh"),
+			recoveryErr:   errors.ErrOperationNotAllowed,
+			expectFailure: true,
+			expectError:   errors.ErrInvalidCredentials,
+		},
+		{
+			name:   "first code of a required enrolment",
+			user:   &AdminEnrolling,
+			code:   currentCode,
+			enable: true,
+		},
+		{
+			name:        "nothing enrolled",
+			user:        &AdminWithoutTOTP,
+			code:        currentCode,
+			expectError: errors.ErrBadRequest,
+		},
+		{
+			name:        "wrong MFA token",
+			token:       SessionID + ".forged",
+			session:     &PendingMFASession,
+			code:        currentCode,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "challenge already completed",
+			session:     &revoked,
+			code:        currentCode,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "expired challenge",
+			session:     &expired,
+			code:        currentCode,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "refresh token of an active session",
+			session:     &ActiveSession,
+			token:       CurrentRefreshToken,
+			code:        currentCode,
+			expectError: errors.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupTwoFactorTest()
+			allowLoginAttempts(m)
+
+			token := MFAToken
+			if tt.token != "" {
+				token = tt.token
+			}
+			session := &PendingMFASession
+			if tt.session != nil {
+				session = tt.session
+			}
+			m.sessionRepo.On("GetSessionByID", SessionID).Return(session, nil)
+			if tt.user != nil {
+				m.userRepo.On("GetUserByUsername", "ana").Return(tt.user, nil)
+			}
+			if tt.recordStep != 0 {
+				m.userRepo.On("RecordTOTPStep", "ana", tt.recordStep).Return(tt.recordErr)
+			}
+			if tt.recoveryHash != "" {
+				m.userRepo.On("ConsumeRecoveryCode", "ana", tt.recoveryHash).Return(tt.recoveryErr)
+			}
+			if tt.enable {
+				m.userRepo.On("EnableTOTP", "ana", AdminEnrolling.TOTPPendingSecret, AdminEnrolling.PendingRecoveryCodeHashes, int64(1000), "now").Return(nil)
+			}
+			if tt.expectError == nil {
+				m.sessionRepo.On("RevokeSession", SessionID, "mfa_completed", "now").Return(nil)
+				m.sessionRepo.On("CreateSession", mock.MatchedBy(func(s models.Session) bool {
+					return s.Username == "ana" && s.Role == models.RoleAdmin && s.Status == models.SessionStatusActive
+				})).Return(nil)
+				m.tokenGen.On("GenerateToken", accessClaims("ana", models.RoleAdmin, twoFactorNow)).Return(GeneratedToken, nil)
+			}
+
+			resp, err := service.CompleteSecondFactor(token, tt.code, testClientIP)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+				m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, GeneratedToken, resp.Token)
+				assert.False(t, resp.MFARequired)
+				m.attemptRepo.AssertCalled(t, "DeleteLoginAttempt", "user:ana")
+			}
+			if tt.expectFailure {
+				m.attemptRepo.AssertCalled(t, "RecordLoginFailure", "user:ana", twoFactorNow, mock.Anything)
+			} else {
+				m.attemptRepo.AssertNotCalled(t, "RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.userRepo.AssertExpectations(t)
+			m.sessionRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		service, m := setupAuthServiceTest()
+
+		_, err := service.CompleteSecondFactor(MFAToken, currentCode, testClientIP)
+
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+		m.sessionRepo.AssertNotCalled(t, "GetSessionByID", mock.Anything)
+	})
+}
+
+func TestBeginTOTPEnrollment(t *testing.T) {
+	t.Run("stores encrypted seed and hashed recovery codes", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.userRepo.On("GetUserByUsername", "ana").Return(&AdminWithoutTOTP, nil)
+
+		var encrypted string
+		var hashes []string
+		m.userRepo.On("SaveTOTPEnrollment", "ana", mock.Anything, mock.Anything, "now").Run(func(args mock.Arguments) {
+			encrypted = args.String(1)
+			hashes = args.Get(2).([]string)
+		}).Return(nil)
+
+		resp, err := service.BeginTOTPEnrollment("ana")
+		require.NoError(t, err)
+
+		seed, err := TOTPCipher.Decrypt(encrypted, "ana")
+		require.NoError(t, err)
+		assert.Equal(t, utils.EncodeTOTPSecret(seed), resp.Secret)
+		assert.NotContains(t, encrypted, resp.Secret)
+		assert.True(t, strings.HasPrefix(resp.OTPAuthURI, "otpauth://totp/Rendalla:ana?"))
+		assert.Contains(t, resp.OTPAuthURI, "secret="+resp.Secret)
+
+		require.Len(t, resp.RecoveryCodes, 10)
+		for i, code := range resp.RecoveryCodes {
+			assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+			assert.Equal(t, utils.HashToken(strings.ReplaceAll(code, "-", "")), hashes[i])
+		}
+	})
+
+	t.Run("identity outside the users store", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.userRepo.On("GetUserByUsername", "oidc:ext-1").Return(nil, errors.ErrResourceNotFound)
+
+		_, err := service.BeginTOTPEnrollment("oidc:ext-1")
+
+		assert.ErrorIs(t, err, errors.ErrOperationNotAllowed)
+	})
+
+	t.Run("during login", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.sessionRepo.On("GetSessionByID", SessionID).Return(&PendingMFASession, nil)
+		m.userRepo.On("GetUserByUsername", "ana").Return(&AdminWithoutTOTP, nil)
+		m.userRepo.On("SaveTOTPEnrollment", "ana", mock.Anything, mock.Anything, "now").Return(nil)
+
+		resp, err := service.BeginTOTPEnrollmentForLogin(MFAToken)
+
+		require.NoError(t, err)
+		assert.Len(t, resp.RecoveryCodes, 10)
+	})
+
+	t.Run("during login cannot replace an active factor", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.sessionRepo.On("GetSessionByID", SessionID).Return(&PendingMFASession, nil)
+		m.userRepo.On("GetUserByUsername", "ana").Return(&AdminWithTOTP, nil)
+
+		_, err := service.BeginTOTPEnrollmentForLogin(MFAToken)
+
+		assert.ErrorIs(t, err, errors.ErrOperationNotAllowed)
+		m.userRepo.AssertNotCalled(t, "SaveTOTPEnrollment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmTOTPEnrollment(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        *models.User
+		code        string
+		enableErr   error
+		expectCall  bool
+		expectError error
+	}{
+		{name: "valid code", user: &AdminEnrolling, code: utils.TOTPCode(TOTPSeed, 1001), expectCall: true},
+		{name: "wrong code", user: &AdminEnrolling, code: "000000", expectError: errors.ErrInvalidCredentials},
+		{name: "enrolment replaced meanwhile", user: &AdminEnrolling, code: utils.TOTPCode(TOTPSeed, 1000), enableErr: errors.ErrOperationNotAllowed, expectCall: true, expectError: errors.ErrOperationNotAllowed},
+		{name: "nothing pending", user: &AdminWithTOTP, code: utils.TOTPCode(TOTPSeed, 1000), expectError: errors.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupTwoFactorTest()
+			m.userRepo.On("GetUserByUsername", "ana").Return(tt.user, nil)
+			if tt.expectCall {
+				m.userRepo.On("EnableTOTP", "ana", tt.user.TOTPPendingSecret, tt.user.PendingRecoveryCodeHashes, mock.Anything, "now").Return(tt.enableErr)
+			}
+
+			err := service.ConfirmTOTPEnrollment("ana", tt.code)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	tests := []struct {
+		name          string
+		user          *models.User
+		code          string
+		expectDisable bool
+		expectFailure bool
+		expectError   error
+	}{
+		{name: "valid code", user: &EditorWithTOTP, code: utils.TOTPCode(TOTPSeed, 1000), expectDisable: true},
+		{name: "wrong code", user: &EditorWithTOTP, code: "000000", expectFailure: true, expectError: errors.ErrInvalidCredentials},
+		{name: "role requires a second factor", user: &AdminWithTOTP, code: utils.TOTPCode(TOTPSeed, 1000), expectError: errors.ErrOperationNotAllowed},
+		{name: "not enabled", user: &ActiveEditorUser, code: "000000", expectError: errors.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupTwoFactorTest()
+			allowLoginAttempts(m)
+			m.userRepo.On("GetUserByUsername", "ana").Return(tt.user, nil)
+			if tt.expectDisable {
+				m.userRepo.On("RecordTOTPStep", "ana", int64(1000)).Return(nil)
+				m.userRepo.On("DisableTOTP", "ana", "now").Return(nil)
+			}
+
+			err := service.DisableTOTP("ana", tt.code)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				m.userRepo.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectFailure {
+				m.attemptRepo.AssertCalled(t, "RecordLoginFailure", "user:ana", twoFactorNow, mock.Anything)
+			}
+			m.userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetTOTP(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.userRepo.On("DisableTOTP", "ana", "now").Return(nil)
+
+		err := service.ResetTOTP("admin", " Ana ")
+
+		assert.NoError(t, err)
+		m.userRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		service, m := setupTwoFactorTest()
+		m.userRepo.On("DisableTOTP", "ghost", "now").Return(errors.ErrOperationNotAllowed)
+
+		err := service.ResetTOTP("admin", "ghost")
+
+		assert.ErrorIs(t, err, errors.ErrOperationNotAllowed)
+	})
+
+	t.Run("empty username", func(t *testing.T) {
+		service, _ := setupTwoFactorTest()
+
+		err := service.ResetTOTP("admin", " ")
+
+		assert.ErrorIs(t, err, errors.ErrValidationFailed)
+	})
+}
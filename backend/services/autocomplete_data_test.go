@@ -0,0 +1,36 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+var AutocompleteSongs = []models.Song{
+	{
+		ID:     "s1",
+		Title:  "Bohemian Rhapsody",
+		Author: "Queen",
+		Genres: []string{"rock", "opera"},
+	},
+	{
+		ID:     "s2",
+		Title:  "Bésame Mucho",
+		Author: "Consuelo Velázquez",
+		Genres: []string{"bolero"},
+	},
+	{
+		ID:     "s3",
+		Title:  "Radio Ga Ga",
+		Author: "Queen",
+		Genres: []string{"rock"},
+	},
+}
+
+var AutocompleteDocuments = []models.Document{
+	{ID: "d1", SongID: "s1", Instrument: []string{"piano"}},
+	{ID: "d2", SongID: "s2", Instrument: []string{"guitar", "voice"}},
+}
+
+var AutocompleteNewSong = models.Song{
+	ID:     "s4",
+	Title:  "Bicycle Race",
+	Author: "Queen",
+	Genres: []string{"rock"},
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// AutocompleteServiceInterface defines search-as-you-type operations over song titles, authors, genres and instruments.
+type AutocompleteServiceInterface interface {
+
+	// Suggest returns the top suggestions starting with the given prefix.
+	// Parameters:
+	//   - prefix: text typed so far (normalized internally)
+	//   - suggestionType: "song", "author", "genre", "instrument", or "" for all of them
+	//   - limit: max number of suggestions to return
+	// Returns:
+	//   - ([]dto.Suggestion, nil) on success
+	//   - (nil, errors.ErrValidationFailed) if the type is unknown
+	//   - (nil, error) if the index cannot be loaded
+	Suggest(prefix, suggestionType string, limit int) ([]dto.Suggestion, error)
+}
+
+// CatalogIndexer receives catalogue mutations so that in-memory search structures stay current.
+// Implementations must be safe for concurrent use and must not fail the calling operation.
+type CatalogIndexer interface {
+
+	// IndexSong adds a song to the index, replacing any previously indexed version.
+	IndexSong(song models.Song)
+
+	// RemoveSong removes a song and all of its documents from the index.
+	RemoveSong(songID string)
+
+	// IndexDocument adds a document to the index, replacing any previously indexed version.
+	IndexDocument(doc models.Document)
+
+	// RemoveDocument removes a single document from the index.
+	RemoveDocument(songID, docID string)
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Ensure AutocompleteService implements AutocompleteServiceInterface and CatalogIndexer.
+var _ AutocompleteServiceInterface = (*AutocompleteService)(nil)
+var _ CatalogIndexer = (*AutocompleteService)(nil)
+
+// AutocompleteService serves prefix suggestions from in-memory sorted indexes.
+//
+// The indexes are built from a single catalogue scan the first time they are needed
+// (i.e. once per warm Lambda container) and kept current through the CatalogIndexer
+// callbacks invoked by SongService and DocumentService. Because other containers may
+// also mutate the catalogue, the indexes are rebuilt after refreshInterval has elapsed.
+type AutocompleteService struct {
+	repo            repository.SearchRepository
+	timeProvider    utils.TimeProvider
+	refreshInterval time.Duration
+
+	loadMu sync.Mutex
+	mu     sync.RWMutex
+	state  *autocompleteState
+}
+
+// autocompleteState holds one generation of the prefix indexes together with the
+// terms each entity contributed, so that updates and deletes can be undone precisely.
+type autocompleteState struct {
+	loadedAt    int64
+	titles      *utils.PrefixIndex
+	authors     *utils.PrefixIndex
+	genres      *utils.PrefixIndex
+	instruments *utils.PrefixIndex
+	songs       map[string]models.Song
+	documents   map[string]map[string][]string
+}
+
+// NewAutocompleteService returns a new instance of AutocompleteService.
+// The indexes are not loaded until the first suggestion is requested.
+func NewAutocompleteService(
+	repo repository.SearchRepository,
+	timeProvider utils.TimeProvider,
+	refreshInterval time.Duration,
+) *AutocompleteService {
+	return &AutocompleteService{
+		repo:            repo,
+		timeProvider:    timeProvider,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Suggest returns up to limit suggestions whose normalized value starts with the normalized prefix.
+// When suggestionType is empty, suggestions of every type are merged and ordered alphabetically.
+// Returns:
+//   - ([]dto.Suggestion, nil) on success
+//   - (nil, errors.ErrValidationFailed) if the suggestion type is unknown
+//   - (nil, error) if the indexes have never been loaded and loading fails
+func (s *AutocompleteService) Suggest(prefix, suggestionType string, limit int) ([]dto.Suggestion, error) {
+	state, err := s.currentState()
+	if err != nil {
+		return nil, fmt.Errorf("loading autocomplete index: %w", err)
+	}
+
+	indexes := map[string]*utils.PrefixIndex{
+		dto.SuggestionTypeSong:       state.titles,
+		dto.SuggestionTypeAuthor:     state.authors,
+		dto.SuggestionTypeGenre:      state.genres,
+		dto.SuggestionTypeInstrument: state.instruments,
+	}
+
+	types := []string{suggestionType}
+	if suggestionType == "" {
+		types = []string{dto.SuggestionTypeSong, dto.SuggestionTypeAuthor, dto.SuggestionTypeGenre, dto.SuggestionTypeInstrument}
+	} else if _, ok := indexes[suggestionType]; !ok {
+		return nil, fmt.Errorf("unknown suggestion type %q: %w", suggestionType, errors.ErrValidationFailed)
+	}
+
+	suggestions := []dto.Suggestion{}
+	for _, t := range types {
+		for _, value := range indexes[t].Search(prefix, limit) {
+			suggestions = append(suggestions, dto.Suggestion{Value: value, Type: t})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return utils.Normalize(suggestions[i].Value) < utils.Normalize(suggestions[j].Value)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// IndexSong adds or replaces a song's title, author and genres in the index.
+func (s *AutocompleteService) IndexSong(song models.Song) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return
+	}
+	s.state.removeSong(song.ID)
+	s.state.addSong(song)
+}
+
+// RemoveSong removes a song and the instruments of all its documents from the index.
+func (s *AutocompleteService) RemoveSong(songID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return
+	}
+	s.state.removeSong(songID)
+	for docID := range s.state.documents[songID] {
+		s.state.removeDocument(songID, docID)
+	}
+}
+
+// IndexDocument adds or replaces a document's instruments in the index.
+func (s *AutocompleteService) IndexDocument(doc models.Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return
+	}
+	s.state.removeDocument(doc.SongID, doc.ID)
+	s.state.addDocument(doc)
+}
+
+// RemoveDocument removes a document's instruments from the index.
+func (s *AutocompleteService) RemoveDocument(songID, docID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		return
+	}
+	s.state.removeDocument(songID, docID)
+}
+
+// currentState returns the loaded indexes, (re)building them when missing or older than refreshInterval.
+// If a refresh fails while an older generation is available, the stale indexes keep being served.
+func (s *AutocompleteService) currentState() (*autocompleteState, error) {
+	s.mu.RLock()
+	state := s.state
+	s.mu.RUnlock()
+
+	if state != nil && !s.isStale(state) {
+		return state, nil
+	}
+
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	s.mu.RLock()
+	state = s.state
+	s.mu.RUnlock()
+	if state != nil && !s.isStale(state) {
+		return state, nil
+	}
+
+	fresh, err := s.buildState()
+	if err != nil {
+		if state != nil {
+			logrus.WithField("operation", "autocomplete_refresh").WithError(err).Warn("Failed to refresh autocomplete index, serving stale data")
+			return state, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.state = fresh
+	s.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"operation":   "autocomplete_refresh",
+		"songs":       len(fresh.songs),
+		"titles":      fresh.titles.Len(),
+		"authors":     fresh.authors.Len(),
+		"genres":      fresh.genres.Len(),
+		"instruments": fresh.instruments.Len(),
+	}).Info("Autocomplete index loaded")
+
+	return fresh, nil
+}
+
+// isStale reports whether the given state is older than the refresh interval.
+func (s *AutocompleteService) isStale(state *autocompleteState) bool {
+	if s.refreshInterval <= 0 {
+		return false
+	}
+	return s.timeProvider.NowUnix()-state.loadedAt >= int64(s.refreshInterval.Seconds())
+}
+
+// catalogScanBatch is how many songs and documents buildState reads per call to the repository.
+const catalogScanBatch = 1000
+
+// buildState scans the whole catalogue once and builds a fresh generation of indexes.
+func (s *AutocompleteService) buildState() (*autocompleteState, error) {
+	var songs []models.Song
+	var documents []models.Document
+	for cursor := ""; ; {
+		batchSongs, batchDocuments, next, err := s.repo.SearchCatalog("", catalogScanBatch, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("scanning catalogue: %w", err)
+		}
+		songs = append(songs, batchSongs...)
+		documents = append(documents, batchDocuments...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	state := &autocompleteState{
+		loadedAt:    s.timeProvider.NowUnix(),
+		titles:      utils.NewPrefixIndex(),
+		authors:     utils.NewPrefixIndex(),
+		genres:      utils.NewPrefixIndex(),
+		instruments: utils.NewPrefixIndex(),
+		songs:       make(map[string]models.Song, len(songs)),
+		documents:   make(map[string]map[string][]string),
+	}
+
+	for _, song := range songs {
+		state.addSong(song)
+	}
+	for _, doc := range documents {
+		state.addDocument(doc)
+	}
+
+	return state, nil
+}
+
+func (st *autocompleteState) addSong(song models.Song) {
+	st.songs[song.ID] = song
+	st.titles.Add(song.Title)
+	st.authors.Add(song.Author)
+	for _, genre := range song.Genres {
+		st.genres.Add(genre)
+	}
+}
+
+func (st *autocompleteState) removeSong(songID string) {
+	song, ok := st.songs[songID]
+	if !ok {
+		return
+	}
+	delete(st.songs, songID)
+	st.titles.Remove(song.Title)
+	st.authors.Remove(song.Author)
+	for _, genre := range song.Genres {
+		st.genres.Remove(genre)
+	}
+}
+
+func (st *autocompleteState) addDocument(doc models.Document) {
+	if st.documents[doc.SongID] == nil {
+		st.documents[doc.SongID] = make(map[string][]string)
+	}
+	st.documents[doc.SongID][doc.ID] = doc.Instrument
+	for _, instrument := range doc.Instrument {
+		st.instruments.Add(instrument)
+	}
+}
+
+func (st *autocompleteState) removeDocument(songID, docID string) {
+	instruments, ok := st.documents[songID][docID]
+	if !ok {
+		return
+	}
+	delete(st.documents[songID], docID)
+	if len(st.documents[songID]) == 0 {
+		delete(st.documents, songID)
+	}
+	for _, instrument := range instruments {
+		st.instruments.Remove(instrument)
+	}
+}
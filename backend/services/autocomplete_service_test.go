@@ -0,0 +1,185 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAutocompleteServiceTest() (*services.AutocompleteService, *mocks.MockSearchRepository, *mocks.MockTimeProvider) {
+	repo := new(mocks.MockSearchRepository)
+	timeProvider := new(mocks.MockTimeProvider)
+	service := services.NewAutocompleteService(repo, timeProvider, 10*time.Minute)
+	return service, repo, timeProvider
+}
+
+func suggestionValues(suggestions []dto.Suggestion) []string {
+	values := []string{}
+	for _, s := range suggestions {
+		values = append(values, s.Value)
+	}
+	return values
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name           string
+		prefix         string
+		suggestionType string
+		limit          int
+		mockRepoError  error
+		expectError    error
+		expectedValues []string
+	}{
+		{
+			name:           "song titles are accent-insensitive",
+			prefix:         "be",
+			suggestionType: dto.SuggestionTypeSong,
+			limit:          10,
+			expectedValues: []string{"Bésame Mucho"},
+		},
+		{
+			name:           "authors are deduplicated",
+			prefix:         "qu",
+			suggestionType: dto.SuggestionTypeAuthor,
+			limit:          10,
+			expectedValues: []string{"Queen"},
+		},
+		{
+			name:           "genres",
+			prefix:         "R",
+			suggestionType: dto.SuggestionTypeGenre,
+			limit:          10,
+			expectedValues: []string{"rock"},
+		},
+		{
+			name:           "instruments",
+			prefix:         "g",
+			suggestionType: dto.SuggestionTypeInstrument,
+			limit:          10,
+			expectedValues: []string{"guitar"},
+		},
+		{
+			name:           "all types merged and limited",
+			prefix:         "b",
+			limit:          2,
+			expectedValues: []string{"Bésame Mucho", "Bohemian Rhapsody"},
+		},
+		{
+			name:           "unknown type",
+			prefix:         "b",
+			suggestionType: "album",
+			limit:          10,
+			expectError:    errors.ErrValidationFailed,
+		},
+		{
+			name:          "repository error on first load",
+			prefix:        "b",
+			limit:         10,
+			mockRepoError: errors.ErrInternalServer,
+			expectError:   errors.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, clock := setupAutocompleteServiceTest()
+			clock.On("NowUnix").Return(int64(1000))
+			repo.On("SearchCatalog", "", mock.Anything, "").Return(AutocompleteSongs, AutocompleteDocuments, "", tt.mockRepoError).Once()
+
+			suggestions, err := service.Suggest(tt.prefix, tt.suggestionType, tt.limit)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedValues, suggestionValues(suggestions))
+		})
+	}
+}
+
+func TestSuggest_LoadsIndexOnlyOnce(t *testing.T) {
+	service, repo, clock := setupAutocompleteServiceTest()
+	clock.On("NowUnix").Return(int64(1000))
+	repo.On("SearchCatalog", "", mock.Anything, "").Return(AutocompleteSongs, AutocompleteDocuments, "", nil).Once()
+
+	for _, prefix := range []string{"b", "bo", "boh"} {
+		_, err := service.Suggest(prefix, dto.SuggestionTypeSong, 10)
+		assert.NoError(t, err)
+	}
+
+	repo.AssertNumberOfCalls(t, "SearchCatalog", 1)
+}
+
+func TestSuggest_LoadsIndexAcrossScanBatches(t *testing.T) {
+	service, repo, clock := setupAutocompleteServiceTest()
+	clock.On("NowUnix").Return(int64(1000))
+	repo.On("SearchCatalog", "", mock.Anything, "").Return(AutocompleteSongs, []models.Document{}, "batch-2", nil).Once()
+	repo.On("SearchCatalog", "", mock.Anything, "batch-2").Return([]models.Song{AutocompleteNewSong}, AutocompleteDocuments, "", nil).Once()
+
+	suggestions, err := service.Suggest(AutocompleteNewSong.Title[:3], dto.SuggestionTypeSong, 10)
+
+	assert.NoError(t, err)
+	assert.Contains(t, suggestions, dto.Suggestion{Value: AutocompleteNewSong.Title, Type: dto.SuggestionTypeSong})
+	repo.AssertExpectations(t)
+}
+
+func TestSuggest_RefreshesStaleIndex(t *testing.T) {
+	service, repo, clock := setupAutocompleteServiceTest()
+	clock.On("NowUnix").Return(int64(1000)).Once()
+	repo.On("SearchCatalog", "", mock.Anything, "").Return(AutocompleteSongs, AutocompleteDocuments, "", nil).Once()
+
+	_, err := service.Suggest("b", dto.SuggestionTypeSong, 10)
+	assert.NoError(t, err)
+
+	clock.On("NowUnix").Return(int64(1000 + 600))
+	repo.On("SearchCatalog", "", mock.Anything, "").Return([]models.Song{AutocompleteNewSong}, []models.Document{}, "", nil).Once()
+
+	suggestions, err := service.Suggest("b", dto.SuggestionTypeSong, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bicycle Race"}, suggestionValues(suggestions))
+}
+
+func TestCatalogIndexer_KeepsIndexCurrent(t *testing.T) {
+	service, repo, clock := setupAutocompleteServiceTest()
+	clock.On("NowUnix").Return(int64(1000))
+	repo.On("SearchCatalog", "", mock.Anything, "").Return(AutocompleteSongs, AutocompleteDocuments, "", nil).Once()
+
+	_, err := service.Suggest("b", "", 10)
+	assert.NoError(t, err)
+
+	service.IndexSong(AutocompleteNewSong)
+	suggestions, _ := service.Suggest("bi", dto.SuggestionTypeSong, 10)
+	assert.Equal(t, []string{"Bicycle Race"}, suggestionValues(suggestions))
+
+	renamed := AutocompleteNewSong
+	renamed.Title = "Bicycle"
+	service.IndexSong(renamed)
+	suggestions, _ = service.Suggest("bi", dto.SuggestionTypeSong, 10)
+	assert.Equal(t, []string{"Bicycle"}, suggestionValues(suggestions))
+
+	service.RemoveSong("s1")
+	suggestions, _ = service.Suggest("p", dto.SuggestionTypeInstrument, 10)
+	assert.Empty(t, suggestions)
+	suggestions, _ = service.Suggest("queen", dto.SuggestionTypeAuthor, 10)
+	assert.Equal(t, []string{"Queen"}, suggestionValues(suggestions))
+
+	service.IndexDocument(models.Document{ID: "d3", SongID: "s3", Instrument: []string{"drums"}})
+	suggestions, _ = service.Suggest("dr", dto.SuggestionTypeInstrument, 10)
+	assert.Equal(t, []string{"drums"}, suggestionValues(suggestions))
+
+	service.RemoveDocument("s3", "d3")
+	suggestions, _ = service.Suggest("dr", dto.SuggestionTypeInstrument, 10)
+	assert.Empty(t, suggestions)
+
+	repo.AssertNumberOfCalls(t, "SearchCatalog", 1)
+}
@@ -0,0 +1,11 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// CacheServiceInterface reports on the read-through cache of the song and document repositories.
+type CacheServiceInterface interface {
+
+	// Stats returns the hit and miss counters of the cache since startup.
+	// A disabled cache reports Enabled false and zero counters.
+	Stats() dto.CacheStatsResponse
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+)
+
+// Ensure CacheService implements CacheServiceInterface.
+var _ CacheServiceInterface = (*CacheService)(nil)
+
+// CacheService exposes the metrics of the repository.RepositoryCache shared by the cached repositories.
+type CacheService struct {
+	cache  *repository.RepositoryCache
+	shared bool
+}
+
+// NewCacheService returns a new instance of CacheService. cache is nil when caching is disabled,
+// and shared tells whether it is backed by a repository.SharedCache.
+func NewCacheService(cache *repository.RepositoryCache, shared bool) *CacheService {
+	return &CacheService{cache: cache, shared: shared}
+}
+
+// Stats returns the hit and miss counters of the cache. The hit ratio counts hits from memory and
+// from the shared cache over every read.
+func (s *CacheService) Stats() dto.CacheStatsResponse {
+	if s.cache == nil {
+		return dto.CacheStatsResponse{}
+	}
+
+	stats := s.cache.Stats()
+	response := dto.CacheStatsResponse{
+		Enabled:    true,
+		Shared:     s.shared,
+		Hits:       stats.Hits,
+		SharedHits: stats.SharedHits,
+		Misses:     stats.Misses,
+		Evictions:  stats.Evictions,
+		Entries:    stats.Entries,
+	}
+	if reads := stats.Hits + stats.SharedHits + stats.Misses; reads > 0 {
+		response.HitRatio = float64(stats.Hits+stats.SharedHits) / float64(reads)
+	}
+	return response
+}
@@ -0,0 +1,285 @@
+package services_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memorySharedCache is a repository.SharedCache kept in a map, standing in for Redis.
+type memorySharedCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMemorySharedCache() *memorySharedCache {
+	return &memorySharedCache{values: make(map[string][]byte)}
+}
+
+func (m *memorySharedCache) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	return value, ok, nil
+}
+
+func (m *memorySharedCache) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memorySharedCache) Delete(keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func TestCachedSongRepository_ServesReadsFromCacheAfterFirstCall(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	cache := repository.NewRepositoryCache(100, time.Minute, nil)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+
+	next.On("GetSongByID", "1").Return(&MockedSong, nil).Once()
+	next.On("GetAllSongs").Return([]models.Song{MockedSong}, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		song, err := repo.GetSongByID("1")
+		assert.NoError(t, err)
+		assert.Equal(t, MockedSong, *song)
+
+		songs, err := repo.GetAllSongs()
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Song{MockedSong}, songs)
+	}
+
+	next.AssertExpectations(t)
+	assert.Equal(t, repository.CacheStats{Hits: 4, Misses: 2, Entries: 2}, cache.Stats())
+}
+
+func TestCachedSongRepository_CallersDoNotShareCachedValues(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), repository.NewRepositoryCache(100, time.Minute, nil))
+
+	stored := models.Song{ID: "1", Title: "Bohemian Rhapsody", Genres: []string{"rock"}}
+	next.On("GetSongByID", "1").Return(&stored, nil).Once()
+
+	first, _ := repo.GetSongByID("1")
+	first.Title = "Changed by the caller"
+	first.Genres[0] = "changed"
+
+	second, err := repo.GetSongByID("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bohemian Rhapsody", second.Title)
+	assert.Equal(t, []string{"rock"}, second.Genres)
+}
+
+func TestCachedSongRepository_DoesNotCacheErrors(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	cache := repository.NewRepositoryCache(100, time.Minute, nil)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+
+	next.On("GetSongByID", "missing").Return(nil, errors.ErrResourceNotFound).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetSongByID("missing")
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+	}
+
+	next.AssertExpectations(t)
+	assert.Equal(t, uint64(2), cache.Stats().Misses)
+}
+
+func TestCachedRepositories_InvalidateOnWrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(songs *repository.CachedSongRepository, docs *repository.CachedDocumentRepository, next *mocks.MockSongRepository, nextDocs *mocks.MockDocumentRepository) error
+	}{
+		{
+			name: "song update",
+			write: func(songs *repository.CachedSongRepository, _ *repository.CachedDocumentRepository, next *mocks.MockSongRepository, _ *mocks.MockDocumentRepository) error {
+				next.On("UpdateSong", "song-123", 1, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				return songs.UpdateSong("song-123", 1, map[string]interface{}{"title": "New"}, models.AuditEntry{}, models.Revision{})
+			},
+		},
+		{
+			name: "rejected song update",
+			write: func(songs *repository.CachedSongRepository, _ *repository.CachedDocumentRepository, next *mocks.MockSongRepository, _ *mocks.MockDocumentRepository) error {
+				next.On("UpdateSong", "song-123", 1, mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrPreconditionFailed)
+				return songs.UpdateSong("song-123", 1, map[string]interface{}{"title": "New"}, models.AuditEntry{}, models.Revision{})
+			},
+		},
+		{
+			name: "song trashed with its documents",
+			write: func(songs *repository.CachedSongRepository, _ *repository.CachedDocumentRepository, next *mocks.MockSongRepository, _ *mocks.MockDocumentRepository) error {
+				next.On("TrashSongWithDocuments", "song-123", 1, mock.Anything).Return(nil)
+				return songs.TrashSongWithDocuments("song-123", 1, models.AuditEntry{})
+			},
+		},
+		{
+			name: "document update",
+			write: func(_ *repository.CachedSongRepository, docs *repository.CachedDocumentRepository, _ *mocks.MockSongRepository, nextDocs *mocks.MockDocumentRepository) error {
+				nextDocs.On("UpdateDocument", "song-123", "doc-1", 1, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				return docs.UpdateDocument("song-123", "doc-1", 1, map[string]interface{}{"type": "tablature"}, models.AuditEntry{}, models.Revision{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := new(mocks.MockSongRepository)
+			nextDocs := new(mocks.MockDocumentRepository)
+			cache := repository.NewRepositoryCache(100, time.Minute, nil)
+			docs := repository.NewCachedDocumentRepository(nextDocs, cache)
+			songs := repository.NewCachedSongRepository(next, docs, cache)
+
+			next.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+			nextDocs.On("GetDocumentsBySongID", "song-123").Return([]models.Document{MockedDocument}, nil)
+			nextDocs.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil)
+
+			_, _ = songs.GetSongByID("song-123")
+			_, _ = docs.GetDocumentsBySongID("song-123")
+			_, _ = docs.GetDocumentByID("song-123", "doc-1")
+			before := cache.Stats().Entries
+
+			_ = tt.write(songs, docs, next, nextDocs)
+
+			assert.Less(t, cache.Stats().Entries, before, "the write must drop the entries it affects")
+		})
+	}
+}
+
+func TestCachedRepositories_ReadAfterWriteGoesToStorage(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	cache := repository.NewRepositoryCache(100, time.Minute, nil)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+
+	updated := MockedSong
+	updated.Title = "Bohemian Rhapsody (Live)"
+	updated.Version = 2
+	next.On("GetSongByID", "1").Return(&MockedSong, nil).Once()
+	next.On("UpdateSong", "1", 1, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	next.On("GetSongByID", "1").Return(&updated, nil)
+
+	_, _ = repo.GetSongByID("1")
+	assert.NoError(t, repo.UpdateSong("1", 1, map[string]interface{}{"title": updated.Title}, models.AuditEntry{}, models.Revision{}))
+
+	song, err := repo.GetSongByID("1")
+	assert.NoError(t, err)
+	assert.Equal(t, updated, *song)
+}
+
+func TestRepositoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	cache := repository.NewRepositoryCache(2, time.Minute, nil)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+
+	next.On("GetSongByID", mock.Anything).Return(&MockedSong, nil)
+
+	_, _ = repo.GetSongByID("a")
+	_, _ = repo.GetSongByID("b")
+	_, _ = repo.GetSongByID("a") // hit, "b" becomes the least recently used
+	_, _ = repo.GetSongByID("c") // evicts "b"
+	_, _ = repo.GetSongByID("a") // hit
+	_, _ = repo.GetSongByID("b") // miss
+
+	assert.Equal(t, repository.CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}, cache.Stats())
+}
+
+func TestRepositoryCache_ExpiresEntriesAfterTTL(t *testing.T) {
+	next := new(mocks.MockSongRepository)
+	cache := repository.NewRepositoryCache(100, 20*time.Millisecond, nil)
+	repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+
+	next.On("GetSongByID", "1").Return(&MockedSong, nil).Twice()
+
+	_, _ = repo.GetSongByID("1")
+	_, _ = repo.GetSongByID("1")
+	time.Sleep(30 * time.Millisecond)
+	_, _ = repo.GetSongByID("1")
+
+	next.AssertExpectations(t)
+	assert.Equal(t, uint64(1), cache.Stats().Hits)
+}
+
+func TestRepositoryCache_SharesEntriesBetweenInstances(t *testing.T) {
+	shared := newMemorySharedCache()
+	next := new(mocks.MockDocumentRepository)
+	first := repository.NewCachedDocumentRepository(next, repository.NewRepositoryCache(100, time.Minute, shared))
+	secondCache := repository.NewRepositoryCache(100, time.Minute, shared)
+	second := repository.NewCachedDocumentRepository(next, secondCache)
+
+	next.On("GetDocumentsBySongID", "song-123").Return([]models.Document{MockedDocument}, nil).Once()
+
+	_, err := first.GetDocumentsBySongID("song-123")
+	assert.NoError(t, err)
+	documents, err := second.GetDocumentsBySongID("song-123")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Document{MockedDocument}, documents)
+
+	next.AssertExpectations(t)
+	assert.Equal(t, uint64(1), secondCache.Stats().SharedHits)
+
+	next.On("CreateDocument", mock.Anything, mock.Anything).Return(nil)
+	assert.NoError(t, first.CreateDocument(models.Document{ID: "doc-2", SongID: "song-123"}, models.AuditEntry{}))
+	_, found, _ := shared.Get("documents:song-123")
+	assert.False(t, found, "writes must invalidate the shared cache too")
+}
+
+func TestDocumentService_ReadsSongThroughCache(t *testing.T) {
+	songRepo := new(mocks.MockSongRepository)
+	docRepo := new(mocks.MockDocumentRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProv := new(mocks.MockTimeProvider)
+	cache := repository.NewRepositoryCache(100, time.Minute, nil)
+	service := services.NewDocumentService(docRepo, repository.NewCachedSongRepository(songRepo, docRepo, cache), newMockRevisionRepository(), idGen, timeProv, newMockCatalogIndexer(), newInstrumentService())
+
+	idGen.On("NewID").Return("doc-1")
+	timeProv.On("Now").Return("now")
+	songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil).Once()
+	docRepo.On("CreateDocument", mock.Anything, mock.Anything).Return(nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.CreateDocument(EditorActor, ValidCreateDocumentRequest)
+		assert.NoError(t, err)
+	}
+
+	songRepo.AssertExpectations(t)
+	assert.Equal(t, uint64(2), cache.Stats().Hits)
+}
+
+func TestCacheService_Stats(t *testing.T) {
+	t.Run("disabled cache", func(t *testing.T) {
+		stats := services.NewCacheService(nil, false).Stats()
+		assert.False(t, stats.Enabled)
+	})
+
+	t.Run("hit ratio", func(t *testing.T) {
+		next := new(mocks.MockSongRepository)
+		cache := repository.NewRepositoryCache(100, time.Minute, nil)
+		repo := repository.NewCachedSongRepository(next, new(mocks.MockDocumentRepository), cache)
+		next.On("GetSongByID", "1").Return(&MockedSong, nil).Once()
+		for i := 0; i < 4; i++ {
+			_, _ = repo.GetSongByID("1")
+		}
+
+		stats := services.NewCacheService(cache, false).Stats()
+
+		assert.True(t, stats.Enabled)
+		assert.Equal(t, uint64(3), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.InDelta(t, 0.75, stats.HitRatio, 1e-9)
+	})
+}
@@ -0,0 +1,39 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// RenamedSong was renamed from "Bohemian Rhapsody"; only DriftedDocument still carries the old title.
+var RenamedSong = models.Song{
+	ID:              "song-123",
+	Title:           "Bohemian Rhapsody (Live)",
+	TitleNormalized: "bohemian rhapsody (live)",
+	Author:          "Queen",
+}
+
+var SyncedDocument = models.Document{
+	ID:              "doc-1",
+	SongID:          "song-123",
+	Title:           "Bohemian Rhapsody (Live)",
+	TitleNormalized: "bohemian rhapsody (live)",
+}
+
+var DriftedDocument = models.Document{
+	ID:              "doc-2",
+	SongID:          "song-123",
+	Title:           "Bohemian Rhapsody",
+	TitleNormalized: "bohemian rhapsody",
+}
+
+// UntitledDocument was stored before documents kept the title of their song as written.
+var UntitledDocument = models.Document{
+	ID:              "doc-3",
+	SongID:          "song-123",
+	TitleNormalized: "bohemian rhapsody (live)",
+}
+
+// OrphanDocument belongs to no song the check can see, like the documents of a song still being created.
+var OrphanDocument = models.Document{
+	ID:              "doc-9",
+	SongID:          "song-pending",
+	TitleNormalized: "anything",
+}
@@ -0,0 +1,14 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// ConsistencyServiceInterface checks the data that is denormalized across tables against its source.
+type ConsistencyServiceInterface interface {
+
+	// CheckDocumentTitles compares the title copied to every document with the title of its song,
+	// and with repair set, copies the current title of the song to the documents that drifted.
+	// Returns:
+	//   - the number of documents checked, those that drifted and, with repair, those repaired
+	//   - error if the songs or documents cannot be read, or a repair fails
+	CheckDocumentTitles(repair bool) (dto.TitleConsistencyReport, error)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Ensure ConsistencyService implements ConsistencyServiceInterface.
+var _ ConsistencyServiceInterface = (*ConsistencyService)(nil)
+
+// TitleRepairActor is recorded in the audit trail for document titles repaired by the consistency check.
+var TitleRepairActor = models.Actor{Username: "system:title-repair"}
+
+// ConsistencyService finds and repairs documents whose copy of the song title drifted from the song,
+// which happens when a rename of a song with many documents is interrupted between transactions.
+// songRepo should read from storage: a stale cached song would make a repair revert a rename.
+type ConsistencyService struct {
+	songRepo     repository.SongRepository
+	trashRepo    repository.TrashRepository
+	documentRepo repository.DocumentRepository
+	idGen        utils.IDGenerator
+	timeProvider utils.TimeProvider
+}
+
+// NewConsistencyService returns a new instance of ConsistencyService.
+func NewConsistencyService(
+	songRepo repository.SongRepository,
+	trashRepo repository.TrashRepository,
+	documentRepo repository.DocumentRepository,
+	idGen utils.IDGenerator,
+	timeProvider utils.TimeProvider,
+) *ConsistencyService {
+	return &ConsistencyService{
+		songRepo:     songRepo,
+		trashRepo:    trashRepo,
+		documentRepo: documentRepo,
+		idGen:        idGen,
+		timeProvider: timeProvider,
+	}
+}
+
+// CheckDocumentTitles compares every document, live or trashed, with the song it belongs to. Documents of
+// songs with a write in progress, or of no song at all, are left to ReconcilePendingSongs and not reported.
+// A document drifts when either copy of the title differs from the song, so documents stored before the
+// title was kept as written are reported, and repaired, even when their normalized title is current.
+// A repair re-reads each song first, so a rename made during the check is not undone, and records
+// every document it fixes in the audit trail on behalf of TitleRepairActor.
+func (s *ConsistencyService) CheckDocumentTitles(repair bool) (dto.TitleConsistencyReport, error) {
+	titles, err := s.songTitles()
+	if err != nil {
+		return dto.TitleConsistencyReport{}, err
+	}
+
+	report := dto.TitleConsistencyReport{Drifted: []dto.TitleDrift{}}
+	var drifted []models.Document
+	err = s.documentRepo.ScanDocuments(func(doc models.Document) {
+		title, ok := titles[doc.SongID]
+		if !ok {
+			return
+		}
+		report.Checked++
+		if doc.Title != title || doc.TitleNormalized != utils.Normalize(title) {
+			drifted = append(drifted, doc)
+		}
+	})
+	if err != nil {
+		return dto.TitleConsistencyReport{}, fmt.Errorf("scanning documents: %w", err)
+	}
+
+	sort.Slice(drifted, func(i, j int) bool {
+		if drifted[i].SongID != drifted[j].SongID {
+			return drifted[i].SongID < drifted[j].SongID
+		}
+		return drifted[i].ID < drifted[j].ID
+	})
+	for _, doc := range drifted {
+		report.Drifted = append(report.Drifted, dto.TitleDrift{
+			SongID:     doc.SongID,
+			DocumentID: doc.ID,
+			Expected:   utils.Normalize(titles[doc.SongID]),
+			Actual:     doc.TitleNormalized,
+		})
+	}
+
+	if len(report.Drifted) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"checked":   report.Checked,
+			"drifted":   len(report.Drifted),
+			"operation": "check_document_titles",
+		}).Warn("Document titles drifted from their songs")
+	}
+	if !repair {
+		return report, nil
+	}
+
+	for _, songID := range driftedSongIDs(report.Drifted) {
+		documents := driftedDocuments(drifted, songID)
+		title := titles[songID]
+		if song, err := s.songRepo.GetSongByID(songID); err == nil {
+			title = song.Title
+		} else if !stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return report, fmt.Errorf("retrieving song %s: %w", songID, err)
+		}
+
+		audit := newAuditEntry(s.idGen, TitleRepairActor, s.timeProvider.Now(), models.AuditEntitySong, songID, songID, models.AuditActionUpdate, nil, nil)
+		if err := s.documentRepo.SetDocumentTitles(songID, documents, title, audit); err != nil {
+			return report, fmt.Errorf("repairing document titles of song %s: %w", songID, err)
+		}
+		report.Repaired += len(documents)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"repaired":  report.Repaired,
+		"operation": "repair_document_titles",
+	}).Info("Document titles repaired")
+	return report, nil
+}
+
+// StartConsistencyJob runs CheckDocumentTitles with repair every interval until the returned stop function is called.
+func (s *ConsistencyService) StartConsistencyJob(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.CheckDocumentTitles(true); err != nil {
+					logrus.WithField("operation", "repair_document_titles").WithError(err).Error("Consistency job failed")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// songTitles returns the title of every song, live or trashed, as written, keyed by song ID.
+// Songs with a write in progress are left out.
+func (s *ConsistencyService) songTitles() (map[string]string, error) {
+	songs, err := s.songRepo.GetAllSongs()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving songs: %w", err)
+	}
+	trashed, err := s.trashRepo.GetTrashedSongs()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving trashed songs: %w", err)
+	}
+
+	titles := make(map[string]string, len(songs)+len(trashed))
+	for _, song := range append(songs, trashed...) {
+		if song.Pending == "" {
+			titles[song.ID] = song.Title
+		}
+	}
+	return titles, nil
+}
+
+// driftedSongIDs returns the IDs of the songs in drifted, in order and without repetition.
+func driftedSongIDs(drifted []dto.TitleDrift) []string {
+	var ids []string
+	for i, drift := range drifted {
+		if i == 0 || drifted[i-1].SongID != drift.SongID {
+			ids = append(ids, drift.SongID)
+		}
+	}
+	return ids
+}
+
+// driftedDocuments returns the documents of songID in drifted.
+func driftedDocuments(drifted []models.Document, songID string) []models.Document {
+	var documents []models.Document
+	for _, doc := range drifted {
+		if doc.SongID == songID {
+			documents = append(documents, doc)
+		}
+	}
+	return documents
+}
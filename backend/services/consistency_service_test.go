@@ -0,0 +1,167 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type consistencyServiceMocks struct {
+	songRepo     *mocks.MockSongRepository
+	trashRepo    *mocks.MockTrashRepository
+	documentRepo *mocks.MockDocumentRepository
+}
+
+var driftedDocuments = []models.Document{DriftedDocument}
+
+var titleRepairAudit = models.AuditEntry{
+	ID:        "audit-1",
+	Entity:    models.AuditEntitySong,
+	EntityID:  "song-123",
+	SongID:    "song-123",
+	Action:    models.AuditActionUpdate,
+	Actor:     services.TitleRepairActor.Username,
+	Timestamp: "2025-01-02T00:00:00Z",
+	Changes:   []models.FieldChange{},
+}
+
+func setupConsistencyServiceTest() (*services.ConsistencyService, consistencyServiceMocks) {
+	m := consistencyServiceMocks{
+		songRepo:     new(mocks.MockSongRepository),
+		trashRepo:    new(mocks.MockTrashRepository),
+		documentRepo: new(mocks.MockDocumentRepository),
+	}
+	idGen := new(mocks.MockIDGenerator)
+	idGen.On("NewID").Return("audit-1").Maybe()
+	timeProvider := new(mocks.MockTimeProvider)
+	timeProvider.On("Now").Return("2025-01-02T00:00:00Z").Maybe()
+	service := services.NewConsistencyService(m.songRepo, m.trashRepo, m.documentRepo, idGen, timeProvider)
+	return service, m
+}
+
+func TestCheckDocumentTitles_ReportsDriftWithoutRepairing(t *testing.T) {
+	service, m := setupConsistencyServiceTest()
+	pending := PendingTrashSong
+	pending.ID = "song-pending"
+	m.songRepo.On("GetAllSongs").Return([]models.Song{RenamedSong}, nil)
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{pending}, nil)
+	m.documentRepo.On("ScanDocuments").Return([]models.Document{SyncedDocument, DriftedDocument, OrphanDocument}, nil)
+
+	report, err := service.CheckDocumentTitles(false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Checked, "documents of pending or unknown songs are not checked")
+	assert.Equal(t, []dto.TitleDrift{{
+		SongID:     "song-123",
+		DocumentID: "doc-2",
+		Expected:   "bohemian rhapsody (live)",
+		Actual:     "bohemian rhapsody",
+	}}, report.Drifted)
+	assert.Zero(t, report.Repaired)
+	m.documentRepo.AssertNotCalled(t, "SetDocumentTitles")
+}
+
+func TestCheckDocumentTitles_RepairsWithTheCurrentTitle(t *testing.T) {
+	service, m := setupConsistencyServiceTest()
+	renamedAgain := RenamedSong
+	renamedAgain.Title = "Bohemian Rhapsody (Remastered)"
+	renamedAgain.TitleNormalized = "bohemian rhapsody (remastered)"
+	m.songRepo.On("GetAllSongs").Return([]models.Song{RenamedSong}, nil)
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{}, nil)
+	m.documentRepo.On("ScanDocuments").Return([]models.Document{SyncedDocument, DriftedDocument}, nil)
+	m.songRepo.On("GetSongByID", "song-123").Return(&renamedAgain, nil)
+	m.documentRepo.On("SetDocumentTitles", "song-123", driftedDocuments, "Bohemian Rhapsody (Remastered)", titleRepairAudit).Return(nil)
+
+	report, err := service.CheckDocumentTitles(true)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifted, 1)
+	assert.Equal(t, 1, report.Repaired)
+	m.documentRepo.AssertExpectations(t)
+}
+
+func TestCheckDocumentTitles_RepairsDocumentsWithoutTheTitleAsWritten(t *testing.T) {
+	service, m := setupConsistencyServiceTest()
+	m.songRepo.On("GetAllSongs").Return([]models.Song{RenamedSong}, nil)
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{}, nil)
+	m.documentRepo.On("ScanDocuments").Return([]models.Document{SyncedDocument, UntitledDocument}, nil)
+	m.songRepo.On("GetSongByID", "song-123").Return(&RenamedSong, nil)
+	m.documentRepo.On("SetDocumentTitles", "song-123", []models.Document{UntitledDocument}, "Bohemian Rhapsody (Live)", titleRepairAudit).Return(nil)
+
+	report, err := service.CheckDocumentTitles(true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.TitleDrift{{
+		SongID:     "song-123",
+		DocumentID: "doc-3",
+		Expected:   "bohemian rhapsody (live)",
+		Actual:     "bohemian rhapsody (live)",
+	}}, report.Drifted)
+	assert.Equal(t, 1, report.Repaired)
+	m.documentRepo.AssertExpectations(t)
+}
+
+func TestCheckDocumentTitles_RepairsTrashedSongsFromTheScan(t *testing.T) {
+	service, m := setupConsistencyServiceTest()
+	trashed := RenamedSong
+	trashed.DeletedAt = "2025-01-01T00:00:00Z"
+	m.songRepo.On("GetAllSongs").Return([]models.Song{}, nil)
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{trashed}, nil)
+	m.documentRepo.On("ScanDocuments").Return([]models.Document{DriftedDocument}, nil)
+	m.songRepo.On("GetSongByID", "song-123").Return(nil, errors.ErrResourceNotFound)
+	m.documentRepo.On("SetDocumentTitles", "song-123", driftedDocuments, "Bohemian Rhapsody (Live)", titleRepairAudit).Return(nil)
+
+	report, err := service.CheckDocumentTitles(true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Repaired)
+	m.documentRepo.AssertExpectations(t)
+}
+
+func TestCheckDocumentTitles_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m consistencyServiceMocks)
+	}{
+		{
+			name: "songs cannot be read",
+			setup: func(m consistencyServiceMocks) {
+				m.songRepo.On("GetAllSongs").Return([]models.Song{}, errors.ErrInternalServer)
+			},
+		},
+		{
+			name: "documents cannot be scanned",
+			setup: func(m consistencyServiceMocks) {
+				m.songRepo.On("GetAllSongs").Return([]models.Song{RenamedSong}, nil)
+				m.trashRepo.On("GetTrashedSongs").Return([]models.Song{}, nil)
+				m.documentRepo.On("ScanDocuments").Return([]models.Document{}, errors.ErrInternalServer)
+			},
+		},
+		{
+			name: "repair fails",
+			setup: func(m consistencyServiceMocks) {
+				m.songRepo.On("GetAllSongs").Return([]models.Song{RenamedSong}, nil)
+				m.trashRepo.On("GetTrashedSongs").Return([]models.Song{}, nil)
+				m.documentRepo.On("ScanDocuments").Return([]models.Document{DriftedDocument}, nil)
+				m.songRepo.On("GetSongByID", "song-123").Return(&RenamedSong, nil)
+				m.documentRepo.On("SetDocumentTitles", "song-123", driftedDocuments, "Bohemian Rhapsody (Live)", titleRepairAudit).Return(errors.ErrInternalServer)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupConsistencyServiceTest()
+			tt.setup(m)
+
+			_, err := service.CheckDocumentTitles(true)
+
+			assert.ErrorIs(t, err, errors.ErrInternalServer)
+		})
+	}
+}
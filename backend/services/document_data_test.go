@@ -19,12 +19,27 @@ var InvalidCreateDocumentRequest_EmptyInstrument = dto.CreateDocumentRequest{
 	SongID:     "song-123",
 }
 
+var CreateDocumentRequest_InstrumentAliases = dto.CreateDocumentRequest{
+	Type:       "score",
+	Instrument: []string{"Guitarra", "voz", "Guitar"},
+	PDFURL:     "https://example.com/bohemian-guitar.pdf",
+	SongID:     "song-123",
+}
+
+var InvalidCreateDocumentRequest_UnknownInstrument = dto.CreateDocumentRequest{
+	Type:       "score",
+	Instrument: []string{"theremin"},
+	PDFURL:     "https://example.com/invalid.pdf",
+	SongID:     "song-123",
+}
+
 var MockedDocument = models.Document{
 	ID:              "doc-1",
 	SongID:          "song-123",
 	Type:            "score",
 	Instrument:      []string{"piano"},
 	PDFURL:          "https://example.com/bohemian-piano.pdf",
+	Title:           "Bohemian Rhapsody",
 	TitleNormalized: "bohemian rhapsody",
 	CreatedAt:       "now",
 	UpdatedAt:       "now",
@@ -33,6 +48,7 @@ var MockedDocument = models.Document{
 var DocumentResponse = dto.DocumentResponseItem{
 	ID:         "doc-1",
 	SongID:     "song-123",
+	Title:      "Bohemian Rhapsody",
 	Type:       "score",
 	Instrument: []string{"piano"},
 	PDFURL:     "https://example.com/bohemian-piano.pdf",
@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 )
 
 // DocumentServiceInterface defines application-level operations for managing musical documents (scores or tablatures) associated with a song.
@@ -12,13 +13,15 @@ type DocumentServiceInterface interface {
 	// Returns:
 	//   - the generated document ID on success
 	//   - error if creation fails
-	CreateDocument(document dto.CreateDocumentRequest) (string, error)
+	CreateDocument(actor models.Actor, document dto.CreateDocumentRequest) (string, error)
 
-	// GetDocumentsBySongID retrieves all documents linked to the specified song ID.
+	// ListDocuments returns one page of the documents linked to the specified song ID, selected and sorted by query.
 	// Returns:
-	//   - ([]DocumentResponseItem, nil) on success
-	//   - (nil, error) if the retrieval fails
-	GetDocumentsBySongID(songID string) ([]dto.DocumentResponseItem, error)
+	//   - (dto.DocumentListResponse, nil) on success
+	//   - errors.ErrValidationFailed if the limit, sort or order is invalid
+	//   - errors.ErrBadRequest if the cursor was not issued for this listing
+	//   - error if the retrieval fails
+	ListDocuments(songID string, query dto.ListQuery) (dto.DocumentListResponse, error)
 
 	// GetDocumentByID retrieves a single document by song ID and document ID.
 	// Returns:
@@ -27,16 +30,19 @@ type DocumentServiceInterface interface {
 	//   - (nil, error) for unexpected errors
 	GetDocumentByID(songID string, docID string) (dto.DocumentResponseItem, error)
 
-	// UpdateDocument applies partial updates to a document identified by song ID and document ID.
-	// Also updates the 'updated_at' timestamp.
+	// UpdateDocument applies partial updates to a document identified by song ID and document ID
+	// if the document is at a version allowed by ifMatch. Also updates the 'updated_at' timestamp.
 	// Returns:
-	//   - nil on success
+	//   - (new version, nil) on success
+	//   - errors.ErrPreconditionFailed if the document is not at a version allowed by ifMatch
 	//   - error if the update operation fails
-	UpdateDocument(songID string, docID string, updates dto.UpdateDocumentRequest) error
+	UpdateDocument(actor models.Actor, songID string, docID string, updates dto.UpdateDocumentRequest, ifMatch dto.Precondition) (int, error)
 
-	// DeleteDocument removes a document by song ID and document ID.
+	// DeleteDocument moves a document identified by song ID and document ID to the trash
+	// if the document is at a version allowed by ifMatch.
 	// Returns:
 	//   - nil on success
+	//   - errors.ErrPreconditionFailed if the document is not at a version allowed by ifMatch
 	//   - error if the deletion fails
-	DeleteDocument(songID string, docID string) error
+	DeleteDocument(actor models.Actor, songID string, docID string, ifMatch dto.Precondition) error
 }
@@ -4,17 +4,24 @@ import (
 	"fmt"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 )
 
 // DocumentService provides application-level operations for managing musical documents
 // such as scores and tablatures, in relation to songs.
+// Every change is recorded in the audit trail together with the actor that requested it,
+// and every update keeps the version it replaces as a revision of the song.
 type DocumentService struct {
 	repo         repository.DocumentRepository
 	songRepo     repository.SongRepository
+	revisionRepo repository.RevisionRepository
 	idGen        utils.IDGenerator
 	timeProvider utils.TimeProvider
+	indexer      CatalogIndexer
+	instruments  InstrumentServiceInterface
 }
 
 // Ensure DocumentService implements DocumentServiceInterface.
@@ -24,54 +31,82 @@ var _ DocumentServiceInterface = (*DocumentService)(nil)
 func NewDocumentService(
 	repo repository.DocumentRepository,
 	songRepo repository.SongRepository,
+	revisionRepo repository.RevisionRepository,
 	idGen utils.IDGenerator,
 	timeProvider utils.TimeProvider,
+	indexer CatalogIndexer,
+	instruments InstrumentServiceInterface,
 ) *DocumentService {
 	return &DocumentService{
 		repo:         repo,
 		songRepo:     songRepo,
+		revisionRepo: revisionRepo,
 		idGen:        idGen,
 		timeProvider: timeProvider,
+		indexer:      indexer,
+		instruments:  instruments,
 	}
 }
 
 // CreateDocument creates and stores a new document linked to a song.
-// It normalizes the song's title, assigns a UUID, and sets timestamps.
+// It normalizes the song's title, maps instruments to their canonical IDs, assigns a UUID, and sets timestamps.
 // Returns:
 //   - the generated document ID on success
+//   - errors.ErrValidationFailed if an instrument is not part of the catalogue
 //   - error if the song is not found or document creation fails
-func (s *DocumentService) CreateDocument(req dto.CreateDocumentRequest) (string, error) {
+func (s *DocumentService) CreateDocument(actor models.Actor, req dto.CreateDocumentRequest) (string, error) {
 	document := dto.ToDocumentModel(req)
 
+	instruments, err := s.instruments.NormalizeInstruments(document.Instrument)
+	if err != nil {
+		return "", fmt.Errorf("validating instruments: %w", err)
+	}
+	document.Instrument = instruments
+
 	song, err := s.songRepo.GetSongByID(document.SongID)
 	if err != nil {
 		return "", fmt.Errorf("retrieving song for document creation (song_id=%s): %w", document.SongID, err)
 	}
 
+	document.Title = song.Title
 	document.TitleNormalized = utils.Normalize(song.Title)
 	document.ID = s.idGen.NewID()
 	now := s.timeProvider.Now()
 	document.CreatedAt = now
 	document.UpdatedAt = now
+	document.Version = 1
 
-	if err := s.repo.CreateDocument(document); err != nil {
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntityDocument, document.ID, document.SongID, models.AuditActionCreate, nil, &document)
+	if err := s.repo.CreateDocument(document, audit); err != nil {
 		return "", fmt.Errorf("creating document %s: %w", document.ID, err)
 	}
 
+	s.indexer.IndexDocument(document)
 	return document.ID, nil
 }
 
-// GetDocumentsBySongID returns all documents associated with the specified song ID.
+// ListDocuments returns one page of the documents associated with the specified song ID.
+// The limit defaults to dto.DefaultListLimit and the order to ascending.
 // Returns:
-//   - ([]dto.DocumentResponseItem, nil) on success
-//   - (nil, error) if the retrieval fails
-func (s *DocumentService) GetDocumentsBySongID(songID string) ([]dto.DocumentResponseItem, error) {
-	documents, err := s.repo.GetDocumentsBySongID(songID)
+//   - (dto.DocumentListResponse, nil) on success
+//   - errors.ErrValidationFailed if the query is invalid
+//   - errors.ErrBadRequest if the cursor is invalid
+//   - error if the retrieval fails
+func (s *DocumentService) ListDocuments(songID string, query dto.ListQuery) (dto.DocumentListResponse, error) {
+	if err := dto.ValidateListQuery(query, dto.DocumentSortFields); err != nil {
+		return dto.DocumentListResponse{}, fmt.Errorf("validating documents query: %w", err)
+	}
+
+	documents, next, err := s.repo.ListDocumentsBySongID(songID, listOptions(query))
 	if err != nil {
-		return nil, fmt.Errorf("retrieving documents for song %s: %w", songID, err)
+		return dto.DocumentListResponse{}, fmt.Errorf("listing documents for song %s: %w", songID, err)
 	}
 
-	return dto.ToDocumentResponseList(documents), nil
+	return dto.DocumentListResponse{
+		Data:      dto.ToDocumentResponseList(documents),
+		Count:     len(documents),
+		NextToken: next,
+	}, nil
 }
 
 // GetDocumentByID retrieves a document by its song ID and document ID.
@@ -87,66 +122,121 @@ func (s *DocumentService) GetDocumentByID(songID string, docID string) (dto.Docu
 	return dto.ToDocumentResponseItem(*doc), nil
 }
 
-// UpdateDocument applies updates to a document and refreshes the title_normalized and updated_at fields.
-// If title_normalized is not explicitly provided, it is recalculated from the song's title.
+// UpdateDocument applies updates to a document and refreshes the title, title_normalized and updated_at fields.
+// If title_normalized is not explicitly provided, both titles are copied again from the song.
+// Instruments are mapped to their canonical IDs. The changed fields are recorded in the audit trail
+// and the replaced version, including its PDF link, is kept as a revision of the song.
+// The document must be at a version allowed by ifMatch, both when it is read and when it is written.
 // Returns:
-//   - nil on success
+//   - (new version, nil) on success
 //   - errors.ErrResourceNotFound if the document does not exist
+//   - errors.ErrValidationFailed if an instrument is not part of the catalogue
+//   - errors.ErrPreconditionFailed if the document is not, or no longer, at a version allowed by ifMatch
+//   - errors.ErrConflict if a concurrent update took the same revision number
 //   - error if the update fails or the song does not exist
-func (s *DocumentService) UpdateDocument(songID, docID string, updates dto.UpdateDocumentRequest) error {
+func (s *DocumentService) UpdateDocument(actor models.Actor, songID, docID string, updates dto.UpdateDocumentRequest, ifMatch dto.Precondition) (int, error) {
 
 	song, err := s.songRepo.GetSongByID(songID)
 	if err != nil {
-		return fmt.Errorf("retrieving song for update of document %s: %w", docID, err)
+		return 0, fmt.Errorf("retrieving song for update of document %s: %w", docID, err)
 	}
 
-	_, err = s.repo.GetDocumentByID(songID, docID)
+	doc, err := documentAt(s.repo, songID, docID, ifMatch)
 	if err != nil {
-		return fmt.Errorf("checking existence of document %s: %w", docID, err)
+		return 0, err
 	}
 
+	updated := *doc
 	updateMap := make(map[string]interface{})
 
 	if updates.Type != "" {
 		updateMap["type"] = updates.Type
+		updated.Type = updates.Type
 	}
 	if len(updates.Instrument) > 0 {
-		updateMap["instrument"] = updates.Instrument
+		instruments, err := s.instruments.NormalizeInstruments(updates.Instrument)
+		if err != nil {
+			return 0, fmt.Errorf("validating instruments: %w", err)
+		}
+		updateMap["instrument"] = instruments
+		updated.Instrument = instruments
 	}
 	if updates.PDFURL != "" {
 		updateMap["pdf_url"] = updates.PDFURL
+		updated.PDFURL = updates.PDFURL
 	}
 	if updates.AudioURL != "" {
 		updateMap["audio_url"] = updates.AudioURL
+		updated.AudioURL = updates.AudioURL
 	}
 
 	if _, ok := updateMap["title_normalized"]; !ok {
 
+		updateMap["title"] = song.Title
 		updateMap["title_normalized"] = utils.Normalize(song.Title)
 	}
 
-	updateMap["updated_at"] = s.timeProvider.Now()
+	now := s.timeProvider.Now()
+	updateMap["updated_at"] = now
+	updated.UpdatedAt = now
+	updated.Version = doc.Version + 1
 
-	if err := s.repo.UpdateDocument(songID, docID, updateMap); err != nil {
-		return fmt.Errorf("updating document %s for song %s: %w", docID, songID, err)
+	revision, err := newRevision(s.revisionRepo, actor, now, songID)
+	if err != nil {
+		return 0, err
 	}
+	revision.Entity = models.AuditEntityDocument
+	revision.EntityID = docID
+	revision.Document = doc
 
-	return nil
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntityDocument, docID, songID, models.AuditActionUpdate, doc, &updated)
+	if err := s.repo.UpdateDocument(songID, docID, doc.Version, updateMap, audit, revision); err != nil {
+		return 0, fmt.Errorf("updating document %s for song %s: %w", docID, songID, err)
+	}
+
+	s.indexer.IndexDocument(updated)
+	return updated.Version, nil
 }
 
-// DeleteDocument deletes a document identified by song ID and document ID.
+// documentAt returns the document to be written if it is at a version allowed by ifMatch. A cached
+// copy that does not match may be stale, so the document is read again from storage before refusing.
+func documentAt(repo repository.DocumentRepository, songID, docID string, ifMatch dto.Precondition) (*models.Document, error) {
+	doc, err := repo.GetDocumentByID(songID, docID)
+	if err != nil {
+		return nil, fmt.Errorf("checking existence of document %s: %w", docID, err)
+	}
+	if ifMatch.Matches(doc.Version) {
+		return doc, nil
+	}
+
+	doc, err = repo.GetDocumentByIDUncached(songID, docID)
+	if err != nil {
+		return nil, fmt.Errorf("checking existence of document %s: %w", docID, err)
+	}
+	if !ifMatch.Matches(doc.Version) {
+		return nil, fmt.Errorf("document %s is at version %d: %w", docID, doc.Version, errors.ErrPreconditionFailed)
+	}
+	return doc, nil
+}
+
+// DeleteDocument moves a document identified by song ID and document ID to the trash.
+// The audit entry keeps the last values of the document. The document must be at a version allowed by ifMatch.
 // Returns:
 //   - nil on success
 //   - errors.ErrResourceNotFound if the document does not exist
+//   - errors.ErrPreconditionFailed if the document is not, or no longer, at a version allowed by ifMatch
 //   - error if the deletion fails
-func (s *DocumentService) DeleteDocument(songID string, docID string) error {
-	_, err := s.repo.GetDocumentByID(songID, docID)
+func (s *DocumentService) DeleteDocument(actor models.Actor, songID string, docID string, ifMatch dto.Precondition) error {
+	doc, err := documentAt(s.repo, songID, docID, ifMatch)
 	if err != nil {
-		return fmt.Errorf("checking existence of document %s: %w", docID, err)
+		return err
 	}
 
-	if err := s.repo.DeleteDocument(songID, docID); err != nil {
-		return fmt.Errorf("deleting document %s for song %s: %w", docID, songID, err)
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntityDocument, docID, songID, models.AuditActionDelete, doc, nil)
+	if err := s.repo.TrashDocument(songID, docID, doc.Version, audit); err != nil {
+		return fmt.Errorf("trashing document %s for song %s: %w", docID, songID, err)
 	}
+
+	s.indexer.RemoveDocument(songID, docID)
 	return nil
 }
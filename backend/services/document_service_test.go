@@ -7,6 +7,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,7 +18,7 @@ func setupDocumentServiceTest() (*services.DocumentService, *mocks.MockDocumentR
 	songRepo := new(mocks.MockSongRepository)
 	idGen := new(mocks.MockIDGenerator)
 	timeProv := new(mocks.MockTimeProvider)
-	service := services.NewDocumentService(docRepo, songRepo, idGen, timeProv)
+	service := services.NewDocumentService(docRepo, songRepo, newMockRevisionRepository(), idGen, timeProv, newMockCatalogIndexer(), newInstrumentService())
 	return service, docRepo, songRepo, idGen, timeProv
 }
 
@@ -38,6 +39,12 @@ func TestCreateDocument(t *testing.T) {
 			mockDocErr:  nil,
 			expectError: false,
 		},
+		{
+			name:        "unknown instrument",
+			request:     InvalidCreateDocumentRequest_UnknownInstrument,
+			mockSong:    &RelatedSong,
+			expectError: true,
+		},
 		{
 			name:        "song not found",
 			request:     ValidCreateDocumentRequest,
@@ -68,10 +75,10 @@ func TestCreateDocument(t *testing.T) {
 			}
 
 			if tt.mockSongErr == nil {
-				docRepo.On("CreateDocument", mock.Anything).Return(tt.mockDocErr)
+				docRepo.On("CreateDocument", mock.Anything, mock.Anything).Return(tt.mockDocErr)
 			}
 
-			_, err := service.CreateDocument(tt.request)
+			_, err := service.CreateDocument(EditorActor, tt.request)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -82,36 +89,68 @@ func TestCreateDocument(t *testing.T) {
 	}
 }
 
-func TestGetDocumentsBySongID(t *testing.T) {
+func TestCreateDocument_NormalizesInstruments(t *testing.T) {
+	service, docRepo, songRepo, idGen, timeProv := setupDocumentServiceTest()
+
+	idGen.On("NewID").Return("doc-1")
+	timeProv.On("Now").Return("now")
+	songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+	docRepo.On("CreateDocument", mock.MatchedBy(func(d models.Document) bool {
+		return assert.ObjectsAreEqual([]string{"guitar", "voice"}, d.Instrument)
+	}), mock.Anything).Return(nil)
+
+	_, err := service.CreateDocument(EditorActor, CreateDocumentRequest_InstrumentAliases)
+
+	assert.NoError(t, err)
+	docRepo.AssertExpectations(t)
+}
+
+func TestDocumentService_ListDocuments(t *testing.T) {
 	tests := []struct {
 		name           string
 		songID         string
+		query          dto.ListQuery
+		expectedOpts   repository.ListOptions
 		mockDocs       []models.Document
+		mockNext       string
 		mockError      error
-		expectError    bool
-		expectedResult []dto.DocumentResponseItem
+		expectError    error
+		expectedResult dto.DocumentListResponse
 	}{
 		{
 			name:           "documents found",
 			songID:         "song-123",
+			expectedOpts:   repository.ListOptions{Limit: dto.DefaultListLimit},
 			mockDocs:       []models.Document{MockedDocument},
-			mockError:      nil,
-			expectError:    false,
-			expectedResult: []dto.DocumentResponseItem{DocumentResponse},
+			mockNext:       "next",
+			expectedResult: dto.DocumentListResponse{Data: []dto.DocumentResponseItem{DocumentResponse}, Count: 1, NextToken: "next"},
 		},
 		{
 			name:           "no documents found",
 			songID:         "song-456",
+			query:          dto.ListQuery{Limit: 10, Sort: "type", Order: "desc"},
+			expectedOpts:   repository.ListOptions{Limit: 10, Sort: "type", Order: repository.SortOrderDesc},
 			mockDocs:       []models.Document{},
-			mockError:      nil,
-			expectError:    false,
-			expectedResult: []dto.DocumentResponseItem{},
+			expectedResult: dto.DocumentListResponse{Data: []dto.DocumentResponseItem{}},
 		},
 		{
-			name:        "repository error",
-			songID:      "song-789",
-			mockError:   errors.ErrInternalServer,
-			expectError: true,
+			name:         "repository error",
+			songID:       "song-789",
+			expectedOpts: repository.ListOptions{Limit: dto.DefaultListLimit},
+			mockError:    errors.ErrInternalServer,
+			expectError:  errors.ErrInternalServer,
+		},
+		{
+			name:        "unknown sort field",
+			songID:      "song-123",
+			query:       dto.ListQuery{Sort: "title"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "negative limit",
+			songID:      "song-123",
+			query:       dto.ListQuery{Limit: -1},
+			expectError: errors.ErrValidationFailed,
 		},
 	}
 
@@ -119,16 +158,19 @@ func TestGetDocumentsBySongID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service, docRepo, _, _, _ := setupDocumentServiceTest()
 
-			docRepo.On("GetDocumentsBySongID", tt.songID).Return(tt.mockDocs, tt.mockError)
+			if tt.expectError != errors.ErrValidationFailed {
+				docRepo.On("ListDocumentsBySongID", tt.songID, tt.expectedOpts).Return(tt.mockDocs, tt.mockNext, tt.mockError)
+			}
 
-			result, err := service.GetDocumentsBySongID(tt.songID)
+			result, err := service.ListDocuments(tt.songID, tt.query)
 
-			if tt.expectError {
-				assert.Error(t, err)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResult, result)
 			}
+			docRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -252,8 +294,9 @@ func TestUpdateDocument(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, docRepo, songRepo, _, timeProv := setupDocumentServiceTest()
+			service, docRepo, songRepo, idGen, timeProv := setupDocumentServiceTest()
 
+			idGen.On("NewID").Return("audit-1").Maybe()
 			timeProv.On("Now").Return("now")
 
 			songRepo.On("GetSongByID", tt.songID).Return(tt.mockSong, tt.mockSongErr)
@@ -263,11 +306,11 @@ func TestUpdateDocument(t *testing.T) {
 					docRepo.On("GetDocumentByID", tt.songID, tt.docID).Return(nil, errors.ErrResourceNotFound)
 				} else {
 					docRepo.On("GetDocumentByID", tt.songID, tt.docID).Return(&MockedDocument, nil)
-					docRepo.On("UpdateDocument", tt.songID, tt.docID, mock.Anything).Return(tt.mockUpdateErr)
+					docRepo.On("UpdateDocument", tt.songID, tt.docID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.mockUpdateErr)
 				}
 			}
 
-			err := service.UpdateDocument(tt.songID, tt.docID, tt.updates)
+			_, err := service.UpdateDocument(EditorActor, tt.songID, tt.docID, tt.updates, dto.Precondition{})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -315,15 +358,17 @@ func TestDeleteDocument(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, docRepo, _, _, _ := setupDocumentServiceTest()
+			service, docRepo, _, idGen, timeProv := setupDocumentServiceTest()
 
+			idGen.On("NewID").Return("audit-1").Maybe()
+			timeProv.On("Now").Return("now").Maybe()
 			docRepo.On("GetDocumentByID", tt.songID, tt.docID).Return(&MockedDocument, tt.mockGetDocErr)
 
 			if tt.mockGetDocErr == nil {
-				docRepo.On("DeleteDocument", tt.songID, tt.docID).Return(tt.mockDeleteDocErr)
+				docRepo.On("TrashDocument", tt.songID, tt.docID, mock.Anything, mock.Anything).Return(tt.mockDeleteDocErr)
 			}
 
-			err := service.DeleteDocument(tt.songID, tt.docID)
+			err := service.DeleteDocument(EditorActor, tt.songID, tt.docID, dto.Precondition{})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -333,3 +378,130 @@ func TestDeleteDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentService_RecordsAuditEntries(t *testing.T) {
+	service, docRepo, songRepo, idGen, timeProv := setupDocumentServiceTest()
+
+	idGen.On("NewID").Return("doc-1")
+	timeProv.On("Now").Return("later")
+	songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+	docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil)
+
+	var entries []models.AuditEntry
+	record := func(args mock.Arguments) {
+		for _, arg := range args {
+			if entry, ok := arg.(models.AuditEntry); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	docRepo.On("CreateDocument", mock.Anything, mock.Anything, mock.Anything).Run(record).Return(nil)
+	docRepo.On("UpdateDocument", "song-123", "doc-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(record).Return(nil)
+	docRepo.On("TrashDocument", "song-123", "doc-1", mock.Anything, mock.Anything).Run(record).Return(nil)
+
+	_, err := service.CreateDocument(EditorActor, ValidCreateDocumentRequest)
+	assert.NoError(t, err)
+	_, err = service.UpdateDocument(EditorActor, "song-123", "doc-1", ValidUpdateDocumentRequestPDFAndAudio, dto.Precondition{})
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteDocument(EditorActor, "song-123", "doc-1", dto.Precondition{}))
+
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+	for _, entry := range entries {
+		assert.Equal(t, models.AuditEntityDocument, entry.Entity)
+		assert.Equal(t, "doc-1", entry.EntityID)
+		assert.Equal(t, "song-123", entry.SongID)
+		assert.Equal(t, "maria", entry.Actor)
+		assert.Equal(t, "req-1", entry.RequestID)
+	}
+
+	assert.Equal(t, models.AuditActionCreate, entries[0].Action)
+	assert.Contains(t, entries[0].Changes, models.FieldChange{Field: "pdf_url", After: ValidCreateDocumentRequest.PDFURL})
+
+	assert.Equal(t, models.AuditActionUpdate, entries[1].Action)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "audio_url", After: ValidUpdateDocumentRequestPDFAndAudio.AudioURL},
+		{Field: "pdf_url", Before: MockedDocument.PDFURL, After: ValidUpdateDocumentRequestPDFAndAudio.PDFURL},
+	}, entries[1].Changes)
+
+	assert.Equal(t, models.AuditActionDelete, entries[2].Action)
+	assert.Contains(t, entries[2].Changes, models.FieldChange{Field: "type", Before: "score"})
+}
+
+func TestDocumentService_KeepsRevisions(t *testing.T) {
+	docRepo := new(mocks.MockDocumentRepository)
+	songRepo := new(mocks.MockSongRepository)
+	revisionRepo := new(mocks.MockRevisionRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProv := new(mocks.MockTimeProvider)
+	service := services.NewDocumentService(docRepo, songRepo, revisionRepo, idGen, timeProv, newMockCatalogIndexer(), newInstrumentService())
+
+	idGen.On("NewID").Return("id")
+	timeProv.On("Now").Return("later")
+	songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+	docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil)
+	revisionRepo.On("GetLatestRevisionNumber", "song-123").Return(0, nil)
+
+	var kept models.Revision
+	docRepo.On("UpdateDocument", "song-123", "doc-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		kept = args.Get(5).(models.Revision)
+	}).Return(nil)
+
+	_, err := service.UpdateDocument(EditorActor, "song-123", "doc-1", ValidUpdateDocumentRequestPDFAndAudio, dto.Precondition{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, kept.Revision)
+	assert.Equal(t, models.AuditEntityDocument, kept.Entity)
+	assert.Equal(t, "doc-1", kept.EntityID)
+	assert.Equal(t, MockedDocument.PDFURL, kept.Document.PDFURL, "the replaced PDF link is kept")
+}
+
+func TestDocumentService_HonoursIfMatch(t *testing.T) {
+	versioned := MockedDocument
+	versioned.Version = 2
+	stale := MockedDocument
+	stale.Version = 1
+
+	tests := []struct {
+		name        string
+		ifMatch     dto.Precondition
+		cached      *models.Document
+		expectError error
+	}{
+		{name: "any version", ifMatch: dto.NewPrecondition("*")},
+		{name: "one of the listed versions", ifMatch: dto.NewPrecondition(`"1", "2"`)},
+		{name: "weak tag never matches", ifMatch: dto.NewPrecondition(`W/"2"`), expectError: errors.ErrPreconditionFailed},
+		{name: "stale version", ifMatch: dto.NewPrecondition(`"1"`), expectError: errors.ErrPreconditionFailed},
+		{name: "stale cached copy", ifMatch: dto.NewPrecondition(`"2"`), cached: &stale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, docRepo, songRepo, idGen, timeProv := setupDocumentServiceTest()
+			idGen.On("NewID").Return("audit-1")
+			timeProv.On("Now").Return("now")
+			songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+			cached := &versioned
+			if tt.cached != nil {
+				cached = tt.cached
+			}
+			docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(cached, nil)
+			docRepo.On("GetDocumentByIDUncached", "song-123", "doc-1").Return(&versioned, nil).Maybe()
+			docRepo.On("UpdateDocument", "song-123", "doc-1", 2, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			docRepo.On("TrashDocument", "song-123", "doc-1", 2, mock.Anything).Return(nil).Maybe()
+
+			version, err := service.UpdateDocument(EditorActor, "song-123", "doc-1", ValidUpdateDocumentRequestPDFAndAudio, tt.ifMatch)
+			deleteErr := service.DeleteDocument(EditorActor, "song-123", "doc-1", tt.ifMatch)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.ErrorIs(t, deleteErr, tt.expectError)
+				docRepo.AssertNotCalled(t, "UpdateDocument", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, deleteErr)
+			assert.Equal(t, 3, version)
+		})
+	}
+}
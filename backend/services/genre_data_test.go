@@ -0,0 +1,17 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+var GenreRock = models.Genre{ID: "g-rock", Name: "Rock", NameNormalized: "rock"}
+var GenreProgressive = models.Genre{ID: "g-prog", Name: "Progressive", NameNormalized: "progressive", ParentID: "g-rock"}
+var GenreFolk = models.Genre{ID: "g-folk", Name: "Folk", NameNormalized: "folk"}
+var GenreFlamenco = models.Genre{ID: "g-flamenco", Name: "Flamenco", NameNormalized: "flamenco", ParentID: "g-folk"}
+var GenrePop = models.Genre{ID: "g-pop", Name: "Pop", NameNormalized: "pop"}
+
+var CatalogGenres = []models.Genre{GenreRock, GenreProgressive, GenreFolk, GenreFlamenco, GenrePop}
+
+var GenreSongs = []models.Song{
+	{ID: "s1", Title: "Bohemian Rhapsody", Genres: []string{"Rock", "Progressive"}},
+	{ID: "s2", Title: "Don't Stop Me Now", Genres: []string{"rock", "Pop"}},
+	{ID: "s3", Title: "Entre dos aguas", Genres: []string{"Flamenco", "Folk"}},
+}
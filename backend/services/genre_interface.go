@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// GenreServiceInterface defines operations on the managed genre catalogue.
+type GenreServiceInterface interface {
+
+	// ListGenres returns every genre ordered by name.
+	// Returns:
+	//   - ([]dto.GenreResponseItem, nil) on success
+	//   - error if the catalogue cannot be read
+	ListGenres() ([]dto.GenreResponseItem, error)
+
+	// CreateGenre adds a new genre, optionally under an existing parent.
+	// Returns:
+	//   - the generated genre ID on success
+	//   - errors.ErrValidationFailed if the name is invalid or already in use
+	//   - errors.ErrResourceNotFound if the parent does not exist
+	//   - error if persistence fails
+	CreateGenre(req dto.CreateGenreRequest) (string, error)
+
+	// UpdateGenre renames and/or moves a genre. A rename rewrites every song using the old name,
+	// recording each rewrite in the audit trail on behalf of actor.
+	// Returns:
+	//   - the number of songs rewritten on success
+	//   - errors.ErrValidationFailed if the name is in use or the new parent would create a cycle
+	//   - errors.ErrResourceNotFound if the genre or the new parent does not exist
+	//   - errors.ErrConflict if songs kept being edited while they were rewritten
+	//   - error if persistence fails
+	UpdateGenre(actor models.Actor, id string, req dto.UpdateGenreRequest) (int, error)
+
+	// MergeGenres folds the source genre into the target: songs and child genres of the
+	// source are moved to the target and the source is removed. Song rewrites are audited on behalf of actor.
+	// Returns:
+	//   - the number of songs rewritten on success
+	//   - errors.ErrValidationFailed if source and target are the same genre
+	//   - errors.ErrResourceNotFound if either genre does not exist
+	//   - errors.ErrConflict if songs kept being edited while they were rewritten
+	//   - error if persistence fails
+	MergeGenres(actor models.Actor, sourceID, targetID string) (int, error)
+
+	// ResolveGenres maps genre names (ignoring case and accents) to their canonical names, removing duplicates.
+	// Returns:
+	//   - the canonical names in input order on success
+	//   - errors.ErrValidationFailed if any name is not part of the catalogue
+	//   - error if the catalogue cannot be read
+	ResolveGenres(names []string) ([]string, error)
+}
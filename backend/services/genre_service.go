@@ -0,0 +1,370 @@
+package services
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Ensure GenreService implements GenreServiceInterface.
+var _ GenreServiceInterface = (*GenreService)(nil)
+
+// genreBatchAttempts is how many times a rename or merge rescans the songs when one of them was edited
+// while the batch was being written, before giving up with errors.ErrConflict.
+const genreBatchAttempts = 3
+
+// GenreService manages the genre catalogue and keeps the genres stored in songs consistent with it.
+// Songs reference genres by name, so renames and merges rewrite every affected song in a single batch job.
+type GenreService struct {
+	repo         repository.GenreRepository
+	idGen        utils.IDGenerator
+	timeProvider utils.TimeProvider
+	indexer      CatalogIndexer
+}
+
+// NewGenreService returns a new instance of GenreService.
+func NewGenreService(
+	repo repository.GenreRepository,
+	idGen utils.IDGenerator,
+	timeProvider utils.TimeProvider,
+	indexer CatalogIndexer,
+) *GenreService {
+	return &GenreService{
+		repo:         repo,
+		idGen:        idGen,
+		timeProvider: timeProvider,
+		indexer:      indexer,
+	}
+}
+
+// ListGenres returns every genre ordered by normalized name.
+func (s *GenreService) ListGenres() ([]dto.GenreResponseItem, error) {
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving genres: %w", err)
+	}
+
+	sort.SliceStable(genres, func(i, j int) bool {
+		return genres[i].NameNormalized < genres[j].NameNormalized
+	})
+	return dto.ToGenreResponseList(genres), nil
+}
+
+// CreateGenre validates the request, checks that the name is unused and the parent exists,
+// and stores the new genre.
+// Returns:
+//   - the generated genre ID on success
+//   - errors.ErrValidationFailed if the request is invalid or the name is taken
+//   - errors.ErrResourceNotFound if the parent does not exist
+//   - error if persistence fails
+func (s *GenreService) CreateGenre(req dto.CreateGenreRequest) (string, error) {
+	if err := dto.ValidateCreateGenreRequest(req); err != nil {
+		return "", fmt.Errorf("validating genre: %w", err)
+	}
+
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return "", fmt.Errorf("retrieving genres: %w", err)
+	}
+
+	genre := dto.ToGenreModel(req)
+	if existing, ok := findGenreByName(genres, genre.Name); ok {
+		return "", fmt.Errorf("genre %q already exists (id=%s): %w", genre.Name, existing.ID, errors.ErrValidationFailed)
+	}
+	if genre.ParentID != "" {
+		if _, ok := findGenreByID(genres, genre.ParentID); !ok {
+			return "", fmt.Errorf("parent genre %s: %w", genre.ParentID, errors.ErrResourceNotFound)
+		}
+	}
+
+	genre.ID = s.idGen.NewID()
+	now := s.timeProvider.Now()
+	genre.CreatedAt = now
+	genre.UpdatedAt = now
+
+	if err := s.repo.CreateGenre(genre); err != nil {
+		return "", fmt.Errorf("creating genre %s: %w", genre.ID, err)
+	}
+	return genre.ID, nil
+}
+
+// UpdateGenre renames and/or moves a genre. When the name changes, every song carrying
+// the old name is rewritten with the new one in the same batch job.
+// Returns:
+//   - the number of songs rewritten on success
+//   - errors.ErrValidationFailed if the request is invalid, the name is taken or the move creates a cycle
+//   - errors.ErrResourceNotFound if the genre or its new parent does not exist
+//   - errors.ErrConflict if songs kept being edited while they were rewritten
+//   - error if persistence fails
+func (s *GenreService) UpdateGenre(actor models.Actor, id string, req dto.UpdateGenreRequest) (int, error) {
+	if err := dto.ValidateUpdateGenreRequest(req); err != nil {
+		return 0, fmt.Errorf("validating genre update: %w", err)
+	}
+
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return 0, fmt.Errorf("retrieving genres: %w", err)
+	}
+
+	genre, ok := findGenreByID(genres, id)
+	if !ok {
+		return 0, fmt.Errorf("genre %s: %w", id, errors.ErrResourceNotFound)
+	}
+
+	updated := genre
+	updated.UpdatedAt = s.timeProvider.Now()
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if existing, ok := findGenreByName(genres, name); ok && existing.ID != id {
+			return 0, fmt.Errorf("genre %q already exists (id=%s): %w", name, existing.ID, errors.ErrValidationFailed)
+		}
+		updated.Name = name
+		updated.NameNormalized = utils.Normalize(name)
+	}
+
+	if req.ParentID != nil {
+		parentID := strings.TrimSpace(*req.ParentID)
+		if parentID != "" {
+			if _, ok := findGenreByID(genres, parentID); !ok {
+				return 0, fmt.Errorf("parent genre %s: %w", parentID, errors.ErrResourceNotFound)
+			}
+			if isDescendant(genres, parentID, id) {
+				return 0, fmt.Errorf("moving genre %s under %s would create a cycle: %w", id, parentID, errors.ErrValidationFailed)
+			}
+		}
+		updated.ParentID = parentID
+	}
+
+	batch := repository.GenreBatch{
+		Genres:    []models.Genre{updated},
+		UpdatedAt: updated.UpdatedAt,
+	}
+	songs, err := s.applyGenreBatch(actor, batch, genre.Name, updated.Name)
+	if err != nil {
+		return 0, fmt.Errorf("updating genre %s: %w", id, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"genre_id":  id,
+		"songs":     len(songs),
+		"operation": "update_genre",
+	}).Info("Genre updated")
+	return len(songs), nil
+}
+
+// MergeGenres moves every song and child genre of the source to the target and removes the source.
+// Returns:
+//   - the number of songs rewritten on success
+//   - errors.ErrValidationFailed if source and target are the same genre
+//   - errors.ErrResourceNotFound if either genre does not exist
+//   - errors.ErrConflict if songs kept being edited while they were rewritten
+//   - error if persistence fails
+func (s *GenreService) MergeGenres(actor models.Actor, sourceID, targetID string) (int, error) {
+	if sourceID == targetID {
+		return 0, fmt.Errorf("cannot merge genre %s into itself: %w", sourceID, errors.ErrValidationFailed)
+	}
+
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return 0, fmt.Errorf("retrieving genres: %w", err)
+	}
+
+	source, ok := findGenreByID(genres, sourceID)
+	if !ok {
+		return 0, fmt.Errorf("source genre %s: %w", sourceID, errors.ErrResourceNotFound)
+	}
+	target, ok := findGenreByID(genres, targetID)
+	if !ok {
+		return 0, fmt.Errorf("target genre %s: %w", targetID, errors.ErrResourceNotFound)
+	}
+
+	now := s.timeProvider.Now()
+
+	// Children of the source are re-attached to the target. If the target itself was one
+	// of them, it takes the place of the source in the hierarchy instead.
+	var moved []models.Genre
+	for _, g := range genres {
+		if g.ParentID != source.ID {
+			continue
+		}
+		if g.ID == target.ID {
+			g.ParentID = source.ParentID
+		} else {
+			g.ParentID = target.ID
+		}
+		g.UpdatedAt = now
+		moved = append(moved, g)
+	}
+
+	batch := repository.GenreBatch{
+		Genres:     moved,
+		DeletedIDs: []string{source.ID},
+		UpdatedAt:  now,
+	}
+	songs, err := s.applyGenreBatch(actor, batch, source.Name, target.Name)
+	if err != nil {
+		return 0, fmt.Errorf("merging genre %s into %s: %w", sourceID, targetID, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"source_id": sourceID,
+		"target_id": targetID,
+		"songs":     len(songs),
+		"operation": "merge_genres",
+	}).Info("Genres merged")
+	return len(songs), nil
+}
+
+// ResolveGenres maps each name to the canonical name of the matching genre.
+// Matching ignores case, accents and surrounding whitespace.
+// Returns errors.ErrValidationFailed if any name is not part of the catalogue.
+func (s *GenreService) ResolveGenres(names []string) ([]string, error) {
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving genres: %w", err)
+	}
+
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		genre, ok := findGenreByName(genres, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown genre %q: %w", name, errors.ErrValidationFailed)
+		}
+		if !seen[genre.ID] {
+			seen[genre.ID] = true
+			out = append(out, genre.Name)
+		}
+	}
+	return out, nil
+}
+
+// applyGenreBatch applies batch together with the rewrite of oldName into newName in every song, when
+// they differ. The songs are scanned again and the batch retried, up to genreBatchAttempts times, when one
+// of them was edited between the scan and its rewrite; the songs rewritten by earlier attempts stay so
+// and are no longer picked up. The rewritten songs are reindexed and returned.
+func (s *GenreService) applyGenreBatch(actor models.Actor, batch repository.GenreBatch, oldName, newName string) ([]models.Song, error) {
+	var rewritten []models.Song
+	defer func() { s.reindex(rewritten) }()
+
+	for attempt := 1; ; attempt++ {
+		if oldName != newName {
+			songs, audits, err := s.rewriteSongs(actor, oldName, newName, batch.UpdatedAt)
+			if err != nil {
+				return rewritten, err
+			}
+			batch.Songs, batch.Audits = songs, audits
+		}
+
+		written, err := s.repo.ApplyGenreBatch(batch)
+		rewritten = append(rewritten, batch.Songs[:written]...)
+		if err == nil {
+			return rewritten, nil
+		}
+		if !stdErrors.Is(err, errors.ErrPreconditionFailed) {
+			return rewritten, err
+		}
+		if attempt == genreBatchAttempts {
+			return rewritten, fmt.Errorf("songs kept changing during %d attempts: %w", attempt, errors.ErrConflict)
+		}
+		logrus.WithFields(logrus.Fields{
+			"attempt":   attempt,
+			"rewritten": len(rewritten),
+			"operation": "apply_genre_batch",
+		}).WithError(err).Warn("A song changed during the genre rewrite; scanning the songs again")
+	}
+}
+
+// rewriteSongs returns every song carrying oldName with that genre replaced by newName, including
+// songs in the trash and songs with a write in progress, so that none keeps a genre that is gone.
+// The returned songs, ordered by ID, hold their new genre list and timestamp but keep the version they were
+// read at, and are not yet persisted; each comes with the audit entry of its rewrite on behalf of actor,
+// keyed by song ID.
+func (s *GenreService) rewriteSongs(actor models.Actor, oldName, newName, now string) ([]models.Song, map[string]models.AuditEntry, error) {
+	songs, err := s.repo.ScanAllSongs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("retrieving songs for genre rewrite: %w", err)
+	}
+
+	oldKey := utils.Normalize(strings.TrimSpace(oldName))
+	var affected []models.Song
+	audits := make(map[string]models.AuditEntry)
+	for _, song := range songs {
+		before := song
+		matched := false
+		seen := make(map[string]bool, len(song.Genres))
+		genres := make([]string, 0, len(song.Genres))
+		for _, g := range song.Genres {
+			if utils.Normalize(strings.TrimSpace(g)) == oldKey {
+				matched = true
+				g = newName
+			}
+			if key := utils.Normalize(g); !seen[key] {
+				seen[key] = true
+				genres = append(genres, g)
+			}
+		}
+		if matched {
+			song.Genres = genres
+			song.UpdatedAt = now
+			affected = append(affected, song)
+			audits[song.ID] = newAuditEntry(s.idGen, actor, now, models.AuditEntitySong, song.ID, song.ID, models.AuditActionUpdate, &before, &song)
+		}
+	}
+	sort.Slice(affected, func(i, j int) bool { return affected[i].ID < affected[j].ID })
+	return affected, audits, nil
+}
+
+// reindex refreshes the autocomplete index for the live songs rewritten by a batch job.
+func (s *GenreService) reindex(songs []models.Song) {
+	for _, song := range songs {
+		if song.DeletedAt == "" && song.Pending == "" {
+			s.indexer.IndexSong(song)
+		}
+	}
+}
+
+func findGenreByID(genres []models.Genre, id string) (models.Genre, bool) {
+	for _, g := range genres {
+		if g.ID == id {
+			return g, true
+		}
+	}
+	return models.Genre{}, false
+}
+
+func findGenreByName(genres []models.Genre, name string) (models.Genre, bool) {
+	key := utils.Normalize(strings.TrimSpace(name))
+	for _, g := range genres {
+		if g.NameNormalized == key {
+			return g, true
+		}
+	}
+	return models.Genre{}, false
+}
+
+// isDescendant reports whether genreID is ancestorID itself or lies below it in the hierarchy.
+func isDescendant(genres []models.Genre, genreID, ancestorID string) bool {
+	visited := make(map[string]bool)
+	for current := genreID; current != "" && !visited[current]; {
+		if current == ancestorID {
+			return true
+		}
+		visited[current] = true
+		g, ok := findGenreByID(genres, current)
+		if !ok {
+			return false
+		}
+		current = g.ParentID
+	}
+	return false
+}
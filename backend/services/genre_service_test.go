@@ -0,0 +1,390 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGenreServiceTest() (*services.GenreService, *mocks.MockGenreRepository) {
+	repo := new(mocks.MockGenreRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProvider := new(mocks.MockTimeProvider)
+	idGen.On("NewID").Return("g-new").Maybe()
+	timeProvider.On("Now").Return("now").Maybe()
+	service := services.NewGenreService(repo, idGen, timeProvider, newMockCatalogIndexer())
+	return service, repo
+}
+
+func TestListGenres(t *testing.T) {
+	service, repo := setupGenreServiceTest()
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+
+	genres, err := service.ListGenres()
+
+	assert.NoError(t, err)
+	names := []string{}
+	for _, g := range genres {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"Flamenco", "Folk", "Pop", "Progressive", "Rock"}, names)
+	assert.Equal(t, "g-folk", genres[0].ParentID)
+}
+
+func TestCreateGenre(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       dto.CreateGenreRequest
+		mockCreateErr error
+		expectCreate  bool
+		expectError   error
+	}{
+		{
+			name:         "success with parent",
+			request:      dto.CreateGenreRequest{Name: "Rumba", ParentID: "g-flamenco"},
+			expectCreate: true,
+		},
+		{
+			name:        "name already in use ignoring case and accents",
+			request:     dto.CreateGenreRequest{Name: "  FÓLK "},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "parent not found",
+			request:     dto.CreateGenreRequest{Name: "Rumba", ParentID: "g-missing"},
+			expectError: errors.ErrResourceNotFound,
+		},
+		{
+			name:        "name too short",
+			request:     dto.CreateGenreRequest{Name: "ab"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:          "repository error",
+			request:       dto.CreateGenreRequest{Name: "Rumba"},
+			expectCreate:  true,
+			mockCreateErr: errors.ErrInternalServer,
+			expectError:   errors.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupGenreServiceTest()
+			repo.On("GetAllGenres").Return(CatalogGenres, nil).Maybe()
+			if tt.expectCreate {
+				repo.On("CreateGenre", mock.MatchedBy(func(g models.Genre) bool {
+					return g.ID == "g-new" && g.Name == tt.request.Name && g.NameNormalized == "rumba" && g.ParentID == tt.request.ParentID
+				})).Return(tt.mockCreateErr)
+			}
+
+			id, err := service.CreateGenre(tt.request)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "g-new", id)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateGenre_RenameRewritesSongs(t *testing.T) {
+	service, repo := setupGenreServiceTest()
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+	repo.On("ScanAllSongs").Return(GenreSongs, nil)
+
+	var batch repository.GenreBatch
+	repo.On("ApplyGenreBatch", mock.Anything).Run(func(args mock.Arguments) {
+		batch = args.Get(0).(repository.GenreBatch)
+	}).Return(2, nil)
+
+	updated, err := service.UpdateGenre(EditorActor, "g-rock", dto.UpdateGenreRequest{Name: ptr("Rock & Roll")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated)
+	assert.Equal(t, map[string][]string{
+		"s1": {"Rock & Roll", "Progressive"},
+		"s2": {"Rock & Roll", "Pop"},
+	}, batchSongGenres(batch))
+	assert.Len(t, batch.Genres, 1)
+	assert.Equal(t, "Rock & Roll", batch.Genres[0].Name)
+	assert.Equal(t, "rock & roll", batch.Genres[0].NameNormalized)
+	assert.Equal(t, "now", batch.UpdatedAt)
+
+	if assert.Len(t, batch.Audits, 2) {
+		audit := batch.Audits["s1"]
+		assert.Equal(t, models.AuditEntitySong, audit.Entity)
+		assert.Equal(t, "s1", audit.EntityID)
+		assert.Equal(t, models.AuditActionUpdate, audit.Action)
+		assert.Equal(t, EditorActor.Username, audit.Actor)
+		assert.Equal(t, EditorActor.RequestID, audit.RequestID)
+		assert.Equal(t, []models.FieldChange{{
+			Field:  "genres",
+			Before: []interface{}{"Rock", "Progressive"},
+			After:  []interface{}{"Rock & Roll", "Progressive"},
+		}}, audit.Changes)
+	}
+}
+
+func TestUpdateGenre_RenameRewritesTrashedAndPendingSongs(t *testing.T) {
+	repo := new(mocks.MockGenreRepository)
+	idGen := new(mocks.MockIDGenerator)
+	idGen.On("NewID").Return("audit-1")
+	timeProvider := new(mocks.MockTimeProvider)
+	timeProvider.On("Now").Return("now")
+	indexer := new(mocks.MockCatalogIndexer)
+	indexer.On("IndexSong", mock.MatchedBy(func(s models.Song) bool { return s.ID == "s1" })).Once()
+	service := services.NewGenreService(repo, idGen, timeProvider, indexer)
+
+	trashed := models.Song{ID: "s-trashed", Genres: []string{"Rock"}, DeletedAt: "2024-01-01T00:00:00Z"}
+	pending := models.Song{ID: "s-pending", Genres: []string{"Rock"}, Pending: models.SongPendingCreate}
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+	repo.On("ScanAllSongs").Return([]models.Song{GenreSongs[0], trashed, pending}, nil)
+
+	var batch repository.GenreBatch
+	repo.On("ApplyGenreBatch", mock.Anything).Run(func(args mock.Arguments) {
+		batch = args.Get(0).(repository.GenreBatch)
+	}).Return(3, nil)
+
+	updated, err := service.UpdateGenre(EditorActor, "g-rock", dto.UpdateGenreRequest{Name: ptr("Rock & Roll")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated)
+	assert.Equal(t, []string{"Rock & Roll"}, batchSongGenres(batch)["s-trashed"])
+	assert.Equal(t, []string{"Rock & Roll"}, batchSongGenres(batch)["s-pending"])
+	indexer.AssertExpectations(t)
+}
+
+func TestUpdateGenre_RescansSongsEditedDuringTheRewrite(t *testing.T) {
+	service, repo := setupGenreServiceTest()
+	edited := models.Song{ID: "s2", Genres: []string{"Rock", "Pop", "Progressive"}, Version: 4}
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+	repo.On("ScanAllSongs").Return(GenreSongs, nil).Once()
+	repo.On("ScanAllSongs").Return([]models.Song{edited}, nil).Once()
+
+	var batches []repository.GenreBatch
+	repo.On("ApplyGenreBatch", mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).(repository.GenreBatch))
+	}).Return(1, errors.ErrPreconditionFailed).Once()
+	repo.On("ApplyGenreBatch", mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).(repository.GenreBatch))
+	}).Return(1, nil).Once()
+
+	updated, err := service.UpdateGenre(EditorActor, "g-rock", dto.UpdateGenreRequest{Name: ptr("Rock & Roll")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated, "the song rewritten before the conflict is counted once")
+	if assert.Len(t, batches, 2) {
+		assert.Equal(t, "s1", batches[0].Songs[0].ID)
+		assert.Equal(t, []models.Song{{ID: "s2", Genres: []string{"Rock & Roll", "Pop", "Progressive"}, UpdatedAt: "now", Version: 4}}, batches[1].Songs,
+			"the edited song is rewritten from its new state and version")
+		assert.Len(t, batches[1].Genres, 1)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateGenre_ConflictWhenSongsKeepChanging(t *testing.T) {
+	service, repo := setupGenreServiceTest()
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+	repo.On("ScanAllSongs").Return(GenreSongs, nil)
+	repo.On("ApplyGenreBatch", mock.Anything).Return(0, errors.ErrPreconditionFailed)
+
+	_, err := service.UpdateGenre(EditorActor, "g-rock", dto.UpdateGenreRequest{Name: ptr("Rock & Roll")})
+
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	repo.AssertNumberOfCalls(t, "ApplyGenreBatch", 3)
+}
+
+func TestUpdateGenre(t *testing.T) {
+	tests := []struct {
+		name        string
+		genreID     string
+		request     dto.UpdateGenreRequest
+		expectBatch bool
+		expectError error
+	}{
+		{
+			name:        "move under another parent",
+			genreID:     "g-flamenco",
+			request:     dto.UpdateGenreRequest{ParentID: ptr("g-pop")},
+			expectBatch: true,
+		},
+		{
+			name:        "move to top level",
+			genreID:     "g-prog",
+			request:     dto.UpdateGenreRequest{ParentID: ptr("")},
+			expectBatch: true,
+		},
+		{
+			name:        "move under own descendant",
+			genreID:     "g-rock",
+			request:     dto.UpdateGenreRequest{ParentID: ptr("g-prog")},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "move under itself",
+			genreID:     "g-rock",
+			request:     dto.UpdateGenreRequest{ParentID: ptr("g-rock")},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "rename to a name in use",
+			genreID:     "g-rock",
+			request:     dto.UpdateGenreRequest{Name: ptr("pop")},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "genre not found",
+			genreID:     "g-missing",
+			request:     dto.UpdateGenreRequest{Name: ptr("Jazz")},
+			expectError: errors.ErrResourceNotFound,
+		},
+		{
+			name:        "empty update",
+			genreID:     "g-rock",
+			request:     dto.UpdateGenreRequest{},
+			expectError: errors.ErrValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupGenreServiceTest()
+			repo.On("GetAllGenres").Return(CatalogGenres, nil).Maybe()
+			if tt.expectBatch {
+				repo.On("ApplyGenreBatch", mock.MatchedBy(func(b repository.GenreBatch) bool {
+					return len(b.Genres) == 1 && b.Genres[0].ID == tt.genreID && b.Genres[0].ParentID == *tt.request.ParentID && len(b.Songs) == 0
+				})).Return(0, nil)
+			}
+
+			updated, err := service.UpdateGenre(EditorActor, tt.genreID, tt.request)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 0, updated)
+			}
+			repo.AssertNotCalled(t, "ScanAllSongs")
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeGenres(t *testing.T) {
+	service, repo := setupGenreServiceTest()
+	repo.On("GetAllGenres").Return(CatalogGenres, nil)
+	repo.On("ScanAllSongs").Return(GenreSongs, nil)
+
+	var batch repository.GenreBatch
+	repo.On("ApplyGenreBatch", mock.Anything).Run(func(args mock.Arguments) {
+		batch = args.Get(0).(repository.GenreBatch)
+	}).Return(1, nil)
+
+	updated, err := service.MergeGenres(EditorActor, "g-folk", "g-flamenco")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, map[string][]string{"s3": {"Flamenco"}}, batchSongGenres(batch))
+	assert.Equal(t, []string{"g-folk"}, batch.DeletedIDs)
+	assert.Len(t, batch.Audits, 1)
+	assert.Equal(t, EditorActor.Username, batch.Audits["s3"].Actor)
+	assert.Len(t, batch.Genres, 1)
+	assert.Equal(t, "g-flamenco", batch.Genres[0].ID)
+	assert.Equal(t, "", batch.Genres[0].ParentID)
+}
+
+func TestMergeGenres_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		sourceID    string
+		targetID    string
+		expectError error
+	}{
+		{
+			name:        "same genre",
+			sourceID:    "g-rock",
+			targetID:    "g-rock",
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "source not found",
+			sourceID:    "g-missing",
+			targetID:    "g-rock",
+			expectError: errors.ErrResourceNotFound,
+		},
+		{
+			name:        "target not found",
+			sourceID:    "g-rock",
+			targetID:    "g-missing",
+			expectError: errors.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupGenreServiceTest()
+			repo.On("GetAllGenres").Return(CatalogGenres, nil).Maybe()
+
+			_, err := service.MergeGenres(EditorActor, tt.sourceID, tt.targetID)
+
+			assert.ErrorIs(t, err, tt.expectError)
+			repo.AssertNotCalled(t, "ApplyGenreBatch", mock.Anything)
+		})
+	}
+}
+
+func TestResolveGenres(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		expected    []string
+		expectError error
+	}{
+		{
+			name:     "canonical names ignoring case and accents",
+			input:    []string{"rock", " FLAMENCO ", "Rock"},
+			expected: []string{"Rock", "Flamenco"},
+		},
+		{
+			name:        "unknown genre",
+			input:       []string{"Rock", "Polka"},
+			expectError: errors.ErrValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupGenreServiceTest()
+			repo.On("GetAllGenres").Return(CatalogGenres, nil)
+
+			result, err := service.ResolveGenres(tt.input)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+// batchSongGenres returns the new genre list of each song rewritten by batch, keyed by song ID.
+func batchSongGenres(batch repository.GenreBatch) map[string][]string {
+	out := make(map[string][]string, len(batch.Songs))
+	for _, song := range batch.Songs {
+		out[song.ID] = song.Genres
+	}
+	return out
+}
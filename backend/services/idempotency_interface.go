@@ -0,0 +1,31 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// IdempotencyServiceInterface defines the bookkeeping of create requests sent with an Idempotency-Key,
+// keyed by the actor and the key and compared by the fingerprint of the request.
+type IdempotencyServiceInterface interface {
+
+	// BeginIdempotentRequest claims the key for a request.
+	// Returns:
+	//   - (nil, nil) if the request is the first with the key and should run
+	//   - (*models.IdempotencyKey, nil) if an earlier request with the same fingerprint holds the key;
+	//     its Response is set once that request succeeded and nil while it is still running
+	//   - errors.ErrConflict if the key was used for a request with a different fingerprint
+	//   - error if the key cannot be claimed
+	BeginIdempotentRequest(actor, key, fingerprint string) (*models.IdempotencyKey, error)
+
+	// CompleteIdempotentRequest stores the response of a request that claimed the key, to be replayed
+	// to its retries until the key expires.
+	// Returns:
+	//   - nil on success
+	//   - error if the response cannot be stored
+	CompleteIdempotentRequest(actor, key, fingerprint string, response models.StoredResponse) error
+
+	// ReleaseIdempotentRequest gives up the key of a request that claimed it and did not succeed,
+	// so a retry runs again.
+	// Returns:
+	//   - nil on success
+	//   - error if the key cannot be released
+	ReleaseIdempotentRequest(actor, key, fingerprint string) error
+}
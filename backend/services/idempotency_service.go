@@ -0,0 +1,97 @@
+package services
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// idempotencyLockTimeout is how long a request may hold a key without completing it before a retry
+// takes the key over, in case the first attempt died without releasing it.
+const idempotencyLockTimeout = time.Minute
+
+// Ensure IdempotencyService implements IdempotencyServiceInterface.
+var _ IdempotencyServiceInterface = (*IdempotencyService)(nil)
+
+// IdempotencyService lets clients retry create requests safely: the first request with a key runs and
+// its successful response is kept for ttl, to be returned again to every retry with the same request.
+type IdempotencyService struct {
+	repo         repository.IdempotencyRepository
+	timeProvider utils.TimeProvider
+	ttl          time.Duration
+}
+
+// NewIdempotencyService returns a new instance of IdempotencyService that keeps keys for ttl.
+func NewIdempotencyService(repo repository.IdempotencyRepository, timeProvider utils.TimeProvider, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:         repo,
+		timeProvider: timeProvider,
+		ttl:          ttl,
+	}
+}
+
+// BeginIdempotentRequest reserves the key, or returns the record of the request already holding it.
+// A key released between the reservation and the read is reserved again once.
+func (s *IdempotencyService) BeginIdempotentRequest(actor, key, fingerprint string) (*models.IdempotencyKey, error) {
+	id := idempotencyKeyID(actor, key)
+	for attempt := 0; attempt < 2; attempt++ {
+		now := s.timeProvider.NowUnix()
+		err := s.repo.ReserveIdempotencyKey(models.IdempotencyKey{
+			ID:          id,
+			Fingerprint: fingerprint,
+			CreatedAt:   s.timeProvider.Now(),
+			LockedUntil: now + int64(idempotencyLockTimeout.Seconds()),
+			ExpiresAt:   now + int64(s.ttl.Seconds()),
+		}, now)
+		if err == nil {
+			return nil, nil
+		}
+		if !stdErrors.Is(err, errors.ErrOperationNotAllowed) {
+			return nil, fmt.Errorf("reserving idempotency key: %w", err)
+		}
+
+		existing, err := s.repo.GetIdempotencyKey(id)
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("retrieving idempotency key: %w", err)
+		}
+		if existing.Fingerprint != fingerprint {
+			logrus.WithFields(logrus.Fields{
+				"actor":     actor,
+				"operation": "begin_idempotent_request",
+			}).Warn("Idempotency key reused for a different request")
+			return nil, fmt.Errorf("idempotency key used for a different request: %w", errors.ErrConflict)
+		}
+		return existing, nil
+	}
+	return nil, fmt.Errorf("reserving idempotency key: %w", errors.ErrInternalServer)
+}
+
+// CompleteIdempotentRequest stores the response of the request holding the key.
+func (s *IdempotencyService) CompleteIdempotentRequest(actor, key, fingerprint string, response models.StoredResponse) error {
+	if err := s.repo.CompleteIdempotencyKey(idempotencyKeyID(actor, key), fingerprint, response); err != nil {
+		return fmt.Errorf("storing idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotentRequest deletes the unfinished record of the request holding the key.
+func (s *IdempotencyService) ReleaseIdempotentRequest(actor, key, fingerprint string) error {
+	if err := s.repo.ReleaseIdempotencyKey(idempotencyKeyID(actor, key), fingerprint); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// idempotencyKeyID scopes a client key to the actor that sent it.
+func idempotencyKeyID(actor, key string) string {
+	return actor + ":" + key
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const idempotencyNow int64 = 1738368000
+
+func setupIdempotencyServiceTest() (*services.IdempotencyService, *mocks.MockIdempotencyRepository) {
+	repo := new(mocks.MockIdempotencyRepository)
+	timeProvider := new(mocks.MockTimeProvider)
+	timeProvider.On("Now").Return("2025-02-01T00:00:00Z").Maybe()
+	timeProvider.On("NowUnix").Return(idempotencyNow).Maybe()
+	return services.NewIdempotencyService(repo, timeProvider, 24*time.Hour), repo
+}
+
+func TestBeginIdempotentRequest_ReservesNewKey(t *testing.T) {
+	service, repo := setupIdempotencyServiceTest()
+	repo.On("ReserveIdempotencyKey", models.IdempotencyKey{
+		ID:          "maria:key-1",
+		Fingerprint: "fp-1",
+		CreatedAt:   "2025-02-01T00:00:00Z",
+		LockedUntil: idempotencyNow + 60,
+		ExpiresAt:   idempotencyNow + 24*3600,
+	}, idempotencyNow).Return(nil)
+
+	existing, err := service.BeginIdempotentRequest("maria", "key-1", "fp-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	repo.AssertExpectations(t)
+}
+
+func TestBeginIdempotentRequest_ReturnsTheFirstRequest(t *testing.T) {
+	stored := &models.IdempotencyKey{
+		ID:          "maria:key-1",
+		Fingerprint: "fp-1",
+		Response:    &models.StoredResponse{StatusCode: 201, Body: `{"song_id":"song-1"}`},
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		expected    *models.IdempotencyKey
+		expectedErr error
+	}{
+		{name: "same request is replayed", fingerprint: "fp-1", expected: stored},
+		{name: "different request conflicts", fingerprint: "fp-2", expectedErr: errors.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupIdempotencyServiceTest()
+			repo.On("ReserveIdempotencyKey", mock.Anything, idempotencyNow).Return(errors.ErrOperationNotAllowed)
+			repo.On("GetIdempotencyKey", "maria:key-1").Return(stored, nil)
+
+			existing, err := service.BeginIdempotentRequest("maria", "key-1", tt.fingerprint)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, existing)
+		})
+	}
+}
+
+func TestBeginIdempotentRequest_RetriesKeyReleasedMeanwhile(t *testing.T) {
+	service, repo := setupIdempotencyServiceTest()
+	repo.On("ReserveIdempotencyKey", mock.Anything, idempotencyNow).Return(errors.ErrOperationNotAllowed).Once()
+	repo.On("GetIdempotencyKey", "maria:key-1").Return(nil, errors.ErrResourceNotFound).Once()
+	repo.On("ReserveIdempotencyKey", mock.Anything, idempotencyNow).Return(nil).Once()
+
+	existing, err := service.BeginIdempotentRequest("maria", "key-1", "fp-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	repo.AssertExpectations(t)
+}
+
+func TestBeginIdempotentRequest_StoreError(t *testing.T) {
+	service, repo := setupIdempotencyServiceTest()
+	repo.On("ReserveIdempotencyKey", mock.Anything, idempotencyNow).Return(errors.ErrInternalServer)
+
+	_, err := service.BeginIdempotentRequest("maria", "key-1", "fp-1")
+
+	assert.ErrorIs(t, err, errors.ErrInternalServer)
+}
+
+func TestCompleteIdempotentRequest(t *testing.T) {
+	service, repo := setupIdempotencyServiceTest()
+	response := models.StoredResponse{StatusCode: 201, Body: `{"song_id":"song-1"}`}
+	repo.On("CompleteIdempotencyKey", "maria:key-1", "fp-1", response).Return(nil)
+
+	err := service.CompleteIdempotentRequest("maria", "key-1", "fp-1", response)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestReleaseIdempotentRequest(t *testing.T) {
+	service, repo := setupIdempotencyServiceTest()
+	repo.On("ReleaseIdempotencyKey", "maria:key-1", "fp-1").Return(errors.ErrInternalServer)
+
+	err := service.ReleaseIdempotentRequest("maria", "key-1", "fp-1")
+
+	assert.ErrorIs(t, err, errors.ErrInternalServer)
+}
@@ -0,0 +1,16 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+var TestInstrumentFamilies = []models.InstrumentFamily{
+	{ID: "strings", Names: map[string]string{"es": "Cuerdas", "en": "Strings"}},
+	{ID: "voices", Names: map[string]string{"es": "Voces", "en": "Voices"}},
+}
+
+var TestInstruments = []models.Instrument{
+	{ID: "guitar", Family: "strings", Names: map[string]string{"es": "Guitarra", "en": "Guitar"}, Aliases: []string{"gtr", "acoustic guitar"}},
+	{ID: "double_bass", Family: "strings", Names: map[string]string{"es": "Contrabajo", "en": "Double bass"}},
+	{ID: "voice", Family: "voices", Names: map[string]string{"es": "Voz", "en": "Voice"}, Aliases: []string{"vocals"}},
+	{ID: "soprano", Family: "voices", Names: map[string]string{"es": "Soprano", "en": "Soprano"}},
+	{ID: "bass", Family: "voices", Names: map[string]string{"es": "Bajo (voz)", "en": "Bass"}, Aliases: []string{"double bass"}},
+}
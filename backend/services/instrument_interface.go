@@ -0,0 +1,28 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// InstrumentServiceInterface defines operations on the canonical instrument catalogue.
+type InstrumentServiceInterface interface {
+
+	// ListInstruments returns the whole catalogue grouped by family.
+	// Returns:
+	//   - (dto.InstrumentCatalogResponse, nil) on success
+	//   - error if the catalogue cannot be loaded
+	ListInstruments() (dto.InstrumentCatalogResponse, error)
+
+	// NormalizeInstruments maps instrument names, aliases or IDs to canonical IDs, removing duplicates.
+	// Returns:
+	//   - the canonical IDs in input order on success
+	//   - errors.ErrValidationFailed if any value is not part of the catalogue
+	//   - error if the catalogue cannot be loaded
+	NormalizeInstruments(names []string) ([]string, error)
+
+	// ExpandInstrumentQuery returns every stored value that should match an instrument search term.
+	// A family matches all of its members; an instrument matches its canonical ID and the legacy
+	// spellings of its names and aliases. Unknown terms are returned unchanged.
+	// Returns:
+	//   - the list of values to match on success
+	//   - error if the catalogue cannot be loaded
+	ExpandInstrumentQuery(query string) ([]string, error)
+}
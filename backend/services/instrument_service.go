@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+// Ensure InstrumentService implements InstrumentServiceInterface.
+var _ InstrumentServiceInterface = (*InstrumentService)(nil)
+
+// InstrumentService resolves free-text instrument names against the canonical catalogue.
+// The catalogue is loaded from the repository on first use and kept in memory.
+type InstrumentService struct {
+	repo repository.InstrumentRepository
+
+	mu      sync.Mutex
+	catalog *instrumentCatalog
+}
+
+// instrumentCatalog holds the catalogue together with lookup tables keyed by normalized text.
+type instrumentCatalog struct {
+	instruments []models.Instrument
+	families    []models.InstrumentFamily
+	byID        map[string]models.Instrument
+	byTerm      map[string]string
+	familyTerm  map[string]string
+	members     map[string][]string
+}
+
+// NewInstrumentService returns a new instance of InstrumentService.
+func NewInstrumentService(repo repository.InstrumentRepository) *InstrumentService {
+	return &InstrumentService{repo: repo}
+}
+
+// ListInstruments returns the whole catalogue grouped by family.
+func (s *InstrumentService) ListInstruments() (dto.InstrumentCatalogResponse, error) {
+	catalog, err := s.load()
+	if err != nil {
+		return dto.InstrumentCatalogResponse{}, err
+	}
+	return dto.ToInstrumentCatalogResponse(catalog.instruments, catalog.families), nil
+}
+
+// NormalizeInstruments maps each value to its canonical instrument ID.
+// Matching ignores case, accents and surrounding whitespace, and accepts IDs, localized names and aliases.
+// Returns:
+//   - the canonical IDs in input order without duplicates
+//   - errors.ErrValidationFailed if any value is unknown
+func (s *InstrumentService) NormalizeInstruments(names []string) ([]string, error) {
+	catalog, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		id, ok := catalog.byTerm[instrumentKey(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown instrument %q: %w", name, errors.ErrValidationFailed)
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// ExpandInstrumentQuery returns every stored value that should match the given search term.
+// Family names take precedence, so searching "voices" matches soprano, alto, tenor, etc.
+// Legacy spellings (names and aliases as typed before the catalogue existed) are included
+// so that documents stored before validation was introduced are still found.
+func (s *InstrumentService) ExpandInstrumentQuery(query string) ([]string, error) {
+	catalog, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	key := instrumentKey(query)
+	var ids []string
+	if family, ok := catalog.familyTerm[key]; ok {
+		ids = catalog.members[family]
+	} else if id, ok := catalog.byTerm[key]; ok {
+		ids = []string{id}
+	} else {
+		return []string{strings.TrimSpace(query)}, nil
+	}
+
+	seen := make(map[string]bool)
+	var values []string
+	add := func(v string) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	for _, id := range ids {
+		inst := catalog.byID[id]
+		add(inst.ID)
+		for _, spelling := range append(append([]string{}, inst.Aliases...), mapValues(inst.Names)...) {
+			add(spelling)
+			add(strings.ToLower(spelling))
+			add(capitalize(spelling))
+		}
+	}
+	return values, nil
+}
+
+// load returns the cached catalogue, reading it from the repository the first time it is needed.
+func (s *InstrumentService) load() (*instrumentCatalog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.catalog != nil {
+		return s.catalog, nil
+	}
+
+	instruments, err := s.repo.GetAllInstruments()
+	if err != nil {
+		return nil, fmt.Errorf("loading instrument catalogue: %w", err)
+	}
+	families, err := s.repo.GetAllInstrumentFamilies()
+	if err != nil {
+		return nil, fmt.Errorf("loading instrument families: %w", err)
+	}
+
+	catalog := &instrumentCatalog{
+		instruments: instruments,
+		families:    families,
+		byID:        make(map[string]models.Instrument, len(instruments)),
+		byTerm:      make(map[string]string),
+		familyTerm:  make(map[string]string),
+		members:     make(map[string][]string),
+	}
+
+	// IDs are registered first so that they always win over a colliding alias or name.
+	for _, inst := range instruments {
+		catalog.byID[inst.ID] = inst
+		catalog.byTerm[instrumentKey(inst.ID)] = inst.ID
+		catalog.members[inst.Family] = append(catalog.members[inst.Family], inst.ID)
+	}
+	for _, inst := range instruments {
+		for _, term := range append(append([]string{}, inst.Aliases...), mapValues(inst.Names)...) {
+			if _, taken := catalog.byTerm[instrumentKey(term)]; !taken {
+				catalog.byTerm[instrumentKey(term)] = inst.ID
+			}
+		}
+	}
+	for _, family := range families {
+		catalog.familyTerm[instrumentKey(family.ID)] = family.ID
+		for _, name := range family.Names {
+			catalog.familyTerm[instrumentKey(name)] = family.ID
+		}
+	}
+
+	s.catalog = catalog
+	return catalog, nil
+}
+
+// instrumentKey normalizes a term for catalogue lookups: accent- and case-insensitive,
+// with underscores, hyphens and repeated spaces treated as a single space.
+func instrumentKey(term string) string {
+	replaced := strings.NewReplacer("_", " ", "-", " ").Replace(utils.Normalize(term))
+	return strings.Join(strings.Fields(replaced), " ")
+}
+
+// mapValues returns the values of m ordered by key, so that lookups are deterministic.
+func mapValues(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = m[k]
+	}
+	return values
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	return strings.ToUpper(string(r[0])) + string(r[1:])
+}
@@ -0,0 +1,140 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupInstrumentServiceTest() *services.InstrumentService {
+	return services.NewInstrumentService(&repository.StaticInstrumentRepository{
+		Instruments: TestInstruments,
+		Families:    TestInstrumentFamilies,
+	})
+}
+
+func TestNormalizeInstruments(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		expected    []string
+		expectError error
+	}{
+		{
+			name:     "canonical IDs are kept",
+			input:    []string{"guitar", "voice"},
+			expected: []string{"guitar", "voice"},
+		},
+		{
+			name:     "names and aliases ignore case and accents",
+			input:    []string{"Guitarra", "GTR", " acoustic   guitar ", "VOZ"},
+			expected: []string{"guitar", "voice"},
+		},
+		{
+			name:     "IDs take precedence over colliding aliases",
+			input:    []string{"double-bass", "Contrabajo"},
+			expected: []string{"double_bass"},
+		},
+		{
+			name:        "unknown instrument",
+			input:       []string{"guitar", "theremin"},
+			expectError: errors.ErrValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := setupInstrumentServiceTest()
+
+			result, err := service.NormalizeInstruments(tt.input)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExpandInstrumentQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "instrument expands to its legacy spellings",
+			query:       "guitarra",
+			contains:    []string{"guitar", "Guitar", "Guitarra", "guitarra", "gtr"},
+			notContains: []string{"voice"},
+		},
+		{
+			name:        "family expands to its members",
+			query:       "voces",
+			contains:    []string{"voice", "Voz", "vocals", "soprano", "bass"},
+			notContains: []string{"guitar"},
+		},
+		{
+			name:     "unknown term is returned unchanged",
+			query:    " theremin ",
+			contains: []string{"theremin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := setupInstrumentServiceTest()
+
+			result, err := service.ExpandInstrumentQuery(tt.query)
+
+			assert.NoError(t, err)
+			for _, value := range tt.contains {
+				assert.Contains(t, result, value)
+			}
+			for _, value := range tt.notContains {
+				assert.NotContains(t, result, value)
+			}
+		})
+	}
+}
+
+func TestListInstruments(t *testing.T) {
+	service := setupInstrumentServiceTest()
+
+	catalog, err := service.ListInstruments()
+
+	assert.NoError(t, err)
+	assert.Len(t, catalog.Instruments, len(TestInstruments))
+	assert.Len(t, catalog.Families, len(TestInstrumentFamilies))
+	assert.Equal(t, "strings", catalog.Families[0].ID)
+	assert.Equal(t, []string{"guitar", "double_bass"}, catalog.Families[0].Instruments)
+}
+
+func TestDefaultInstrumentCatalogIsConsistent(t *testing.T) {
+	families := map[string]bool{}
+	for _, family := range repository.DefaultInstrumentFamilies {
+		families[family.ID] = true
+	}
+
+	ids := map[string]bool{}
+	for _, inst := range repository.DefaultInstruments {
+		assert.False(t, ids[inst.ID], "duplicate instrument id %s", inst.ID)
+		ids[inst.ID] = true
+		assert.True(t, families[inst.Family], "instrument %s has unknown family %s", inst.ID, inst.Family)
+		assert.NotEmpty(t, inst.Names["es"], "instrument %s has no Spanish name", inst.ID)
+		assert.NotEmpty(t, inst.Names["en"], "instrument %s has no English name", inst.ID)
+	}
+
+	service := newInstrumentService()
+	for id := range ids {
+		normalized, err := service.NormalizeInstruments([]string{id})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{id}, normalized)
+	}
+}
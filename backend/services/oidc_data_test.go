@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+)
+
+const (
+	OIDCIssuer   = "https://idp.example.org"
+	OIDCClientID = "rendalla"
+	OIDCState    = "state-1"
+	OIDCNonce    = "nonce-1"
+	OIDCVerifier = "verifier-1"
+	OIDCCode     = "code-1"
+	OIDCKeyID    = "idp-key"
+)
+
+var OIDCTestConfig = services.OIDCConfig{
+	Issuer:      OIDCIssuer,
+	ClientID:    OIDCClientID,
+	RedirectURL: "https://rendalla.example.org/auth/callback",
+	Scopes:      []string{"profile", "groups"},
+	RoleMapping: services.OIDCRoleMapping{
+		Subjects: map[string]string{"boss": models.RoleAdmin},
+		Groups: map[string]string{
+			"music-editors": models.RoleEditor,
+			"music-readers": models.RoleViewer,
+			"broken":        "superuser",
+		},
+	},
+}
+
+var OIDCTestDiscovery = repository.OIDCDiscovery{
+	Issuer:                OIDCIssuer,
+	AuthorizationEndpoint: OIDCIssuer + "/authorize",
+	TokenEndpoint:         OIDCIssuer + "/token",
+	JWKSURI:               OIDCIssuer + "/jwks",
+}
+
+var OIDCPendingLogin = models.OIDCLogin{
+	ID:           OIDCState,
+	CodeVerifier: OIDCVerifier,
+	Nonce:        OIDCNonce,
+	ExpiresAt:    2000,
+}
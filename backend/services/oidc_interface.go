@@ -0,0 +1,25 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// OIDCServiceInterface defines the sign-in of editors through the organisation's OpenID provider
+// using the authorization-code flow with PKCE.
+type OIDCServiceInterface interface {
+
+	// BeginLogin prepares a login and returns the provider URL the browser must visit.
+	// Returns:
+	//   - the authorization URL and its state on success
+	//   - errors.ErrResourceNotFound if OIDC login is not configured
+	//   - errors.ErrInternalServer if the provider cannot be discovered or the login cannot be stored
+	BeginLogin() (*dto.OIDCLoginResponse, error)
+
+	// CompleteLogin redeems the code returned to the redirect URL, verifies the ID token,
+	// maps the external subject and groups to a role and opens a session.
+	// Returns:
+	//   - the access and refresh tokens on success
+	//   - errors.ErrResourceNotFound if OIDC login is not configured
+	//   - errors.ErrInvalidCredentials if the state, code or ID token is invalid
+	//   - errors.ErrOperationNotAllowed if no role is mapped to the identity
+	//   - errors.ErrInternalServer if the provider or the stores fail
+	CompleteLogin(code, state string) (*dto.AuthResponse, error)
+}
@@ -0,0 +1,451 @@
+package services
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	stdErrors "errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// OIDCUsernamePrefix namespaces the usernames of sessions opened through OIDC, so an external
+// subject can never be confused with an account of the users store.
+const OIDCUsernamePrefix = "oidc:"
+
+// Defaults and limits of the OIDC login flow.
+const (
+	defaultOIDCLoginTTL   = 10 * time.Minute
+	oidcDiscoveryTTL      = time.Hour
+	oidcKeyRefreshBackoff = time.Minute
+	oidcClockSkew         = time.Minute
+)
+
+// oidcSigningMethods are the ID token algorithms accepted from the provider.
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// roleRank orders roles so that the most privileged role mapped from a user's groups wins.
+var roleRank = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+}
+
+// OIDCRoleMapping maps external identities to Rendalla roles.
+// A role mapped to the subject wins over group roles; among the user's groups the most
+// privileged mapped role wins. Identities matching nothing get DefaultRole, and are refused
+// when it is empty.
+type OIDCRoleMapping struct {
+	Subjects    map[string]string
+	Groups      map[string]string
+	DefaultRole string
+}
+
+// OIDCConfig configures the login with the organisation's OpenID provider.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // Empty for public clients, which rely on PKCE alone
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string // ID token claim listing the user's groups, "groups" if empty
+	RoleMapping  OIDCRoleMapping
+	LoginTTL     time.Duration // Time allowed to complete a login, defaultOIDCLoginTTL if zero
+}
+
+// OIDCService signs users in through an OpenID provider with the authorization-code flow and PKCE.
+//
+// BeginLogin stores a pending login holding the PKCE verifier and the nonce, keyed by the state
+// sent to the provider. CompleteLogin consumes it, so each state can be redeemed once, exchanges
+// the code, verifies the ID token against the provider keys and opens a regular session through
+// the authentication service.
+//
+// The discovery document and the provider keys are cached in memory; keys are fetched again when
+// an ID token is signed with an unknown key, at most once per oidcKeyRefreshBackoff.
+type OIDCService struct {
+	cfg          *OIDCConfig
+	provider     repository.OIDCProviderRepository
+	logins       repository.OIDCLoginRepository
+	sessions     AuthServiceInterface
+	timeProvider utils.TimeProvider
+
+	mu           sync.Mutex
+	discovery    *repository.OIDCDiscovery
+	discoveredAt int64
+	keys         map[string]utils.JWK
+	keysFetched  int64
+}
+
+// Ensure OIDCService implements OIDCServiceInterface.
+var _ OIDCServiceInterface = (*OIDCService)(nil)
+
+// NewOIDCService returns a new instance of OIDCService.
+// A nil cfg disables OIDC login: every call returns errors.ErrResourceNotFound.
+func NewOIDCService(
+	cfg *OIDCConfig,
+	provider repository.OIDCProviderRepository,
+	logins repository.OIDCLoginRepository,
+	sessions AuthServiceInterface,
+	timeProvider utils.TimeProvider,
+) *OIDCService {
+	return &OIDCService{
+		cfg:          cfg,
+		provider:     provider,
+		logins:       logins,
+		sessions:     sessions,
+		timeProvider: timeProvider,
+	}
+}
+
+// BeginLogin generates the state, nonce and PKCE verifier of a new login, stores them and
+// returns the authorization URL carrying the S256 code challenge.
+// Returns:
+//   - the authorization URL on success
+//   - errors.ErrResourceNotFound if OIDC login is not configured
+//   - errors.ErrInternalServer if the provider cannot be discovered or the login cannot be stored
+func (s *OIDCService) BeginLogin() (*dto.OIDCLoginResponse, error) {
+	if s.cfg == nil {
+		return nil, fmt.Errorf("OIDC login is not configured: %w", errors.ErrResourceNotFound)
+	}
+
+	discovery, err := s.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generating OIDC state: %w", errors.ErrInternalServer)
+	}
+	nonce, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("generating OIDC nonce: %w", errors.ErrInternalServer)
+	}
+	verifier, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generating PKCE verifier: %w", errors.ErrInternalServer)
+	}
+
+	ttl := s.loginTTL()
+	login := models.OIDCLogin{
+		ID:           state,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		CreatedAt:    s.timeProvider.Now(),
+		ExpiresAt:    s.timeProvider.NowUnix() + int64(ttl.Seconds()),
+	}
+	if err := s.logins.CreateOIDCLogin(login); err != nil {
+		return nil, fmt.Errorf("storing OIDC login: %w", err)
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.cfg.ClientID},
+		"redirect_uri":          {s.cfg.RedirectURL},
+		"scope":                 {strings.Join(s.scopes(), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {pkceChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return &dto.OIDCLoginResponse{
+		AuthorizationURL: discovery.AuthorizationEndpoint + separator + params.Encode(),
+		State:            state,
+		ExpiresIn:        int64(ttl.Seconds()),
+	}, nil
+}
+
+// CompleteLogin finishes a login started by BeginLogin.
+// Returns:
+//   - the access and refresh tokens on success
+//   - errors.ErrResourceNotFound if OIDC login is not configured
+//   - errors.ErrInvalidCredentials if the state is unknown, used or expired, the provider rejects
+//     the code, or the ID token fails verification
+//   - errors.ErrOperationNotAllowed if no role is mapped to the identity
+//   - errors.ErrInternalServer if the provider or the stores fail
+func (s *OIDCService) CompleteLogin(code, state string) (*dto.AuthResponse, error) {
+	if s.cfg == nil {
+		return nil, fmt.Errorf("OIDC login is not configured: %w", errors.ErrResourceNotFound)
+	}
+	if utils.IsEmptyString(code) || utils.IsEmptyString(state) {
+		return nil, fmt.Errorf("OIDC callback needs a code and a state: %w", errors.ErrInvalidCredentials)
+	}
+
+	login, err := s.logins.ConsumeOIDCLogin(state)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return nil, fmt.Errorf("unknown or already used OIDC state: %w", errors.ErrInvalidCredentials)
+		}
+		return nil, fmt.Errorf("consuming OIDC login: %w", err)
+	}
+	if s.timeProvider.NowUnix() > login.ExpiresAt {
+		return nil, fmt.Errorf("OIDC login expired: %w", errors.ErrInvalidCredentials)
+	}
+
+	discovery, err := s.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.provider.ExchangeCode(discovery.TokenEndpoint, repository.OIDCCodeExchange{
+		Code:         code,
+		CodeVerifier: login.CodeVerifier,
+		RedirectURI:  s.cfg.RedirectURL,
+		ClientID:     s.cfg.ClientID,
+		ClientSecret: s.cfg.ClientSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exchanging OIDC code: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("OIDC token response has no ID token: %w", errors.ErrInvalidCredentials)
+	}
+
+	claims, err := s.verifyIDToken(tokens.IDToken, login.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if utils.IsEmptyString(subject) {
+		return nil, fmt.Errorf("ID token has no subject: %w", errors.ErrInvalidCredentials)
+	}
+	groups := claimStrings(claims[s.groupsClaim()])
+
+	role := s.resolveRole(subject, groups)
+	if role == "" {
+		logrus.WithFields(logrus.Fields{
+			"subject": subject,
+			"groups":  groups,
+		}).Warn("OIDC login refused, no role mapped to identity")
+		return nil, fmt.Errorf("no role mapped to OIDC subject %s: %w", subject, errors.ErrOperationNotAllowed)
+	}
+
+	username := OIDCUsernamePrefix + subject
+	logrus.WithFields(logrus.Fields{
+		"username": username,
+		"email":    claims["email"],
+		"role":     role,
+	}).Info("OIDC identity verified")
+
+	return s.sessions.OpenExternalSession(username, role)
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token.
+// Returns its claims, or errors.ErrInvalidCredentials if any check fails.
+func (s *OIDCService) verifyIDToken(raw, nonce string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(s.cfg.Issuer),
+		jwt.WithAudience(s.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+		jwt.WithTimeFunc(func() time.Time { return time.Unix(s.timeProvider.NowUnix(), 0) }),
+	)
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.providerKey(kid, token.Method.Alg())
+	})
+	if err != nil {
+		logrus.WithError(err).Warn("OIDC ID token rejected")
+		return nil, fmt.Errorf("verifying ID token: %w", errors.ErrInvalidCredentials)
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("ID token nonce mismatch: %w", errors.ErrInvalidCredentials)
+	}
+
+	// With several audiences the token must have been issued to this client (OIDC Core 3.1.3.7).
+	if audiences, _ := claims.GetAudience(); len(audiences) > 1 {
+		if azp, _ := claims["azp"].(string); azp != s.cfg.ClientID {
+			return nil, fmt.Errorf("ID token authorized party mismatch: %w", errors.ErrInvalidCredentials)
+		}
+	}
+	return claims, nil
+}
+
+// providerKey returns the provider public key with the given kid. Unknown kids trigger a
+// refresh of the key set, so keys rotated by the provider are picked up.
+func (s *OIDCService) providerKey(kid, alg string) (crypto.PublicKey, error) {
+	jwk, err := s.lookupKey(kid, false)
+	if err != nil {
+		return nil, err
+	}
+	if jwk == nil {
+		if jwk, err = s.lookupKey(kid, true); err != nil {
+			return nil, err
+		}
+	}
+	if jwk == nil {
+		return nil, fmt.Errorf("unknown OIDC signing key %q", kid)
+	}
+	if jwk.Alg != "" && jwk.Alg != alg {
+		return nil, fmt.Errorf("OIDC key %q is for %s, token uses %s", kid, jwk.Alg, alg)
+	}
+	return jwk.PublicKey()
+}
+
+// lookupKey finds a signing key in the cached key set, fetching the set when it was never
+// fetched or when refresh is requested and the last fetch is old enough.
+// Without a kid the key is only found if the provider publishes a single signing key.
+func (s *OIDCService) lookupKey(kid string, refresh bool) (*utils.JWK, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timeProvider.NowUnix()
+	stale := refresh && now-s.keysFetched >= int64(oidcKeyRefreshBackoff.Seconds())
+	if s.keys == nil || stale {
+		discovery, err := s.discoverLocked(now)
+		if err != nil {
+			return nil, err
+		}
+		jwks, err := s.provider.GetJWKS(discovery.JWKSURI)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = map[string]utils.JWK{}
+		for _, jwk := range jwks {
+			if jwk.Use == "" || jwk.Use == "sig" {
+				s.keys[jwk.Kid] = jwk
+			}
+		}
+		s.keysFetched = now
+	}
+
+	if kid == "" && len(s.keys) == 1 {
+		for _, jwk := range s.keys {
+			return &jwk, nil
+		}
+	}
+	if jwk, ok := s.keys[kid]; ok {
+		return &jwk, nil
+	}
+	return nil, nil
+}
+
+// getDiscovery returns the cached discovery document, fetching it when older than oidcDiscoveryTTL.
+func (s *OIDCService) getDiscovery() (*repository.OIDCDiscovery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.discoverLocked(s.timeProvider.NowUnix())
+}
+
+// discoverLocked implements getDiscovery; s.mu must be held.
+// The document must be published by the configured issuer.
+func (s *OIDCService) discoverLocked(now int64) (*repository.OIDCDiscovery, error) {
+	if s.discovery != nil && now-s.discoveredAt < int64(oidcDiscoveryTTL.Seconds()) {
+		return s.discovery, nil
+	}
+
+	discovery, err := s.provider.GetDiscovery(s.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC provider: %w", err)
+	}
+	if discovery.Issuer != s.cfg.Issuer {
+		logrus.WithFields(logrus.Fields{
+			"expected": s.cfg.Issuer,
+			"actual":   discovery.Issuer,
+		}).Error("OIDC discovery issuer mismatch")
+		return nil, fmt.Errorf("OIDC discovery issuer mismatch: %w", errors.ErrInternalServer)
+	}
+
+	s.discovery = discovery
+	s.discoveredAt = now
+	return discovery, nil
+}
+
+// resolveRole maps an external identity to a role, or returns "" if it gets none.
+// Mapped values that are not known roles are ignored.
+func (s *OIDCService) resolveRole(subject string, groups []string) string {
+	mapping := s.cfg.RoleMapping
+	if role, ok := mapping.Subjects[subject]; ok && models.IsValidRole(role) {
+		return role
+	}
+
+	best := ""
+	for _, group := range groups {
+		role, ok := mapping.Groups[group]
+		if ok && models.IsValidRole(role) && roleRank[role] > roleRank[best] {
+			best = role
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	if models.IsValidRole(mapping.DefaultRole) {
+		return mapping.DefaultRole
+	}
+	return ""
+}
+
+// scopes returns the configured scopes, always including "openid".
+func (s *OIDCService) scopes() []string {
+	scopes := slices.Clone(s.cfg.Scopes)
+	if !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	return scopes
+}
+
+// groupsClaim returns the name of the claim listing the user's groups.
+func (s *OIDCService) groupsClaim() string {
+	if s.cfg.GroupsClaim == "" {
+		return "groups"
+	}
+	return s.cfg.GroupsClaim
+}
+
+// loginTTL returns the time allowed to complete a login.
+func (s *OIDCService) loginTTL() time.Duration {
+	if s.cfg.LoginTTL <= 0 {
+		return defaultOIDCLoginTTL
+	}
+	return s.cfg.LoginTTL
+}
+
+// pkceChallenge returns the S256 code challenge of a PKCE verifier (RFC 7636).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// claimStrings reads a claim holding a string or a list of strings.
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,285 @@
+package services_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type oidcServiceMocks struct {
+	provider *mocks.MockOIDCProviderRepository
+	logins   *mocks.MockOIDCLoginRepository
+	auth     *mocks.MockAuthService
+}
+
+func setupOIDCServiceTest(cfg *services.OIDCConfig) (*services.OIDCService, oidcServiceMocks) {
+	m := oidcServiceMocks{
+		provider: new(mocks.MockOIDCProviderRepository),
+		logins:   new(mocks.MockOIDCLoginRepository),
+		auth:     new(mocks.MockAuthService),
+	}
+	clock := new(mocks.MockTimeProvider)
+	clock.On("Now").Return("now").Maybe()
+	clock.On("NowUnix").Return(int64(1000)).Maybe()
+	return services.NewOIDCService(cfg, m.provider, m.logins, m.auth, clock), m
+}
+
+// newIDTokenSigner returns a key set standing in for the provider signing key.
+func newIDTokenSigner(t *testing.T, kid string) *utils.JWTKeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err := utils.NewJWTKeySet(OIDCIssuer, OIDCClientID, []utils.JWTKey{
+		{ID: kid, Algorithm: utils.JWTAlgorithmEdDSA, PrivateKey: private},
+	}, &utils.UTCTimeProvider{})
+	require.NoError(t, err)
+	return keys
+}
+
+// signIDToken signs claims as the provider would, defaulting the issuer and audience.
+func signIDToken(t *testing.T, keys *utils.JWTKeySet, claims jwt.MapClaims) string {
+	signed := jwt.MapClaims{"iss": OIDCIssuer, "aud": OIDCClientID}
+	for k, v := range claims {
+		signed[k] = v
+	}
+	key, err := keys.SigningKey()
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, signed)
+	token.Header["kid"] = key.ID
+	idToken, err := token.SignedString(key.PrivateKey)
+	require.NoError(t, err)
+	return idToken
+}
+
+func idTokenClaims(sub string, groups ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    sub,
+		"groups": groups,
+		"nonce":  OIDCNonce,
+		"iat":    int64(1000),
+		"exp":    int64(1300),
+	}
+}
+
+func TestBeginLogin(t *testing.T) {
+	t.Run("returns authorization URL with PKCE challenge", func(t *testing.T) {
+		cfg := OIDCTestConfig
+		service, m := setupOIDCServiceTest(&cfg)
+		m.provider.On("GetDiscovery", OIDCIssuer).Return(&OIDCTestDiscovery, nil).Once()
+
+		var stored models.OIDCLogin
+		m.logins.On("CreateOIDCLogin", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(models.OIDCLogin)
+		}).Return(nil).Twice()
+
+		resp, err := service.BeginLogin()
+		require.NoError(t, err)
+
+		authURL, err := url.Parse(resp.AuthorizationURL)
+		require.NoError(t, err)
+		query := authURL.Query()
+		sum := sha256.Sum256([]byte(stored.CodeVerifier))
+
+		assert.Equal(t, OIDCIssuer+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+		assert.Equal(t, resp.State, stored.ID)
+		assert.Equal(t, "code", query.Get("response_type"))
+		assert.Equal(t, OIDCClientID, query.Get("client_id"))
+		assert.Equal(t, cfg.RedirectURL, query.Get("redirect_uri"))
+		assert.Equal(t, "openid profile groups", query.Get("scope"))
+		assert.Equal(t, stored.ID, query.Get("state"))
+		assert.Equal(t, stored.Nonce, query.Get("nonce"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), query.Get("code_challenge"))
+		assert.Empty(t, query.Get("code_verifier"))
+		assert.Equal(t, int64(1000+600), stored.ExpiresAt)
+		assert.Equal(t, int64(600), resp.ExpiresIn)
+
+		// The discovery document is cached.
+		_, err = service.BeginLogin()
+		assert.NoError(t, err)
+		m.provider.AssertExpectations(t)
+		m.logins.AssertExpectations(t)
+	})
+
+	t.Run("discovery issuer mismatch", func(t *testing.T) {
+		cfg := OIDCTestConfig
+		service, m := setupOIDCServiceTest(&cfg)
+		discovery := OIDCTestDiscovery
+		discovery.Issuer = "https://evil.example.org"
+		m.provider.On("GetDiscovery", OIDCIssuer).Return(&discovery, nil)
+
+		_, err := service.BeginLogin()
+
+		assert.ErrorIs(t, err, errors.ErrInternalServer)
+		m.logins.AssertNotCalled(t, "CreateOIDCLogin", mock.Anything)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		service, _ := setupOIDCServiceTest(nil)
+
+		_, err := service.BeginLogin()
+
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+	})
+}
+
+func TestCompleteLogin(t *testing.T) {
+	signer := newIDTokenSigner(t, OIDCKeyID)
+	otherSigner := newIDTokenSigner(t, "rotated-away")
+
+	tests := []struct {
+		name        string
+		login       *models.OIDCLogin
+		consumeErr  error
+		exchangeErr error
+		claims      jwt.MapClaims
+		signer      *utils.JWTKeySet
+		expectRole  string
+		expectUser  string
+		expectError error
+	}{
+		{
+			name:       "group mapped to editor",
+			claims:     idTokenClaims("ext-1", "staff", "music-readers", "music-editors"),
+			expectRole: models.RoleEditor,
+			expectUser: "oidc:ext-1",
+		},
+		{
+			name:       "subject mapping wins over groups",
+			claims:     idTokenClaims("boss", "music-readers"),
+			expectRole: models.RoleAdmin,
+			expectUser: "oidc:boss",
+		},
+		{
+			name:        "no mapped role",
+			claims:      idTokenClaims("ext-2", "staff", "broken"),
+			expectError: errors.ErrOperationNotAllowed,
+		},
+		{
+			name:        "unknown state",
+			consumeErr:  errors.ErrResourceNotFound,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "expired login",
+			login:       &models.OIDCLogin{ID: OIDCState, CodeVerifier: OIDCVerifier, Nonce: OIDCNonce, ExpiresAt: 999},
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "code rejected by provider",
+			exchangeErr: errors.ErrInvalidCredentials,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name: "nonce mismatch",
+			claims: func() jwt.MapClaims {
+				c := idTokenClaims("ext-1", "music-editors")
+				c["nonce"] = "replayed"
+				return c
+			}(),
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name: "expired ID token",
+			claims: func() jwt.MapClaims {
+				c := idTokenClaims("ext-1", "music-editors")
+				c["exp"] = int64(900)
+				return c
+			}(),
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "signed with unknown key",
+			claims:      idTokenClaims("ext-1", "music-editors"),
+			signer:      otherSigner,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name: "issued for another client",
+			claims: func() jwt.MapClaims {
+				c := idTokenClaims("ext-1", "music-editors")
+				c["aud"] = "another-client"
+				return c
+			}(),
+			expectError: errors.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := OIDCTestConfig
+			service, m := setupOIDCServiceTest(&cfg)
+
+			login := &OIDCPendingLogin
+			if tt.login != nil {
+				login = tt.login
+			}
+			if tt.consumeErr != nil {
+				m.logins.On("ConsumeOIDCLogin", OIDCState).Return(nil, tt.consumeErr)
+			} else {
+				m.logins.On("ConsumeOIDCLogin", OIDCState).Return(login, nil)
+			}
+			m.provider.On("GetDiscovery", OIDCIssuer).Return(&OIDCTestDiscovery, nil).Maybe()
+
+			exchange := repository.OIDCCodeExchange{
+				Code:         OIDCCode,
+				CodeVerifier: OIDCVerifier,
+				RedirectURI:  cfg.RedirectURL,
+				ClientID:     OIDCClientID,
+			}
+			if tt.exchangeErr != nil {
+				m.provider.On("ExchangeCode", OIDCTestDiscovery.TokenEndpoint, exchange).Return(nil, tt.exchangeErr).Maybe()
+			} else if tt.claims != nil {
+				tokenSigner := signer
+				if tt.signer != nil {
+					tokenSigner = tt.signer
+				}
+				idToken := signIDToken(t, tokenSigner, tt.claims)
+				m.provider.On("ExchangeCode", OIDCTestDiscovery.TokenEndpoint, exchange).Return(&repository.OIDCTokenResponse{IDToken: idToken}, nil)
+			}
+
+			m.provider.On("GetJWKS", OIDCTestDiscovery.JWKSURI).Return(signer.PublicJWKs(), nil).Maybe()
+
+			if tt.expectError == nil {
+				m.auth.On("OpenExternalSession", tt.expectUser, tt.expectRole).Return(&dto.AuthResponse{Token: "access", RefreshToken: "refresh"}, nil)
+			}
+
+			resp, err := service.CompleteLogin(OIDCCode, OIDCState)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, resp)
+				m.auth.AssertNotCalled(t, "OpenExternalSession", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "access", resp.Token)
+			}
+			m.logins.AssertExpectations(t)
+			m.provider.AssertExpectations(t)
+			m.auth.AssertExpectations(t)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		service, m := setupOIDCServiceTest(nil)
+
+		_, err := service.CompleteLogin(OIDCCode, OIDCState)
+
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+		m.logins.AssertNotCalled(t, "ConsumeOIDCLogin", mock.Anything)
+	})
+}
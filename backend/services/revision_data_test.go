@@ -0,0 +1,33 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// OldSongRevision keeps MockedSong as it was before its title and genres were edited.
+var OldSongRevision = models.Revision{
+	SongID:    "1",
+	Revision:  1,
+	Entity:    models.AuditEntitySong,
+	EntityID:  "1",
+	Song:      &models.Song{ID: "1", Title: "Bohemian Rapsody", Author: "Queen", Genres: []string{"Rock"}, YoutubeURL: "https://youtu.be/old"},
+	Actor:     "maria",
+	CreatedAt: "before",
+}
+
+// OldDocumentRevision keeps MockedDocument as it was before its PDF was replaced.
+var OldDocumentRevision = models.Revision{
+	SongID:   "song-123",
+	Revision: 2,
+	Entity:   models.AuditEntityDocument,
+	EntityID: "doc-1",
+	Document: &models.Document{
+		ID:         "doc-1",
+		SongID:     "song-123",
+		Type:       "score",
+		Instrument: []string{"piano"},
+		PDFURL:     "https://example.com/bohemian-piano-v1.pdf",
+		CreatedAt:  "now",
+		UpdatedAt:  "before",
+	},
+	Actor:     "pedro",
+	CreatedAt: "before",
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// RevisionServiceInterface defines operations on the previous versions of songs and their documents.
+type RevisionServiceInterface interface {
+
+	// ListRevisions returns the revisions of a song and its documents, newest first.
+	// Returns:
+	//   - ([]dto.RevisionResponseItem, nil) on success
+	//   - errors.ErrResourceNotFound if the song does not exist
+	//   - error if the revisions cannot be read
+	ListRevisions(songID string) ([]dto.RevisionResponseItem, error)
+
+	// DiffRevisions compares two revisions of the same song or document, or a revision with the
+	// current version when query.To is zero.
+	// Returns:
+	//   - (dto.RevisionDiffResponse, nil) on success
+	//   - errors.ErrValidationFailed if the query is invalid or the revisions copy different entities
+	//   - errors.ErrResourceNotFound if a revision or the current entity does not exist
+	//   - error if the versions cannot be read
+	DiffRevisions(songID string, query dto.RevisionDiffQuery) (dto.RevisionDiffResponse, error)
+
+	// RestoreRevision brings a song or document back to the version copied in a revision.
+	// The version replaced by the restore is kept as a new revision. ifMatch is checked against the
+	// current version of the restored entity.
+	// Returns:
+	//   - the number of the new revision on success
+	//   - errors.ErrResourceNotFound if the revision or the entity it copies does not exist
+	//   - errors.ErrValidationFailed if the song revision names a genre no longer in the catalogue
+	//   - errors.ErrPreconditionFailed if ifMatch does not match the entity or it changed while it was being restored
+	//   - errors.ErrConflict if another update took the number of the new revision
+	//   - error if the restore fails
+	RestoreRevision(actor models.Actor, songID string, revision int, ifMatch dto.Precondition) (int, error)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+// Ensure RevisionService implements RevisionServiceInterface.
+var _ RevisionServiceInterface = (*RevisionService)(nil)
+
+// RevisionService exposes the revisions written by SongService and DocumentService and restores them.
+// A restore is an update like any other: it is audited and keeps the version it replaces as a new revision.
+type RevisionService struct {
+	repo         repository.RevisionRepository
+	songRepo     repository.SongRepository
+	docRepo      repository.DocumentRepository
+	idGen        utils.IDGenerator
+	timeProvider utils.TimeProvider
+	indexer      CatalogIndexer
+	genres       GenreServiceInterface
+}
+
+// NewRevisionService returns a new instance of RevisionService.
+func NewRevisionService(
+	repo repository.RevisionRepository,
+	songRepo repository.SongRepository,
+	docRepo repository.DocumentRepository,
+	idGen utils.IDGenerator,
+	timeProvider utils.TimeProvider,
+	indexer CatalogIndexer,
+	genres GenreServiceInterface,
+) *RevisionService {
+	return &RevisionService{
+		repo:         repo,
+		songRepo:     songRepo,
+		docRepo:      docRepo,
+		idGen:        idGen,
+		timeProvider: timeProvider,
+		indexer:      indexer,
+		genres:       genres,
+	}
+}
+
+// ListRevisions returns the revisions of a song and its documents, newest first.
+func (s *RevisionService) ListRevisions(songID string) ([]dto.RevisionResponseItem, error) {
+	if _, err := s.songRepo.GetSongByID(songID); err != nil {
+		return nil, fmt.Errorf("checking existence of song %s: %w", songID, err)
+	}
+
+	revisions, err := s.repo.GetRevisionsBySongID(songID)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving revisions of song %s: %w", songID, err)
+	}
+	return dto.ToRevisionResponseList(revisions), nil
+}
+
+// DiffRevisions compares revision query.From with revision query.To, or with the current version
+// of the same entity when query.To is zero.
+func (s *RevisionService) DiffRevisions(songID string, query dto.RevisionDiffQuery) (dto.RevisionDiffResponse, error) {
+	if err := dto.ValidateRevisionDiffQuery(query); err != nil {
+		return dto.RevisionDiffResponse{}, fmt.Errorf("validating revision diff query: %w", err)
+	}
+
+	from, err := s.repo.GetRevision(songID, query.From)
+	if err != nil {
+		return dto.RevisionDiffResponse{}, fmt.Errorf("retrieving revision %d of song %s: %w", query.From, songID, err)
+	}
+
+	var to interface{}
+	if query.To > 0 {
+		rev, err := s.repo.GetRevision(songID, query.To)
+		if err != nil {
+			return dto.RevisionDiffResponse{}, fmt.Errorf("retrieving revision %d of song %s: %w", query.To, songID, err)
+		}
+		if rev.Entity != from.Entity || rev.EntityID != from.EntityID {
+			return dto.RevisionDiffResponse{}, fmt.Errorf("revisions %d and %d copy different entities: %w", query.From, query.To, errors.ErrValidationFailed)
+		}
+		to = revisionSnapshot(*rev)
+	} else if to, err = s.currentVersion(*from); err != nil {
+		return dto.RevisionDiffResponse{}, err
+	}
+
+	return dto.RevisionDiffResponse{
+		SongID:   songID,
+		Entity:   from.Entity,
+		EntityID: from.EntityID,
+		From:     query.From,
+		To:       query.To,
+		Changes:  dto.ToFieldChangeResponseList(diffFields(revisionSnapshot(*from), to)),
+	}, nil
+}
+
+// RestoreRevision brings the song or document copied in a revision back to that version.
+// Song revisions restore the title, author, genres and YouTube link; document revisions restore
+// the type, instruments, PDF and audio files. IDs and creation timestamps are never changed.
+// Genres are resolved against the current catalogue, so a renamed genre comes back under its new
+// name and a song revision naming a genre that was removed cannot be restored. ifMatch is checked
+// against the current version of the restored song or document.
+func (s *RevisionService) RestoreRevision(actor models.Actor, songID string, number int, ifMatch dto.Precondition) (int, error) {
+	rev, err := s.repo.GetRevision(songID, number)
+	if err != nil {
+		return 0, fmt.Errorf("retrieving revision %d of song %s: %w", number, songID, err)
+	}
+
+	switch {
+	case rev.Song != nil:
+		return s.restoreSong(actor, songID, *rev.Song, ifMatch)
+	case rev.Document != nil:
+		return s.restoreDocument(actor, songID, *rev.Document, ifMatch)
+	}
+	return 0, fmt.Errorf("revision %d of song %s has no copy: %w", number, songID, errors.ErrInternalServer)
+}
+
+func (s *RevisionService) restoreSong(actor models.Actor, songID string, snapshot models.Song, ifMatch dto.Precondition) (int, error) {
+	song, err := songAt(s.songRepo, songID, ifMatch)
+	if err != nil {
+		return 0, err
+	}
+
+	genres, err := s.genres.ResolveGenres(snapshot.Genres)
+	if err != nil {
+		return 0, fmt.Errorf("resolving genres of the revision of song %s: %w", songID, err)
+	}
+
+	now := s.timeProvider.Now()
+	restored := *song
+	restored.Title = snapshot.Title
+	restored.TitleNormalized = utils.Normalize(snapshot.Title)
+	restored.Author = snapshot.Author
+	restored.Genres = genres
+	restored.YoutubeURL = snapshot.YoutubeURL
+	restored.UpdatedAt = now
+	restored.Version = song.Version + 1
+
+	updateMap := map[string]interface{}{
+		"title":            restored.Title,
+		"title_normalized": restored.TitleNormalized,
+		"author":           restored.Author,
+		"genres":           restored.Genres,
+		"youtube_url":      restored.YoutubeURL,
+		"updated_at":       now,
+	}
+
+	revision, err := newRevision(s.repo, actor, now, songID)
+	if err != nil {
+		return 0, err
+	}
+	revision.Entity = models.AuditEntitySong
+	revision.EntityID = songID
+	revision.Song = song
+
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntitySong, songID, songID, models.AuditActionUpdate, song, &restored)
+	if err := s.songRepo.UpdateSong(songID, song.Version, updateMap, audit, revision); err != nil {
+		return 0, fmt.Errorf("restoring song %s: %w", songID, err)
+	}
+
+	s.indexer.IndexSong(restored)
+	return revision.Revision, nil
+}
+
+func (s *RevisionService) restoreDocument(actor models.Actor, songID string, snapshot models.Document, ifMatch dto.Precondition) (int, error) {
+	song, err := s.songRepo.GetSongByID(songID)
+	if err != nil {
+		return 0, fmt.Errorf("retrieving song for restore of document %s: %w", snapshot.ID, err)
+	}
+
+	doc, err := documentAt(s.docRepo, songID, snapshot.ID, ifMatch)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.timeProvider.Now()
+	restored := *doc
+	restored.Type = snapshot.Type
+	restored.Instrument = snapshot.Instrument
+	restored.PDFURL = snapshot.PDFURL
+	restored.AudioURL = snapshot.AudioURL
+	restored.Title = song.Title
+	restored.TitleNormalized = utils.Normalize(song.Title)
+	restored.UpdatedAt = now
+	restored.Version = doc.Version + 1
+
+	updateMap := map[string]interface{}{
+		"type":             restored.Type,
+		"instrument":       restored.Instrument,
+		"pdf_url":          restored.PDFURL,
+		"audio_url":        restored.AudioURL,
+		"title":            restored.Title,
+		"title_normalized": restored.TitleNormalized,
+		"updated_at":       now,
+	}
+
+	revision, err := newRevision(s.repo, actor, now, songID)
+	if err != nil {
+		return 0, err
+	}
+	revision.Entity = models.AuditEntityDocument
+	revision.EntityID = doc.ID
+	revision.Document = doc
+
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntityDocument, doc.ID, songID, models.AuditActionUpdate, doc, &restored)
+	if err := s.docRepo.UpdateDocument(songID, doc.ID, doc.Version, updateMap, audit, revision); err != nil {
+		return 0, fmt.Errorf("restoring document %s for song %s: %w", doc.ID, songID, err)
+	}
+
+	s.indexer.IndexDocument(restored)
+	return revision.Revision, nil
+}
+
+// currentVersion returns the entity copied in rev as it is stored now.
+func (s *RevisionService) currentVersion(rev models.Revision) (interface{}, error) {
+	if rev.Entity == models.AuditEntityDocument {
+		doc, err := s.docRepo.GetDocumentByID(rev.SongID, rev.EntityID)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving document %s: %w", rev.EntityID, err)
+		}
+		return doc, nil
+	}
+	song, err := s.songRepo.GetSongByID(rev.SongID)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving song %s: %w", rev.SongID, err)
+	}
+	return song, nil
+}
+
+// revisionSnapshot returns the copy held by rev, in the form compared by diffFields.
+func revisionSnapshot(rev models.Revision) interface{} {
+	if rev.Document != nil {
+		return rev.Document
+	}
+	return rev.Song
+}
+
+// newRevision numbers the revision that keeps the version replaced by an update as the next one of
+// the song. The caller fills in the copy. If another update takes the same number first, the
+// repository rejects the write with errors.ErrConflict.
+func newRevision(repo repository.RevisionRepository, actor models.Actor, timestamp, songID string) (models.Revision, error) {
+	latest, err := repo.GetLatestRevisionNumber(songID)
+	if err != nil {
+		return models.Revision{}, fmt.Errorf("numbering revision of song %s: %w", songID, err)
+	}
+	return models.Revision{
+		SongID:    songID,
+		Revision:  latest + 1,
+		Actor:     actor.Username,
+		RequestID: actor.RequestID,
+		CreatedAt: timestamp,
+	}, nil
+}
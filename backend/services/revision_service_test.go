@@ -0,0 +1,283 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type revisionServiceMocks struct {
+	revisionRepo *mocks.MockRevisionRepository
+	songRepo     *mocks.MockSongRepository
+	docRepo      *mocks.MockDocumentRepository
+	idGen        *mocks.MockIDGenerator
+	timeProvider *mocks.MockTimeProvider
+	genres       *mocks.MockGenreService
+}
+
+func setupRevisionServiceTest() (*services.RevisionService, revisionServiceMocks) {
+	m := revisionServiceMocks{
+		revisionRepo: new(mocks.MockRevisionRepository),
+		songRepo:     new(mocks.MockSongRepository),
+		docRepo:      new(mocks.MockDocumentRepository),
+		idGen:        new(mocks.MockIDGenerator),
+		timeProvider: new(mocks.MockTimeProvider),
+		genres:       new(mocks.MockGenreService),
+	}
+	m.idGen.On("NewID").Return("audit-1").Maybe()
+	m.timeProvider.On("Now").Return("now").Maybe()
+	service := services.NewRevisionService(m.revisionRepo, m.songRepo, m.docRepo, m.idGen, m.timeProvider, newMockCatalogIndexer(), m.genres)
+	return service, m
+}
+
+func TestListRevisions(t *testing.T) {
+	tests := []struct {
+		name        string
+		songErr     error
+		expectError error
+	}{
+		{name: "song with revisions"},
+		{name: "unknown song", songErr: errors.ErrResourceNotFound, expectError: errors.ErrResourceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupRevisionServiceTest()
+			if tt.songErr != nil {
+				m.songRepo.On("GetSongByID", "1").Return(nil, tt.songErr)
+			} else {
+				m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+				m.revisionRepo.On("GetRevisionsBySongID", "1").Return([]models.Revision{OldSongRevision}, nil)
+			}
+
+			revisions, err := service.ListRevisions("1")
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				m.revisionRepo.AssertNotCalled(t, "GetRevisionsBySongID", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, revisions, 1) {
+				assert.Equal(t, 1, revisions[0].Revision)
+				assert.Equal(t, "Bohemian Rapsody", revisions[0].Song.Title)
+				assert.Nil(t, revisions[0].Document)
+			}
+		})
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	laterSongRevision := OldSongRevision
+	laterSongRevision.Revision = 3
+	laterSongRevision.Song = &models.Song{ID: "1", Title: "Bohemian Rhapsody", Author: "Queen", Genres: []string{"Rock"}, YoutubeURL: "https://youtu.be/old"}
+
+	tests := []struct {
+		name          string
+		query         dto.RevisionDiffQuery
+		expectChanges []dto.FieldChangeResponseItem
+		expectError   error
+	}{
+		{
+			name:  "revision against current song",
+			query: dto.RevisionDiffQuery{From: 1},
+			expectChanges: []dto.FieldChangeResponseItem{
+				{Field: "genres", Before: []interface{}{"Rock"}},
+				{Field: "title", Before: "Bohemian Rapsody", After: "Bohemian Rhapsody"},
+				{Field: "youtube_url", Before: "https://youtu.be/old"},
+			},
+		},
+		{
+			name:          "two revisions of the song",
+			query:         dto.RevisionDiffQuery{From: 1, To: 3},
+			expectChanges: []dto.FieldChangeResponseItem{{Field: "title", Before: "Bohemian Rapsody", After: "Bohemian Rhapsody"}},
+		},
+		{
+			name:  "revision against current document",
+			query: dto.RevisionDiffQuery{From: 2},
+			expectChanges: []dto.FieldChangeResponseItem{
+				{Field: "pdf_url", Before: "https://example.com/bohemian-piano-v1.pdf", After: MockedDocument.PDFURL},
+			},
+		},
+		{name: "revisions of different entities", query: dto.RevisionDiffQuery{From: 1, To: 2}, expectError: errors.ErrValidationFailed},
+		{name: "missing from", query: dto.RevisionDiffQuery{To: 2}, expectError: errors.ErrValidationFailed},
+		{name: "same revision", query: dto.RevisionDiffQuery{From: 2, To: 2}, expectError: errors.ErrValidationFailed},
+		{name: "unknown revision", query: dto.RevisionDiffQuery{From: 9}, expectError: errors.ErrResourceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupRevisionServiceTest()
+			m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil).Maybe()
+			m.revisionRepo.On("GetRevision", "1", 2).Return(&OldDocumentRevision, nil).Maybe()
+			m.revisionRepo.On("GetRevision", "1", 3).Return(&laterSongRevision, nil).Maybe()
+			m.revisionRepo.On("GetRevision", "1", 9).Return(nil, errors.ErrResourceNotFound).Maybe()
+			m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil).Maybe()
+			m.docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil).Maybe()
+
+			diff, err := service.DiffRevisions("1", tt.query)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.query.From, diff.From)
+			assert.Equal(t, tt.query.To, diff.To)
+			assert.Equal(t, tt.expectChanges, diff.Changes)
+		})
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	t.Run("song revision", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil)
+		m.revisionRepo.On("GetLatestRevisionNumber", "1").Return(4, nil)
+		m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+		m.genres.On("ResolveGenres", []string{"Rock"}).Return([]string{"Rock"}, nil)
+
+		var updates map[string]interface{}
+		var kept models.Revision
+		m.songRepo.On("UpdateSong", "1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updates = args.Get(2).(map[string]interface{})
+			kept = args.Get(4).(models.Revision)
+		}).Return(nil)
+
+		revision, err := service.RestoreRevision(EditorActor, "1", 1, dto.Precondition{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, revision)
+		assert.Equal(t, "Bohemian Rapsody", updates["title"])
+		assert.Equal(t, "bohemian rapsody", updates["title_normalized"])
+		assert.Equal(t, []string{"Rock"}, updates["genres"])
+		assert.Equal(t, "https://youtu.be/old", updates["youtube_url"])
+		assert.Equal(t, 5, kept.Revision)
+		assert.Equal(t, &MockedSong, kept.Song)
+		assert.Equal(t, "maria", kept.Actor)
+	})
+
+	t.Run("song revision with a renamed genre", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil)
+		m.revisionRepo.On("GetLatestRevisionNumber", "1").Return(4, nil)
+		m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+		m.genres.On("ResolveGenres", []string{"Rock"}).Return([]string{"Rock & Roll"}, nil)
+
+		var updates map[string]interface{}
+		m.songRepo.On("UpdateSong", "1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updates = args.Get(2).(map[string]interface{})
+		}).Return(nil)
+
+		_, err := service.RestoreRevision(EditorActor, "1", 1, dto.Precondition{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Rock & Roll"}, updates["genres"])
+	})
+
+	t.Run("song revision with a removed genre", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil)
+		m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+		m.genres.On("ResolveGenres", []string{"Rock"}).Return(nil, errors.ErrValidationFailed)
+
+		_, err := service.RestoreRevision(EditorActor, "1", 1, dto.Precondition{})
+
+		assert.ErrorIs(t, err, errors.ErrValidationFailed)
+		m.songRepo.AssertNotCalled(t, "UpdateSong", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("document revision restores the earlier file", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "song-123", 2).Return(&OldDocumentRevision, nil)
+		m.revisionRepo.On("GetLatestRevisionNumber", "song-123").Return(2, nil)
+		m.songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+		m.docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil)
+
+		var updates map[string]interface{}
+		var audit models.AuditEntry
+		m.docRepo.On("UpdateDocument", "song-123", "doc-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updates = args.Get(3).(map[string]interface{})
+			audit = args.Get(4).(models.AuditEntry)
+		}).Return(nil)
+
+		revision, err := service.RestoreRevision(EditorActor, "song-123", 2, dto.Precondition{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, revision)
+		assert.Equal(t, "https://example.com/bohemian-piano-v1.pdf", updates["pdf_url"])
+		assert.Equal(t, []models.FieldChange{
+			{Field: "pdf_url", Before: MockedDocument.PDFURL, After: "https://example.com/bohemian-piano-v1.pdf"},
+		}, audit.Changes)
+	})
+
+	t.Run("deleted document", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "song-123", 2).Return(&OldDocumentRevision, nil)
+		m.songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+		m.docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(nil, errors.ErrResourceNotFound)
+
+		_, err := service.RestoreRevision(EditorActor, "song-123", 2, dto.Precondition{})
+
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+		m.docRepo.AssertNotCalled(t, "UpdateDocument", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("song revision re-reads a stale cached song before checking If-Match", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		stored := MockedSong
+		stored.Version = 3
+		m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil)
+		m.revisionRepo.On("GetLatestRevisionNumber", "1").Return(4, nil)
+		m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+		m.songRepo.On("GetSongByIDUncached", "1").Return(&stored, nil)
+		m.genres.On("ResolveGenres", []string{"Rock"}).Return([]string{"Rock"}, nil)
+		m.songRepo.On("UpdateSong", "1", 3, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		revision, err := service.RestoreRevision(EditorActor, "1", 1, dto.NewPrecondition(`"3"`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, revision)
+		m.songRepo.AssertExpectations(t)
+	})
+
+	t.Run("song revision with a stale If-Match", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "1", 1).Return(&OldSongRevision, nil)
+		m.songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+		m.songRepo.On("GetSongByIDUncached", "1").Return(&MockedSong, nil)
+
+		_, err := service.RestoreRevision(EditorActor, "1", 1, dto.NewPrecondition(`"99"`))
+
+		assert.ErrorIs(t, err, errors.ErrPreconditionFailed)
+		m.songRepo.AssertNotCalled(t, "UpdateSong", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("document revision with a stale If-Match", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "song-123", 2).Return(&OldDocumentRevision, nil)
+		m.songRepo.On("GetSongByID", "song-123").Return(&RelatedSong, nil)
+		m.docRepo.On("GetDocumentByID", "song-123", "doc-1").Return(&MockedDocument, nil)
+		m.docRepo.On("GetDocumentByIDUncached", "song-123", "doc-1").Return(&MockedDocument, nil)
+
+		_, err := service.RestoreRevision(EditorActor, "song-123", 2, dto.NewPrecondition(`"99"`))
+
+		assert.ErrorIs(t, err, errors.ErrPreconditionFailed)
+		m.docRepo.AssertNotCalled(t, "UpdateDocument", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		service, m := setupRevisionServiceTest()
+		m.revisionRepo.On("GetRevision", "1", 7).Return(nil, errors.ErrResourceNotFound)
+
+		_, err := service.RestoreRevision(EditorActor, "1", 7, dto.Precondition{})
+
+		assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+	})
+}
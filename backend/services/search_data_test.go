@@ -42,3 +42,56 @@ var DocumentGuitarTab = models.Document{
 
 var ValidNextToken = map[string]string{"last_id": "2"}
 var ReturnedNextToken = map[string]string{"last_id": "5"}
+
+var SongLoveOfMyLife = models.Song{
+	ID:              "3",
+	Title:           "Love of My Life",
+	TitleNormalized: "love of my life",
+	Author:          "Queen",
+}
+
+var SongSomebodyToLove = models.Song{
+	ID:              "4",
+	Title:           "Somebody to Love",
+	TitleNormalized: "somebody to love",
+	Author:          "Queen",
+}
+
+var SongLove = models.Song{
+	ID:              "5",
+	Title:           "Love",
+	TitleNormalized: "love",
+	Author:          "The Cult",
+}
+
+var DocumentLoveOfMyLifeScore = models.Document{
+	ID:              "d3",
+	SongID:          "3",
+	Type:            "score",
+	Instrument:      []string{"guitar"},
+	TitleNormalized: "love of my life",
+}
+
+var DocumentOrphanedLove = models.Document{
+	ID:              "d4",
+	SongID:          "missing-song",
+	Type:            "score",
+	Instrument:      []string{"piano"},
+	TitleNormalized: "lovely day",
+}
+
+var SongBesameMucho = models.Song{
+	ID:              "6",
+	Title:           "Bésame Mucho",
+	TitleNormalized: "besame mucho",
+	Author:          "Consuelo Velázquez",
+}
+
+var DocumentBesameMuchoTab = models.Document{
+	ID:              "d6",
+	SongID:          "6",
+	Type:            "tablature",
+	Instrument:      []string{"guitar"},
+	Title:           "Bésame Mucho",
+	TitleNormalized: "besame mucho",
+}
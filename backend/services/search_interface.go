@@ -1,6 +1,7 @@
 package services
 
 import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 )
@@ -25,7 +26,7 @@ type SearchServiceInterface interface {
 	// ListDocuments returns a paginated list of documents filtered by title, instrument, and type.
 	// Parameters:
 	//   - title: optional title filter (normalized)
-	//   - instrument: optional instrument filter
+	//   - instrument: optional instrument or family filter (resolved through the instrument catalogue)
 	//   - docType: optional document type filter ("score", "tablature", etc.)
 	//   - sortField: "title" or "created_at"
 	//   - sortOrder: "asc" or "desc"
@@ -36,4 +37,15 @@ type SearchServiceInterface interface {
 	//   - a token for the next page (or nil)
 	//   - error if the query fails
 	ListDocuments(title, instrument, docType, sortField, sortOrder string, limit int, nextToken repository.PagingKey) ([]models.Document, repository.PagingKey, error)
+
+	// SearchCatalog searches songs and documents together and returns a ranked, typed result list.
+	// Parameters:
+	//   - query: search term (normalized internally)
+	//   - limit: max number of results to return
+	//   - cursor: opaque token returned by a previous call, or "" for the first page
+	// Returns:
+	//   - a page of ranked results with the per-type counts of its segment and the token for the next page
+	//   - errors.ErrBadRequest if the cursor is invalid
+	//   - error if the search fails
+	SearchCatalog(query string, limit int, cursor string) (dto.SearchResponse, error)
 }
@@ -1,10 +1,16 @@
 package services
 
 import (
+	stdErrors "errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 )
 
 // Ensure SearchService implements SearchServiceInterface.
@@ -13,12 +19,22 @@ var _ SearchServiceInterface = (*SearchService)(nil)
 // SearchService provides business-level search functionality
 // for songs and documents with optional filters and sorting.
 type SearchService struct {
-	repo repository.SearchRepository
+	repo        repository.SearchRepository
+	songRepo    repository.SongRepository
+	instruments InstrumentServiceInterface
 }
 
 // NewSearchService returns a new instance of SearchService.
-func NewSearchService(repo repository.SearchRepository) *SearchService {
-	return &SearchService{repo: repo}
+func NewSearchService(
+	repo repository.SearchRepository,
+	songRepo repository.SongRepository,
+	instruments InstrumentServiceInterface,
+) *SearchService {
+	return &SearchService{
+		repo:        repo,
+		songRepo:    songRepo,
+		instruments: instruments,
+	}
 }
 
 // ListSongs returns a filtered and sorted list of songs with pagination support.
@@ -35,11 +51,21 @@ func (s *SearchService) ListSongs(title, sortField, sortOrder string, limit int,
 }
 
 // ListDocuments returns a filtered and sorted list of documents with pagination support.
-// It validates sorting parameters before forwarding the request to the repository.
+// It validates sorting parameters and expands the instrument filter through the catalogue
+// (aliases and families) before forwarding the request to the repository.
 func (s *SearchService) ListDocuments(title, instrument, docType, sortField, sortOrder string, limit int, nextToken repository.PagingKey) ([]models.Document, repository.PagingKey, error) {
 	sortField, sortOrder = applySortingDefaults(sortField, sortOrder)
 
-	documents, next, err := s.repo.ListDocuments(title, instrument, docType, sortField, sortOrder, limit, nextToken)
+	var instruments []string
+	if instrument != "" {
+		expanded, err := s.instruments.ExpandInstrumentQuery(instrument)
+		if err != nil {
+			return nil, nil, fmt.Errorf("expanding instrument filter %q: %w", instrument, err)
+		}
+		instruments = expanded
+	}
+
+	documents, next, err := s.repo.ListDocuments(title, instruments, docType, sortField, sortOrder, limit, nextToken)
 	if err != nil {
 		return nil, nil, fmt.Errorf("listing documents: %w", err)
 	}
@@ -57,3 +83,161 @@ func applySortingDefaults(sortField, sortOrder string) (string, string) {
 	}
 	return sortField, sortOrder
 }
+
+// maxSearchHits is how many songs, and how many documents, SearchCatalog ranks together. When more items
+// match, the catalogue is searched in consecutive segments of at most this many hits of each type, each
+// ranked on its own, so that a request never reads the whole catalogue.
+const maxSearchHits = 100
+
+// maxSearchSegments is how many segments one SearchCatalog request reads at most while they hold no hit,
+// so that sparse matches in a large catalogue do not leave the client paging through empty responses.
+const maxSearchSegments = 4
+
+// Relevance scores assigned to search hits, from strongest to weakest match.
+const (
+	scoreExactMatch      = 100
+	scorePrefixMatch     = 75
+	scoreWordPrefixMatch = 50
+	scoreContainsMatch   = 25
+)
+
+// SearchCatalog searches songs and documents together and returns a single ranked list.
+// Hits are ranked by how closely the normalized title matches the query; on ties songs come
+// before documents and titles are ordered alphabetically. Each document hit embeds a summary
+// of its parent song. Every hit carries highlight spans that point into the original
+// (non-normalized) title and author.
+// Hits are read in segments of at most maxSearchHits songs and maxSearchHits documents, ranked and counted
+// within their segment, which is the whole catalogue unless more items match; the response tells which.
+// Segments without hits are skipped, up to maxSearchSegments per request, so a page can only be empty
+// with a next token after that many. The cursor holds where the scan of the current segment started and
+// the offset into its ranking; the page after the last one of a segment resumes the scan.
+// Returns:
+//   - the requested page, per-type counts of its segment and the token for the next page (if any)
+//   - errors.ErrBadRequest if the cursor is malformed
+//   - error if the repository search fails
+func (s *SearchService) SearchCatalog(query string, limit int, cursor string) (dto.SearchResponse, error) {
+	segment, offset, err := utils.DecodeSegmentCursor(cursor)
+	if err != nil {
+		return dto.SearchResponse{}, fmt.Errorf("decoding search cursor: %w", err)
+	}
+
+	var songs []models.Song
+	var documents []models.Document
+	var nextSegment string
+	for read := 1; ; read++ {
+		songs, documents, nextSegment, err = s.repo.SearchCatalog(query, maxSearchHits, segment)
+		if err != nil {
+			return dto.SearchResponse{}, fmt.Errorf("searching catalog for %q: %w", query, err)
+		}
+		if len(songs)+len(documents) > 0 || nextSegment == "" || read == maxSearchSegments {
+			break
+		}
+		segment, offset = nextSegment, 0
+	}
+
+	normalizedQuery := utils.Normalize(strings.TrimSpace(query))
+	parents := make(map[string]dto.SongSummary, len(songs))
+	results := make([]dto.SearchResultItem, 0, len(songs)+len(documents))
+
+	for _, song := range songs {
+		parents[song.ID] = dto.ToSongSummary(song)
+		item := dto.ToSongResponseItem(song)
+		results = append(results, dto.SearchResultItem{
+			Type:       dto.SearchResultTypeSong,
+			Score:      scoreTitleMatch(song.TitleNormalized, normalizedQuery),
+			Song:       &item,
+			Highlights: dto.ToSongHighlights(song, query),
+		})
+	}
+
+	for _, doc := range documents {
+		parent, err := s.resolveParentSong(doc.SongID, parents)
+		if err != nil {
+			return dto.SearchResponse{}, fmt.Errorf("resolving parent song for document %s: %w", doc.ID, err)
+		}
+		hit := dto.ToDocumentSearchHit(doc, parent)
+		results = append(results, dto.SearchResultItem{
+			Type:       dto.SearchResultTypeDocument,
+			Score:      scoreTitleMatch(doc.TitleNormalized, normalizedQuery),
+			Document:   &hit,
+			Highlights: dto.ToDocumentHighlights(doc, query),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type == dto.SearchResultTypeSong
+		}
+		return searchResultTitle(results[i]) < searchResultTitle(results[j])
+	})
+
+	response := dto.SearchResponse{
+		Data: []dto.SearchResultItem{},
+		SegmentCounts: dto.SearchCounts{
+			Songs:     len(songs),
+			Documents: len(documents),
+		},
+		Complete: segment == "" && nextSegment == "",
+	}
+
+	end := len(results)
+	if offset < len(results) {
+		end = min(offset+limit, len(results))
+		response.Data = results[offset:end]
+	}
+	switch {
+	case end < len(results):
+		response.NextToken = utils.EncodeSegmentCursor(segment, end)
+	case nextSegment != "":
+		response.NextToken = utils.EncodeSegmentCursor(nextSegment, 0)
+	}
+
+	return response, nil
+}
+
+// resolveParentSong returns the summary of a document's song, looking it up when it was not part of the hits.
+// A missing parent is tolerated and reported as a summary containing only the song ID.
+func (s *SearchService) resolveParentSong(songID string, parents map[string]dto.SongSummary) (dto.SongSummary, error) {
+	if summary, ok := parents[songID]; ok {
+		return summary, nil
+	}
+
+	song, err := s.songRepo.GetSongByID(songID)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return dto.SongSummary{ID: songID}, nil
+		}
+		return dto.SongSummary{}, err
+	}
+
+	summary := dto.ToSongSummary(*song)
+	parents[songID] = summary
+	return summary, nil
+}
+
+// scoreTitleMatch rates how well a normalized title matches a normalized query.
+func scoreTitleMatch(title, query string) int {
+	switch {
+	case query == "":
+		return scoreContainsMatch
+	case title == query:
+		return scoreExactMatch
+	case strings.HasPrefix(title, query):
+		return scorePrefixMatch
+	case strings.Contains(title, " "+query):
+		return scoreWordPrefixMatch
+	default:
+		return scoreContainsMatch
+	}
+}
+
+// searchResultTitle returns the title used to break ranking ties between search hits.
+func searchResultTitle(item dto.SearchResultItem) string {
+	if item.Song != nil {
+		return utils.Normalize(item.Song.Title)
+	}
+	return utils.Normalize(item.Document.Song.Title)
+}
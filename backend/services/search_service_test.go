@@ -3,10 +3,12 @@ package services_test
 import (
 	"testing"
 
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -84,7 +86,7 @@ func TestListSongs(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(mocks.MockSearchRepository)
-			service := services.NewSearchService(repo)
+			service := services.NewSearchService(repo, new(mocks.MockSongRepository), newInstrumentService())
 
 			repo.On("ListSongs", tt.title, mock.Anything, mock.Anything, tt.limit, tt.nextToken).
 				Return(tt.mockSongs, tt.mockNext, tt.mockError)
@@ -107,6 +109,7 @@ func TestListDocuments(t *testing.T) {
 		name         string
 		title        string
 		instrument   string
+		instruments  []string
 		docType      string
 		sortField    string
 		sortOrder    string
@@ -128,6 +131,7 @@ func TestListDocuments(t *testing.T) {
 		{
 			name:         "filter by instrument",
 			instrument:   "guitar",
+			instruments:  []string{"guitar", "Guitarra", "acoustic guitar"},
 			mockDocs:     []models.Document{DocumentGuitarTab},
 			expectedSize: 1,
 		},
@@ -135,12 +139,27 @@ func TestListDocuments(t *testing.T) {
 			name:         "combined filters and sort",
 			title:        "love",
 			instrument:   "violin",
+			instruments:  []string{"violin", "Violín", "fiddle"},
 			docType:      "sheet_music",
 			sortField:    "title",
 			sortOrder:    "asc",
 			mockDocs:     []models.Document{DocumentPianoScore, DocumentGuitarTab},
 			expectedSize: 2,
 		},
+		{
+			name:         "family expands to its members",
+			instrument:   "Voces",
+			instruments:  []string{"voice", "Voz", "soprano", "alto", "tenor", "baritone", "bass"},
+			mockDocs:     []models.Document{DocumentPianoScore},
+			expectedSize: 1,
+		},
+		{
+			name:         "unknown instrument is passed through",
+			instrument:   "theremin",
+			instruments:  []string{"theremin"},
+			mockDocs:     []models.Document{},
+			expectedSize: 0,
+		},
 		{
 			name:         "with next token",
 			nextToken:    map[string]string{"last_id": "d1"},
@@ -159,9 +178,20 @@ func TestListDocuments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(mocks.MockSearchRepository)
-			service := services.NewSearchService(repo)
+			service := services.NewSearchService(repo, new(mocks.MockSongRepository), newInstrumentService())
 
-			repo.On("ListDocuments", tt.title, tt.instrument, tt.docType, mock.Anything, mock.Anything, tt.limit, tt.nextToken).
+			matchInstruments := mock.MatchedBy(func(values []string) bool {
+				if len(tt.instruments) == 0 {
+					return len(values) == 0
+				}
+				for _, expected := range tt.instruments {
+					if !containsString(values, expected) {
+						return false
+					}
+				}
+				return true
+			})
+			repo.On("ListDocuments", tt.title, matchInstruments, tt.docType, mock.Anything, mock.Anything, tt.limit, tt.nextToken).
 				Return(tt.mockDocs, tt.mockNext, tt.mockError)
 
 			docs, next, err := service.ListDocuments(
@@ -184,3 +214,267 @@ func TestListDocuments(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchCatalog(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		limit          int
+		cursor         string
+		mockSegment    string
+		mockSongs      []models.Song
+		mockDocs       []models.Document
+		mockNext       string
+		mockRepoError  error
+		mockParents    map[string]*models.Song
+		expectError    error
+		expectedTypes  []string
+		expectedIDs    []string
+		expectedNext   string
+		expectedCounts dto.SearchCounts
+		expectComplete bool
+	}{
+		{
+			name:           "ranks exact, prefix and word matches",
+			query:          "Love",
+			limit:          10,
+			mockSongs:      []models.Song{SongSomebodyToLove, SongLoveOfMyLife, SongLove},
+			mockDocs:       []models.Document{DocumentLoveOfMyLifeScore},
+			expectedTypes:  []string{"song", "song", "document", "song"},
+			expectedIDs:    []string{"5", "3", "d3", "4"},
+			expectedCounts: dto.SearchCounts{Songs: 3, Documents: 1},
+			expectComplete: true,
+		},
+		{
+			name:           "paginates the merged list",
+			query:          "love",
+			limit:          2,
+			mockSongs:      []models.Song{SongSomebodyToLove, SongLoveOfMyLife, SongLove},
+			mockDocs:       []models.Document{DocumentLoveOfMyLifeScore},
+			expectedTypes:  []string{"song", "song"},
+			expectedIDs:    []string{"5", "3"},
+			expectedNext:   utils.EncodeSegmentCursor("", 2),
+			expectedCounts: dto.SearchCounts{Songs: 3, Documents: 1},
+			expectComplete: true,
+		},
+		{
+			name:           "resumes from cursor",
+			query:          "love",
+			limit:          2,
+			cursor:         utils.EncodeSegmentCursor("", 2),
+			mockSongs:      []models.Song{SongSomebodyToLove, SongLoveOfMyLife, SongLove},
+			mockDocs:       []models.Document{DocumentLoveOfMyLifeScore},
+			expectedTypes:  []string{"document", "song"},
+			expectedIDs:    []string{"d3", "4"},
+			expectedCounts: dto.SearchCounts{Songs: 3, Documents: 1},
+			expectComplete: true,
+		},
+		{
+			name:           "continues the scan after the last page of a segment",
+			query:          "love",
+			limit:          2,
+			cursor:         utils.EncodeSegmentCursor("", 2),
+			mockSongs:      []models.Song{SongSomebodyToLove, SongLoveOfMyLife, SongLove},
+			mockDocs:       []models.Document{DocumentLoveOfMyLifeScore},
+			mockNext:       "segment-2",
+			expectedTypes:  []string{"document", "song"},
+			expectedIDs:    []string{"d3", "4"},
+			expectedNext:   utils.EncodeSegmentCursor("segment-2", 0),
+			expectedCounts: dto.SearchCounts{Songs: 3, Documents: 1},
+		},
+		{
+			name:           "reads the segment of the cursor",
+			query:          "love",
+			limit:          10,
+			cursor:         utils.EncodeSegmentCursor("segment-2", 0),
+			mockSegment:    "segment-2",
+			mockSongs:      []models.Song{},
+			mockDocs:       []models.Document{DocumentLoveOfMyLifeScore},
+			mockParents:    map[string]*models.Song{DocumentLoveOfMyLifeScore.SongID: &SongLoveOfMyLife},
+			expectedTypes:  []string{"document"},
+			expectedIDs:    []string{"d3"},
+			expectedCounts: dto.SearchCounts{Songs: 0, Documents: 1},
+		},
+		{
+			name:           "looks up parent song missing from hits",
+			query:          "lovely",
+			limit:          10,
+			mockSongs:      []models.Song{},
+			mockDocs:       []models.Document{DocumentOrphanedLove},
+			mockParents:    map[string]*models.Song{"missing-song": nil},
+			expectedTypes:  []string{"document"},
+			expectedIDs:    []string{"d4"},
+			expectedCounts: dto.SearchCounts{Songs: 0, Documents: 1},
+			expectComplete: true,
+		},
+		{
+			name:        "invalid cursor",
+			query:       "love",
+			limit:       10,
+			cursor:      "not-a-cursor",
+			expectError: errors.ErrBadRequest,
+		},
+		{
+			name:          "repository error",
+			query:         "love",
+			limit:         10,
+			mockRepoError: errors.ErrInternalServer,
+			expectError:   errors.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockSearchRepository)
+			songRepo := new(mocks.MockSongRepository)
+			service := services.NewSearchService(repo, songRepo, newInstrumentService())
+
+			repo.On("SearchCatalog", tt.query, mock.Anything, tt.mockSegment).Return(tt.mockSongs, tt.mockDocs, tt.mockNext, tt.mockRepoError).Maybe()
+			for id, song := range tt.mockParents {
+				if song == nil {
+					songRepo.On("GetSongByID", id).Return(nil, errors.ErrResourceNotFound)
+				} else {
+					songRepo.On("GetSongByID", id).Return(song, nil)
+				}
+			}
+
+			res, err := service.SearchCatalog(tt.query, tt.limit, tt.cursor)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCounts, res.SegmentCounts)
+			assert.Equal(t, tt.expectComplete, res.Complete)
+			assert.Equal(t, tt.expectedNext, res.NextToken)
+
+			var types, ids []string
+			for _, item := range res.Data {
+				types = append(types, item.Type)
+				if item.Song != nil {
+					ids = append(ids, item.Song.ID)
+				} else {
+					ids = append(ids, item.Document.ID)
+					assert.Equal(t, item.Document.SongID, item.Document.Song.ID)
+				}
+			}
+			assert.Equal(t, tt.expectedTypes, types)
+			assert.Equal(t, tt.expectedIDs, ids)
+
+			songRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchCatalog_SkipsSegmentsWithoutHits(t *testing.T) {
+	repo := new(mocks.MockSearchRepository)
+	service := services.NewSearchService(repo, new(mocks.MockSongRepository), newInstrumentService())
+	repo.On("SearchCatalog", "love", mock.Anything, "").Return([]models.Song{}, []models.Document{}, "segment-2", nil).Once()
+	repo.On("SearchCatalog", "love", mock.Anything, "segment-2").Return([]models.Song{SongLove}, []models.Document{}, "segment-3", nil).Once()
+
+	res, err := service.SearchCatalog("love", 10, "")
+
+	assert.NoError(t, err)
+	if assert.Len(t, res.Data, 1) {
+		assert.Equal(t, SongLove.ID, res.Data[0].Song.ID)
+	}
+	assert.Equal(t, dto.SearchCounts{Songs: 1}, res.SegmentCounts)
+	assert.False(t, res.Complete)
+	assert.Equal(t, utils.EncodeSegmentCursor("segment-3", 0), res.NextToken)
+	repo.AssertExpectations(t)
+}
+
+func TestSearchCatalog_StopsAfterMaxEmptySegments(t *testing.T) {
+	repo := new(mocks.MockSearchRepository)
+	service := services.NewSearchService(repo, new(mocks.MockSongRepository), newInstrumentService())
+	repo.On("SearchCatalog", "love", mock.Anything, mock.Anything).Return([]models.Song{}, []models.Document{}, "segment-n", nil)
+
+	res, err := service.SearchCatalog("love", 10, "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, res.Data)
+	assert.Equal(t, utils.EncodeSegmentCursor("segment-n", 0), res.NextToken, "the client can keep paging")
+	repo.AssertNumberOfCalls(t, "SearchCatalog", 4)
+}
+
+func TestSearchCatalog_Highlights(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []dto.Highlight
+	}{
+		{
+			name:  "accent-insensitive match maps to original title",
+			query: "besame",
+			expected: []dto.Highlight{
+				{Field: dto.HighlightFieldTitle, Start: 0, End: 6},
+			},
+		},
+		{
+			name:  "matches author after accented characters",
+			query: "VELAZ",
+			expected: []dto.Highlight{
+				{Field: dto.HighlightFieldAuthor, Start: 9, End: 14},
+			},
+		},
+		{
+			name:  "multiple occurrences",
+			query: "m",
+			expected: []dto.Highlight{
+				{Field: dto.HighlightFieldTitle, Start: 4, End: 5},
+				{Field: dto.HighlightFieldTitle, Start: 7, End: 8},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockSearchRepository)
+			service := services.NewSearchService(repo, new(mocks.MockSongRepository), newInstrumentService())
+
+			repo.On("SearchCatalog", tt.query, mock.Anything, "").
+				Return([]models.Song{SongBesameMucho}, []models.Document{DocumentBesameMuchoTab}, "", nil)
+
+			res, err := service.SearchCatalog(tt.query, 10, "")
+
+			assert.NoError(t, err)
+			assert.Len(t, res.Data, 2)
+			assert.Equal(t, tt.expected, res.Data[0].Highlights)
+
+			for _, h := range res.Data[1].Highlights {
+				assert.Equal(t, dto.HighlightFieldTitle, h.Field)
+			}
+		})
+	}
+}
+
+func TestSearchCatalog_HighlightsDocumentsAgainstTheirOwnTitle(t *testing.T) {
+	repo := new(mocks.MockSearchRepository)
+	songRepo := new(mocks.MockSongRepository)
+	service := services.NewSearchService(repo, songRepo, newInstrumentService())
+
+	renamed := SongBesameMucho
+	renamed.Title = "Mucho, Bésame"
+	renamed.TitleNormalized = "mucho, besame"
+	repo.On("SearchCatalog", "besame mucho", mock.Anything, "").
+		Return([]models.Song{}, []models.Document{DocumentBesameMuchoTab}, "", nil)
+	songRepo.On("GetSongByID", "6").Return(&renamed, nil)
+
+	res, err := service.SearchCatalog("besame mucho", 10, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Data, 1)
+	assert.Equal(t, []dto.Highlight{{Field: dto.HighlightFieldTitle, Start: 0, End: 12}}, res.Data[0].Highlights,
+		"the document matched on the title it still holds")
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -41,6 +41,9 @@ var SongNotFoundInvalidUpdateSongRequest = dto.UpdateSongRequest{
 	Title: ptr("Radio"),
 }
 
+// EditorActor is the actor recorded in audit entries by service tests.
+var EditorActor = models.Actor{Username: "maria", RequestID: "req-1"}
+
 var MockedSong = models.Song{
 	ID:     "1",
 	Title:  "Bohemian Rhapsody",
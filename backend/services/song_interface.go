@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 )
 
 // SongServiceInterface defines application-level operations for managing songs and their associated documents.
@@ -12,13 +13,15 @@ type SongServiceInterface interface {
 	// Returns:
 	//   - the generated song ID on success
 	//   - error if the operation fails
-	CreateSongWithDocuments(dto.CreateSongRequest) (string, error)
+	CreateSongWithDocuments(actor models.Actor, req dto.CreateSongRequest) (string, error)
 
-	// GetAllSongs returns all songs stored in the system.
+	// ListSongs returns one page of the songs, selected and sorted by query.
 	// Returns:
-	//   - ([]dto.SongResponseItem, nil) on success
-	//   - (nil, error) if the retrieval fails
-	GetAllSongs() ([]dto.SongResponseItem, error)
+	//   - (dto.SongListResponse, nil) on success
+	//   - errors.ErrValidationFailed if the limit, sort or order is invalid
+	//   - errors.ErrBadRequest if the cursor was not issued for this listing
+	//   - error if the retrieval fails
+	ListSongs(query dto.ListQuery) (dto.SongListResponse, error)
 
 	// GetSongByID retrieves a single song by its unique identifier.
 	// Returns:
@@ -27,16 +30,21 @@ type SongServiceInterface interface {
 	//   - (nil, error) for unexpected errors
 	GetSongByID(songID string) (dto.SongResponseItem, error)
 
-	// UpdateSong applies partial updates to a song, including optional title normalization.
+	// UpdateSong applies partial updates to a song, including optional title normalization,
+	// if the song is at a version allowed by ifMatch.
 	// Returns:
-	//   - nil on success
+	//   - (new version, nil) on success
 	//   - errors.ErrNotFound if the song does not exist
+	//   - errors.ErrPreconditionFailed if the song is not at a version allowed by ifMatch
+	//   - errors.ErrConflict if a document of the song is deleted while it is renamed
 	//   - error if the update fails
-	UpdateSong(songID string, updates dto.UpdateSongRequest) error
+	UpdateSong(actor models.Actor, songID string, updates dto.UpdateSongRequest, ifMatch dto.Precondition) (int, error)
 
-	// DeleteSongWithDocuments removes a song and all documents linked to it.
+	// DeleteSongWithDocuments moves a song and all documents linked to it to the trash
+	// if the song is at a version allowed by ifMatch.
 	// Returns:
 	//   - nil on success
+	//   - errors.ErrPreconditionFailed if the song is not at a version allowed by ifMatch
 	//   - error if the operation fails
-	DeleteSongWithDocuments(songID string) error
+	DeleteSongWithDocuments(actor models.Actor, songID string, ifMatch dto.Precondition) error
 }
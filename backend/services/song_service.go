@@ -4,17 +4,25 @@ import (
 	"fmt"
 
 	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
 	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/utils"
 )
 
 // SongService provides application-level operations for managing songs and their associated documents.
 // It uses repositories for persistence and utility interfaces for time and ID generation.
+// Every change is recorded in the audit trail together with the actor that requested it,
+// and every update keeps the version it replaces as a revision.
 type SongService struct {
 	songRepo     repository.SongRepository
 	docRepo      repository.DocumentRepository
+	revisionRepo repository.RevisionRepository
 	idGen        utils.IDGenerator
 	timeProvider utils.TimeProvider
+	indexer      CatalogIndexer
+	instruments  InstrumentServiceInterface
+	genres       GenreServiceInterface
 }
 
 // Ensure SongService implements SongServiceInterface.
@@ -24,61 +32,101 @@ var _ SongServiceInterface = (*SongService)(nil)
 func NewSongService(
 	songRepo repository.SongRepository,
 	docRepo repository.DocumentRepository,
+	revisionRepo repository.RevisionRepository,
 	idGen utils.IDGenerator,
 	timeProvider utils.TimeProvider,
+	indexer CatalogIndexer,
+	instruments InstrumentServiceInterface,
+	genres GenreServiceInterface,
 ) *SongService {
 	return &SongService{
 		songRepo:     songRepo,
 		docRepo:      docRepo,
+		revisionRepo: revisionRepo,
 		idGen:        idGen,
 		timeProvider: timeProvider,
+		indexer:      indexer,
+		instruments:  instruments,
+		genres:       genres,
 	}
 }
 
 // CreateSongWithDocuments creates a new song and all associated documents.
-// It generates UUIDs and timestamps, and normalizes the title before saving.
+// It generates UUIDs and timestamps, normalizes the title and maps genres and document
+// instruments to their canonical forms before saving. A single audit entry records the song and its documents.
 // Returns:
 //   - the generated song ID on success
+//   - errors.ErrValidationFailed if the request, any genre or any instrument is invalid
 //   - error if the creation fails at any point
-func (s *SongService) CreateSongWithDocuments(req dto.CreateSongRequest) (string, error) {
+func (s *SongService) CreateSongWithDocuments(actor models.Actor, req dto.CreateSongRequest) (string, error) {
 	song, documents := dto.ToSongAndDocuments(req)
 
 	if err := dto.ValidateCreateSongRequest(req); err != nil {
 		return "", fmt.Errorf("validating song and documents: %w", err)
 	}
 
+	genres, err := s.genres.ResolveGenres(song.Genres)
+	if err != nil {
+		return "", fmt.Errorf("validating genres: %w", err)
+	}
+	song.Genres = genres
+
 	song.ID = s.idGen.NewID()
 	now := s.timeProvider.Now()
 	song.CreatedAt = now
 	song.UpdatedAt = now
+	song.Version = 1
 	song.TitleNormalized = utils.Normalize(song.Title)
 
 	for i := range documents {
+		instruments, err := s.instruments.NormalizeInstruments(documents[i].Instrument)
+		if err != nil {
+			return "", fmt.Errorf("validating instruments of document %d: %w", i, err)
+		}
+		documents[i].Instrument = instruments
 		documents[i].ID = s.idGen.NewID()
 		documents[i].SongID = song.ID
+		documents[i].Title = song.Title
 		documents[i].TitleNormalized = song.TitleNormalized
 		documents[i].CreatedAt = now
 		documents[i].UpdatedAt = now
+		documents[i].Version = 1
 	}
 
-	err := s.songRepo.CreateSongWithDocuments(song, documents)
-	if err != nil {
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntitySong, song.ID, song.ID, models.AuditActionCreate, nil, newSongAuditView(song, documents))
+	if err := s.songRepo.CreateSongWithDocuments(song, documents, audit); err != nil {
 		return "", fmt.Errorf("creating song with documents: %w", err)
 	}
 
+	s.indexer.IndexSong(song)
+	for _, doc := range documents {
+		s.indexer.IndexDocument(doc)
+	}
+
 	return song.ID, nil
 }
 
-// GetAllSongs retrieves all songs from the repository.
+// ListSongs retrieves one page of songs from the repository. The limit defaults to
+// dto.DefaultListLimit and the order to ascending.
 // Returns:
-//   - ([]dto.SongResponseItem, nil) on success
-//   - (nil, error) if the operation fails
-func (s *SongService) GetAllSongs() ([]dto.SongResponseItem, error) {
-	songs, err := s.songRepo.GetAllSongs()
+//   - (dto.SongListResponse, nil) on success
+//   - errors.ErrValidationFailed if the query is invalid
+//   - errors.ErrBadRequest if the cursor is invalid
+//   - error if the operation fails
+func (s *SongService) ListSongs(query dto.ListQuery) (dto.SongListResponse, error) {
+	if err := dto.ValidateListQuery(query, dto.SongSortFields); err != nil {
+		return dto.SongListResponse{}, fmt.Errorf("validating songs query: %w", err)
+	}
+
+	songs, next, err := s.songRepo.ListSongs(listOptions(query))
 	if err != nil {
-		return nil, fmt.Errorf("retrieving all songs: %w", err)
+		return dto.SongListResponse{}, fmt.Errorf("listing songs: %w", err)
 	}
-	return dto.ToSongResponseList(songs), nil
+	return dto.SongListResponse{
+		Data:      dto.ToSongResponseList(songs),
+		Count:     len(songs),
+		NextToken: next,
+	}, nil
 }
 
 // GetSongByID retrieves a song by its unique identifier.
@@ -94,55 +142,132 @@ func (s *SongService) GetSongByID(id string) (dto.SongResponseItem, error) {
 	return dto.ToSongResponseItem(*song), nil
 }
 
-// UpdateSong applies partial updates to a song, normalizing the title if provided.
-// It also updates the 'updated_at' timestamp.
+// UpdateSong applies partial updates to a song, normalizing the title and resolving genres
+// against the catalogue if provided. It also updates the 'updated_at' timestamp, records the
+// changed fields in the audit trail and keeps the replaced version as a revision.
+// The song must be at a version allowed by ifMatch, both when it is read and when it is written.
 // Returns:
-//   - nil on success
+//   - (new version, nil) on success
+//   - errors.ErrValidationFailed if the update or any genre is invalid
 //   - errors.ErrResourceNotFound if the song does not exist
+//   - errors.ErrPreconditionFailed if the song is not, or no longer, at a version allowed by ifMatch
+//   - errors.ErrConflict if a concurrent update took the same revision number
 //   - error if the update operation fails
-func (s *SongService) UpdateSong(id string, updates dto.UpdateSongRequest) error {
-	_, err := s.songRepo.GetSongByID(id)
+func (s *SongService) UpdateSong(actor models.Actor, id string, updates dto.UpdateSongRequest, ifMatch dto.Precondition) (int, error) {
+	song, err := songAt(s.songRepo, id, ifMatch)
 	if err != nil {
-		return fmt.Errorf("checking existence of song %s: %w", id, err)
+		return 0, err
 	}
 
+	updated := *song
 	updateMap := make(map[string]interface{})
 	if updates.Title != nil {
 		updateMap["title"] = *updates.Title
 		updateMap["title_normalized"] = utils.Normalize(*updates.Title)
+		updated.Title = *updates.Title
+		updated.TitleNormalized = utils.Normalize(*updates.Title)
 	}
 	if updates.Author != nil {
 		updateMap["author"] = *updates.Author
+		updated.Author = *updates.Author
 	}
+	now := s.timeProvider.Now()
+	updateMap["updated_at"] = now
+	updated.UpdatedAt = now
+	updated.Version = song.Version + 1
+
+	if err := dto.ValidateUpdateSongRequest(updates); err != nil {
+		return 0, fmt.Errorf("validating song update: %w", err)
+	}
+
 	if updates.Genres != nil {
-		updateMap["genres"] = updates.Genres
+		genres, err := s.genres.ResolveGenres(updates.Genres)
+		if err != nil {
+			return 0, fmt.Errorf("validating genres: %w", err)
+		}
+		updateMap["genres"] = genres
+		updated.Genres = genres
 	}
-	updateMap["updated_at"] = s.timeProvider.Now()
 
-	if err := dto.ValidateUpdateSongRequest(updates); err != nil {
-		return fmt.Errorf("validating song update: %w", err)
+	revision, err := newRevision(s.revisionRepo, actor, now, id)
+	if err != nil {
+		return 0, err
 	}
+	revision.Entity = models.AuditEntitySong
+	revision.EntityID = id
+	revision.Song = song
 
-	if err := s.songRepo.UpdateSong(id, updateMap); err != nil {
-		return fmt.Errorf("updating song %s: %w", id, err)
+	audit := newAuditEntry(s.idGen, actor, now, models.AuditEntitySong, id, id, models.AuditActionUpdate, song, &updated)
+	if err := s.songRepo.UpdateSong(id, song.Version, updateMap, audit, revision); err != nil {
+		return 0, fmt.Errorf("updating song %s: %w", id, err)
 	}
 
-	return nil
+	s.indexer.IndexSong(updated)
+	return updated.Version, nil
 }
 
-// DeleteSongWithDocuments removes a song and all associated documents.
+// songAt returns the song to be written if it is at a version allowed by ifMatch. A cached copy
+// that does not match may be stale, so the song is read again from storage before refusing.
+func songAt(songRepo repository.SongRepository, id string, ifMatch dto.Precondition) (*models.Song, error) {
+	song, err := songRepo.GetSongByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("checking existence of song %s: %w", id, err)
+	}
+	if ifMatch.Matches(song.Version) {
+		return song, nil
+	}
+
+	song, err = songRepo.GetSongByIDUncached(id)
+	if err != nil {
+		return nil, fmt.Errorf("checking existence of song %s: %w", id, err)
+	}
+	if !ifMatch.Matches(song.Version) {
+		return nil, fmt.Errorf("song %s is at version %d: %w", id, song.Version, errors.ErrPreconditionFailed)
+	}
+	return song, nil
+}
+
+// DeleteSongWithDocuments moves a song and all associated documents to the trash, from which an
+// admin can restore or purge them. The audit entry keeps the last values of the song and the IDs of
+// the documents trashed with it. The song must be at a version allowed by ifMatch.
 // Returns:
 //   - nil on success
 //   - errors.ErrResourceNotFound if the song does not exist
+//   - errors.ErrPreconditionFailed if the song is not, or no longer, at a version allowed by ifMatch
 //   - error if the deletion fails
-func (s *SongService) DeleteSongWithDocuments(songID string) error {
-	_, err := s.songRepo.GetSongByID(songID)
+func (s *SongService) DeleteSongWithDocuments(actor models.Actor, songID string, ifMatch dto.Precondition) error {
+	song, err := songAt(s.songRepo, songID, ifMatch)
 	if err != nil {
-		return fmt.Errorf("checking existence of song %s: %w", songID, err)
+		return err
 	}
 
-	if err := s.songRepo.DeleteSongWithDocuments(songID); err != nil {
-		return fmt.Errorf("deleting song %s with documents: %w", songID, err)
+	documents, err := s.docRepo.GetDocumentsBySongID(songID)
+	if err != nil {
+		return fmt.Errorf("retrieving documents of song %s: %w", songID, err)
 	}
+
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntitySong, songID, songID, models.AuditActionDelete, newSongAuditView(*song, documents), nil)
+	if err := s.songRepo.TrashSongWithDocuments(songID, song.Version, audit); err != nil {
+		return fmt.Errorf("trashing song %s with documents: %w", songID, err)
+	}
+
+	s.indexer.RemoveSong(songID)
 	return nil
 }
+
+// listOptions applies the defaults of dto.ListQuery.
+func listOptions(query dto.ListQuery) repository.ListOptions {
+	opts := repository.ListOptions{
+		Limit:  query.Limit,
+		Cursor: query.Cursor,
+		Sort:   query.Sort,
+		Order:  query.Order,
+	}
+	if opts.Limit == 0 {
+		opts.Limit = dto.DefaultListLimit
+	}
+	if opts.Sort != "" && opts.Order == "" {
+		opts.Order = repository.SortOrderAsc
+	}
+	return opts
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
 	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
 	"github.com/CristinaRendaLopez/rendalla-backend/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,10 +18,39 @@ func setupSongServiceTest() (*services.SongService, *mocks.MockSongRepository, *
 	docRepo := new(mocks.MockDocumentRepository)
 	idGen := new(mocks.MockIDGenerator)
 	timeProv := new(mocks.MockTimeProvider)
-	service := services.NewSongService(songRepo, docRepo, idGen, timeProv)
+	service := services.NewSongService(songRepo, docRepo, newMockRevisionRepository(), idGen, timeProv, newMockCatalogIndexer(), newInstrumentService(), newMockGenreResolver())
 	return service, songRepo, docRepo, idGen, timeProv
 }
 
+// newMockRevisionRepository returns a revision repository mock for a song without previous revisions.
+func newMockRevisionRepository() *mocks.MockRevisionRepository {
+	revisions := new(mocks.MockRevisionRepository)
+	revisions.On("GetLatestRevisionNumber", mock.Anything).Return(0, nil).Maybe()
+	return revisions
+}
+
+// newMockGenreResolver returns a genre service mock that accepts every genre unchanged.
+func newMockGenreResolver() *mocks.MockGenreService {
+	genres := new(mocks.MockGenreService)
+	genres.On("ResolveGenres", mock.Anything).Return(func(names []string) []string { return names }, nil).Maybe()
+	return genres
+}
+
+// newInstrumentService returns an instrument service backed by the default static catalogue.
+func newInstrumentService() *services.InstrumentService {
+	return services.NewInstrumentService(repository.NewStaticInstrumentRepository())
+}
+
+// newMockCatalogIndexer returns an indexer mock that accepts any indexing call.
+func newMockCatalogIndexer() *mocks.MockCatalogIndexer {
+	indexer := new(mocks.MockCatalogIndexer)
+	indexer.On("IndexSong", mock.Anything).Maybe()
+	indexer.On("RemoveSong", mock.Anything).Maybe()
+	indexer.On("IndexDocument", mock.Anything).Maybe()
+	indexer.On("RemoveDocument", mock.Anything, mock.Anything).Maybe()
+	return indexer
+}
+
 func TestCreateSongWithDocuments(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -54,14 +84,14 @@ func TestCreateSongWithDocuments(t *testing.T) {
 			timeProvider.On("Now").Return("now").Maybe()
 
 			if tt.mockSongError == nil && !tt.expectError {
-				songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything).
+				songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything, mock.Anything).
 					Return(tt.mockSongError)
 			} else if tt.mockSongError != nil {
-				songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything).
+				songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything, mock.Anything).
 					Return(tt.mockSongError)
 			}
 
-			_, err := service.CreateSongWithDocuments(tt.request)
+			_, err := service.CreateSongWithDocuments(EditorActor, tt.request)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -72,33 +102,91 @@ func TestCreateSongWithDocuments(t *testing.T) {
 	}
 }
 
-func TestGetAllSongs(t *testing.T) {
+func TestSongService_ResolvesGenres(t *testing.T) {
+	songRepo := new(mocks.MockSongRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProvider := new(mocks.MockTimeProvider)
+	genres := new(mocks.MockGenreService)
+	service := services.NewSongService(songRepo, new(mocks.MockDocumentRepository), newMockRevisionRepository(), idGen, timeProvider, newMockCatalogIndexer(), newInstrumentService(), genres)
+
+	idGen.On("NewID").Return("id")
+	timeProvider.On("Now").Return("now")
+	genres.On("ResolveGenres", []string{"rock"}).Return([]string{"Rock"}, nil)
+	genres.On("ResolveGenres", []string{"Polka"}).Return(nil, errors.ErrValidationFailed)
+	songRepo.On("CreateSongWithDocuments", mock.MatchedBy(func(s models.Song) bool {
+		return assert.ObjectsAreEqual([]string{"Rock"}, s.Genres)
+	}), mock.Anything, mock.Anything).Return(nil)
+	songRepo.On("GetSongByID", "id").Return(&models.Song{ID: "id"}, nil)
+
+	_, err := service.CreateSongWithDocuments(EditorActor, ValidCreateSongRequest)
+	assert.NoError(t, err)
+
+	_, err = service.UpdateSong(EditorActor, "id", dto.UpdateSongRequest{Genres: []string{"Polka"}}, dto.Precondition{})
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+
+	songRepo.AssertNotCalled(t, "UpdateSong", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	songRepo.AssertExpectations(t)
+}
+
+func TestSongService_ListSongs(t *testing.T) {
 	tests := []struct {
 		name         string
+		query        dto.ListQuery
+		expectedOpts repository.ListOptions
 		mockSongs    []models.Song
+		mockNext     string
 		mockError    error
-		expectError  bool
+		expectError  error
 		expectedSize int
 	}{
 		{
-			name:         "returns songs",
+			name:         "applies the default limit",
+			query:        dto.ListQuery{},
+			expectedOpts: repository.ListOptions{Limit: dto.DefaultListLimit},
 			mockSongs:    []models.Song{MockedSong},
-			mockError:    nil,
-			expectError:  false,
+			mockNext:     "next",
 			expectedSize: 1,
 		},
 		{
-			name:         "empty list",
+			name:         "sorts ascending by default",
+			query:        dto.ListQuery{Limit: 5, Sort: "title", Cursor: "abc"},
+			expectedOpts: repository.ListOptions{Limit: 5, Sort: "title", Order: repository.SortOrderAsc, Cursor: "abc"},
 			mockSongs:    []models.Song{},
-			mockError:    nil,
-			expectError:  false,
 			expectedSize: 0,
 		},
+		{
+			name:         "keeps a descending order",
+			query:        dto.ListQuery{Sort: "created_at", Order: "desc"},
+			expectedOpts: repository.ListOptions{Limit: dto.DefaultListLimit, Sort: "created_at", Order: repository.SortOrderDesc},
+			mockSongs:    []models.Song{MockedSong},
+			expectedSize: 1,
+		},
 		{
 			name:         "repository error",
+			query:        dto.ListQuery{},
+			expectedOpts: repository.ListOptions{Limit: dto.DefaultListLimit},
 			mockError:    errors.ErrInternalServer,
-			expectError:  true,
-			expectedSize: 0,
+			expectError:  errors.ErrInternalServer,
+		},
+		{
+			name:        "limit above maximum",
+			query:       dto.ListQuery{Limit: dto.MaxListLimit + 1},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "unknown sort field",
+			query:       dto.ListQuery{Sort: "type"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "order without sort",
+			query:       dto.ListQuery{Order: "desc"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "invalid order",
+			query:       dto.ListQuery{Sort: "title", Order: "up"},
+			expectError: errors.ErrValidationFailed,
 		},
 	}
 
@@ -106,16 +194,21 @@ func TestGetAllSongs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service, songRepo, _, _, _ := setupSongServiceTest()
 
-			songRepo.On("GetAllSongs").Return(tt.mockSongs, tt.mockError)
+			if tt.expectError != errors.ErrValidationFailed {
+				songRepo.On("ListSongs", tt.expectedOpts).Return(tt.mockSongs, tt.mockNext, tt.mockError)
+			}
 
-			songs, err := service.GetAllSongs()
+			page, err := service.ListSongs(tt.query)
 
-			if tt.expectError {
-				assert.Error(t, err)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, songs, tt.expectedSize)
+				assert.Len(t, page.Data, tt.expectedSize)
+				assert.Equal(t, tt.expectedSize, page.Count)
+				assert.Equal(t, tt.mockNext, page.NextToken)
 			}
+			songRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -219,8 +312,9 @@ func TestUpdateSong(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, songRepo, _, _, timeProvider := setupSongServiceTest()
+			service, songRepo, _, idGen, timeProvider := setupSongServiceTest()
 
+			idGen.On("NewID").Return("audit-1").Maybe()
 			timeProvider.On("Now").Return("mocked-time").Maybe()
 
 			if tt.mockGetError == nil {
@@ -230,12 +324,12 @@ func TestUpdateSong(t *testing.T) {
 			}
 
 			if tt.mockUpdateError != nil {
-				songRepo.On("UpdateSong", tt.songID, mock.Anything).Return(tt.mockUpdateError)
+				songRepo.On("UpdateSong", tt.songID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.mockUpdateError)
 			} else if tt.mockGetError == nil {
-				songRepo.On("UpdateSong", tt.songID, mock.Anything).Return(nil)
+				songRepo.On("UpdateSong", tt.songID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			}
 
-			err := service.UpdateSong(tt.songID, tt.updates)
+			_, err := service.UpdateSong(EditorActor, tt.songID, tt.updates, dto.Precondition{})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -279,15 +373,18 @@ func TestDeleteSongWithDocuments(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, songRepo, _, _, _ := setupSongServiceTest()
+			service, songRepo, docRepo, idGen, timeProvider := setupSongServiceTest()
 
+			idGen.On("NewID").Return("audit-1").Maybe()
+			timeProvider.On("Now").Return("now").Maybe()
 			songRepo.On("GetSongByID", tt.songID).Return(&MockedSong, tt.mockGetSongErr)
 
 			if tt.mockGetSongErr == nil {
-				songRepo.On("DeleteSongWithDocuments", tt.songID).Return(tt.mockDeleteSongErr)
+				docRepo.On("GetDocumentsBySongID", tt.songID).Return([]models.Document{}, nil)
+				songRepo.On("TrashSongWithDocuments", tt.songID, mock.Anything, mock.Anything).Return(tt.mockDeleteSongErr)
 			}
 
-			err := service.DeleteSongWithDocuments(tt.songID)
+			err := service.DeleteSongWithDocuments(EditorActor, tt.songID, dto.Precondition{})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -297,3 +394,173 @@ func TestDeleteSongWithDocuments(t *testing.T) {
 		})
 	}
 }
+
+func TestSongService_UpdatesCatalogIndex(t *testing.T) {
+	songRepo := new(mocks.MockSongRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProvider := new(mocks.MockTimeProvider)
+	indexer := new(mocks.MockCatalogIndexer)
+	docRepo := new(mocks.MockDocumentRepository)
+	service := services.NewSongService(songRepo, docRepo, newMockRevisionRepository(), idGen, timeProvider, indexer, newInstrumentService(), newMockGenreResolver())
+
+	idGen.On("NewID").Return("id")
+	timeProvider.On("Now").Return("now")
+	songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	songRepo.On("GetSongByID", "id").Return(&models.Song{ID: "id", Title: "Old Title"}, nil)
+	songRepo.On("UpdateSong", "id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	docRepo.On("GetDocumentsBySongID", "id").Return([]models.Document{}, nil)
+	songRepo.On("TrashSongWithDocuments", "id", mock.Anything, mock.Anything).Return(nil)
+
+	indexer.On("IndexSong", mock.MatchedBy(func(s models.Song) bool { return s.Title == ValidCreateSongRequest.Title })).Once()
+	indexer.On("IndexDocument", mock.MatchedBy(func(d models.Document) bool { return d.SongID == "id" })).Once()
+	indexer.On("IndexSong", mock.MatchedBy(func(s models.Song) bool { return s.Title == *ValidUpdateSongRequest.Title })).Once()
+	indexer.On("RemoveSong", "id").Once()
+
+	_, err := service.CreateSongWithDocuments(EditorActor, ValidCreateSongRequest)
+	assert.NoError(t, err)
+	_, err = service.UpdateSong(EditorActor, "id", ValidUpdateSongRequest, dto.Precondition{})
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteSongWithDocuments(EditorActor, "id", dto.Precondition{}))
+
+	indexer.AssertExpectations(t)
+}
+
+func TestSongService_RecordsAuditEntries(t *testing.T) {
+	service, songRepo, docRepo, idGen, timeProvider := setupSongServiceTest()
+
+	idGen.On("NewID").Return("id")
+	timeProvider.On("Now").Return("now")
+	songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+	docRepo.On("GetDocumentsBySongID", "1").Return([]models.Document{MockedDocument}, nil)
+
+	var entries []models.AuditEntry
+	record := func(args mock.Arguments) {
+		for _, arg := range args {
+			if entry, ok := arg.(models.AuditEntry); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	songRepo.On("CreateSongWithDocuments", mock.Anything, mock.Anything, mock.Anything).Run(record).Return(nil)
+	songRepo.On("UpdateSong", "1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(record).Return(nil)
+	songRepo.On("TrashSongWithDocuments", "1", mock.Anything, mock.Anything).Run(record).Return(nil)
+
+	_, err := service.CreateSongWithDocuments(EditorActor, ValidCreateSongRequest)
+	assert.NoError(t, err)
+	_, err = service.UpdateSong(EditorActor, "1", ValidAuthorUpdateRequest, dto.Precondition{})
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteSongWithDocuments(EditorActor, "1", dto.Precondition{}))
+
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+	for _, entry := range entries {
+		assert.Equal(t, models.AuditEntitySong, entry.Entity)
+		assert.Equal(t, "maria", entry.Actor)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.Equal(t, "now", entry.Timestamp)
+	}
+
+	created := entries[0]
+	assert.Equal(t, models.AuditActionCreate, created.Action)
+	assert.Equal(t, "id", created.EntityID)
+	assert.Contains(t, created.Changes, models.FieldChange{Field: "documents", After: []interface{}{"id"}})
+	assert.Contains(t, created.Changes, models.FieldChange{Field: "title", After: "Bohemian Rhapsody"})
+
+	assert.Equal(t, models.AuditActionUpdate, entries[1].Action)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "author", Before: "Queen", After: "Freddie Mercury"},
+	}, entries[1].Changes)
+
+	deleted := entries[2]
+	assert.Equal(t, models.AuditActionDelete, deleted.Action)
+	assert.Contains(t, deleted.Changes, models.FieldChange{Field: "documents", Before: []interface{}{"doc-1"}})
+	for _, change := range deleted.Changes {
+		assert.Nil(t, change.After)
+	}
+}
+
+func TestSongService_KeepsRevisions(t *testing.T) {
+	songRepo := new(mocks.MockSongRepository)
+	revisionRepo := new(mocks.MockRevisionRepository)
+	idGen := new(mocks.MockIDGenerator)
+	timeProvider := new(mocks.MockTimeProvider)
+	service := services.NewSongService(songRepo, new(mocks.MockDocumentRepository), revisionRepo, idGen, timeProvider, newMockCatalogIndexer(), newInstrumentService(), newMockGenreResolver())
+
+	idGen.On("NewID").Return("id")
+	timeProvider.On("Now").Return("now")
+	songRepo.On("GetSongByID", "1").Return(&MockedSong, nil)
+	revisionRepo.On("GetLatestRevisionNumber", "1").Return(3, nil)
+
+	var kept models.Revision
+	songRepo.On("UpdateSong", "1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		kept = args.Get(4).(models.Revision)
+	}).Return(nil).Once()
+	songRepo.On("UpdateSong", "1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.ErrConflict).Once()
+
+	_, err := service.UpdateSong(EditorActor, "1", ValidUpdateSongRequest, dto.Precondition{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Revision{
+		SongID:    "1",
+		Revision:  4,
+		Entity:    models.AuditEntitySong,
+		EntityID:  "1",
+		Song:      &MockedSong,
+		Actor:     "maria",
+		RequestID: "req-1",
+		CreatedAt: "now",
+	}, kept)
+
+	_, err = service.UpdateSong(EditorActor, "1", ValidUpdateSongRequest, dto.Precondition{})
+	assert.ErrorIs(t, err, errors.ErrConflict, "a concurrent update that took the revision number is reported")
+}
+
+func TestSongService_HonoursIfMatch(t *testing.T) {
+	versioned := MockedSong
+	versioned.Version = 3
+	stale := MockedSong
+	stale.Version = 2
+
+	tests := []struct {
+		name        string
+		ifMatch     dto.Precondition
+		cached      *models.Song
+		repoErr     error
+		expectError error
+	}{
+		{name: "no precondition", ifMatch: dto.Precondition{}},
+		{name: "matching version", ifMatch: dto.NewPrecondition(`"3"`)},
+		{name: "stale version", ifMatch: dto.NewPrecondition(`"2"`), expectError: errors.ErrPreconditionFailed},
+		{name: "stale cached copy", ifMatch: dto.NewPrecondition(`"3"`), cached: &stale},
+		{name: "changed after read", ifMatch: dto.NewPrecondition(`"3"`), repoErr: errors.ErrPreconditionFailed, expectError: errors.ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, songRepo, docRepo, idGen, timeProvider := setupSongServiceTest()
+			idGen.On("NewID").Return("audit-1")
+			timeProvider.On("Now").Return("now")
+			cached := &versioned
+			if tt.cached != nil {
+				cached = tt.cached
+			}
+			songRepo.On("GetSongByID", "1").Return(cached, nil)
+			songRepo.On("GetSongByIDUncached", "1").Return(&versioned, nil).Maybe()
+			docRepo.On("GetDocumentsBySongID", "1").Return([]models.Document{}, nil)
+			songRepo.On("UpdateSong", "1", 3, mock.Anything, mock.Anything, mock.Anything).Return(tt.repoErr).Maybe()
+			songRepo.On("TrashSongWithDocuments", "1", 3, mock.Anything).Return(tt.repoErr).Maybe()
+
+			version, err := service.UpdateSong(EditorActor, "1", ValidAuthorUpdateRequest, tt.ifMatch)
+			deleteErr := service.DeleteSongWithDocuments(EditorActor, "1", tt.ifMatch)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.ErrorIs(t, deleteErr, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, deleteErr)
+			assert.Equal(t, 4, version)
+		})
+	}
+}
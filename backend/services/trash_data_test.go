@@ -0,0 +1,86 @@
+package services_test
+
+import "github.com/CristinaRendaLopez/rendalla-backend/models"
+
+// TrashNow is the current time of the trash service tests: 2025-02-01T00:00:00Z.
+const TrashNow int64 = 1738368000
+
+// TrashedSong was deleted on 2025-01-01 together with TrashedWithSongDocument.
+var TrashedSong = models.Song{
+	ID:        "song-old",
+	Title:     "Radio Ga Ga",
+	Author:    "Queen",
+	Genres:    []string{"Rock"},
+	DeletedAt: "2025-01-01T00:00:00Z",
+	DeletedBy: "admin",
+}
+
+// RecentlyTrashedSong was deleted on 2025-01-25, a week before TrashNow.
+var RecentlyTrashedSong = models.Song{
+	ID:        "song-new",
+	Title:     "Innuendo",
+	Author:    "Queen",
+	DeletedAt: "2025-01-25T00:00:00Z",
+	DeletedBy: "admin",
+}
+
+var TrashedWithSongDocument = models.Document{
+	ID:              "doc-old",
+	SongID:          "song-old",
+	Type:            "score",
+	Instrument:      []string{"piano"},
+	DeletedAt:       "2025-01-01T00:00:00Z",
+	DeletedBy:       "admin",
+	DeletedWithSong: true,
+}
+
+// TrashedDocument was deleted on its own on 2025-01-02 from a live song.
+var TrashedDocument = models.Document{
+	ID:         "doc-1",
+	SongID:     "song-123",
+	Type:       "tablature",
+	Instrument: []string{"guitar"},
+	DeletedAt:  "2025-01-02T00:00:00Z",
+	DeletedBy:  "maria",
+}
+
+// PendingTrashSong went to the trash at 23:00 the day before TrashNow, but its documents were not all
+// trashed with it.
+var PendingTrashSong = models.Song{
+	ID:           "song-trashing",
+	Title:        "Somebody to Love",
+	Author:       "Queen",
+	DeletedAt:    "2025-01-31T23:00:00Z",
+	DeletedBy:    "admin",
+	Pending:      models.SongPendingTrash,
+	PendingSince: "2025-01-31T23:00:00Z",
+}
+
+// PendingRestoreSong left the trash an hour before TrashNow, but not all of its documents did.
+var PendingRestoreSong = models.Song{
+	ID:           "song-restoring",
+	Title:        "Killer Queen",
+	Author:       "Queen",
+	Pending:      models.SongPendingRestore,
+	PendingSince: "2025-01-31T23:00:00Z",
+}
+
+// PendingCreateSong was being created an hour before TrashNow when its creation was interrupted.
+var PendingCreateSong = models.Song{
+	ID:           "song-creating",
+	Title:        "The Show Must Go On",
+	Author:       "Queen",
+	Pending:      models.SongPendingCreate,
+	PendingSince: "2025-01-31T23:00:00Z",
+}
+
+// InFlightSong is being trashed right now: its write started a minute before TrashNow.
+var InFlightSong = models.Song{
+	ID:           "song-in-flight",
+	Title:        "Under Pressure",
+	Author:       "Queen",
+	DeletedAt:    "2025-01-31T23:59:00Z",
+	DeletedBy:    "admin",
+	Pending:      models.SongPendingTrash,
+	PendingSince: "2025-01-31T23:59:00Z",
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+)
+
+// TrashServiceInterface defines the administration of songs and documents moved to the trash.
+type TrashServiceInterface interface {
+
+	// ListTrash returns the songs in the trash and the documents trashed on their own, newest first.
+	// Returns:
+	//   - (dto.TrashResponse, nil) on success
+	//   - error if the trash cannot be read
+	ListTrash() (dto.TrashResponse, error)
+
+	// RestoreSong brings a song back from the trash together with the documents trashed with it.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrResourceNotFound if the song is not in the trash
+	//   - error if the restore fails
+	RestoreSong(actor models.Actor, songID string) error
+
+	// RestoreDocument brings a document trashed on its own back from the trash.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrResourceNotFound if the document is not in the trash
+	//   - errors.ErrOperationNotAllowed if the document went to the trash with its song, or its song is in the trash
+	//   - error if the restore fails
+	RestoreDocument(actor models.Actor, songID string, docID string) error
+
+	// PurgeSong permanently deletes a song in the trash and every document of it.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrResourceNotFound if the song is not in the trash
+	//   - error if the deletion fails
+	PurgeSong(actor models.Actor, songID string) error
+
+	// PurgeDocument permanently deletes a document in the trash.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrResourceNotFound if the document is not in the trash
+	//   - error if the deletion fails
+	PurgeDocument(actor models.Actor, songID string, docID string) error
+
+	// PurgeExpired permanently deletes the items that have been in the trash longer than the retention period.
+	// Returns:
+	//   - the number of songs and documents purged
+	//   - error if the trash cannot be read or an item cannot be deleted
+	PurgeExpired(actor models.Actor) (dto.PurgeTrashResponse, error)
+
+	// ReconcilePendingSongs finishes or, for creations, rolls back the multi-transaction writes on songs
+	// that were interrupted.
+	// Returns:
+	//   - the number of songs completed and rolled back
+	//   - error if the songs cannot be read or a song cannot be reconciled
+	ReconcilePendingSongs(actor models.Actor) (dto.ReconcileSongsResponse, error)
+}
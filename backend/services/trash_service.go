@@ -0,0 +1,356 @@
+package services
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// TrashRetentionActor is recorded in the audit trail for items purged by the retention job.
+var TrashRetentionActor = models.Actor{Username: "system:trash-retention"}
+
+// pendingWriteGracePeriod is how long a song may carry a pending multi-step write before
+// ReconcilePendingSongs treats it as interrupted. Writes take seconds; younger ones may still be running.
+const pendingWriteGracePeriod = 5 * time.Minute
+
+// Ensure TrashService implements TrashServiceInterface.
+var _ TrashServiceInterface = (*TrashService)(nil)
+
+// TrashService administers the songs and documents that SongService and DocumentService move to the trash.
+// Items stay in the trash for the retention period and are then purged by PurgeExpired.
+type TrashService struct {
+	repo         repository.TrashRepository
+	songRepo     repository.SongRepository
+	idGen        utils.IDGenerator
+	timeProvider utils.TimeProvider
+	indexer      CatalogIndexer
+	retention    time.Duration
+}
+
+// NewTrashService returns a new instance of TrashService that keeps trashed items for retention.
+func NewTrashService(
+	repo repository.TrashRepository,
+	songRepo repository.SongRepository,
+	idGen utils.IDGenerator,
+	timeProvider utils.TimeProvider,
+	indexer CatalogIndexer,
+	retention time.Duration,
+) *TrashService {
+	return &TrashService{
+		repo:         repo,
+		songRepo:     songRepo,
+		idGen:        idGen,
+		timeProvider: timeProvider,
+		indexer:      indexer,
+		retention:    retention,
+	}
+}
+
+// ListTrash returns the songs in the trash, with the number of documents trashed with each, and the
+// documents trashed on their own. Both lists are ordered by deletion time, newest first.
+func (s *TrashService) ListTrash() (dto.TrashResponse, error) {
+	songs, err := s.repo.GetTrashedSongs()
+	if err != nil {
+		return dto.TrashResponse{}, fmt.Errorf("retrieving trashed songs: %w", err)
+	}
+	documents, err := s.repo.GetTrashedDocuments()
+	if err != nil {
+		return dto.TrashResponse{}, fmt.Errorf("retrieving trashed documents: %w", err)
+	}
+
+	sort.SliceStable(songs, func(i, j int) bool { return songs[i].DeletedAt > songs[j].DeletedAt })
+	sort.SliceStable(documents, func(i, j int) bool { return documents[i].DeletedAt > documents[j].DeletedAt })
+
+	withSong := make(map[string]int)
+	response := dto.TrashResponse{Songs: []dto.TrashedSongItem{}, Documents: []dto.TrashedDocumentItem{}}
+	for _, doc := range documents {
+		if doc.DeletedWithSong {
+			withSong[doc.SongID]++
+			continue
+		}
+		response.Documents = append(response.Documents, dto.ToTrashedDocumentItem(doc, s.purgeAt(doc.DeletedAt)))
+	}
+	for _, song := range songs {
+		response.Songs = append(response.Songs, dto.ToTrashedSongItem(song, withSong[song.ID], s.purgeAt(song.DeletedAt)))
+	}
+	return response, nil
+}
+
+// RestoreSong brings a song back from the trash together with the documents trashed with it.
+// Documents that were trashed on their own before the song stay in the trash.
+func (s *TrashService) RestoreSong(actor models.Actor, songID string) error {
+	song, err := s.repo.GetTrashedSongByID(songID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed song %s: %w", songID, err)
+	}
+	trashed, err := s.repo.GetTrashedDocumentsBySongID(songID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed documents of song %s: %w", songID, err)
+	}
+
+	var documents []models.Document
+	var ids []string
+	for _, doc := range trashed {
+		if doc.DeletedWithSong {
+			documents = append(documents, doc)
+			ids = append(ids, doc.ID)
+		}
+	}
+
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntitySong, songID, songID, models.AuditActionRestore, nil, newSongAuditView(*song, documents))
+	if err := s.repo.RestoreSong(songID, ids, audit); err != nil {
+		return fmt.Errorf("restoring song %s: %w", songID, err)
+	}
+
+	song.DeletedAt, song.DeletedBy = "", ""
+	s.indexer.IndexSong(*song)
+	for _, doc := range documents {
+		doc.DeletedAt, doc.DeletedBy, doc.DeletedWithSong = "", "", false
+		s.indexer.IndexDocument(doc)
+	}
+	return nil
+}
+
+// RestoreDocument brings a document trashed on its own back from the trash. Documents trashed
+// with their song are restored with it, and a document cannot come back while its song is in the trash.
+func (s *TrashService) RestoreDocument(actor models.Actor, songID, docID string) error {
+	doc, err := s.repo.GetTrashedDocumentByID(songID, docID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed document %s: %w", docID, err)
+	}
+	if doc.DeletedWithSong {
+		return fmt.Errorf("document %s went to the trash with song %s, restore the song instead: %w", docID, songID, errors.ErrOperationNotAllowed)
+	}
+	if _, err := s.songRepo.GetSongByID(songID); err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return fmt.Errorf("song %s of document %s is not live: %w", songID, docID, errors.ErrOperationNotAllowed)
+		}
+		return fmt.Errorf("retrieving song %s: %w", songID, err)
+	}
+
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntityDocument, docID, songID, models.AuditActionRestore, nil, doc)
+	if err := s.repo.RestoreDocument(songID, docID, audit); err != nil {
+		return fmt.Errorf("restoring document %s for song %s: %w", docID, songID, err)
+	}
+
+	doc.DeletedAt, doc.DeletedBy = "", ""
+	s.indexer.IndexDocument(*doc)
+	return nil
+}
+
+// PurgeSong permanently deletes a song in the trash and every document of it.
+func (s *TrashService) PurgeSong(actor models.Actor, songID string) error {
+	song, err := s.repo.GetTrashedSongByID(songID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed song %s: %w", songID, err)
+	}
+	documents, err := s.repo.GetTrashedDocumentsBySongID(songID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed documents of song %s: %w", songID, err)
+	}
+	return s.purgeSong(actor, *song, documents)
+}
+
+// PurgeDocument permanently deletes a document in the trash.
+func (s *TrashService) PurgeDocument(actor models.Actor, songID, docID string) error {
+	doc, err := s.repo.GetTrashedDocumentByID(songID, docID)
+	if err != nil {
+		return fmt.Errorf("retrieving trashed document %s: %w", docID, err)
+	}
+	return s.purgeDocument(actor, *doc)
+}
+
+// PurgeExpired permanently deletes the songs and the documents trashed on their own whose deletion is
+// older than the retention period. Documents trashed with a song are purged with it. Items that fail
+// are left for the next run, and their errors are returned together after every item was attempted.
+func (s *TrashService) PurgeExpired(actor models.Actor) (dto.PurgeTrashResponse, error) {
+	songs, err := s.repo.GetTrashedSongs()
+	if err != nil {
+		return dto.PurgeTrashResponse{}, fmt.Errorf("retrieving trashed songs: %w", err)
+	}
+	documents, err := s.repo.GetTrashedDocuments()
+	if err != nil {
+		return dto.PurgeTrashResponse{}, fmt.Errorf("retrieving trashed documents: %w", err)
+	}
+
+	bySong := make(map[string][]models.Document)
+	for _, doc := range documents {
+		bySong[doc.SongID] = append(bySong[doc.SongID], doc)
+	}
+
+	response := dto.PurgeTrashResponse{Message: "Expired trash purged successfully"}
+	purgedSongs := make(map[string]bool)
+	var errs []error
+	for _, song := range songs {
+		if !s.expired(song.DeletedAt) {
+			continue
+		}
+		if err := s.purgeSong(actor, song, bySong[song.ID]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purgedSongs[song.ID] = true
+		response.Songs++
+	}
+	for _, doc := range documents {
+		if doc.DeletedWithSong || purgedSongs[doc.SongID] || !s.expired(doc.DeletedAt) {
+			continue
+		}
+		if err := s.purgeDocument(actor, doc); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		response.Documents++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operation": "purge_expired_trash",
+		"actor":     actor.Username,
+		"songs":     response.Songs,
+		"documents": response.Documents,
+		"failures":  len(errs),
+	}).Info("Expired trash purged")
+
+	if len(errs) > 0 {
+		return response, fmt.Errorf("purging expired trash: %w", stdErrors.Join(errs...))
+	}
+	return response, nil
+}
+
+// ReconcilePendingSongs finishes the trashes, restores and purges of songs with more documents than fit
+// in one transaction that were interrupted between transactions, and rolls back interrupted creations
+// along with the documents already written for them. Songs whose write started less than
+// pendingWriteGracePeriod ago are left alone. Failed songs are left for the next run, and their errors
+// are returned together after every song was attempted.
+func (s *TrashService) ReconcilePendingSongs(actor models.Actor) (dto.ReconcileSongsResponse, error) {
+	songs, err := s.repo.GetPendingSongs()
+	if err != nil {
+		return dto.ReconcileSongsResponse{}, fmt.Errorf("retrieving pending songs: %w", err)
+	}
+
+	response := dto.ReconcileSongsResponse{Message: "Pending songs reconciled successfully"}
+	var errs []error
+	for _, song := range songs {
+		if !s.interrupted(song) {
+			continue
+		}
+
+		if song.Pending == models.SongPendingCreate {
+			audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntitySong, song.ID, song.ID, models.AuditActionPurge, newSongAuditView(song, nil), nil)
+			if err := s.repo.RollBackSongCreation(song.ID, audit); err != nil {
+				errs = append(errs, fmt.Errorf("rolling back creation of song %s: %w", song.ID, err))
+				continue
+			}
+			s.indexer.RemoveSong(song.ID)
+			response.RolledBack++
+			continue
+		}
+
+		if err := s.repo.CompletePendingSong(song); err != nil {
+			errs = append(errs, fmt.Errorf("completing pending %s of song %s: %w", song.Pending, song.ID, err))
+			continue
+		}
+		if song.Pending == models.SongPendingRestore {
+			song.DeletedAt, song.DeletedBy, song.Pending, song.PendingSince = "", "", "", ""
+			s.indexer.IndexSong(song)
+		} else {
+			s.indexer.RemoveSong(song.ID)
+		}
+		response.Completed++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operation":   "reconcile_pending_songs",
+		"actor":       actor.Username,
+		"completed":   response.Completed,
+		"rolled_back": response.RolledBack,
+		"failures":    len(errs),
+	}).Info("Pending songs reconciled")
+
+	if len(errs) > 0 {
+		return response, fmt.Errorf("reconciling pending songs: %w", stdErrors.Join(errs...))
+	}
+	return response, nil
+}
+
+// StartRetentionJob runs ReconcilePendingSongs and PurgeExpired every interval until the returned stop function is called.
+func (s *TrashService) StartRetentionJob(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.ReconcilePendingSongs(TrashRetentionActor); err != nil {
+					logrus.WithField("operation", "reconcile_pending_songs").WithError(err).Error("Pending song reconciliation failed")
+				}
+				if _, err := s.PurgeExpired(TrashRetentionActor); err != nil {
+					logrus.WithField("operation", "purge_expired_trash").WithError(err).Error("Trash retention job failed")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (s *TrashService) purgeSong(actor models.Actor, song models.Song, documents []models.Document) error {
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntitySong, song.ID, song.ID, models.AuditActionPurge, newSongAuditView(song, documents), nil)
+	if err := s.repo.PurgeSong(song.ID, audit); err != nil {
+		return fmt.Errorf("purging song %s: %w", song.ID, err)
+	}
+	return nil
+}
+
+func (s *TrashService) purgeDocument(actor models.Actor, doc models.Document) error {
+	audit := newAuditEntry(s.idGen, actor, s.timeProvider.Now(), models.AuditEntityDocument, doc.ID, doc.SongID, models.AuditActionPurge, &doc, nil)
+	if err := s.repo.PurgeDocument(doc.SongID, doc.ID, audit); err != nil {
+		return fmt.Errorf("purging document %s for song %s: %w", doc.ID, doc.SongID, err)
+	}
+	return nil
+}
+
+// purgeAt returns when an item deleted at deletedAt becomes eligible for PurgeExpired,
+// or an empty string if deletedAt is not a valid timestamp.
+func (s *TrashService) purgeAt(deletedAt string) string {
+	deleted, err := time.Parse(time.RFC3339, deletedAt)
+	if err != nil {
+		return ""
+	}
+	return deleted.Add(s.retention).UTC().Format(time.RFC3339)
+}
+
+// interrupted reports whether the pending write on song started more than pendingWriteGracePeriod ago.
+// Songs with an invalid pending timestamp are never reconciled automatically.
+func (s *TrashService) interrupted(song models.Song) bool {
+	since, err := time.Parse(time.RFC3339, song.PendingSince)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"song_id":       song.ID,
+			"pending_since": song.PendingSince,
+		}).Warn("Ignoring pending song with an invalid timestamp")
+		return false
+	}
+	return since.Add(pendingWriteGracePeriod).Unix() <= s.timeProvider.NowUnix()
+}
+
+// expired reports whether an item deleted at deletedAt has outlived the retention period.
+// Items with an invalid deletion timestamp are never purged automatically.
+func (s *TrashService) expired(deletedAt string) bool {
+	deleted, err := time.Parse(time.RFC3339, deletedAt)
+	if err != nil {
+		logrus.WithField("deleted_at", deletedAt).Warn("Ignoring trashed item with an invalid deletion timestamp")
+		return false
+	}
+	return deleted.Add(s.retention).Unix() <= s.timeProvider.NowUnix()
+}
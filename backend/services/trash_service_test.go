@@ -0,0 +1,228 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type trashServiceMocks struct {
+	trashRepo    *mocks.MockTrashRepository
+	songRepo     *mocks.MockSongRepository
+	idGen        *mocks.MockIDGenerator
+	timeProvider *mocks.MockTimeProvider
+	indexer      *mocks.MockCatalogIndexer
+}
+
+func setupTrashServiceTest() (*services.TrashService, trashServiceMocks) {
+	m := trashServiceMocks{
+		trashRepo:    new(mocks.MockTrashRepository),
+		songRepo:     new(mocks.MockSongRepository),
+		idGen:        new(mocks.MockIDGenerator),
+		timeProvider: new(mocks.MockTimeProvider),
+		indexer:      newMockCatalogIndexer(),
+	}
+	m.idGen.On("NewID").Return("audit-1").Maybe()
+	m.timeProvider.On("Now").Return("now").Maybe()
+	m.timeProvider.On("NowUnix").Return(TrashNow).Maybe()
+	service := services.NewTrashService(m.trashRepo, m.songRepo, m.idGen, m.timeProvider, m.indexer, 30*24*time.Hour)
+	return service, m
+}
+
+func TestListTrash_GroupsDocumentsWithTheirSong(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{TrashedSong, RecentlyTrashedSong}, nil)
+	m.trashRepo.On("GetTrashedDocuments").Return([]models.Document{TrashedWithSongDocument, TrashedDocument}, nil)
+
+	trash, err := service.ListTrash()
+
+	assert.NoError(t, err)
+	if assert.Len(t, trash.Songs, 2) {
+		assert.Equal(t, "song-new", trash.Songs[0].ID, "newest deletion first")
+		assert.Equal(t, 0, trash.Songs[0].Documents)
+		assert.Equal(t, "song-old", trash.Songs[1].ID)
+		assert.Equal(t, 1, trash.Songs[1].Documents)
+		assert.Equal(t, "2025-01-31T00:00:00Z", trash.Songs[1].PurgeAt)
+	}
+	if assert.Len(t, trash.Documents, 1) {
+		assert.Equal(t, "doc-1", trash.Documents[0].ID)
+		assert.Equal(t, "maria", trash.Documents[0].DeletedBy)
+	}
+}
+
+func TestRestoreSong_RestoresDocumentsTrashedWithIt(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	song := TrashedSong
+	trashedAlone := TrashedDocument
+	trashedAlone.SongID = "song-old"
+	m.trashRepo.On("GetTrashedSongByID", "song-old").Return(&song, nil)
+	m.trashRepo.On("GetTrashedDocumentsBySongID", "song-old").Return([]models.Document{TrashedWithSongDocument, trashedAlone}, nil)
+
+	var audit models.AuditEntry
+	m.trashRepo.On("RestoreSong", "song-old", []string{"doc-old"}, mock.Anything).
+		Run(func(args mock.Arguments) { audit = args.Get(2).(models.AuditEntry) }).
+		Return(nil)
+
+	err := service.RestoreSong(EditorActor, "song-old")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AuditActionRestore, audit.Action)
+	assert.Equal(t, "maria", audit.Actor)
+	m.indexer.AssertCalled(t, "IndexSong", mock.MatchedBy(func(s models.Song) bool { return s.ID == "song-old" && s.DeletedAt == "" }))
+	m.indexer.AssertNumberOfCalls(t, "IndexDocument", 1)
+}
+
+func TestRestoreSong_NotInTrash(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetTrashedSongByID", "1").Return(nil, errors.ErrResourceNotFound)
+
+	err := service.RestoreSong(EditorActor, "1")
+
+	assert.ErrorIs(t, err, errors.ErrResourceNotFound)
+	m.trashRepo.AssertNotCalled(t, "RestoreSong", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         models.Document
+		songErr     error
+		expectError error
+	}{
+		{name: "document trashed on its own", doc: TrashedDocument},
+		{name: "document trashed with its song", doc: TrashedWithSongDocument, expectError: errors.ErrOperationNotAllowed},
+		{name: "song in the trash", doc: TrashedDocument, songErr: errors.ErrResourceNotFound, expectError: errors.ErrOperationNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := setupTrashServiceTest()
+			doc := tt.doc
+			m.trashRepo.On("GetTrashedDocumentByID", doc.SongID, doc.ID).Return(&doc, nil)
+			if tt.songErr != nil {
+				m.songRepo.On("GetSongByID", doc.SongID).Return(nil, tt.songErr)
+			} else {
+				m.songRepo.On("GetSongByID", doc.SongID).Return(&RelatedSong, nil).Maybe()
+			}
+			m.trashRepo.On("RestoreDocument", doc.SongID, doc.ID, mock.Anything).Return(nil).Maybe()
+
+			err := service.RestoreDocument(EditorActor, doc.SongID, doc.ID)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				m.trashRepo.AssertNotCalled(t, "RestoreDocument", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			m.trashRepo.AssertCalled(t, "RestoreDocument", doc.SongID, doc.ID, mock.Anything)
+			m.indexer.AssertCalled(t, "IndexDocument", mock.Anything)
+		})
+	}
+}
+
+func TestPurgeSong_RecordsPurgeInAudit(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	song := TrashedSong
+	m.trashRepo.On("GetTrashedSongByID", "song-old").Return(&song, nil)
+	m.trashRepo.On("GetTrashedDocumentsBySongID", "song-old").Return([]models.Document{TrashedWithSongDocument}, nil)
+
+	var audit models.AuditEntry
+	m.trashRepo.On("PurgeSong", "song-old", mock.Anything).
+		Run(func(args mock.Arguments) { audit = args.Get(1).(models.AuditEntry) }).
+		Return(nil)
+
+	err := service.PurgeSong(EditorActor, "song-old")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AuditActionPurge, audit.Action)
+	assert.Equal(t, "song-old", audit.EntityID)
+	assert.NotEmpty(t, audit.Changes)
+}
+
+func TestPurgeExpired_OnlyPurgesItemsPastRetention(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{TrashedSong, RecentlyTrashedSong}, nil)
+	m.trashRepo.On("GetTrashedDocuments").Return([]models.Document{TrashedWithSongDocument, TrashedDocument}, nil)
+	m.trashRepo.On("PurgeSong", "song-old", mock.Anything).Return(nil)
+	m.trashRepo.On("PurgeDocument", "song-123", "doc-1", mock.Anything).Return(nil)
+
+	result, err := service.PurgeExpired(services.TrashRetentionActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Songs)
+	assert.Equal(t, 1, result.Documents)
+	m.trashRepo.AssertNotCalled(t, "PurgeSong", "song-new", mock.Anything)
+	m.trashRepo.AssertNotCalled(t, "PurgeDocument", "song-old", "doc-old", mock.Anything)
+}
+
+func TestPurgeExpired_ContinuesAfterFailures(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetTrashedSongs").Return([]models.Song{TrashedSong}, nil)
+	m.trashRepo.On("GetTrashedDocuments").Return([]models.Document{TrashedDocument}, nil)
+	m.trashRepo.On("PurgeSong", "song-old", mock.Anything).Return(errors.ErrThroughputExceeded)
+	m.trashRepo.On("PurgeDocument", "song-123", "doc-1", mock.Anything).Return(nil)
+
+	result, err := service.PurgeExpired(services.TrashRetentionActor)
+
+	assert.ErrorIs(t, err, errors.ErrThroughputExceeded)
+	assert.Equal(t, 0, result.Songs)
+	assert.Equal(t, 1, result.Documents)
+}
+
+func TestReconcilePendingSongs_CompletesAndRollsBackInterruptedWrites(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetPendingSongs").Return([]models.Song{PendingTrashSong, PendingRestoreSong, PendingCreateSong, InFlightSong}, nil)
+	m.trashRepo.On("CompletePendingSong", PendingTrashSong).Return(nil)
+	m.trashRepo.On("CompletePendingSong", PendingRestoreSong).Return(nil)
+
+	var audit models.AuditEntry
+	m.trashRepo.On("RollBackSongCreation", "song-creating", mock.Anything).
+		Run(func(args mock.Arguments) { audit = args.Get(1).(models.AuditEntry) }).
+		Return(nil)
+
+	result, err := service.ReconcilePendingSongs(services.TrashRetentionActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Completed)
+	assert.Equal(t, 1, result.RolledBack)
+	assert.Equal(t, models.AuditActionPurge, audit.Action)
+	assert.Equal(t, services.TrashRetentionActor.Username, audit.Actor)
+	m.trashRepo.AssertNotCalled(t, "CompletePendingSong", InFlightSong)
+	m.trashRepo.AssertExpectations(t)
+
+	m.indexer.AssertCalled(t, "RemoveSong", "song-trashing")
+	m.indexer.AssertCalled(t, "RemoveSong", "song-creating")
+	m.indexer.AssertCalled(t, "IndexSong", mock.MatchedBy(func(song models.Song) bool {
+		return song.ID == "song-restoring" && song.Pending == ""
+	}))
+}
+
+func TestReconcilePendingSongs_ContinuesAfterFailures(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetPendingSongs").Return([]models.Song{PendingTrashSong, PendingCreateSong}, nil)
+	m.trashRepo.On("CompletePendingSong", PendingTrashSong).Return(errors.ErrThroughputExceeded)
+	m.trashRepo.On("RollBackSongCreation", "song-creating", mock.Anything).Return(nil)
+
+	result, err := service.ReconcilePendingSongs(services.TrashRetentionActor)
+
+	assert.ErrorIs(t, err, errors.ErrThroughputExceeded)
+	assert.Equal(t, 0, result.Completed)
+	assert.Equal(t, 1, result.RolledBack)
+	m.indexer.AssertNotCalled(t, "RemoveSong", "song-trashing")
+}
+
+func TestReconcilePendingSongs_RepositoryError(t *testing.T) {
+	service, m := setupTrashServiceTest()
+	m.trashRepo.On("GetPendingSongs").Return(nil, errors.ErrInternalServer)
+
+	_, err := service.ReconcilePendingSongs(services.TrashRetentionActor)
+
+	assert.ErrorIs(t, err, errors.ErrInternalServer)
+	m.trashRepo.AssertNotCalled(t, "CompletePendingSong", mock.Anything)
+}
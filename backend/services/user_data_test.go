@@ -0,0 +1,22 @@
+package services_test
+
+import (
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+)
+
+const InviteToken = "invite-token"
+
+var PendingInvitedUser = models.User{
+	Username:        "lucia",
+	Role:            models.RoleEditor,
+	Status:          models.UserStatusInvited,
+	InviteTokenHash: utils.HashToken(InviteToken),
+	InviteExpiresAt: 5000,
+}
+
+var StoredUsers = []models.User{
+	{Username: "pablo", Role: models.RoleViewer, Status: models.UserStatusActive},
+	{Username: "admin", Role: models.RoleAdmin, Status: models.UserStatusActive},
+	{Username: "lucia", Role: models.RoleEditor, Status: models.UserStatusInvited},
+}
@@ -0,0 +1,53 @@
+package services
+
+import "github.com/CristinaRendaLopez/rendalla-backend/dto"
+
+// UserServiceInterface defines the management of back-office user accounts.
+type UserServiceInterface interface {
+
+	// ListUsers returns every user ordered by username.
+	// Returns:
+	//   - ([]dto.UserResponseItem, nil) on success
+	//   - error if the users cannot be read
+	ListUsers() ([]dto.UserResponseItem, error)
+
+	// InviteUser creates a pending account with the given role and a one-time invitation token.
+	// Returns:
+	//   - the invitation, including the clear token, on success
+	//   - errors.ErrValidationFailed if the username or role is invalid
+	//   - errors.ErrOperationNotAllowed if the username is already taken
+	//   - error if persistence fails
+	InviteUser(actor string, req dto.InviteUserRequest) (*dto.InviteUserResponse, error)
+
+	// AcceptInvite sets the password of an invited user and activates the account.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrValidationFailed if the password does not meet the strength policy
+	//   - errors.ErrInvalidCredentials if the token is wrong, expired or already used
+	//   - error if persistence fails
+	AcceptInvite(req dto.AcceptInviteRequest) error
+
+	// DisableUser prevents a user from signing in without removing the account and revokes its sessions.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the actor targets its own account
+	//   - errors.ErrResourceNotFound if the user does not exist
+	//   - error if persistence fails
+	DisableUser(actor, username string) error
+
+	// EnableUser reactivates a disabled user.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the user is not disabled
+	//   - errors.ErrResourceNotFound if the user does not exist
+	//   - error if persistence fails
+	EnableUser(actor, username string) error
+
+	// DeleteUser removes a user account and revokes its sessions.
+	// Returns:
+	//   - nil on success
+	//   - errors.ErrOperationNotAllowed if the actor targets its own account
+	//   - errors.ErrResourceNotFound if the user does not exist
+	//   - error if persistence fails
+	DeleteUser(actor, username string) error
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"crypto/subtle"
+	stdErrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/repository"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// inviteTTLSeconds is how long an invitation token can be accepted after it is issued.
+const inviteTTLSeconds = 7 * 24 * 3600
+
+// Ensure UserService implements UserServiceInterface.
+var _ UserServiceInterface = (*UserService)(nil)
+
+// UserService manages back-office accounts: invitations, activation, disabling and removal.
+// Invitation tokens are returned once to the inviting admin and only their SHA-256 digest is stored.
+// Disabling or removing an account revokes its sessions, so the user is logged out at once.
+type UserService struct {
+	repo         repository.UserRepository
+	sessions     repository.SessionRepository
+	timeProvider utils.TimeProvider
+}
+
+// NewUserService returns a new instance of UserService.
+func NewUserService(repo repository.UserRepository, sessions repository.SessionRepository, timeProvider utils.TimeProvider) *UserService {
+	return &UserService{
+		repo:         repo,
+		sessions:     sessions,
+		timeProvider: timeProvider,
+	}
+}
+
+// ListUsers returns every user ordered by username.
+func (s *UserService) ListUsers() ([]dto.UserResponseItem, error) {
+	users, err := s.repo.GetAllUsers()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving users: %w", err)
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return dto.ToUserResponseList(users), nil
+}
+
+// InviteUser validates the request and stores a new account in the invited state.
+// The generated token is returned in clear exactly once.
+func (s *UserService) InviteUser(actor string, req dto.InviteUserRequest) (*dto.InviteUserResponse, error) {
+	if err := dto.ValidateInviteUserRequest(req); err != nil {
+		return nil, fmt.Errorf("validating invitation: %w", err)
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generating invitation token: %w", errors.ErrInternalServer)
+	}
+
+	now := s.timeProvider.Now()
+	user := models.User{
+		Username:        dto.NormalizeUsername(req.Username),
+		Role:            req.Role,
+		Status:          models.UserStatusInvited,
+		InviteTokenHash: utils.HashToken(token),
+		InviteExpiresAt: s.timeProvider.NowUnix() + inviteTTLSeconds,
+		InvitedBy:       actor,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.repo.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("creating user %s: %w", user.Username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"username":   user.Username,
+		"role":       user.Role,
+		"invited_by": actor,
+	}).Info("User invited")
+
+	return &dto.InviteUserResponse{
+		Message:     "User invited successfully",
+		Username:    user.Username,
+		InviteToken: token,
+		ExpiresAt:   user.InviteExpiresAt,
+	}, nil
+}
+
+// AcceptInvite checks the invitation token, stores the bcrypt hash of the chosen password
+// and activates the account. The token is cleared so it cannot be reused.
+func (s *UserService) AcceptInvite(req dto.AcceptInviteRequest) error {
+	if err := dto.ValidateAcceptInviteRequest(req); err != nil {
+		return fmt.Errorf("validating invitation acceptance: %w", err)
+	}
+
+	username := dto.NormalizeUsername(req.Username)
+	user, err := s.repo.GetUserByUsername(username)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrResourceNotFound) {
+			return errors.ErrInvalidCredentials
+		}
+		return fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+
+	if user.Status != models.UserStatusInvited || user.InviteTokenHash == "" {
+		return errors.ErrInvalidCredentials
+	}
+	if s.timeProvider.NowUnix() > user.InviteExpiresAt {
+		return fmt.Errorf("invitation for %s expired: %w", username, errors.ErrInvalidCredentials)
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(req.Token)), []byte(user.InviteTokenHash)) != 1 {
+		return errors.ErrInvalidCredentials
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", errors.ErrInternalServer)
+	}
+
+	updates := map[string]interface{}{
+		"password_hash":     string(hash),
+		"status":            models.UserStatusActive,
+		"invite_token_hash": nil,
+		"invite_expires_at": nil,
+		"updated_at":        s.timeProvider.Now(),
+	}
+	if err := s.repo.UpdateUser(username, updates); err != nil {
+		return fmt.Errorf("activating user %s: %w", username, err)
+	}
+
+	logrus.WithField("username", username).Info("Invitation accepted")
+	return nil
+}
+
+// DisableUser marks the user as disabled and revokes its sessions. Admins cannot disable themselves.
+func (s *UserService) DisableUser(actor, username string) error {
+	username = dto.NormalizeUsername(username)
+	if username == actor {
+		return fmt.Errorf("user %s cannot disable itself: %w", actor, errors.ErrOperationNotAllowed)
+	}
+
+	if _, err := s.repo.GetUserByUsername(username); err != nil {
+		return fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+
+	updates := map[string]interface{}{
+		"status":     models.UserStatusDisabled,
+		"updated_at": s.timeProvider.Now(),
+	}
+	if err := s.repo.UpdateUser(username, updates); err != nil {
+		return fmt.Errorf("disabling user %s: %w", username, err)
+	}
+	if _, err := revokeUserSessions(s.sessions, username, revokeReasonUserDisabled, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("logging out disabled user %s: %w", username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{"username": username, "actor": actor}).Info("User disabled")
+	return nil
+}
+
+// EnableUser reactivates a disabled user. Invited users must accept their invitation instead.
+func (s *UserService) EnableUser(actor, username string) error {
+	username = dto.NormalizeUsername(username)
+	user, err := s.repo.GetUserByUsername(username)
+	if err != nil {
+		return fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+	if user.Status != models.UserStatusDisabled {
+		return fmt.Errorf("user %s is %s: %w", username, user.Status, errors.ErrOperationNotAllowed)
+	}
+
+	updates := map[string]interface{}{
+		"status":     models.UserStatusActive,
+		"updated_at": s.timeProvider.Now(),
+	}
+	if err := s.repo.UpdateUser(username, updates); err != nil {
+		return fmt.Errorf("enabling user %s: %w", username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{"username": username, "actor": actor}).Info("User enabled")
+	return nil
+}
+
+// DeleteUser removes the account and revokes its sessions. Admins cannot delete themselves.
+func (s *UserService) DeleteUser(actor, username string) error {
+	username = dto.NormalizeUsername(username)
+	if username == actor {
+		return fmt.Errorf("user %s cannot delete itself: %w", actor, errors.ErrOperationNotAllowed)
+	}
+
+	if _, err := s.repo.GetUserByUsername(username); err != nil {
+		return fmt.Errorf("retrieving user %s: %w", username, err)
+	}
+
+	if err := s.repo.DeleteUser(username); err != nil {
+		return fmt.Errorf("deleting user %s: %w", username, err)
+	}
+	if _, err := revokeUserSessions(s.sessions, username, revokeReasonUserDeleted, s.timeProvider.Now()); err != nil {
+		return fmt.Errorf("logging out deleted user %s: %w", username, err)
+	}
+
+	logrus.WithFields(logrus.Fields{"username": username, "actor": actor}).Info("User deleted")
+	return nil
+}
@@ -0,0 +1,330 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/dto"
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
+	"github.com/CristinaRendaLopez/rendalla-backend/mocks"
+	"github.com/CristinaRendaLopez/rendalla-backend/models"
+	"github.com/CristinaRendaLopez/rendalla-backend/services"
+	"github.com/CristinaRendaLopez/rendalla-backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func setupUserServiceTest(nowUnix int64) (*services.UserService, *mocks.MockUserRepository, *mocks.MockSessionRepository) {
+	repo := new(mocks.MockUserRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	timeProvider := new(mocks.MockTimeProvider)
+	timeProvider.On("Now").Return("now").Maybe()
+	timeProvider.On("NowUnix").Return(nowUnix).Maybe()
+	return services.NewUserService(repo, sessionRepo, timeProvider), repo, sessionRepo
+}
+
+func TestListUsers(t *testing.T) {
+	service, repo, _ := setupUserServiceTest(1000)
+	repo.On("GetAllUsers").Return(append([]models.User{}, StoredUsers...), nil)
+
+	users, err := service.ListUsers()
+
+	assert.NoError(t, err)
+	names := []string{}
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	assert.Equal(t, []string{"admin", "lucia", "pablo"}, names)
+}
+
+func TestInviteUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       dto.InviteUserRequest
+		expectCreate  bool
+		mockCreateErr error
+		expectError   error
+	}{
+		{
+			name:         "success",
+			request:      dto.InviteUserRequest{Username: " Lucia ", Role: models.RoleEditor},
+			expectCreate: true,
+		},
+		{
+			name:        "unknown role",
+			request:     dto.InviteUserRequest{Username: "lucia", Role: "owner"},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "invalid username",
+			request:     dto.InviteUserRequest{Username: "lu cia", Role: models.RoleViewer},
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:          "username taken",
+			request:       dto.InviteUserRequest{Username: "lucia", Role: models.RoleViewer},
+			expectCreate:  true,
+			mockCreateErr: errors.ErrOperationNotAllowed,
+			expectError:   errors.ErrOperationNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupUserServiceTest(1000)
+			var created models.User
+			if tt.expectCreate {
+				repo.On("CreateUser", mock.Anything).Run(func(args mock.Arguments) {
+					created = args.Get(0).(models.User)
+				}).Return(tt.mockCreateErr)
+			}
+
+			invite, err := service.InviteUser("admin", tt.request)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, invite)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "lucia", invite.Username)
+				assert.NotEmpty(t, invite.InviteToken)
+				assert.Equal(t, int64(1000+7*24*3600), invite.ExpiresAt)
+
+				assert.Equal(t, "lucia", created.Username)
+				assert.Equal(t, models.UserStatusInvited, created.Status)
+				assert.Equal(t, "admin", created.InvitedBy)
+				assert.Empty(t, created.PasswordHash)
+				assert.Equal(t, utils.HashToken(invite.InviteToken), created.InviteTokenHash)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAcceptInvite(t *testing.T) {
+	activeUser := PendingInvitedUser
+	activeUser.Status = models.UserStatusActive
+
+	tests := []struct {
+		name         string
+		request      dto.AcceptInviteRequest
+		nowUnix      int64
+		storedUser   *models.User
+		mockGetErr   error
+		expectUpdate bool
+		expectError  error
+	}{
+		{
+			name:         "success",
+			request:      dto.AcceptInviteRequest{Username: "Lucia", Token: InviteToken, Password: StrongPassword},
+			nowUnix:      1000,
+			storedUser:   &PendingInvitedUser,
+			expectUpdate: true,
+		},
+		{
+			name:        "password too short",
+			request:     dto.AcceptInviteRequest{Username: "lucia", Token: InviteToken, Password: "short"},
+			nowUnix:     1000,
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "weak password",
+			request:     dto.AcceptInviteRequest{Username: "lucia", Token: InviteToken, Password: "longenough"},
+			nowUnix:     1000,
+			expectError: errors.ErrValidationFailed,
+		},
+		{
+			name:        "wrong token",
+			request:     dto.AcceptInviteRequest{Username: "lucia", Token: "other", Password: StrongPassword},
+			nowUnix:     1000,
+			storedUser:  &PendingInvitedUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "expired invitation",
+			request:     dto.AcceptInviteRequest{Username: "lucia", Token: InviteToken, Password: StrongPassword},
+			nowUnix:     6000,
+			storedUser:  &PendingInvitedUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "already accepted",
+			request:     dto.AcceptInviteRequest{Username: "lucia", Token: InviteToken, Password: StrongPassword},
+			nowUnix:     1000,
+			storedUser:  &activeUser,
+			expectError: errors.ErrInvalidCredentials,
+		},
+		{
+			name:        "unknown user",
+			request:     dto.AcceptInviteRequest{Username: "nobody", Token: InviteToken, Password: StrongPassword},
+			nowUnix:     1000,
+			mockGetErr:  errors.ErrResourceNotFound,
+			expectError: errors.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupUserServiceTest(tt.nowUnix)
+			username := dto.NormalizeUsername(tt.request.Username)
+			if tt.storedUser != nil {
+				repo.On("GetUserByUsername", username).Return(tt.storedUser, nil)
+			} else if tt.mockGetErr != nil {
+				repo.On("GetUserByUsername", username).Return(nil, tt.mockGetErr)
+			}
+			if tt.expectUpdate {
+				repo.On("UpdateUser", username, mock.MatchedBy(func(u map[string]interface{}) bool {
+					hash, _ := u["password_hash"].(string)
+					return u["status"] == models.UserStatusActive &&
+						u["invite_token_hash"] == nil &&
+						bcrypt.CompareHashAndPassword([]byte(hash), []byte(tt.request.Password)) == nil
+				})).Return(nil)
+			}
+
+			err := service.AcceptInvite(tt.request)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisableUser(t *testing.T) {
+	tests := []struct {
+		name            string
+		actor           string
+		username        string
+		mockGetErr      error
+		mockSessionsErr error
+		expectUpdate    bool
+		expectError     error
+	}{
+		{name: "success", actor: "admin", username: "pablo", expectUpdate: true},
+		{name: "cannot disable itself", actor: "admin", username: "Admin", expectError: errors.ErrOperationNotAllowed},
+		{name: "not found", actor: "admin", username: "nobody", mockGetErr: errors.ErrResourceNotFound, expectError: errors.ErrResourceNotFound},
+		{name: "session lookup fails", actor: "admin", username: "pablo", expectUpdate: true, mockSessionsErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, sessionRepo := setupUserServiceTest(1000)
+			if tt.mockGetErr != nil {
+				repo.On("GetUserByUsername", tt.username).Return(nil, tt.mockGetErr)
+			} else if tt.expectUpdate {
+				repo.On("GetUserByUsername", tt.username).Return(&StoredUsers[0], nil)
+				repo.On("UpdateUser", tt.username, map[string]interface{}{
+					"status":     models.UserStatusDisabled,
+					"updated_at": "now",
+				}).Return(nil)
+				if tt.mockSessionsErr != nil {
+					sessionRepo.On("GetSessionsByUsername", tt.username).Return(nil, tt.mockSessionsErr)
+				} else {
+					sessionRepo.On("GetSessionsByUsername", tt.username).Return(userSessions(tt.username), nil)
+					sessionRepo.On("RevokeSession", "session-active", "user_disabled", "now").Return(nil)
+				}
+			}
+
+			err := service.DisableUser(tt.actor, tt.username)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnableUser(t *testing.T) {
+	disabled := models.User{Username: "pablo", Role: models.RoleViewer, Status: models.UserStatusDisabled}
+
+	tests := []struct {
+		name         string
+		storedUser   models.User
+		expectUpdate bool
+		expectError  error
+	}{
+		{name: "success", storedUser: disabled, expectUpdate: true},
+		{name: "user not disabled", storedUser: StoredUsers[0], expectError: errors.ErrOperationNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupUserServiceTest(1000)
+			repo.On("GetUserByUsername", "pablo").Return(&tt.storedUser, nil)
+			if tt.expectUpdate {
+				repo.On("UpdateUser", "pablo", map[string]interface{}{
+					"status":     models.UserStatusActive,
+					"updated_at": "now",
+				}).Return(nil)
+			}
+
+			err := service.EnableUser("admin", "pablo")
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		actor        string
+		username     string
+		mockGetErr   error
+		mockDelErr   error
+		expectDelete bool
+		expectError  error
+	}{
+		{name: "success", actor: "admin", username: "pablo", expectDelete: true},
+		{name: "cannot delete itself", actor: "admin", username: "admin", expectError: errors.ErrOperationNotAllowed},
+		{name: "not found", actor: "admin", username: "nobody", mockGetErr: errors.ErrResourceNotFound, expectError: errors.ErrResourceNotFound},
+		{name: "repository error", actor: "admin", username: "pablo", expectDelete: true, mockDelErr: errors.ErrInternalServer, expectError: errors.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, sessionRepo := setupUserServiceTest(1000)
+			if tt.mockGetErr != nil {
+				repo.On("GetUserByUsername", tt.username).Return(nil, tt.mockGetErr)
+			} else if tt.expectDelete {
+				repo.On("GetUserByUsername", tt.username).Return(&StoredUsers[0], nil)
+				repo.On("DeleteUser", tt.username).Return(tt.mockDelErr)
+				if tt.mockDelErr == nil {
+					sessionRepo.On("GetSessionsByUsername", tt.username).Return(userSessions(tt.username), nil)
+					sessionRepo.On("RevokeSession", "session-active", "user_deleted", "now").Return(nil)
+				}
+			}
+
+			err := service.DeleteUser(tt.actor, tt.username)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// userSessions returns one active and one already revoked session of the user;
+// only the active one is expected to be revoked.
+func userSessions(username string) []models.Session {
+	return []models.Session{
+		{ID: "session-active", Username: username, Status: models.SessionStatusActive},
+		{ID: "session-revoked", Username: username, Status: models.SessionStatusRevoked},
+	}
+}
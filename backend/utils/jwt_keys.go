@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms supported for access tokens.
+// HS256 keys are shared secrets; RS256 and EdDSA keys can be verified by other services
+// from the public keys published at /.well-known/jwks.json.
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// JWTKey is a key used to sign and verify access tokens.
+//
+// Keys are rotated on a schedule: a key signs new tokens from ActivateAt until a key with a later
+// ActivateAt becomes active, and keeps verifying the tokens it signed until ExpireAt. Publishing the
+// next key ahead of time with a future ActivateAt rotates signing without a redeploy.
+type JWTKey struct {
+	ID         string           // Key identifier, sent in the "kid" header of every token
+	Algorithm  string           // One of JWTAlgorithmHS256, JWTAlgorithmRS256 or JWTAlgorithmEdDSA
+	Secret     []byte           // HMAC secret, for HS256 keys
+	PrivateKey crypto.Signer    // *rsa.PrivateKey or ed25519.PrivateKey; nil for verification-only keys
+	PublicKey  crypto.PublicKey // *rsa.PublicKey or ed25519.PublicKey, for RS256 and EdDSA keys
+	ActivateAt time.Time        // Time from which the key may sign tokens; zero means always
+	ExpireAt   time.Time        // Time from which tokens signed with the key are rejected; zero means never
+}
+
+// JWTKeySet holds the keys and the expected issuer and audience shared by the token
+// generator and the authentication middleware.
+type JWTKeySet struct {
+	Issuer   string
+	Audience string
+	Keys     []JWTKey
+	Clock    TimeProvider
+}
+
+// NewJWTKeySet validates the keys and returns a key set.
+// A key without algorithm is treated as an HS256 key. The public key of an asymmetric key
+// is derived from its private key when not given.
+// Returns an error if no key is given, a key has no ID, unsupported algorithm or missing
+// key material, or two keys share an ID.
+func NewJWTKeySet(issuer, audience string, keys []JWTKey, clock TimeProvider) (*JWTKeySet, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one JWT key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for i := range keys {
+		key := &keys[i]
+		if key.ID == "" {
+			return nil, fmt.Errorf("JWT key without kid")
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate JWT kid %q", key.ID)
+		}
+		seen[key.ID] = true
+
+		if key.Algorithm == "" {
+			key.Algorithm = JWTAlgorithmHS256
+		}
+		if err := key.validate(); err != nil {
+			return nil, fmt.Errorf("JWT key %q: %w", key.ID, err)
+		}
+	}
+	return &JWTKeySet{Issuer: issuer, Audience: audience, Keys: keys, Clock: clock}, nil
+}
+
+// Algorithms returns the algorithms of the keys in the set, which are the only ones accepted.
+func (s *JWTKeySet) Algorithms() []string {
+	var algs []string
+	for _, key := range s.Keys {
+		if !slices.Contains(algs, key.Algorithm) {
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	return algs
+}
+
+// SigningKey returns the key that signs new tokens: the most recently activated key
+// that has not expired and holds signing material.
+// Returns an error if no such key exists.
+func (s *JWTKeySet) SigningKey() (JWTKey, error) {
+	now := s.now()
+	var current *JWTKey
+	for i := range s.Keys {
+		key := &s.Keys[i]
+		if !key.canSign() || !key.usableAt(now) {
+			continue
+		}
+		if current == nil || key.ActivateAt.After(current.ActivateAt) {
+			current = key
+		}
+	}
+	if current == nil {
+		return JWTKey{}, fmt.Errorf("no active JWT signing key")
+	}
+	return *current, nil
+}
+
+// VerificationKey returns the key material that verifies a token signed with the key identified by kid.
+// The token algorithm must be the algorithm of that key, so a public key can never be used as an HMAC secret.
+// Returns an error for unknown, not yet active or expired keys, or an algorithm mismatch.
+func (s *JWTKeySet) VerificationKey(kid, alg string) (interface{}, error) {
+	now := s.now()
+	for _, key := range s.Keys {
+		if key.ID != kid {
+			continue
+		}
+		if key.Algorithm != alg {
+			return nil, fmt.Errorf("JWT key %q does not use %s", kid, alg)
+		}
+		if !key.usableAt(now) {
+			return nil, fmt.Errorf("JWT key %q is not active", kid)
+		}
+		if key.Algorithm == JWTAlgorithmHS256 {
+			return key.Secret, nil
+		}
+		return key.PublicKey, nil
+	}
+	return nil, fmt.Errorf("unknown JWT key %q", kid)
+}
+
+// signingMaterial returns what jwt.Token.SignedString expects for the key's algorithm.
+func (k JWTKey) signingMaterial() interface{} {
+	if k.Algorithm == JWTAlgorithmHS256 {
+		return k.Secret
+	}
+	return k.PrivateKey
+}
+
+func (k JWTKey) canSign() bool {
+	if k.Algorithm == JWTAlgorithmHS256 {
+		return len(k.Secret) > 0
+	}
+	return k.PrivateKey != nil
+}
+
+func (k *JWTKey) validate() error {
+	switch k.Algorithm {
+	case JWTAlgorithmHS256:
+		if len(k.Secret) == 0 {
+			return fmt.Errorf("empty secret")
+		}
+		return nil
+	case JWTAlgorithmRS256, JWTAlgorithmEdDSA:
+	default:
+		return fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+	}
+
+	if k.PublicKey == nil && k.PrivateKey != nil {
+		k.PublicKey = k.PrivateKey.Public()
+	}
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if k.Algorithm != JWTAlgorithmRS256 {
+			return fmt.Errorf("RSA key cannot be used with %s", k.Algorithm)
+		}
+	case ed25519.PublicKey:
+		if k.Algorithm != JWTAlgorithmEdDSA {
+			return fmt.Errorf("Ed25519 key cannot be used with %s", k.Algorithm)
+		}
+	case nil:
+		return fmt.Errorf("missing public or private key")
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return nil
+}
+
+func (s *JWTKeySet) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return time.Unix(s.Clock.NowUnix(), 0)
+}
+
+func (k JWTKey) usableAt(now time.Time) bool {
+	if !k.ActivateAt.IsZero() && now.Before(k.ActivateAt) {
+		return false
+	}
+	return k.ExpireAt.IsZero() || now.Before(k.ExpireAt)
+}
+
+// ParsePrivateKeyPEM parses an RSA or Ed25519 private key in PEM format.
+// Returns the key and the algorithm it signs with.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, string, error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, JWTAlgorithmRS256, nil
+	}
+	key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("private key is neither RSA nor Ed25519 PEM")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, JWTAlgorithmEdDSA, nil
+}
+
+// ParsePublicKeyPEM parses an RSA or Ed25519 public key in PEM format.
+// Returns the key and the algorithm it verifies.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, string, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, JWTAlgorithmRS256, nil
+	}
+	key, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("public key is neither RSA nor Ed25519 PEM")
+	}
+	return key, JWTAlgorithmEdDSA, nil
+}
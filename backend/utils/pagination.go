@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"encoding/base64"
 	"strconv"
+	"strings"
+
+	"github.com/CristinaRendaLopez/rendalla-backend/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/guregu/dynamo"
@@ -30,3 +34,41 @@ func ExtractPaginationParams(c *gin.Context) (int, dynamo.PagingKey) {
 
 	return limit, nextToken
 }
+
+// EncodeSegmentCursor returns an opaque pagination token pointing at the given position of a result list
+// held in memory, which was read from storage starting at segment (a repository token, empty for the start).
+func EncodeSegmentCursor(segment string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset) + ":" + segment))
+}
+
+// DecodeSegmentCursor parses a token produced by EncodeSegmentCursor.
+// An empty token decodes to offset 0 of the first segment.
+// Returns:
+//   - the decoded segment and offset on success
+//   - errors.ErrBadRequest if the token is malformed
+func DecodeSegmentCursor(token string) (string, int, error) {
+	if token == "" {
+		return "", 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, errors.ErrBadRequest
+	}
+
+	value, found := strings.CutPrefix(string(raw), "offset:")
+	if !found {
+		return "", 0, errors.ErrBadRequest
+	}
+	value, segment, found := strings.Cut(value, ":")
+	if !found {
+		return "", 0, errors.ErrBadRequest
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return "", 0, errors.ErrBadRequest
+	}
+
+	return segment, offset, nil
+}
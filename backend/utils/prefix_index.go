@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PrefixIndex is a concurrency-safe sorted index of terms supporting prefix lookups.
+// Terms are keyed by their normalized form and reference-counted, so the same value
+// contributed by several entities is stored once and only disappears when the last
+// reference is removed. The original (display) form of each term is preserved.
+type PrefixIndex struct {
+	mu      sync.RWMutex
+	keys    []string
+	counts  map[string]int
+	display map[string]string
+}
+
+// NewPrefixIndex returns an empty PrefixIndex.
+func NewPrefixIndex() *PrefixIndex {
+	return &PrefixIndex{
+		counts:  make(map[string]int),
+		display: make(map[string]string),
+	}
+}
+
+// Add registers one reference to the given term. Blank terms are ignored.
+func (p *PrefixIndex) Add(term string) {
+	key := Normalize(strings.TrimSpace(term))
+	if key == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.counts[key] == 0 {
+		i := sort.SearchStrings(p.keys, key)
+		p.keys = append(p.keys, "")
+		copy(p.keys[i+1:], p.keys[i:])
+		p.keys[i] = key
+	}
+	p.counts[key]++
+	p.display[key] = strings.TrimSpace(term)
+}
+
+// Remove drops one reference to the given term, deleting it once no references remain.
+func (p *PrefixIndex) Remove(term string) {
+	key := Normalize(strings.TrimSpace(term))
+	if key == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.counts[key] == 0 {
+		return
+	}
+	p.counts[key]--
+	if p.counts[key] > 0 {
+		return
+	}
+
+	delete(p.counts, key)
+	delete(p.display, key)
+	i := sort.SearchStrings(p.keys, key)
+	if i < len(p.keys) && p.keys[i] == key {
+		p.keys = append(p.keys[:i], p.keys[i+1:]...)
+	}
+}
+
+// Search returns up to limit terms (in their display form) whose normalized form starts with the normalized prefix.
+// Results are ordered alphabetically by normalized form.
+func (p *PrefixIndex) Search(prefix string, limit int) []string {
+	key := Normalize(strings.TrimSpace(prefix))
+	results := []string{}
+	if key == "" || limit <= 0 {
+		return results
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for i := sort.SearchStrings(p.keys, key); i < len(p.keys) && len(results) < limit; i++ {
+		if !strings.HasPrefix(p.keys[i], key) {
+			break
+		}
+		results = append(results, p.display[p.keys[i]])
+	}
+	return results
+}
+
+// Len returns the number of distinct terms in the index.
+func (p *PrefixIndex) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.keys)
+}
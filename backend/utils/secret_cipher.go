@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// secretCipherVersion prefixes ciphertexts so the format can evolve.
+const secretCipherVersion = "v1:"
+
+// SecretCipher encrypts small secrets, such as TOTP seeds, before they are stored.
+// It uses AES-256-GCM with a random nonce; the associated data binds a ciphertext to its
+// owner so that it cannot be copied to another record.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher returns a SecretCipher using a 32-byte key.
+func NewSecretCipher(key []byte) (*SecretCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt seals plaintext and returns a printable ciphertext.
+func (c *SecretCipher) Encrypt(plaintext []byte, associatedData string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, []byte(associatedData))
+	return secretCipherVersion + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt with the same associated data.
+func (c *SecretCipher) Decrypt(ciphertext, associatedData string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, secretCipherVersion)
+	if !ok {
+		return nil, fmt.Errorf("unsupported ciphertext format")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding ciphertext: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, sealed, []byte(associatedData))
+}
@@ -2,22 +2,67 @@ package utils
 
 import (
 	"strings"
+	"unicode"
 )
 
+// accentReplacements maps common accented characters to their non-accented counterparts.
+// Supports Spanish vowels and the 'ñ' character.
+var accentReplacements = map[rune]rune{
+	'á': 'a', 'é': 'e', 'í': 'i', 'ó': 'o', 'ú': 'u',
+	'Á': 'a', 'É': 'e', 'Í': 'i', 'Ó': 'o', 'Ú': 'u',
+	'ñ': 'n', 'Ñ': 'n',
+}
+
 // Normalize returns a lowercase, accent-free version of the input string.
 // Useful for performing normalized search or comparisons.
+//
+// Normalization maps every rune to exactly one rune, so the n-th rune of the
+// normalized string always corresponds to the n-th rune of the input.
 func Normalize(input string) string {
-	output := strings.ToLower(removeAccents(input))
-	return output
+	return strings.Map(normalizeRune, input)
 }
 
-// removeAccents replaces common accented characters with their non-accented counterparts.
-// Supports Spanish vowels and the 'ñ' character.
-func removeAccents(s string) string {
-	replacer := strings.NewReplacer(
-		"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u",
-		"Á", "a", "É", "e", "Í", "i", "Ó", "o", "Ú", "u",
-		"ñ", "n", "Ñ", "n",
-	)
-	return replacer.Replace(s)
+// normalizeRune removes the accent from a single rune and lowercases it.
+func normalizeRune(r rune) rune {
+	if replacement, ok := accentReplacements[r]; ok {
+		return replacement
+	}
+	return unicode.ToLower(r)
+}
+
+// TextSpan identifies a range of characters in a string using rune (Unicode code point) offsets.
+// Start is inclusive and End is exclusive.
+type TextSpan struct {
+	Start int
+	End   int
+}
+
+// FindMatchSpans returns every non-overlapping occurrence of query in text, ignoring case and accents.
+// Matching is performed on the normalized forms, but the returned offsets refer to the original text.
+func FindMatchSpans(text, query string) []TextSpan {
+	needle := []rune(Normalize(strings.TrimSpace(query)))
+	if len(needle) == 0 {
+		return nil
+	}
+
+	haystack := []rune(Normalize(text))
+	var spans []TextSpan
+	for i := 0; i+len(needle) <= len(haystack); {
+		if runesEqual(haystack[i:i+len(needle)], needle) {
+			spans = append(spans, TextSpan{Start: i, End: i + len(needle)})
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+	return spans
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -10,18 +12,36 @@ type TokenGenerator interface {
 	GenerateToken(claims jwt.MapClaims) (string, error)
 }
 
-// JWTTokenGenerator implements TokenGenerator using HMAC with a provided secret.
+// JWTTokenGenerator implements TokenGenerator using the current signing key of a JWTKeySet.
 type JWTTokenGenerator struct {
-	Secret []byte
+	Keys *JWTKeySet
 }
 
-// GenerateToken creates a signed JWT token with the given claims using HS256.
+// GenerateToken creates a signed JWT token with the given claims using the algorithm of the current signing key.
+// The "iss" and "aud" claims of the key set are added, and the "kid" header identifies the signing key.
 // Returns:
 //   - the signed token as a string on success
-//   - error if signing fails
+//   - error if no key is active or signing fails
 func (g *JWTTokenGenerator) GenerateToken(claims jwt.MapClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(g.Secret)
+	key, err := g.Keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	signed := make(jwt.MapClaims, len(claims)+2)
+	for k, v := range claims {
+		signed[k] = v
+	}
+	signed["iss"] = g.Keys.Issuer
+	signed["aud"] = g.Keys.Audience
+
+	method := jwt.GetSigningMethod(key.Algorithm)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing algorithm %s", key.Algorithm)
+	}
+	token := jwt.NewWithClaims(method, signed)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signingMaterial())
 }
 
 // Ensure JWTTokenGenerator satisfies the TokenGenerator interface.
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+)
+
+// Parameters of the time-based one-time passwords (RFC 6238) used as second factor.
+// They are the defaults understood by every authenticator app.
+const (
+	TOTPDigits     = 6
+	TOTPPeriod     = 30 // seconds
+	totpSecretSize = 20 // bytes, the size of an HMAC-SHA1 key
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random TOTP seed.
+func GenerateTOTPSecret() ([]byte, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeTOTPSecret returns the base32 form of a seed shown to users who cannot scan a QR code.
+func EncodeTOTPSecret(secret []byte) string {
+	return totpEncoding.EncodeToString(secret)
+}
+
+// TOTPStep returns the time step of a Unix time.
+func TOTPStep(unix int64) int64 {
+	return unix / TOTPPeriod
+}
+
+// TOTPCode returns the code of a seed for a time step (HOTP, RFC 4226, with SHA-1).
+func TOTPCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1_000_000)
+}
+
+// TOTPURI returns the otpauth:// URI that authenticator apps import, usually from a QR code.
+func TOTPURI(issuer, account string, secret []byte) string {
+	params := url.Values{
+		"secret":    {EncodeTOTPSecret(secret)},
+		"issuer":    {issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(TOTPDigits)},
+		"period":    {fmt.Sprint(TOTPPeriod)},
+	}
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}